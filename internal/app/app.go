@@ -2,15 +2,24 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/anmaso/pubsub-tui/internal/components/activity"
 	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/dialog"
+	"github.com/anmaso/pubsub-tui/internal/components/palette"
 	"github.com/anmaso/pubsub-tui/internal/components/publisher"
 	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
 	"github.com/anmaso/pubsub-tui/internal/components/subscriptions"
 	"github.com/anmaso/pubsub-tui/internal/components/topics"
-	"github.com/anmaso/pubsub-tui/internal/pubsub"
+	"github.com/anmaso/pubsub-tui/internal/debuglog"
+	"github.com/anmaso/pubsub-tui/internal/httpapi"
+	"github.com/anmaso/pubsub-tui/internal/utils"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -24,11 +33,73 @@ const (
 	FocusSubscriber    FocusPanel = "subscriber"
 )
 
+// Options configures optional application behavior
+type Options struct {
+	// ReceiveConfig controls flow control for subscription streams
+	ReceiveConfig pubsub.ReceiveConfig
+
+	// Snapshots, when set, receives a copy of the subscriber buffer
+	// whenever it changes (used by the optional HTTP endpoint)
+	Snapshots *httpapi.Store
+
+	// Metrics, when set, counts message activity for the /metrics endpoint
+	Metrics *httpapi.Metrics
+
+	// JSONIndent is the indentation for displayed JSON (default two spaces)
+	JSONIndent string
+
+	// ListAttribute is a message attribute shown in each subscriber list row
+	ListAttribute string
+
+	// RowColors colors subscriber list rows by an attribute value
+	RowColors subscriber.RowColors
+
+	// AckDelay is how long the subscriber's delayed ack mode waits before
+	// acknowledging a message (default subscriber.DefaultAckDelay)
+	AckDelay time.Duration
+
+	// EmulatorHost is the emulator address when connected to the Pub/Sub
+	// emulator; empty when connected to real GCP
+	EmulatorHost string
+
+	// CredentialsFile is the service account key file used to create the
+	// client, reused when reconnecting; empty for default credentials
+	CredentialsFile string
+
+	// TemplateDir is the directory the publisher lists message files from;
+	// empty for the working directory
+	TemplateDir string
+
+	// RecursiveTemplates lists message files in subdirectories too
+	RecursiveTemplates bool
+
+	// Template, when set, is a message file selected in the publisher at
+	// startup; it may be outside the working directory
+	Template *utils.JSONFile
+
+	// StatePath is where the focused panel and column split are restored
+	// from and saved to; empty disables it
+	StatePath string
+
+	// KeyWarnings are problems found in the key config file, such as
+	// unknown actions, logged at startup
+	KeyWarnings []string
+
+	// PublishAttempts is how many times a publish is attempted when it
+	// fails with a transient error (default config.DefaultPublishAttempts)
+	PublishAttempts int
+
+	// Footer hides optional parts of the footer
+	Footer FooterOptions
+}
+
 // Model is the main application model
 type Model struct {
 	// Pub/Sub client
-	client    *pubsub.Client
-	projectID string
+	client     *pubsub.Client
+	ownsClient bool // Whether client was created by reconnecting, so the model closes it
+	projectID  string
+	options    Options
 
 	// Child components
 	topics        topics.Model
@@ -36,36 +107,102 @@ type Model struct {
 	publisher     publisher.Model
 	subscriber    subscriber.Model
 	activity      activity.Model
+	dialog        dialog.Model
+	palette       palette.Model
 
 	// Subscription management
 	activeSubscription *pubsub.Subscription
 	subscriptionCtx    context.Context
 	subscriptionCancel context.CancelFunc
+	reconnectAttempts  int // Consecutive reconnect attempts after receive errors
+
+	// Connection health from the periodic background check
+	health         connectionHealth
+	healthFailures int // Consecutive checks that could not reach the server
+
+	// Quit in progress while the active subscription drains
+	quitting    bool
+	exitSummary string
+
+	// Bulk topic deletion in progress (nil when idle)
+	bulk *bulkDelete
+
+	// Last single topic or subscription deleted, for undo (nil when none)
+	lastDeleted *deletedResource
+
+	// Scheduled publishes waiting for their delay, by ID
+	scheduled      map[int]scheduledPublish
+	nextScheduleID int
+
+	// Publishes waiting for the server, cancelled with Esc in the publisher
+	publishes *inFlightPublishes
+
+	// Operation that failed with an auth error, shown in a banner (nil when none)
+	authFailure *authFailure
 
 	// UI state
-	focus    FocusPanel
-	width    int
-	height   int
-	ready    bool
-	showHelp bool
+	focus     FocusPanel
+	layout    layoutMode
+	leftRatio float64 // Left column width as a fraction of the terminal
+	stateErr  error   // Failure loading saved UI state, reported on start
+	width     int
+	height    int
+	ready     bool
+	showHelp  bool
+	help      viewport.Model // Scrollable help overlay content
+
+	pendingGo bool // g was pressed and waits for a panel letter
 
 	// Selected state
 	selectedTopic        string
 	selectedSubscription string
+	sessionAcks          int // Messages acked on the selected subscription, shown in the footer
 }
 
 // New creates a new application model
-func New(client *pubsub.Client, projectID string) Model {
-	return Model{
+func New(client *pubsub.Client, projectID string, opts Options) Model {
+	m := Model{
 		client:        client,
 		projectID:     projectID,
+		options:       opts,
 		topics:        topics.New(),
 		subscriptions: subscriptions.New(),
 		publisher:     publisher.New(),
 		subscriber:    subscriber.New(),
 		activity:      activity.New(),
+		dialog:        dialog.New(),
+		palette:       palette.New(),
+		scheduled:     make(map[int]scheduledPublish),
+		publishes:     newInFlightPublishes(),
 		focus:         FocusTopics,
+		layout:        layoutSplit,
+		leftRatio:     defaultLeftRatio,
+	}
+
+	if opts.StatePath != "" {
+		state, err := loadState(opts.StatePath)
+		m.stateErr = err
+		m.applyState(state)
+	}
+
+	m.subscriber.SetMaxOutstanding(opts.ReceiveConfig.MaxOutstandingMessages)
+	m.subscriber.SetListAttribute(opts.ListAttribute)
+	m.subscriber.SetRowColors(opts.RowColors)
+	m.subscriber.SetAckDelay(opts.AckDelay)
+	m.publisher.SetFilesDir(opts.TemplateDir)
+	m.publisher.SetRecursive(opts.RecursiveTemplates)
+	if opts.Template != nil {
+		m.publisher.SetTemplate(*opts.Template)
+	}
+	m.topics.SetEmulatorMode(m.IsEmulator())
+	m.subscriptions.SetEmulatorMode(m.IsEmulator())
+	m.subscriber.SetEmulatorMode(m.IsEmulator())
+
+	if opts.JSONIndent != "" {
+		m.publisher.SetJSONIndent(opts.JSONIndent)
+		m.subscriber.SetJSONIndent(opts.JSONIndent)
 	}
+	return m
 }
 
 // Init initializes the application
@@ -73,22 +210,49 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadTopics(),
 		m.loadSubscriptions(),
-		publisher.LoadFiles(),
-		publisher.StartFileWatch(""), // Watch current directory for JSON file changes
+		publisher.LoadFiles(m.publisher.FilesDir(), m.publisher.IsRecursive()),
+		publisher.StartFileWatch(m.publisher.FilesDir(), m.publisher.IsRecursive()), // Watch for JSON file changes
 		m.topics.SpinnerTickCmd(),
 		m.subscriptions.SpinnerTickCmd(),
+		common.StatusTick(),
+		m.checkHealth(),
 		func() tea.Msg {
 			return common.Info("Application started")
 		},
 		func() tea.Msg {
 			return common.Network("Connected to project: " + m.projectID)
 		},
+		m.reportStateError(),
+		m.reportKeyWarnings(),
 	)
 }
 
+// reportStateError logs a failure to load saved UI state, if any
+func (m Model) reportStateError() tea.Cmd {
+	if m.stateErr == nil {
+		return nil
+	}
+	err := m.stateErr
+	return func() tea.Msg {
+		return common.Warning(err.Error() + " (using defaults)")
+	}
+}
+
+// reportKeyWarnings logs problems found in the key config file
+func (m Model) reportKeyWarnings() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, w := range m.options.KeyWarnings {
+		w := w
+		cmds = append(cmds, func() tea.Msg {
+			return common.Warning("Key config: " + w + " (ignored)")
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
 // loadTopics loads topics from GCP
 func (m Model) loadTopics() tea.Cmd {
-	return func() tea.Msg {
+	return retryOnAuth("loading topics", func() tea.Msg {
 		ctx := context.Background()
 		topicsList, err := m.client.ListTopics(ctx)
 		if err != nil {
@@ -104,12 +268,12 @@ func (m Model) loadTopics() tea.Cmd {
 		}
 
 		return common.TopicsLoadedMsg{Topics: topics}
-	}
+	})
 }
 
 // loadSubscriptions loads subscriptions from GCP
 func (m Model) loadSubscriptions() tea.Cmd {
-	return func() tea.Msg {
+	return retryOnAuth("loading subscriptions", func() tea.Msg {
 		ctx := context.Background()
 		subsList, err := m.client.ListSubscriptions(ctx)
 		if err != nil {
@@ -123,11 +287,119 @@ func (m Model) loadSubscriptions() tea.Cmd {
 				FullName:  s.FullName,
 				TopicName: s.TopicName,
 				TopicFull: s.TopicFull,
+				Filter:    s.Filter,
+				Orphaned:  s.Orphaned,
+
+				ConfigLoaded: s.ConfigLoaded,
+				IsPush:       s.IsPush,
+				PushEndpoint: s.PushEndpoint,
+
+				ExpirationTTL: s.ExpirationTTL,
 			})
 		}
 
 		return common.SubscriptionsLoadedMsg{Subscriptions: subs}
+	})
+}
+
+// loadSnapshots loads snapshots from GCP
+func (m Model) loadSnapshots() tea.Cmd {
+	return retryOnAuth("loading snapshots", func() tea.Msg {
+		ctx := context.Background()
+		snapList, err := m.client.ListSnapshots(ctx)
+		if err != nil {
+			return common.SnapshotsLoadedMsg{Err: err}
+		}
+
+		var snapshots []common.SnapshotData
+		for _, s := range snapList {
+			snapshots = append(snapshots, common.SnapshotData{
+				Name:       s.Name,
+				FullName:   s.FullName,
+				TopicName:  s.TopicName,
+				Expiration: s.Expiration,
+			})
+		}
+
+		return common.SnapshotsLoadedMsg{Snapshots: snapshots}
+	})
+}
+
+// fetchTopicSchema looks up the schema attached to a topic for decoding
+// received messages
+func (m Model) fetchTopicSchema(topicName string) tea.Cmd {
+	if topicName == "" || topicName == pubsub.UnknownTopic || topicName == pubsub.DeletedTopic {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		schema, err := m.client.GetTopicSchema(ctx, topicName)
+		return subscriber.TopicSchemaMsg{TopicName: topicName, Schema: schema, Err: err}
+	}
+}
+
+// disconnectDialogID identifies the dialog confirming a switch away from
+// the active subscription
+const disconnectDialogID = "confirm-disconnect"
+
+// confirmDisconnect asks whether to disconnect the active subscription and
+// connect to the selected one
+func (m *Model) confirmDisconnect(sel common.SubscriptionSelectedMsg) {
+	message := fmt.Sprintf("Disconnect from %s and connect to %s?", m.selectedSubscription, sel.SubscriptionName)
+	if n := m.subscriber.MessageCount(); n > 0 {
+		message += fmt.Sprintf("\n%d captured messages will be cleared.", n)
+	}
+	m.dialog.ShowConfirm(disconnectDialogID, "Switch Subscription", message, sel)
+}
+
+// confirmAutoAck warns that automatic acks permanently consume messages
+// before switching the subscriber to an automatic ack mode
+func (m *Model) confirmAutoAck(req subscriber.AutoAckConfirmRequestMsg) {
+	target := "this project's subscriptions"
+	if req.Subscription != "" {
+		target = req.Subscription
 	}
+	message := fmt.Sprintf("Messages received from %s will be acknowledged %s and permanently removed from the subscription.\nEnable auto-ack?", target, req.Mode)
+	m.dialog.ShowConfirm(subscriber.AutoAckDialogID, "Enable Auto-Ack", message, req.Mode)
+}
+
+// connectSubscription stops any active subscription and starts receiving
+// from the selected one
+func (m *Model) connectSubscription(msg common.SubscriptionSelectedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+
+	if prev := m.selectedSubscription; prev != "" && prev != msg.SubscriptionName {
+		m.stopSubscription()
+		cmds = append(cmds, func() tea.Msg {
+			return common.Info(fmt.Sprintf("Stopped previous subscription: %s", prev))
+		})
+	}
+
+	if m.selectedSubscription != msg.SubscriptionName {
+		m.sessionAcks = 0
+	}
+	m.selectedSubscription = msg.SubscriptionName
+	m.reconnectAttempts = 0
+
+	// Update subscriptions panel with active subscription
+	m.subscriptions.SetActiveSubscription(msg.SubscriptionName)
+	m.topics.SetActiveSubscription(msg.SubscriptionName)
+
+	// Update subscriber - pass message through Update to start spinner
+	var cmd tea.Cmd
+	m.subscriber, cmd = m.subscriber.Update(msg)
+	cmds = append(cmds, cmd)
+
+	// Start subscription stream
+	cmds = append(cmds, m.startSubscription(msg.SubscriptionName, msg.TopicName))
+	cmds = append(cmds, m.fetchTopicSchema(msg.TopicName))
+
+	m.syncSnapshot()
+	cmds = append(cmds, func() tea.Msg {
+		return common.Network(fmt.Sprintf("Started subscription: %s", msg.SubscriptionName))
+	})
+	return tea.Batch(cmds...)
 }
 
 // startSubscription starts receiving messages from a subscription
@@ -136,11 +408,12 @@ func (m *Model) startSubscription(subName, topicName string) tea.Cmd {
 	m.stopSubscription()
 
 	// Create new subscription
-	m.activeSubscription = m.client.Subscribe(subName)
+	m.activeSubscription = m.client.Subscribe(subName, m.options.ReceiveConfig)
 	m.subscriptionCtx, m.subscriptionCancel = context.WithCancel(context.Background())
 
 	// Start receiving
 	m.activeSubscription.Start(m.subscriptionCtx)
+	debuglog.Printf("subscription %s started on topic %s", subName, topicName)
 
 	// Return command that polls for messages
 	return m.pollMessages()
@@ -149,6 +422,7 @@ func (m *Model) startSubscription(subName, topicName string) tea.Cmd {
 // stopSubscription stops the active subscription
 func (m *Model) stopSubscription() {
 	if m.activeSubscription != nil {
+		debuglog.Printf("subscription stopped")
 		m.activeSubscription.Stop()
 		m.activeSubscription = nil
 	}
@@ -158,6 +432,144 @@ func (m *Model) stopSubscription() {
 	}
 }
 
+// quitDrainTimeout bounds how long quitting waits for in-flight acks
+const quitDrainTimeout = 2 * time.Second
+
+// SubscriptionDrainedMsg is sent when the active subscription has drained on quit
+type SubscriptionDrainedMsg struct {
+	SubscriptionName string
+	Unacked          int
+}
+
+// drainSubscription stops intake on the active subscription and returns a
+// command that waits for in-flight acks to be sent before reporting
+func (m *Model) drainSubscription() tea.Cmd {
+	sub := m.activeSubscription
+	subName := m.subscriber.SubscriptionName()
+	m.activeSubscription = nil
+
+	return tea.Batch(
+		func() tea.Msg {
+			return common.Info(fmt.Sprintf("Stopping %s, waiting for in-flight acks...", subName))
+		},
+		func() tea.Msg {
+			return SubscriptionDrainedMsg{
+				SubscriptionName: subName,
+				Unacked:          sub.Drain(quitDrainTimeout),
+			}
+		},
+	)
+}
+
+// IsEmulator returns whether the application is connected to the emulator
+func (m Model) IsEmulator() bool {
+	return m.options.EmulatorHost != ""
+}
+
+// environmentLabel describes where commands are sent, e.g. "EMULATOR @ host"
+func (m Model) environmentLabel() string {
+	if m.IsEmulator() {
+		return "EMULATOR @ " + m.options.EmulatorHost
+	}
+	return "GCP: " + m.projectID
+}
+
+// ExitSummary describes anything left unfinished when the application quit
+func (m Model) ExitSummary() string {
+	return m.exitSummary
+}
+
+// Reconnect backoff settings for failed subscription streams
+const (
+	maxReconnectAttempts = 5
+	maxReconnectDelay    = 30 * time.Second
+)
+
+// ReconnectSubscriptionMsg requests restarting a subscription stream after an error
+type ReconnectSubscriptionMsg struct {
+	SubscriptionName string
+	TopicName        string
+	Attempt          int
+}
+
+// reconnectDelay returns the backoff before the given attempt (1s, 2s, 4s, ... capped)
+func reconnectDelay(attempt int) time.Duration {
+	delay := time.Second
+	for i := 1; i < attempt && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxReconnectDelay {
+		delay = maxReconnectDelay
+	}
+	return delay
+}
+
+// scheduleReconnect stops the failed stream and returns a command that
+// requests a reconnect after the backoff for the next attempt
+func (m *Model) scheduleReconnect(subName, topicName string) tea.Cmd {
+	m.stopSubscription()
+	m.reconnectAttempts++
+
+	attempt := m.reconnectAttempts
+	return tea.Tick(reconnectDelay(attempt), func(time.Time) tea.Msg {
+		return ReconnectSubscriptionMsg{
+			SubscriptionName: subName,
+			TopicName:        topicName,
+			Attempt:          attempt,
+		}
+	})
+}
+
+// scheduledPublish is a publish waiting for its delay to elapse
+type scheduledPublish struct {
+	topic       string
+	content     []byte
+	attributes  map[string]string
+	orderingKey string
+}
+
+// ScheduledPublishDueMsg is sent when a scheduled publish's delay has elapsed
+type ScheduledPublishDueMsg struct {
+	ID int
+}
+
+// schedulePublish records a delayed publish and returns its ID and a command
+// that fires when the delay elapses
+func (m *Model) schedulePublish(req publisher.SchedulePublishMsg) (int, tea.Cmd) {
+	m.nextScheduleID++
+	id := m.nextScheduleID
+	m.scheduled[id] = scheduledPublish{
+		topic:       req.Topic,
+		content:     req.Content,
+		attributes:  req.Attributes,
+		orderingKey: req.OrderingKey,
+	}
+	m.publisher.SetScheduledCount(len(m.scheduled))
+
+	return id, tea.Tick(req.Delay, func(time.Time) tea.Msg {
+		return ScheduledPublishDueMsg{ID: id}
+	})
+}
+
+// cancelScheduledPublishes drops all pending scheduled publishes so their
+// timers have no effect. Returns how many were cancelled.
+func (m *Model) cancelScheduledPublishes() int {
+	n := len(m.scheduled)
+	for id := range m.scheduled {
+		delete(m.scheduled, id)
+	}
+	m.publisher.SetScheduledCount(0)
+	return n
+}
+
+// syncSnapshot publishes the subscriber buffer to the snapshot store, if enabled
+func (m *Model) syncSnapshot() {
+	if m.options.Snapshots == nil {
+		return
+	}
+	m.options.Snapshots.Update(m.subscriber.SubscriptionName(), m.subscriber.Messages(), m.subscriber.Dropped())
+}
+
 // pollMessages returns a command that polls for new messages
 func (m *Model) pollMessages() tea.Cmd {
 	if m.activeSubscription == nil {
@@ -181,14 +593,90 @@ func (m *Model) pollMessages() tea.Cmd {
 	}
 }
 
-// publishMessage publishes a message to the topic
-func (m *Model) publishMessage(topic string, content []byte) tea.Cmd {
+// publishBatch publishes count copies of content without waiting between
+// them, so the client can batch the requests, and reports once all complete.
+// Like publishMessage, it gives up after publishTimeout or when cancelled.
+func (m *Model) publishBatch(topic string, content []byte, count int) tea.Cmd {
+	client, publishes := m.client, m.publishes
 	return func() tea.Msg {
-		ctx := context.Background()
-		result := m.client.Publish(ctx, topic, content, nil)
+		ctx, done := publishes.start()
+		defer done()
+		handles := make([]*pubsub.PublishHandle, 0, count)
+		for i := 0; i < count; i++ {
+			handles = append(handles, client.PublishAsync(ctx, topic, content, nil, ""))
+		}
+
+		result := pubsub.AwaitAll(ctx, handles)
+		return publisher.BatchPublishResultMsg{
+			Topic:     topic,
+			Published: len(result.MessageIDs),
+			Errors:    result.Errors,
+		}
+	}
+}
+
+// publishFanOut publishes content to every topic without waiting between
+// them, and reports each topic's result once all complete. Like
+// publishMessage, it gives up after publishTimeout or when cancelled.
+func (m *Model) publishFanOut(topics []string, content []byte) tea.Cmd {
+	client, publishes := m.client, m.publishes
+	return func() tea.Msg {
+		ctx, done := publishes.start()
+		defer done()
+		handles := make([]*pubsub.PublishHandle, len(topics))
+		for i, topic := range topics {
+			handles[i] = client.PublishAsync(ctx, topic, content, nil, "")
+		}
+
+		results := make([]publisher.TopicPublishResult, len(topics))
+		for i, h := range handles {
+			result := h.Get(ctx)
+			results[i] = publisher.TopicPublishResult{
+				Topic:     topics[i],
+				TopicFull: result.Topic,
+				MessageID: result.MessageID,
+				Err:       result.Error,
+			}
+		}
+		return publisher.FanOutPublishResultMsg{Content: content, Results: results}
+	}
+}
+
+// publishMessage publishes a message to the topic, giving up after
+// publishTimeout or when cancelled with cancelPublishes. Transient failures
+// are retried with backoff up to the configured attempts.
+func (m *Model) publishMessage(topic string, content []byte, attributes map[string]string, orderingKey string) tea.Cmd {
+	return m.publishAttempt(publishRequest{
+		topic:       topic,
+		content:     content,
+		attributes:  attributes,
+		orderingKey: orderingKey,
+		attempt:     1,
+	})
+}
+
+// publishAttempt returns a command that makes one attempt at a publish. A
+// transient failure before the last attempt reports a publishRetryMsg
+// instead of the result.
+func (m *Model) publishAttempt(req publishRequest) tea.Cmd {
+	client, publishes, maxAttempts := m.client, m.publishes, m.publishAttempts()
+	return retryOnAuth("publishing to "+req.topic, func() tea.Msg {
+		ctx, done := publishes.start()
+		defer done()
+		result := client.PublishWithOrderingKey(ctx, req.topic, req.content, req.attributes, req.orderingKey)
+		err := result.Error
+		if shouldRetryPublish(err, req.attempt, maxAttempts) {
+			return publishRetryMsg{req: req, err: err}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("no response after %s: %w", publishTimeout, err)
+		}
 		return publisher.PublishResultMsg{
+			Topic:     req.topic,
+			TopicFull: result.Topic,
+			Content:   req.content,
 			MessageID: result.MessageID,
-			Err:       result.Error,
+			Err:       err,
 		}
-	}
+	})
 }
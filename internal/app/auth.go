@@ -0,0 +1,142 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reauthCommand is suggested when credentials expire mid-session
+const reauthCommand = "gcloud auth application-default login"
+
+// authBannerHeight is the height of the re-auth banner above the footer
+const authBannerHeight = 1
+
+// authFailure is an operation that failed with an auth error, kept so it
+// can be retried once the user has re-authenticated outside the app
+type authFailure struct {
+	action string
+	err    error
+	retry  tea.Cmd // Nil when the operation cannot be retried
+}
+
+// AuthErrorMsg is sent when a client operation fails because credentials
+// are missing, expired or lack permission. Result is the operation's usual
+// result message, which is still handled as normal.
+type AuthErrorMsg struct {
+	Action string
+	Err    error
+	Retry  tea.Cmd
+	Result tea.Msg
+}
+
+// retryOnAuth wraps a client command so an auth error in its result raises
+// the re-auth banner, keeping the command so the user can retry it
+func retryOnAuth(action string, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := cmd()
+		if err := resultError(msg); pubsub.IsAuthError(err) {
+			return AuthErrorMsg{
+				Action: action,
+				Err:    err,
+				Retry:  retryOnAuth(action, cmd),
+				Result: msg,
+			}
+		}
+		return msg
+	}
+}
+
+// resultError returns the error carried by a client result message
+func resultError(msg tea.Msg) error {
+	switch msg := msg.(type) {
+	case common.TopicsLoadedMsg:
+		return msg.Err
+	case common.SubscriptionsLoadedMsg:
+		return msg.Err
+	case common.SnapshotsLoadedMsg:
+		return msg.Err
+	case common.TopicCreatedMsg:
+		return msg.Err
+	case common.TopicDeletedMsg:
+		return msg.Err
+	case common.SubscriptionCreatedMsg:
+		return msg.Err
+	case common.SubscriptionDeletedMsg:
+		return msg.Err
+	case common.SnapshotCreatedMsg:
+		return msg.Err
+	case common.SnapshotSeekedMsg:
+		return msg.Err
+	case common.SnapshotDeletedMsg:
+		return msg.Err
+	case publisher.PublishResultMsg:
+		return msg.Err
+	}
+	return nil
+}
+
+// setAuthFailure shows the re-auth banner for a failed operation
+func (m *Model) setAuthFailure(f authFailure) {
+	hadBanner := m.authFailure != nil
+	m.authFailure = &f
+	if m.ready && !hadBanner {
+		m.updateComponentSizes()
+	}
+}
+
+// clearAuthFailure hides the re-auth banner
+func (m *Model) clearAuthFailure() {
+	if m.authFailure == nil {
+		return
+	}
+	m.authFailure = nil
+	if m.ready {
+		m.updateComponentSizes()
+	}
+}
+
+// bannerHeight returns the height taken by the re-auth banner, if shown
+func (m Model) bannerHeight() int {
+	if m.authFailure == nil {
+		return 0
+	}
+	return authBannerHeight
+}
+
+// retryAuthFailure hides the banner and re-runs the failed operation
+func (m *Model) retryAuthFailure() tea.Cmd {
+	f := m.authFailure
+	m.clearAuthFailure()
+	if f == nil || f.retry == nil {
+		return nil
+	}
+	return tea.Batch(
+		func() tea.Msg {
+			return common.Network("Retrying: " + f.action)
+		},
+		f.retry,
+	)
+}
+
+// authWarning logs a hint to re-authenticate after an auth failure
+func authWarning(action string) tea.Cmd {
+	return func() tea.Msg {
+		return common.Warning(fmt.Sprintf("Credentials rejected while %s; run %s, then press ctrl+r to retry", action, reauthCommand))
+	}
+}
+
+// renderAuthBanner renders the re-auth banner shown above the footer
+func (m Model) renderAuthBanner() string {
+	f := m.authFailure
+	text := fmt.Sprintf("⚠ Auth failed (%s): run %s", f.action, reauthCommand)
+	if f.retry != nil {
+		text += ", then ctrl+r to retry"
+	}
+	text += " · ctrl+x dismiss"
+	return common.AuthBannerStyle.Width(m.width).Render(common.TruncateString(text, m.width-2))
+}
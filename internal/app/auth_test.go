@@ -0,0 +1,86 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryOnAuth(t *testing.T) {
+	authErr := status.Error(codes.Unauthenticated, "token expired")
+
+	calls := 0
+	cmd := retryOnAuth("loading topics", func() tea.Msg {
+		calls++
+		return common.TopicsLoadedMsg{Err: authErr}
+	})
+
+	msg, ok := cmd().(AuthErrorMsg)
+	if !ok {
+		t.Fatalf("cmd() returned %T, want AuthErrorMsg", msg)
+	}
+	if msg.Action != "loading topics" || msg.Err != authErr {
+		t.Errorf("AuthErrorMsg = %+v, want action loading topics with the auth error", msg)
+	}
+	if _, ok := msg.Result.(common.TopicsLoadedMsg); !ok {
+		t.Errorf("Result = %T, want TopicsLoadedMsg", msg.Result)
+	}
+
+	// The retry runs the same operation again
+	if _, ok := msg.Retry().(AuthErrorMsg); !ok || calls != 2 {
+		t.Errorf("retry ran the operation %d times in total, want 2", calls)
+	}
+
+	// Other errors pass through unchanged
+	plain := retryOnAuth("loading topics", func() tea.Msg {
+		return common.TopicsLoadedMsg{Err: errors.New("boom")}
+	})
+	if _, ok := plain().(common.TopicsLoadedMsg); !ok {
+		t.Error("non-auth error should return the result message unchanged")
+	}
+}
+
+func TestModel_AuthBanner(t *testing.T) {
+	m := newTestModel()
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	retried := false
+	m = update(t, m, AuthErrorMsg{
+		Action: "loading topics",
+		Err:    status.Error(codes.PermissionDenied, "denied"),
+		Retry: func() tea.Msg {
+			retried = true
+			return nil
+		},
+		Result: common.TopicsLoadedMsg{Err: status.Error(codes.PermissionDenied, "denied")},
+	})
+	if m.authFailure == nil {
+		t.Fatal("auth error should show the banner")
+	}
+	if m.bannerHeight() != authBannerHeight {
+		t.Errorf("bannerHeight() = %d, want %d", m.bannerHeight(), authBannerHeight)
+	}
+
+	// ctrl+r hides the banner and re-runs the failed operation
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = next.(Model)
+	if m.authFailure != nil {
+		t.Error("retry should hide the banner")
+	}
+	cmdMsgs(cmd)
+	if !retried {
+		t.Error("retry should re-run the failed operation")
+	}
+
+	// ctrl+x dismisses without retrying
+	m.setAuthFailure(authFailure{action: "publishing to orders"})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlX})
+	if m.authFailure != nil {
+		t.Error("dismiss should hide the banner")
+	}
+}
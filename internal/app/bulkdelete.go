@@ -0,0 +1,173 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bulkDeleteItem is a single deletion within a bulk delete
+type bulkDeleteItem struct {
+	name    string
+	isTopic bool // Otherwise a subscription
+}
+
+// bulkDelete tracks a bulk deletion started from the topics or the
+// subscriptions panel. Items are deleted one at a time. The first failure
+// stops the rest, and so does cancelling, once the deletion in flight
+// completes.
+type bulkDelete struct {
+	panel         FocusPanel // Panel that started it, shown the progress
+	queue         []bulkDeleteItem
+	current       bulkDeleteItem
+	total         int
+	topicsDeleted int
+	subsDeleted   int
+	failed        string // Item that failed, empty if none
+	cancelled     bool
+}
+
+// newBulkTopicDelete queues the given topics for deletion. When withSubs is
+// set, each topic's attached subscriptions are deleted before the topic.
+func (m *Model) newBulkTopicDelete(topicNames []string, withSubs bool) *bulkDelete {
+	b := &bulkDelete{panel: FocusTopics}
+	for _, topic := range topicNames {
+		if withSubs {
+			for _, sub := range m.subscriptions.NamesForTopic(topic) {
+				b.queue = append(b.queue, bulkDeleteItem{name: sub})
+			}
+		}
+		b.queue = append(b.queue, bulkDeleteItem{name: topic, isTopic: true})
+	}
+	b.total = len(b.queue)
+	return b
+}
+
+// newBulkSubscriptionDelete queues the given subscriptions for deletion
+func newBulkSubscriptionDelete(subNames []string) *bulkDelete {
+	b := &bulkDelete{panel: FocusSubscriptions}
+	for _, sub := range subNames {
+		b.queue = append(b.queue, bulkDeleteItem{name: sub})
+	}
+	b.total = len(b.queue)
+	return b
+}
+
+// bulkInProgress warns that a bulk delete cannot start while another runs
+func bulkInProgress() tea.Msg {
+	return common.Warning("Bulk delete already in progress")
+}
+
+// recordBulkResult records the outcome of the current bulk deletion if the
+// result belongs to it. Returns false for unrelated results.
+func (b *bulkDelete) recordBulkResult(name string, isTopic bool, err error) bool {
+	if b.current.name != name || b.current.isTopic != isTopic {
+		return false
+	}
+	switch {
+	case err != nil:
+		b.failed = name
+	case isTopic:
+		b.topicsDeleted++
+	default:
+		b.subsDeleted++
+	}
+	return true
+}
+
+// deleted returns the number of items deleted so far
+func (b *bulkDelete) deleted() int {
+	return b.topicsDeleted + b.subsDeleted
+}
+
+// summary describes what was deleted, e.g. "deleted 2 topics and 3
+// subscriptions"
+func (b *bulkDelete) summary() string {
+	if b.panel == FocusTopics {
+		return fmt.Sprintf("deleted %d topics and %d subscriptions", b.topicsDeleted, b.subsDeleted)
+	}
+	return fmt.Sprintf("deleted %d subscriptions", b.subsDeleted)
+}
+
+// setBulkStatus shows a status in the panel that started the bulk delete
+func (m *Model) setBulkStatus(status string, isError bool) {
+	if m.bulk.panel == FocusTopics {
+		m.topics.SetStatus(status, isError)
+	} else {
+		m.subscriptions.SetStatus(status, isError)
+	}
+}
+
+// setBulkProgress shows progress in the panel that started the bulk delete,
+// kept until the next step replaces it
+func (m *Model) setBulkProgress(status string) {
+	if m.bulk.panel == FocusTopics {
+		m.topics.SetProgress(status)
+	} else {
+		m.subscriptions.SetProgress(status)
+	}
+}
+
+// nextBulkDelete starts the next queued deletion, or finishes the bulk
+// delete and reports the totals when the queue is empty, an item failed or
+// it was cancelled
+func (m *Model) nextBulkDelete() tea.Cmd {
+	b := m.bulk
+	if b == nil {
+		return nil
+	}
+
+	done := b.deleted()
+	switch {
+	case b.failed != "":
+		m.setBulkStatus(fmt.Sprintf("Bulk delete stopped: %d/%d deleted", done, b.total), true)
+		m.bulk = nil
+		return func() tea.Msg {
+			return common.Error(fmt.Sprintf("Bulk delete stopped at %s: %s of %d", b.failed, b.summary(), b.total))
+		}
+	case b.cancelled:
+		m.setBulkStatus(fmt.Sprintf("Bulk delete cancelled: %d/%d deleted", done, b.total), false)
+		m.bulk = nil
+		return func() tea.Msg {
+			return common.Warning(fmt.Sprintf("Bulk delete cancelled: %s of %d", b.summary(), b.total))
+		}
+	case len(b.queue) == 0:
+		m.setBulkStatus(fmt.Sprintf("Deleted %d of %d", done, b.total), false)
+		m.bulk = nil
+		return func() tea.Msg {
+			return common.Success("Bulk delete complete: " + b.summary())
+		}
+	}
+
+	b.current = b.queue[0]
+	b.queue = b.queue[1:]
+	m.setBulkProgress(fmt.Sprintf("Deleting %d/%d... (X: cancel)", done+1, b.total))
+
+	var cmd tea.Cmd
+	if b.current.isTopic {
+		cmd = m.deleteTopic(b.current.name)
+	} else {
+		cmd = m.deleteSubscription(b.current.name)
+	}
+	if done == 0 {
+		return cmd
+	}
+	return tea.Batch(cmd, func() tea.Msg {
+		return common.Info(fmt.Sprintf("Bulk delete progress: %d/%d", done, b.total))
+	})
+}
+
+// cancelBulkDelete stops a bulk delete after the deletion in flight, which
+// cannot be called back
+func (m *Model) cancelBulkDelete() tea.Cmd {
+	if m.bulk == nil || m.bulk.cancelled {
+		return nil
+	}
+	m.bulk.cancelled = true
+	m.setBulkProgress("Cancelling bulk delete...")
+	return func() tea.Msg {
+		return common.Warning("Cancelling bulk delete after the current deletion")
+	}
+}
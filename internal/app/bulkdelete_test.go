@@ -0,0 +1,259 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriptions"
+	"github.com/anmaso/pubsub-tui/internal/components/topics"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	"cloud.google.com/go/pubsub/pstest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newFakeClient returns a client connected to an in-memory Pub/Sub server
+func newFakeClient(t *testing.T) *pubsub.Client {
+	t.Helper()
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+	t.Setenv(pubsub.EmulatorHostEnvVar, srv.Addr)
+
+	client, err := pubsub.NewClient("test-project", "")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// runBulk sends msg and feeds the results of the deletions it starts back
+// into the model until the bulk delete settles, returning the log messages.
+// pause, if set, is called after msg with the model before any deletion
+// result is handled.
+func runBulk(t *testing.T, m Model, msg tea.Msg, pause func(Model) (Model, tea.Cmd)) (Model, []common.LogMsg) {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	m = next.(Model)
+	pending := []tea.Cmd{cmd}
+	if pause != nil {
+		var pauseCmd tea.Cmd
+		m, pauseCmd = pause(m)
+		pending = append(pending, pauseCmd)
+	}
+
+	var logs []common.LogMsg
+	for len(pending) > 0 {
+		cmd, pending = pending[0], pending[1:]
+		for _, out := range cmdMsgs(cmd) {
+			switch out := out.(type) {
+			case common.LogMsg:
+				logs = append(logs, out)
+			case common.TopicDeletedMsg, common.SubscriptionDeletedMsg:
+				next, cmd := m.Update(out)
+				m = next.(Model)
+				pending = append(pending, cmd)
+			}
+		}
+	}
+	return m, logs
+}
+
+// hasLog reports whether a log message at level contains text
+func hasLog(logs []common.LogMsg, level common.LogLevel, text string) bool {
+	for _, log := range logs {
+		if log.Level == level && strings.Contains(log.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestModel_BulkDeleteTopics(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, names ...string) Model {
+		m := newTestModel()
+		m.client = newFakeClient(t)
+		for _, name := range names {
+			if err := m.client.CreateTopic(ctx, name); err != nil {
+				t.Fatalf("CreateTopic(%s) error = %v", name, err)
+			}
+		}
+		return m
+	}
+	remaining := func(t *testing.T, m Model) string {
+		t.Helper()
+		list, err := m.client.ListTopics(ctx)
+		if err != nil {
+			t.Fatalf("ListTopics() error = %v", err)
+		}
+		var names []string
+		for _, topic := range list {
+			names = append(names, topic.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	bulk := topics.BulkDeleteTopicsMsg{TopicNames: []string{"billing", "events", "orders"}}
+
+	t.Run("success", func(t *testing.T) {
+		m := setup(t, "billing", "events", "orders")
+		m, logs := runBulk(t, m, bulk, nil)
+		if m.bulk != nil || remaining(t, m) != "" {
+			t.Errorf("bulk = %+v, remaining %q; want every topic deleted", m.bulk, remaining(t, m))
+		}
+		if !hasLog(logs, common.LogSuccess, "Bulk delete complete: deleted 3 topics") {
+			t.Errorf("logs %+v should report the completed bulk delete", logs)
+		}
+		if m.lastDeleted != nil {
+			t.Error("bulk deletes should not be undoable")
+		}
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		m := setup(t, "billing", "orders")
+		m, logs := runBulk(t, m, bulk, nil)
+		if m.bulk != nil || remaining(t, m) != "orders" {
+			t.Errorf("remaining %q, want orders left after events failed", remaining(t, m))
+		}
+		if !hasLog(logs, common.LogError, "Bulk delete stopped at events: deleted 1 topics and 0 subscriptions of 3") {
+			t.Errorf("logs %+v should report where the bulk delete stopped", logs)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		m := setup(t, "billing", "events", "orders")
+		m, logs := runBulk(t, m, bulk, func(m Model) (Model, tea.Cmd) {
+			next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+			return next.(Model), cmd
+		})
+		if m.bulk != nil || remaining(t, m) != "events,orders" {
+			t.Errorf("remaining %q, want only the deletion in flight done", remaining(t, m))
+		}
+		if !hasLog(logs, common.LogWarning, "Bulk delete cancelled: deleted 1 topics and 0 subscriptions of 3") {
+			t.Errorf("logs %+v should report the cancelled bulk delete", logs)
+		}
+	})
+
+	t.Run("progress stays until the next step", func(t *testing.T) {
+		m := setup(t, "billing", "events", "orders")
+		m.topics.SetSize(80, 20)
+		next, _ := m.Update(bulk)
+		m = next.(Model)
+		m.topics.ExpireStatus(time.Now().Add(time.Hour))
+		if view := m.topics.View(); !strings.Contains(view, "Deleting 1/3") {
+			t.Errorf("topics view %q should keep the bulk delete progress", view)
+		}
+	})
+
+	t.Run("one at a time", func(t *testing.T) {
+		m := setup(t)
+		m.bulk = &bulkDelete{panel: FocusTopics}
+		m, logs := runBulk(t, m, bulk, nil)
+		if !hasLog(logs, common.LogWarning, "Bulk delete already in progress") {
+			t.Errorf("logs %+v should refuse a second bulk delete", logs)
+		}
+	})
+}
+
+func TestModel_BulkDeleteSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, names ...string) Model {
+		m := newTestModel()
+		m.client = newFakeClient(t)
+		if err := m.client.CreateTopic(ctx, "orders"); err != nil {
+			t.Fatalf("CreateTopic() error = %v", err)
+		}
+		for _, name := range names {
+			if err := m.client.CreateSubscription(ctx, name, "orders"); err != nil {
+				t.Fatalf("CreateSubscription(%s) error = %v", name, err)
+			}
+		}
+		return m
+	}
+	remaining := func(t *testing.T, m Model) string {
+		t.Helper()
+		list, err := m.client.ListSubscriptions(ctx)
+		if err != nil {
+			t.Fatalf("ListSubscriptions() error = %v", err)
+		}
+		var names []string
+		for _, sub := range list {
+			names = append(names, sub.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	bulk := subscriptions.BulkDeleteSubscriptionsMsg{SubscriptionNames: []string{"audit-sub", "events-sub", "orders-sub"}}
+
+	t.Run("success", func(t *testing.T) {
+		m := setup(t, "audit-sub", "events-sub", "orders-sub")
+		m, logs := runBulk(t, m, bulk, nil)
+		if m.bulk != nil || remaining(t, m) != "" {
+			t.Errorf("bulk = %+v, remaining %q; want every subscription deleted", m.bulk, remaining(t, m))
+		}
+		if !hasLog(logs, common.LogSuccess, "Bulk delete complete: deleted 3 subscriptions") {
+			t.Errorf("logs %+v should report the completed bulk delete", logs)
+		}
+		if m.lastDeleted != nil {
+			t.Error("bulk deletes should not be undoable")
+		}
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		m := setup(t, "audit-sub", "orders-sub")
+		m, logs := runBulk(t, m, bulk, nil)
+		if m.bulk != nil || remaining(t, m) != "orders-sub" {
+			t.Errorf("remaining %q, want orders-sub left after events-sub failed", remaining(t, m))
+		}
+		if !hasLog(logs, common.LogError, "Bulk delete stopped at events-sub: deleted 1 subscriptions of 3") {
+			t.Errorf("logs %+v should report where the bulk delete stopped", logs)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		m := setup(t, "audit-sub", "events-sub", "orders-sub")
+		m, logs := runBulk(t, m, bulk, func(m Model) (Model, tea.Cmd) {
+			next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+			return next.(Model), cmd
+		})
+		if m.bulk != nil || remaining(t, m) != "events-sub,orders-sub" {
+			t.Errorf("remaining %q, want only the deletion in flight done", remaining(t, m))
+		}
+		if !hasLog(logs, common.LogWarning, "Bulk delete cancelled: deleted 1 subscriptions of 3") {
+			t.Errorf("logs %+v should report the cancelled bulk delete", logs)
+		}
+	})
+
+	t.Run("waits for a topic bulk delete", func(t *testing.T) {
+		m := setup(t, "audit-sub")
+		m.bulk = m.newBulkTopicDelete([]string{"orders"}, false)
+		m, logs := runBulk(t, m, bulk, nil)
+		if !hasLog(logs, common.LogWarning, "Bulk delete already in progress") || m.bulk.panel != FocusTopics {
+			t.Errorf("logs %+v should refuse to start while topics are being deleted", logs)
+		}
+		if remaining(t, m) != "audit-sub" {
+			t.Errorf("remaining %q, want nothing deleted", remaining(t, m))
+		}
+	})
+
+	// D in the panel hands the displayed subscriptions to the shared runner
+	t.Run("from the panel", func(t *testing.T) {
+		m := setup(t)
+		m.focus = FocusSubscriptions
+		m.subscriptions.SetEmulatorMode(true)
+		m.subscriptions.SetSubscriptions([]common.SubscriptionData{{Name: "audit-sub", TopicName: "orders"}, {Name: "orders-sub", TopicName: "orders"}})
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		var got []string
+		for _, msg := range cmdMsgs(cmd) {
+			if msg, ok := msg.(subscriptions.BulkDeleteSubscriptionsMsg); ok {
+				got = msg.SubscriptionNames
+			}
+		}
+		if strings.Join(got, ",") != "audit-sub,orders-sub" {
+			t.Errorf("D requested %q, want the displayed subscriptions", got)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FooterHideEnvVar lists footer parts to hide, comma separated: "project"
+// for the GCP project or emulator, "subscription" for the active
+// subscription
+const FooterHideEnvVar = "PUBSUB_TUI_FOOTER_HIDE"
+
+// FooterOptions selects optional parts of the footer
+type FooterOptions struct {
+	HideProject      bool
+	HideSubscription bool
+}
+
+// ParseFooterOptions parses a comma-separated list of footer parts to hide
+func ParseFooterOptions(spec string) (FooterOptions, error) {
+	var opts FooterOptions
+	for _, part := range strings.Split(spec, ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "":
+		case "project":
+			opts.HideProject = true
+		case "subscription":
+			opts.HideSubscription = true
+		default:
+			return FooterOptions{}, fmt.Errorf("%s: unknown footer part %q (want project or subscription)", FooterHideEnvVar, strings.TrimSpace(part))
+		}
+	}
+	return opts, nil
+}
+
+// FooterOptionsFromEnv returns the footer parts hidden by
+// PUBSUB_TUI_FOOTER_HIDE
+func FooterOptionsFromEnv() (FooterOptions, error) {
+	return ParseFooterOptions(os.Getenv(FooterHideEnvVar))
+}
+
+// Footer segment priorities. When the footer is too wide, segments are
+// dropped lowest priority first, and the rightmost first among equals.
+const (
+	footerPriorityLow      = iota // Global keys also listed in help
+	footerPriorityGlobal          // Panel jumps
+	footerPriorityPanel           // Keys of the focused panel
+	footerPriorityStatus          // Active subscription
+	footerPriorityProject         // GCP project or emulator
+	footerPriorityRequired        // Never dropped
+)
+
+// footerEllipsis marks a footer with dropped segments; help lists them
+const footerEllipsis = "…"
+
+// ansiStyle matches the styling lipgloss adds, to list segments as text
+var ansiStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// footerSegment is one part of the footer
+type footerSegment struct {
+	text      string
+	priority  int
+	sep       string // Shown before the segment unless it comes first on its side
+	separator bool   // Only separates other segments, so not shown last
+}
+
+// footerKey returns a footer segment for a key and what it does
+func footerKey(k, desc string, priority int) footerSegment {
+	return footerSegment{
+		text:     common.FooterKeyStyle.Render(k) + common.FooterDescStyle.Render(desc),
+		priority: priority,
+		sep:      " ",
+	}
+}
+
+// fitFooter lays out the left and right segments across width, dropping
+// low-priority segments until they fit. A footer that lost segments ends its
+// left side with an ellipsis. It also returns the text of the dropped
+// segments, in footer order and without separators.
+func fitFooter(left, right []footerSegment, width int) (string, []string) {
+	all := append(append([]footerSegment(nil), left...), right...)
+	left = append([]footerSegment(nil), left...)
+	right = append([]footerSegment(nil), right...)
+	elided := false
+	for {
+		line := joinFooter(left, right, elided, width)
+		if lipgloss.Width(line) <= width {
+			return line, droppedSegments(all, append(left, right...))
+		}
+		var ok bool
+		if left, right, ok = dropFooterSegment(left, right); !ok {
+			return line, droppedSegments(all, append(left, right...))
+		}
+		elided = true
+	}
+}
+
+// droppedSegments returns the unstyled text of the segments in all that are
+// not among kept, leaving out separators
+func droppedSegments(all, kept []footerSegment) []string {
+	remaining := make(map[footerSegment]int)
+	for _, seg := range kept {
+		remaining[seg]++
+	}
+	var dropped []string
+	for _, seg := range all {
+		if remaining[seg] > 0 {
+			remaining[seg]--
+			continue
+		}
+		if !seg.separator {
+			dropped = append(dropped, ansiStyle.ReplaceAllString(seg.text, ""))
+		}
+	}
+	return dropped
+}
+
+// dropFooterSegment removes the lowest-priority segment that may be dropped,
+// reporting false when none can
+func dropFooterSegment(left, right []footerSegment) ([]footerSegment, []footerSegment, bool) {
+	side, index := -1, -1
+	lowest := footerPriorityRequired
+	for s, segments := range [][]footerSegment{left, right} {
+		for i, seg := range segments {
+			if seg.priority <= lowest && seg.priority < footerPriorityRequired {
+				side, index, lowest = s, i, seg.priority
+			}
+		}
+	}
+	switch side {
+	case 0:
+		return append(left[:index], left[index+1:]...), right, true
+	case 1:
+		return left, append(right[:index], right[index+1:]...), true
+	}
+	return left, right, false
+}
+
+// joinFooter renders the segments with the left side flush left and the
+// right side flush right, at least a space apart
+func joinFooter(left, right []footerSegment, elided bool, width int) string {
+	leftText := joinSegments(left)
+	if elided {
+		leftText += " " + common.FooterDescStyle.Render(footerEllipsis)
+	}
+	rightText := joinSegments(right)
+	gap := width - lipgloss.Width(leftText) - lipgloss.Width(rightText)
+	if gap < 1 {
+		gap = 1
+	}
+	return leftText + strings.Repeat(" ", gap) + rightText
+}
+
+// joinSegments renders segments with their separators, leaving out a
+// separator with nothing after it
+func joinSegments(segments []footerSegment) string {
+	var b strings.Builder
+	for i, seg := range segments {
+		if seg.separator && i == len(segments)-1 {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteString(seg.sep)
+		}
+		b.WriteString(seg.text)
+	}
+	return b.String()
+}
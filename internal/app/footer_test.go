@@ -0,0 +1,160 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseFooterOptions(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    FooterOptions
+		wantErr bool
+	}{
+		{spec: "", want: FooterOptions{}},
+		{spec: "project", want: FooterOptions{HideProject: true}},
+		{spec: " Subscription , project ", want: FooterOptions{HideProject: true, HideSubscription: true}},
+		{spec: "project,", want: FooterOptions{HideProject: true}},
+		{spec: "clock", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFooterOptions(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFooterOptions(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFooterOptions(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestModel_RenderFooter_Widths(t *testing.T) {
+	m := newTestModel()
+	m.projectID = "my-project"
+	m.selectedSubscription = "orders-sub"
+	m.focus = FocusTopics
+
+	tests := []struct {
+		width   int
+		want    []string
+		notWant []string
+	}{
+		{
+			width:   260,
+			want:    []string{"go to panel", "Tab:cycle", "↑↓:nav", "space:mark", "orders-sub", "acked: 0", "GCP: my-project"},
+			notWant: []string{footerEllipsis},
+		},
+		{
+			width:   140,
+			want:    []string{"?:help", "q:quit", "↑↓:nav", "orders-sub", "GCP: my-project", footerEllipsis},
+			notWant: []string{"1-4:panel", "go to panel", "Tab:cycle", ":actions"},
+		},
+		{
+			width:   72,
+			want:    []string{"?:help", "q:quit", "↑↓:nav", "orders-sub", "acked: 0", "GCP: my-project", footerEllipsis},
+			notWant: []string{"Enter:select", "space:mark"},
+		},
+		{
+			width:   40,
+			want:    []string{"?:help", "q:quit", "GCP: my-project", footerEllipsis},
+			notWant: []string{"↑↓:nav", "│", "orders-sub", "acked"},
+		},
+		{
+			width:   30,
+			want:    []string{"?:help", "q:quit", "○", footerEllipsis},
+			notWant: []string{"my-project"},
+		},
+	}
+	for _, tt := range tests {
+		m.width = tt.width
+		footer := m.renderFooter()
+		if w := lipgloss.Width(footer); w > tt.width {
+			t.Errorf("width %d: footer is %d wide: %q", tt.width, w, footer)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(footer, want) {
+				t.Errorf("width %d: footer %q is missing %q", tt.width, footer, want)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(footer, notWant) {
+				t.Errorf("width %d: footer %q should not contain %q", tt.width, footer, notWant)
+			}
+		}
+	}
+}
+
+func TestModel_RenderFooter_Hidden(t *testing.T) {
+	m := newTestModel()
+	m.projectID = "my-project"
+	m.selectedSubscription = "orders-sub"
+	m.width = 260
+	m.options.Footer = FooterOptions{HideProject: true, HideSubscription: true}
+
+	footer := m.renderFooter()
+	if strings.Contains(footer, "my-project") || strings.Contains(footer, "orders-sub") {
+		t.Errorf("footer %q should hide the project and subscription", footer)
+	}
+	if !strings.Contains(footer, "○") {
+		t.Errorf("footer %q should still show the connection health", footer)
+	}
+}
+
+func TestModel_HelpListsHiddenFooter(t *testing.T) {
+	m := newTestModel()
+	m.projectID = "my-project"
+	m.selectedSubscription = "orders-sub"
+	m.focus = FocusTopics
+
+	m.width = 260
+	if hidden := m.hiddenFooter(); len(hidden) != 0 {
+		t.Errorf("wide footer hides %q, want nothing", hidden)
+	}
+
+	m.width = 40
+	hidden := strings.Join(m.hiddenFooter(), "\n")
+	for _, want := range []string{"↑↓:nav", "orders-sub", "acked: 0"} {
+		if !strings.Contains(hidden, want) {
+			t.Errorf("hidden footer %q is missing %q", hidden, want)
+		}
+	}
+	if strings.Contains(hidden, "│") || strings.Contains(hidden, "\x1b[") {
+		t.Errorf("hidden footer %q should be plain text without separators", hidden)
+	}
+
+	help := strings.Join(m.helpLines(), "\n")
+	for _, want := range []string{"Left out of the footer now", "orders-sub"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help is missing %q", want)
+		}
+	}
+}
+
+func TestModel_EnvironmentIndicator(t *testing.T) {
+	m := newTestModel()
+	m.projectID = "my-project"
+	m.width = 140
+	m.height = 40
+	m.openHelp()
+
+	if footer := m.renderFooter(); !strings.Contains(footer, "GCP: my-project") || strings.Contains(footer, "EMULATOR") {
+		t.Errorf("footer %q should name the GCP project", footer)
+	}
+	if help := m.renderHelpOverlay(""); !strings.Contains(help, "GCP: my-project") {
+		t.Errorf("help should name the GCP project")
+	}
+
+	m.options.EmulatorHost = "localhost:8085"
+	if !m.IsEmulator() {
+		t.Fatal("IsEmulator() = false with an emulator host")
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "EMULATOR @ localhost:8085") || strings.Contains(footer, "GCP:") {
+		t.Errorf("footer %q should name the emulator instead of GCP", footer)
+	}
+	if help := m.renderHelpOverlay(""); !strings.Contains(help, "EMULATOR @ localhost:8085") {
+		t.Errorf("help should name the emulator")
+	}
+}
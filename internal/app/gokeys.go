@@ -0,0 +1,60 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// goPrefix starts a jump to a panel by a letter of its name, e.g. g then t.
+// Panels also use g (gg jumps to the top), so a g not followed by a panel
+// letter is passed on to the focused panel.
+const goPrefix = "g"
+
+// goTargets are the panels g jumps to, by the key typed after it. They are
+// kept out of the global key map since the letters are also panel keys.
+var goTargets = []struct {
+	key   string
+	focus FocusPanel
+	name  string
+}{
+	{"t", FocusTopics, "Topics"},
+	{"s", FocusSubscriptions, "Subscriptions"},
+	{"p", FocusPublisher, "Publisher"},
+	{"m", FocusSubscriber, "Subscriber (messages)"},
+}
+
+// goTarget returns the panel a key jumps to after g
+func goTarget(k string) (FocusPanel, bool) {
+	for _, t := range goTargets {
+		if t.key == k {
+			return t.focus, true
+		}
+	}
+	return "", false
+}
+
+// goBindings describes the g sequences for the help overlay. They are never
+// matched against keys.
+func goBindings() []key.Binding {
+	bindings := make([]key.Binding, 0, len(goTargets))
+	for _, t := range goTargets {
+		bindings = append(bindings, key.NewBinding(
+			key.WithKeys(goPrefix+" "+t.key),
+			key.WithHelp(goPrefix+" "+t.key, "Go to the "+t.name+" panel"),
+		))
+	}
+	return bindings
+}
+
+// handleGoKey handles the key after g: a panel letter moves the focus,
+// anything else hands the held g to the focused panel before the key is
+// handled as usual. It returns whether the key was used.
+func (m *Model) handleGoKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	m.pendingGo = false
+	if focus, ok := goTarget(msg.String()); ok {
+		m.focus = focus
+		m.updateFocus()
+		return true, nil
+	}
+	return false, m.routeKeyToFocused(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(goPrefix)})
+}
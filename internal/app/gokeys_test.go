@@ -0,0 +1,65 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_GoToPanel(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusTopics
+	m.topics.SetFocused(true)
+	m = update(t, m, common.TopicsLoadedMsg{Topics: []common.TopicData{{Name: "orders"}, {Name: "billing"}, {Name: "audit"}}})
+	press := func(keys string) {
+		t.Helper()
+		for _, r := range keys {
+			m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	selected := func() string {
+		if topic := m.topics.SelectedTopic(); topic != nil {
+			return topic.Name
+		}
+		return ""
+	}
+
+	// g then a panel letter moves the focus
+	for _, tt := range []struct {
+		keys string
+		want FocusPanel
+	}{
+		{"gs", FocusSubscriptions},
+		{"gp", FocusPublisher},
+		{"gm", FocusSubscriber},
+		{"gt", FocusTopics},
+	} {
+		press(tt.keys)
+		if m.focus != tt.want || m.pendingGo {
+			t.Errorf("%q: focus = %s, pendingGo = %v; want %s", tt.keys, m.focus, m.pendingGo, tt.want)
+		}
+	}
+
+	// The panel still sees gg, and keys after a lone g
+	press("jj")
+	press("gg")
+	if got := selected(); got != "orders" {
+		t.Errorf("gg selected %q, want the first topic", got)
+	}
+	press("gj")
+	if got := selected(); got != "billing" || m.focus != FocusTopics {
+		t.Errorf("g then j selected %q in %s, want the next topic", got, m.focus)
+	}
+
+	// A g typed into an input is text
+	press("/g")
+	if m.pendingGo || !m.topics.IsInputActive() {
+		t.Error("g in a filter should be typed, not start a jump")
+	}
+	press("s")
+	if m.focus != FocusTopics {
+		t.Errorf("focus = %s after typing gs into a filter, want topics", m.focus)
+	}
+}
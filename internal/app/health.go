@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Connection health check settings
+const (
+	healthInterval      = 30 * time.Second // Time between checks
+	healthTimeout       = 5 * time.Second  // Longest a check may take
+	healthSlowThreshold = 2 * time.Second  // Slower answers count as degraded
+)
+
+// connectionHealth is the result of the latest connection health checks
+type connectionHealth int
+
+const (
+	healthUnknown connectionHealth = iota // No check has completed yet
+	healthConnected
+	healthDegraded     // Slow answers, or a single failed check
+	healthDisconnected // Repeated failed checks
+)
+
+// String names the health state for the footer and activity log
+func (h connectionHealth) String() string {
+	switch h {
+	case healthConnected:
+		return "connected"
+	case healthDegraded:
+		return "degraded"
+	case healthDisconnected:
+		return "disconnected"
+	}
+	return "checking"
+}
+
+// HealthTickMsg requests the next connection health check
+type HealthTickMsg struct {
+	Client *pubsub.Client // Client to check; stale after a reconnect
+}
+
+// HealthCheckedMsg is sent when a connection health check completes
+type HealthCheckedMsg struct {
+	Client  *pubsub.Client // Client that was checked
+	Latency time.Duration
+	Err     error
+}
+
+// healthTick returns a command that requests a health check of client after
+// the interval
+func healthTick(client *pubsub.Client) tea.Cmd {
+	return tea.Tick(healthInterval, func(time.Time) tea.Msg {
+		return HealthTickMsg{Client: client}
+	})
+}
+
+// checkHealth returns a command that pings the API with a short timeout.
+// It runs in the background, so a slow network never blocks the UI.
+func (m Model) checkHealth() tea.Cmd {
+	client := m.client
+	if client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+		defer cancel()
+		start := time.Now()
+		err := client.Ping(ctx)
+		return HealthCheckedMsg{Client: client, Latency: time.Since(start), Err: err}
+	}
+}
+
+// classifyHealth returns the health after a check, given how many checks in
+// a row have now failed to reach the server. Errors such as permission
+// denied still mean the server answered.
+func classifyHealth(msg HealthCheckedMsg, failures int) connectionHealth {
+	switch {
+	case failures >= 2:
+		return healthDisconnected
+	case failures == 1, msg.Latency > healthSlowThreshold:
+		return healthDegraded
+	}
+	return healthConnected
+}
+
+// handleHealthChecked records a check result, logs changes in health and
+// schedules the next check. Results for a replaced client are ignored, which
+// ends its check loop; reconnecting starts a new one.
+func (m *Model) handleHealthChecked(msg HealthCheckedMsg) tea.Cmd {
+	if msg.Client != m.client {
+		return nil
+	}
+
+	if pubsub.IsTransientError(msg.Err) {
+		m.healthFailures++
+	} else {
+		m.healthFailures = 0
+	}
+	prev := m.health
+	m.health = classifyHealth(msg, m.healthFailures)
+
+	cmds := []tea.Cmd{healthTick(m.client)}
+	if m.health != prev && prev != healthUnknown {
+		health := m.health
+		cmds = append(cmds, func() tea.Msg {
+			switch health {
+			case healthConnected:
+				return common.Success("Connection " + health.String())
+			case healthDegraded:
+				if msg.Err != nil {
+					return common.Warning("Connection degraded: " + msg.Err.Error())
+				}
+				return common.Warning("Connection degraded: health check took " + msg.Latency.Round(time.Millisecond).String())
+			}
+			return common.ErrorLog("Connection lost", msg.Err)
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// renderHealth renders the connection health indicator for the footer
+func (m Model) renderHealth() string {
+	switch m.health {
+	case healthConnected:
+		return common.LogSuccessStyle.Render("●")
+	case healthDegraded:
+		return common.LogWarningStyle.Render("● degraded")
+	case healthDisconnected:
+		return common.LogErrorStyle.Render("● disconnected")
+	}
+	return common.MutedText.Render("○")
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestModel_HandleHealthChecked(t *testing.T) {
+	client := new(pubsub.Client)
+	m := newTestModel()
+	m.client = client
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	steps := []struct {
+		name string
+		msg  HealthCheckedMsg
+		want connectionHealth
+	}{
+		{"fast answer", HealthCheckedMsg{Client: client, Latency: 50 * time.Millisecond}, healthConnected},
+		{"slow answer", HealthCheckedMsg{Client: client, Latency: 3 * time.Second}, healthDegraded},
+		{"first failure", HealthCheckedMsg{Client: client, Err: unavailable}, healthDegraded},
+		{"second failure", HealthCheckedMsg{Client: client, Err: unavailable}, healthDisconnected},
+		{"stale client", HealthCheckedMsg{Client: new(pubsub.Client)}, healthDisconnected},
+		{"permission denied", HealthCheckedMsg{Client: client, Err: status.Error(codes.PermissionDenied, "denied")}, healthConnected},
+	}
+	for _, step := range steps {
+		m = update(t, m, step.msg)
+		if m.health != step.want {
+			t.Errorf("%s: health = %v, want %v", step.name, m.health, step.want)
+		}
+	}
+	if m.healthFailures != 0 {
+		t.Errorf("healthFailures = %d, want 0 once the server answers", m.healthFailures)
+	}
+}
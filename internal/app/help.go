@@ -0,0 +1,179 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Help overlay layout
+const (
+	helpMaxWidth   = 66 // Widest the help content gets
+	helpMinWidth   = 20 // Narrowest the help content gets on tiny terminals
+	helpKeyColumn  = 12 // Width of the key column; wrapped descriptions indent to it
+	helpBoxChrome  = 4  // Border and padding around the content, horizontally
+	helpBoxHeaders = 5  // Border, title, environment and footer lines
+)
+
+// helpSection is a group of key bindings in the help overlay
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+	notes    []string // Lines shown after the bindings, in the description column
+}
+
+// helpSections returns the help overlay sections, one per panel. Bindings
+// come from each panel's key map, so new keys show up without editing this.
+func (m Model) helpSections() []helpSection {
+	notes := []string{
+		"While editing a filter, ↑/↓ recall recent patterns",
+		"A … in the footer marks shortcuts left out to fit",
+	}
+	if hidden := m.hiddenFooter(); len(hidden) > 0 {
+		notes = append(notes, "Left out of the footer now: "+strings.Join(hidden, ", "))
+	}
+
+	return []helpSection{
+		{
+			title:    "NAVIGATION",
+			bindings: append(common.KeyBindings(keys), goBindings()...),
+			notes:    notes,
+		},
+		{title: "TOPICS PANEL (1)", bindings: panelHelp(m.topics)},
+		{
+			title:    "SUBSCRIPTIONS PANEL (2)",
+			bindings: panelHelp(m.subscriptions),
+			notes:    []string{"(⇪ marks push subscriptions, ? unknown type)"},
+		},
+		{title: "PUBLISHER PANEL (3)", bindings: panelHelp(m.publisher)},
+		{title: "SUBSCRIBER PANEL (4)", bindings: panelHelp(m.subscriber)},
+	}
+}
+
+// panelHelp returns the key bindings a panel lists in the help overlay
+func panelHelp(p common.HelpProvider) []key.Binding {
+	return p.FullHelp()
+}
+
+// helpLines renders the help overlay content, one key per line
+func (m Model) helpLines() []string {
+	lines := []string{""}
+	for _, section := range m.helpSections() {
+		lines = append(lines, section.title, "")
+		for _, b := range section.bindings {
+			h := b.Help()
+			lines = append(lines, fmt.Sprintf("%-*s%s", helpKeyColumn, h.Key, h.Desc))
+		}
+		for _, note := range section.notes {
+			lines = append(lines, strings.Repeat(" ", helpKeyColumn)+note)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// openHelp shows the help overlay scrolled to the top
+func (m *Model) openHelp() {
+	m.showHelp = true
+	m.sizeHelp()
+	m.help.GotoTop()
+}
+
+// sizeHelp fits the help viewport to the terminal and re-wraps its content
+func (m *Model) sizeHelp() {
+	width := m.helpWidth()
+
+	var wrapped []string
+	for _, line := range m.helpLines() {
+		wrapped = append(wrapped, wrapHelpLine(line, width)...)
+	}
+
+	height := m.height - helpBoxHeaders
+	if height > len(wrapped) {
+		height = len(wrapped)
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	m.help.Width = width
+	m.help.Height = height
+	m.help.SetContent(strings.Join(wrapped, "\n"))
+}
+
+// helpWidth returns the width of the help content for the terminal width
+func (m Model) helpWidth() int {
+	width := m.width - helpBoxChrome
+	if width > helpMaxWidth {
+		width = helpMaxWidth
+	}
+	if width < helpMinWidth {
+		width = helpMinWidth
+	}
+	return width
+}
+
+// handleHelpKey scrolls the help overlay; esc and q close it
+func (m *Model) handleHelpKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "q":
+		m.showHelp = false
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.help, cmd = m.help.Update(msg)
+	return cmd
+}
+
+// helpFooter describes how to scroll and close the help overlay
+func (m Model) helpFooter() string {
+	if m.help.TotalLineCount() <= m.help.Height {
+		return "esc/q to close"
+	}
+	return fmt.Sprintf("↑↓ scroll · esc/q close · %d%%", int(m.help.ScrollPercent()*100))
+}
+
+// wrapHelpLine word-wraps a help line to width. Continuation lines are
+// indented to the description column so keys stay easy to scan.
+func wrapHelpLine(line string, width int) []string {
+	if lipgloss.Width(line) <= width {
+		return []string{line}
+	}
+
+	// Keys may contain arrows, so split off the key column by rune
+	runes := []rune(line)
+	indent, head, rest := "", "", line
+	if width-helpKeyColumn >= helpMinWidth/2 && len(runes) > helpKeyColumn && runes[helpKeyColumn-1] == ' ' {
+		indent = strings.Repeat(" ", helpKeyColumn)
+		head, rest = string(runes[:helpKeyColumn]), string(runes[helpKeyColumn:])
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(rest) {
+		if current == "" && len(lines) == 0 && head != "" {
+			current = head + word
+			continue
+		}
+		if current == "" {
+			current = indent + word
+			continue
+		}
+		if lipgloss.Width(current+" "+word) > width {
+			lines = append(lines, current)
+			current = indent + word
+			continue
+		}
+		current += " " + word
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
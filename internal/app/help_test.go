@@ -0,0 +1,64 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+// keyLabels maps key names to how help labels spell them
+var keyLabels = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+	" ":     "space",
+}
+
+func TestHelpLines_EveryBoundKey(t *testing.T) {
+	m := newTestModel()
+	help := strings.Join(m.helpLines(), "\n")
+
+	for _, section := range m.helpSections() {
+		if !strings.Contains(help, section.title) {
+			t.Errorf("help is missing section %q", section.title)
+		}
+		if len(section.bindings) == 0 {
+			t.Errorf("section %q has no bindings", section.title)
+		}
+		for _, b := range section.bindings {
+			h := b.Help()
+			if h.Key == "" || h.Desc == "" {
+				t.Errorf("%s: binding %v has no help text", section.title, b.Keys())
+				continue
+			}
+			if !strings.Contains(help, h.Desc) {
+				t.Errorf("%s: help is missing %q", section.title, h.Desc)
+			}
+			for _, k := range b.Keys() {
+				label := k
+				if l, ok := keyLabels[k]; ok {
+					label = l
+				}
+				if !strings.Contains(h.Key, label) {
+					t.Errorf("%s: key %q is bound but its help label is %q", section.title, k, h.Key)
+				}
+			}
+		}
+	}
+}
+
+func TestWrapHelpLine(t *testing.T) {
+	line := "↑/k         Move the cursor up one row in the list of messages"
+	got := wrapHelpLine(line, 30)
+	if len(got) < 2 {
+		t.Fatalf("wrapHelpLine() = %q, want several lines", got)
+	}
+	if !strings.HasPrefix(got[0], "↑/k         Move") {
+		t.Errorf("first line = %q, want the key column kept", got[0])
+	}
+	for _, l := range got[1:] {
+		if !strings.HasPrefix(l, strings.Repeat(" ", helpKeyColumn)) {
+			t.Errorf("continuation %q is not indented to the description column", l)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriptions"
+	"github.com/anmaso/pubsub-tui/internal/components/topics"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeysEnvVar overrides where key binding overrides are read from. "off"
+// disables them.
+const KeysEnvVar = "PUBSUB_TUI_KEYS_FILE"
+
+// keyList is one or more keys; the config file accepts a string or a list
+type keyList []string
+
+func (k *keyList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*k = keyList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return errors.New("keys must be a string or a list of strings")
+	}
+	*k = list
+	return nil
+}
+
+// keySection applies overrides to one key map, keyed by action name
+type keySection struct {
+	name  string
+	apply func(overrides map[string][]string) ([]string, error)
+}
+
+// keySections lists the key maps in the config file, by action prefix
+var keySections = []keySection{
+	{"global", setGlobalKeyOverrides},
+	{"topics", topics.SetKeyOverrides},
+	{"subscriptions", subscriptions.SetKeyOverrides},
+	{"publisher", publisher.SetKeyOverrides},
+	{"subscriber", subscriber.SetKeyOverrides},
+}
+
+// setGlobalKeyOverrides replaces the global key bindings with the defaults
+// plus overrides
+func setGlobalKeyOverrides(overrides map[string][]string) ([]string, error) {
+	km := defaultKeys()
+	unknown, err := common.OverrideKeys(&km, overrides)
+	if err != nil {
+		return nil, err
+	}
+	keys = km
+	return unknown, nil
+}
+
+// KeysPathFromEnv returns the key config path from PUBSUB_TUI_KEYS_FILE,
+// defaulting to pubsub-tui/keys.json in the user config directory. It
+// returns "" when overrides are disabled or no config directory exists.
+func KeysPathFromEnv() string {
+	v := strings.TrimSpace(os.Getenv(KeysEnvVar))
+	if strings.EqualFold(v, "off") {
+		return ""
+	}
+	if v != "" {
+		return v
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pubsub-tui", "keys.json")
+}
+
+// LoadKeyConfig applies the key binding overrides in the config file at
+// path, a JSON object mapping actions such as "topics.create" to a key or a
+// list of keys. A missing file or empty path keeps the defaults. It returns
+// warnings for unknown actions, and an error for an unreadable file or
+// conflicting bindings.
+func LoadKeyConfig(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key config: %w", err)
+	}
+	var config map[string]keyList
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse key config %s: %w", path, err)
+	}
+	return applyKeyConfig(config)
+}
+
+// applyKeyConfig applies overrides by section, then checks that no global
+// key is also bound in a panel, since global keys would shadow it
+func applyKeyConfig(config map[string]keyList) ([]string, error) {
+	sections := make(map[string]map[string][]string)
+	var warnings []string
+	for action, keys := range config {
+		section, name, _ := strings.Cut(action, ".")
+		if !knownKeySection(section) || name == "" {
+			warnings = append(warnings, fmt.Sprintf("unknown key action %q", action))
+			continue
+		}
+		if sections[section] == nil {
+			sections[section] = make(map[string][]string)
+		}
+		sections[section][name] = keys
+	}
+
+	for _, s := range keySections {
+		unknown, err := s.apply(sections[s.name])
+		if err != nil {
+			return nil, fmt.Errorf("%s keys: %w", s.name, err)
+		}
+		for _, name := range unknown {
+			warnings = append(warnings, fmt.Sprintf("unknown key action %q", s.name+"."+name))
+		}
+	}
+	sort.Strings(warnings)
+
+	if err := checkGlobalKeyConflicts(); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// knownKeySection reports whether name is a section of the key config
+func knownKeySection(name string) bool {
+	for _, s := range keySections {
+		if s.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGlobalKeyConflicts returns an error when a global key is also bound
+// in a panel
+func checkGlobalKeyConflicts() error {
+	panels := []struct {
+		name     string
+		bindings []key.Binding
+	}{
+		{"topics", topics.Model{}.FullHelp()},
+		{"subscriptions", subscriptions.Model{}.FullHelp()},
+		{"publisher", publisher.Model{}.FullHelp()},
+		{"subscriber", subscriber.Model{}.FullHelp()},
+	}
+	for _, g := range common.KeyBindings(keys) {
+		for _, k := range g.Keys() {
+			for _, p := range panels {
+				for _, b := range p.bindings {
+					for _, pk := range b.Keys() {
+						if pk == k {
+							return fmt.Errorf("key %q is bound globally (%s) and in the %s panel (%s)", k, g.Help().Desc, p.name, b.Help().Desc)
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
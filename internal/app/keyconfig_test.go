@@ -0,0 +1,69 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/topics"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadKeyConfig(t *testing.T) {
+	// Restore the defaults for other tests
+	t.Cleanup(func() {
+		if _, err := applyKeyConfig(nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "keys.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Defaults are kept without a config file
+	if warnings, err := LoadKeyConfig(filepath.Join(dir, "missing.json")); err != nil || warnings != nil {
+		t.Fatalf("LoadKeyConfig(missing) = %v, %v; want defaults", warnings, err)
+	}
+
+	warnings, err := LoadKeyConfig(write(`{"global.quit": "Q", "topics.create": ["c", "+"], "topics.bogus": "x", "nowhere.quit": "z"}`))
+	if err != nil {
+		t.Fatalf("LoadKeyConfig() error = %v", err)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("warnings = %v, want one per unknown action", warnings)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")}, keys.Quit) {
+		t.Error("Q should quit")
+	}
+	if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, keys.Quit) {
+		t.Error("q should no longer quit")
+	}
+	found := false
+	for _, b := range (topics.Model{}).FullHelp() {
+		if b.Help().Desc == "Create new topic" {
+			found = b.Help().Key == "c/+"
+		}
+	}
+	if !found {
+		t.Error("topic create help should show c/+")
+	}
+
+	// A panel key shadowed by a global key is rejected
+	if _, err := LoadKeyConfig(write(`{"global.help": "d"}`)); err == nil {
+		t.Error("LoadKeyConfig() should reject a global key used by a panel")
+	}
+	if _, err := LoadKeyConfig(write(`{"topics.create": "d"}`)); err == nil {
+		t.Error("LoadKeyConfig() should reject a key bound to another action")
+	}
+	if _, err := LoadKeyConfig(write(`{"topics.create": 1}`)); err == nil {
+		t.Error("LoadKeyConfig() should reject keys that are not strings")
+	}
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/palette"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriptions"
+	"github.com/anmaso/pubsub-tui/internal/components/topics"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteKeyMsg runs an action chosen in the command palette by pressing its
+// key in a panel, focusing the panel first. An empty focus presses a global
+// key.
+type paletteKeyMsg struct {
+	focus FocusPanel
+	key   tea.KeyMsg
+}
+
+// paletteEntry is a command palette action. Key actions are named like the
+// key config ("topics.create"), so they follow key overrides; other actions
+// send msg.
+type paletteEntry struct {
+	name   string
+	action string
+	msg    tea.Msg
+}
+
+// paletteEntries lists the command palette actions, grouped by panel
+var paletteEntries = []paletteEntry{
+	{name: "Refresh topics", msg: common.RefreshTopicsMsg{}},
+	{name: "Create topic", action: "topics.create"},
+	{name: "Delete selected topic", action: "topics.delete"},
+	{name: "Delete all displayed topics", action: "topics.deleteall"},
+	{name: "Filter topics", action: "topics.filter"},
+	{name: "Search topics", action: "topics.search"},
+	{name: "Clear publish marks", action: "topics.clearmarks"},
+	{name: "Toggle topic/subscription tree", action: "topics.tree"},
+
+	{name: "Refresh subscriptions", msg: common.RefreshSubscriptionsMsg{}},
+	{name: "Create subscription", action: "subscriptions.create"},
+	{name: "Delete selected subscription", action: "subscriptions.delete"},
+	{name: "Delete all displayed subscriptions", action: "subscriptions.deleteall"},
+	{name: "Delete orphaned subscriptions", action: "subscriptions.deleteorphans"},
+	{name: "Filter subscriptions", action: "subscriptions.filter"},
+	{name: "Clear the topic filter", action: "subscriptions.clearfilter"},
+	{name: "Snapshot selected subscription", action: "subscriptions.snapshot"},
+	{name: "Browse snapshots", action: "subscriptions.snapshots"},
+	{name: "Show subscription details", action: "subscriptions.info"},
+	{name: "Stop the subscription", msg: common.StopSubscriptionMsg{}},
+
+	{name: "Edit message body", action: "publisher.edit"},
+	{name: "Edit variables", action: "publisher.variables"},
+	{name: "Save message to a new file", action: "publisher.save"},
+	{name: "Quick publish typed data", action: "publisher.quickpublish"},
+	{name: "Schedule a publish", action: "publisher.schedule"},
+	{name: "Publish copies in batches", action: "publisher.batch"},
+	{name: "Toggle publish history", action: "publisher.history"},
+	{name: "Sort message files", action: "publisher.sortfiles"},
+
+	{name: "Acknowledge selected message", action: "subscriber.ackstay"},
+	{name: "Acknowledge displayed messages", action: "subscriber.ackdisplayed"},
+	{name: "Nack selected message (redeliver)", action: "subscriber.nack"},
+	{name: "Cycle ack mode (auto-ack)", action: "subscriber.ackmode"},
+	{name: "Export selected message to a file", action: "subscriber.dump"},
+	{name: "Republish selected message", action: "subscriber.republish"},
+	{name: "Filter messages", action: "subscriber.filter"},
+	{name: "Toggle follow", action: "subscriber.follow"},
+	{name: "Toggle raw / decoded data", action: "subscriber.raw"},
+	{name: "Toggle local time / UTC", action: "subscriber.timezone"},
+	{name: "Toggle publish time / age", action: "subscriber.relativetime"},
+	{name: "Set max outstanding messages", action: "subscriber.maxoutstanding"},
+	{name: "Show an attribute in message rows", action: "subscriber.listattribute"},
+	{name: "Show only recent messages (age window)", action: "subscriber.agewindow"},
+
+	{name: "Zoom the focused panel", action: "global.zoom"},
+	{name: "Reconnect", action: "global.reconnect"},
+	{name: "Undo the last delete", action: "global.undo"},
+	{name: "Cancel the bulk delete", action: "global.cancelbulk"},
+	{name: "Show help", action: "global.help"},
+	{name: "Quit", action: "global.quit"},
+}
+
+// paletteSection finds the bindings of one key map for palette actions
+type paletteSection struct {
+	focus   FocusPanel
+	binding func(name string) (key.Binding, bool)
+}
+
+// paletteSections maps action prefixes to their key maps, as in keySections
+var paletteSections = map[string]paletteSection{
+	"global":        {"", globalBinding},
+	"topics":        {FocusTopics, topics.Binding},
+	"subscriptions": {FocusSubscriptions, subscriptions.Binding},
+	"publisher":     {FocusPublisher, publisher.Binding},
+	"subscriber":    {FocusSubscriber, subscriber.Binding},
+}
+
+// globalBinding returns a global key binding by action name
+func globalBinding(name string) (key.Binding, bool) {
+	return common.FindKey(keys, name)
+}
+
+// paletteActions returns the command palette actions with their current
+// keys. Key actions whose binding is missing or disabled are left out.
+func paletteActions() []palette.Action {
+	actions := make([]palette.Action, 0, len(paletteEntries))
+	for _, e := range paletteEntries {
+		if e.action == "" {
+			actions = append(actions, palette.Action{Name: e.name, Msg: e.msg})
+			continue
+		}
+		msg, label, ok := paletteKey(e.action)
+		if !ok {
+			continue
+		}
+		actions = append(actions, palette.Action{Name: e.name, Key: label, Msg: msg})
+	}
+	return actions
+}
+
+// paletteKey returns the message pressing the key of a key config action,
+// and its help label
+func paletteKey(action string) (paletteKeyMsg, string, bool) {
+	prefix, name, _ := strings.Cut(action, ".")
+	section, ok := paletteSections[prefix]
+	if !ok {
+		return paletteKeyMsg{}, "", false
+	}
+	b, ok := section.binding(name)
+	if !ok || !b.Enabled() || len(b.Keys()) == 0 {
+		return paletteKeyMsg{}, "", false
+	}
+	return paletteKeyMsg{focus: section.focus, key: common.KeyPress(b.Keys()[0])}, b.Help().Key, true
+}
+
+// openPalette shows the command palette
+func (m *Model) openPalette() {
+	m.palette.SetSize(m.width, m.height)
+	m.palette.Show(paletteActions())
+}
+
+// runPaletteKey presses the key of a palette action. Panel keys go straight
+// to the panel, so they are not taken for global keys or the g prefix.
+func (m Model) runPaletteKey(msg paletteKeyMsg) (tea.Model, tea.Cmd) {
+	if msg.focus == "" {
+		return m.Update(msg.key)
+	}
+	m.focus = msg.focus
+	m.updateFocus()
+	cmd := m.routeKeyToFocused(msg.key)
+	if m.focus == FocusSubscriber {
+		// Keys may ack messages
+		m.syncSnapshot()
+	}
+	return m, cmd
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPaletteActions(t *testing.T) {
+	// Every key action names a binding
+	for _, e := range paletteEntries {
+		if e.action == "" {
+			continue
+		}
+		if _, _, ok := paletteKey(e.action); !ok {
+			t.Errorf("palette action %q (%s) has no key binding", e.name, e.action)
+		}
+	}
+
+	byName := make(map[string]tea.Msg)
+	for _, a := range paletteActions() {
+		byName[a.Name] = a.Msg
+	}
+	tests := []struct {
+		name string
+		want tea.Msg
+	}{
+		{"Create topic", paletteKeyMsg{focus: FocusTopics, key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}}},
+		{"Acknowledge displayed messages", paletteKeyMsg{focus: FocusSubscriber, key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")}}},
+		{"Cycle ack mode (auto-ack)", paletteKeyMsg{focus: FocusSubscriber, key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")}}},
+		{"Show help", paletteKeyMsg{key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}}},
+		{"Refresh topics", common.RefreshTopicsMsg{}},
+	}
+	for _, tt := range tests {
+		got, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("palette is missing %q", tt.name)
+			continue
+		}
+		want, isKey := tt.want.(paletteKeyMsg)
+		if !isKey {
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("%q sends %T, want %T", tt.name, got, tt.want)
+			}
+			continue
+		}
+		if got, ok := got.(paletteKeyMsg); !ok || got.focus != want.focus || got.key.String() != want.key.String() {
+			t.Errorf("%q sends %#v, want %s in %q", tt.name, got, want.key, want.focus)
+		}
+	}
+}
+
+func TestModel_Palette(t *testing.T) {
+	m := newTestModel()
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.focus = FocusSubscriber
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		t.Helper()
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		return cmd
+	}
+
+	// Esc closes the palette without running anything
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if !m.palette.IsVisible() {
+		t.Fatal(": should open the command palette")
+	}
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || m.palette.IsVisible() {
+		t.Error("esc should close the palette")
+	}
+
+	// Choosing an action presses its key in its panel
+	press(tea.KeyMsg{Type: tea.KeyCtrlP})
+	for _, r := range "create topic" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	cmd := press(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should run the selected action")
+	}
+	m = update(t, m, cmd())
+	if m.focus != FocusTopics || !m.topics.IsInputActive() {
+		t.Errorf("focus = %s, topic input active = %v; want the create topic prompt", m.focus, m.topics.IsInputActive())
+	}
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// publishTimeout bounds how long a publish waits for the server, so a
+// publish to an unreachable topic fails instead of hanging
+const publishTimeout = 30 * time.Second
+
+// inFlightPublishes holds the cancel funcs of publishes waiting for the
+// server. Publishes register from their command goroutines, so it is shared
+// by pointer and locked. A nil *inFlightPublishes tracks nothing.
+type inFlightPublishes struct {
+	mu      sync.Mutex
+	nextID  int
+	cancels map[int]context.CancelFunc
+}
+
+func newInFlightPublishes() *inFlightPublishes {
+	return &inFlightPublishes{cancels: make(map[int]context.CancelFunc)}
+}
+
+// start returns the context for a new publish, and a func to call once the
+// publish has completed
+func (p *inFlightPublishes) start() (context.Context, func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	if p == nil {
+		return ctx, cancel
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	id := p.nextID
+	p.cancels[id] = cancel
+	return ctx, func() {
+		p.mu.Lock()
+		delete(p.cancels, id)
+		p.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancelAll cancels every publish in flight and returns how many there were
+func (p *inFlightPublishes) cancelAll() int {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, cancel := range p.cancels {
+		cancel()
+	}
+	return len(p.cancels)
+}
+
+// cancelPublishes cancels the publishes in flight. Their results then report
+// them as cancelled rather than published.
+func (m *Model) cancelPublishes() tea.Cmd {
+	if m.publishes.cancelAll() == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return common.Warning("Cancelling publish...")
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestInFlightPublishes(t *testing.T) {
+	p := newInFlightPublishes()
+	first, doneFirst := p.start()
+	second, doneSecond := p.start()
+
+	// A completed publish is no longer cancelled
+	doneFirst()
+	if n := p.cancelAll(); n != 1 {
+		t.Errorf("cancelAll() = %d, want the one publish still in flight", n)
+	}
+	if !errors.Is(second.Err(), context.Canceled) {
+		t.Errorf("in-flight publish context error = %v, want canceled", second.Err())
+	}
+	if !errors.Is(first.Err(), context.Canceled) {
+		t.Error("a completed publish should release its context")
+	}
+	doneSecond()
+	if n := p.cancelAll(); n != 0 {
+		t.Errorf("cancelAll() = %d after all publishes completed, want 0", n)
+	}
+
+	// A nil tracker still bounds publishes with the timeout
+	var none *inFlightPublishes
+	ctx, done := none.start()
+	defer done()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("publish context should have a deadline")
+	}
+	if none.cancelAll() != 0 {
+		t.Error("a nil tracker has nothing to cancel")
+	}
+}
+
+// inFlight returns how many publishes are waiting for the server
+func (p *inFlightPublishes) inFlight() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.cancels)
+}
+
+// newUnresponsiveClient returns a client for an emulator that accepts
+// connections but never answers, so its publishes wait until cancelled. It is
+// not closed: Close would wait for the queued publishes to time out.
+func newUnresponsiveClient(t *testing.T) *pubsub.Client {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	t.Setenv("PUBSUB_EMULATOR_HOST", lis.Addr().String())
+	client, err := pubsub.NewClient("test-project", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestModel_CancelBatchPublish(t *testing.T) {
+	m := newTestModel()
+	m.client = newUnresponsiveClient(t)
+	m.publishes = newInFlightPublishes()
+	m.focus = FocusPublisher
+	m.publisher.SetPublishing(true)
+
+	result := make(chan tea.Msg)
+	cmd := m.publishBatch("orders", []byte(`{}`), 3)
+	go func() { result <- cmd() }()
+	deadline := time.Now().Add(time.Second)
+	for m.publishes.inFlight() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Esc cancels the batch instead of leaving it to wait for the server
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	for _, msg := range cmdMsgs(cmd) {
+		m = update(t, m, msg)
+	}
+	var msg tea.Msg
+	select {
+	case msg = <-result:
+	case <-time.After(5 * time.Second):
+		t.Fatal("esc should cancel the batch publish")
+	}
+	batch, ok := msg.(publisher.BatchPublishResultMsg)
+	if !ok || batch.Published != 0 || len(batch.Errors) != 3 || !errors.Is(batch.Errors[0], context.Canceled) {
+		t.Fatalf("cancelled batch returned %#v, want 3 cancelled messages", msg)
+	}
+
+	next, cmd = m.Update(batch)
+	m = next.(Model)
+	if m.publisher.IsPublishing() {
+		t.Error("the publisher should stop publishing once the batch is cancelled")
+	}
+	var logged []string
+	for _, msg := range cmdMsgs(cmd) {
+		if entry, ok := msg.(common.LogMsg); ok {
+			logged = append(logged, entry.Message)
+		}
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "cancelled after 0 of 3 messages") {
+		t.Errorf("logged %q, want the batch reported as cancelled", logged)
+	}
+}
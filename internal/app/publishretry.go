@@ -0,0 +1,79 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/internal/config"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// publishRequest is a single publish, kept so it can be retried
+type publishRequest struct {
+	topic       string
+	content     []byte
+	attributes  map[string]string
+	orderingKey string
+	attempt     int // From 1
+}
+
+// publishRetryMsg reports a publish that failed with a transient error and
+// is retried after a backoff
+type publishRetryMsg struct {
+	req publishRequest
+	err error
+}
+
+// publishAttemptMsg runs a retry of a publish once its backoff has passed
+type publishAttemptMsg struct {
+	req publishRequest
+}
+
+// shouldRetryPublish reports whether a publish that failed with err on the
+// given attempt is tried again. Only transient errors, such as an
+// unavailable server or an expired deadline, are retried; errors such as
+// not found or permission denied would fail again.
+func shouldRetryPublish(err error, attempt, maxAttempts int) bool {
+	return err != nil && attempt < maxAttempts && pubsub.IsTransientError(err)
+}
+
+// publishAttempts returns the attempts per publish, at least one
+func (m Model) publishAttempts() int {
+	if m.options.PublishAttempts <= 0 {
+		return config.DefaultPublishAttempts
+	}
+	return m.options.PublishAttempts
+}
+
+// retryPublish logs a failed attempt and returns a command that publishes
+// again after the backoff for the next attempt. Cancelling publishes also
+// cancels the wait.
+func (m *Model) retryPublish(msg publishRetryMsg) tea.Cmd {
+	next := msg.req
+	next.attempt++
+	delay := reconnectDelay(msg.req.attempt)
+	maxAttempts := m.publishAttempts()
+	publishes := m.publishes
+
+	wait := func() tea.Msg {
+		ctx, done := publishes.start()
+		defer done()
+		select {
+		case <-time.After(delay):
+			return publishAttemptMsg{req: next}
+		case <-ctx.Done():
+			return publisher.PublishResultMsg{Topic: next.topic, Content: next.content, Err: ctx.Err()}
+		}
+	}
+	return tea.Batch(
+		func() tea.Msg {
+			return common.Warning(fmt.Sprintf("Publish to %s failed: %v; retrying in %s (attempt %d/%d)",
+				next.topic, msg.err, delay, next.attempt, maxAttempts))
+		},
+		wait,
+	)
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestShouldRetryPublish(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		want    bool
+	}{
+		{"published", nil, 1, false},
+		{"unavailable", unavailable, 1, true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "slow"), 2, true},
+		{"publish timeout", fmt.Errorf("publish: %w", context.DeadlineExceeded), 1, true},
+		{"last attempt", unavailable, 3, false},
+		{"not found", status.Error(codes.NotFound, "no topic"), 1, false},
+		{"permission denied", status.Error(codes.PermissionDenied, "denied"), 1, false},
+		{"cancelled", context.Canceled, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetryPublish(tt.err, tt.attempt, 3); got != tt.want {
+				t.Errorf("shouldRetryPublish(%v, %d, 3) = %v, want %v", tt.err, tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_RetryPublish(t *testing.T) {
+	m := newTestModel()
+	m.publishes = newInFlightPublishes()
+	req := publishRequest{topic: "orders", content: []byte(`{}`), attempt: 1}
+
+	batch, ok := m.retryPublish(publishRetryMsg{req: req, err: errors.New("unavailable")})().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatal("retryPublish() should log the retry and wait")
+	}
+	logged, _ := batch[0]().(common.LogMsg)
+	if logged.Level != common.LogWarning || !strings.Contains(logged.Message, "retrying in 1s (attempt 2/3)") {
+		t.Errorf("logged %q, want the backoff and next attempt", logged.Message)
+	}
+
+	// Cancelling publishes also cancels the wait for a retry
+	result := make(chan tea.Msg)
+	go func() { result <- batch[1]() }()
+	deadline := time.Now().Add(time.Second)
+	for m.publishes.cancelAll() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	msg, ok := (<-result).(publisher.PublishResultMsg)
+	if !ok || !errors.Is(msg.Err, context.Canceled) || msg.Topic != "orders" {
+		t.Errorf("cancelled retry returned %#v, want a cancelled result", msg)
+	}
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/dialog"
+	"github.com/anmaso/pubsub-tui/internal/debuglog"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reconnectPingTimeout bounds the check that a new emulator responds; a
+// variable so tests can shorten it
+var reconnectPingTimeout = 5 * time.Second
+
+// reconnectDialogID identifies the dialog asking for the host to reconnect to
+const reconnectDialogID = "reconnect"
+
+// ClientReconnectedMsg is sent when a new client has been created for the
+// host entered in the reconnect dialog
+type ClientReconnectedMsg struct {
+	Client       *pubsub.Client
+	EmulatorHost string // Empty when connected to real GCP
+	Err          error
+}
+
+// targetName describes where a client connects to, for the activity log
+func targetName(emulatorHost, projectID string) string {
+	if emulatorHost != "" {
+		return "emulator at " + emulatorHost
+	}
+	return "GCP project " + projectID
+}
+
+// reconnectClient returns a command that points PUBSUB_EMULATOR_HOST at
+// host, or clears it to connect to GCP, and creates a new client, checking
+// that an emulator actually responds. On failure the previous value of
+// PUBSUB_EMULATOR_HOST is restored, since the current client is kept.
+func (m Model) reconnectClient(host string) tea.Cmd {
+	projectID, credentialsFile := m.projectID, m.options.CredentialsFile
+	return func() tea.Msg {
+		prev := pubsub.GetEmulatorHost()
+		if err := setEmulatorHost(host); err != nil {
+			return ClientReconnectedMsg{EmulatorHost: host, Err: err}
+		}
+		fail := func(err error) tea.Msg {
+			setEmulatorHost(prev)
+			return ClientReconnectedMsg{EmulatorHost: host, Err: err}
+		}
+
+		client, err := pubsub.NewClient(projectID, credentialsFile)
+		if err != nil {
+			return fail(err)
+		}
+		client.SetCallObserver(debuglog.Call)
+
+		// Dialing the emulator succeeds even when nothing is listening
+		if host != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), reconnectPingTimeout)
+			defer cancel()
+			if err := client.Ping(ctx); pubsub.IsTransientError(err) {
+				client.Close()
+				return fail(fmt.Errorf("emulator not reachable: %w", err))
+			}
+		}
+		return ClientReconnectedMsg{Client: client, EmulatorHost: host}
+	}
+}
+
+// setEmulatorHost sets PUBSUB_EMULATOR_HOST, or unsets it when host is empty
+func setEmulatorHost(host string) error {
+	if host == "" {
+		return os.Unsetenv(pubsub.EmulatorHostEnvVar)
+	}
+	return os.Setenv(pubsub.EmulatorHostEnvVar, host)
+}
+
+// startReconnect asks for the emulator host to reconnect to, prefilled with
+// the current one. The environment of the running application does not
+// change when PUBSUB_EMULATOR_HOST is exported in another shell, so the
+// host has to be entered here.
+func (m *Model) startReconnect() {
+	m.dialog.ShowForm(
+		reconnectDialogID,
+		"Reconnect",
+		"Emulator host, or empty for GCP project "+m.projectID,
+		[]dialog.Field{{
+			Label:       "Host",
+			Placeholder: pubsub.DefaultEmulatorHost,
+			Value:       pubsub.GetEmulatorHost(),
+		}},
+		func(values []string) error {
+			host := strings.TrimSpace(values[0])
+			if host == "" {
+				return nil
+			}
+			if _, _, err := net.SplitHostPort(host); err != nil {
+				return fmt.Errorf("host must be host:port, e.g. %s", pubsub.DefaultEmulatorHost)
+			}
+			return nil
+		},
+		nil,
+	)
+}
+
+// reconnectTo logs the host being connected to and starts reconnecting
+func (m Model) reconnectTo(host string) tea.Cmd {
+	target := targetName(host, m.projectID)
+	return tea.Batch(
+		func() tea.Msg {
+			return common.Network("Reconnecting to " + target + "...")
+		},
+		m.reconnectClient(host),
+	)
+}
+
+// handleReconnected switches to a newly created client: the active
+// subscription is stopped, since it belongs to the old client, and the lists
+// are reloaded. On failure the current client is kept.
+func (m *Model) handleReconnected(msg ClientReconnectedMsg) tea.Cmd {
+	target := targetName(msg.EmulatorHost, m.projectID)
+	if msg.Err != nil {
+		return func() tea.Msg {
+			return common.ErrorLog("Reconnect to "+target+" failed", msg.Err)
+		}
+	}
+
+	var cmds []tea.Cmd
+	if sub := m.selectedSubscription; sub != "" {
+		m.stopSubscription()
+		m.selectedSubscription = ""
+		m.sessionAcks = 0
+		m.reconnectAttempts = 0
+		m.subscriptions.SetActiveSubscription("")
+		m.topics.SetActiveSubscription("")
+		m.subscriber.ClearSubscription()
+		m.syncSnapshot()
+		cmds = append(cmds, func() tea.Msg {
+			return common.Info("Stopped subscription: " + sub)
+		})
+	}
+
+	// The client passed to New is closed by its owner
+	if m.ownsClient {
+		m.client.Close()
+	}
+	m.client = msg.Client
+	m.ownsClient = true
+	m.health = healthUnknown
+	m.healthFailures = 0
+	m.options.EmulatorHost = msg.EmulatorHost
+	m.topics.SetEmulatorMode(m.IsEmulator())
+	m.subscriptions.SetEmulatorMode(m.IsEmulator())
+	m.subscriber.SetEmulatorMode(m.IsEmulator())
+
+	cmds = append(cmds,
+		func() tea.Msg {
+			return common.Success("Reconnected to " + target)
+		},
+		m.loadTopics(),
+		m.loadSubscriptions(),
+		m.checkHealth(),
+	)
+	return tea.Batch(cmds...)
+}
+
+// CloseClient closes a client created by reconnecting. The client passed to
+// New is left to its owner.
+func (m Model) CloseClient() {
+	if m.ownsClient {
+		m.client.Close()
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/dialog"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	"cloud.google.com/go/pubsub/pstest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_HandleReconnected(t *testing.T) {
+	original := new(pubsub.Client)
+	m := newTestModel()
+	m.client = original
+	m.projectID = "test-project"
+
+	// A failed reconnect keeps the current client
+	m = update(t, m, ClientReconnectedMsg{EmulatorHost: "localhost:8085", Err: errors.New("connection refused")})
+	if m.client != original || m.IsEmulator() {
+		t.Fatal("a failed reconnect should keep the current client")
+	}
+
+	// A new client replaces it and stops the active subscription
+	m.selectedSubscription = "orders-sub"
+	m.subscriber.SetSubscription("orders-sub", "orders")
+	replacement := new(pubsub.Client)
+	m = update(t, m, ClientReconnectedMsg{Client: replacement, EmulatorHost: "localhost:8085"})
+	if m.client != replacement || !m.ownsClient {
+		t.Error("the new client should replace the old one")
+	}
+	if !m.IsEmulator() || m.options.EmulatorHost != "localhost:8085" {
+		t.Errorf("EmulatorHost = %q, want localhost:8085", m.options.EmulatorHost)
+	}
+	if m.selectedSubscription != "" || m.subscriber.IsConnected() {
+		t.Error("the active subscription belongs to the old client and should stop")
+	}
+}
+
+func TestModel_ReconnectPrompt(t *testing.T) {
+	srv := pstest.NewServer()
+	defer srv.Close()
+	t.Setenv(pubsub.EmulatorHostEnvVar, "localhost:8085")
+
+	m := newTestModel()
+	m.projectID = "test-project"
+	submit := func(host string) tea.Cmd {
+		t.Helper()
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+		if !m.dialog.IsVisible() || m.dialog.ID() != reconnectDialogID {
+			t.Fatal("R should ask for the emulator host")
+		}
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlU})
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(host)})
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = next.(Model)
+		for _, msg := range cmdMsgs(cmd) {
+			next, cmd = m.Update(msg)
+			m = next.(Model)
+		}
+		return cmd
+	}
+	reconnected := func(cmd tea.Cmd) ClientReconnectedMsg {
+		t.Helper()
+		for _, msg := range cmdMsgs(cmd) {
+			if msg, ok := msg.(ClientReconnectedMsg); ok {
+				return msg
+			}
+		}
+		t.Fatal("no ClientReconnectedMsg")
+		return ClientReconnectedMsg{}
+	}
+
+	// The prompt is prefilled with the current host
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msgs := cmdMsgs(cmd); len(msgs) != 1 || msgs[0].(dialog.ResultMsg).Result.Values[0] != "localhost:8085" {
+		t.Fatalf("prompt result = %v, want the current host", msgs)
+	}
+	m.dialog = dialog.Model{}
+
+	// The entered host is used for the new client
+	msg := reconnected(submit(srv.Addr))
+	if msg.Err != nil || msg.Client == nil || msg.EmulatorHost != srv.Addr {
+		t.Fatalf("reconnect = %+v, want a client for %s", msg, srv.Addr)
+	}
+	defer msg.Client.Close()
+	if got := pubsub.GetEmulatorHost(); got != srv.Addr {
+		t.Errorf("PUBSUB_EMULATOR_HOST = %q, want %q", got, srv.Addr)
+	}
+
+	// A host that does not respond leaves the environment as it was
+	defer func(timeout time.Duration) { reconnectPingTimeout = timeout }(reconnectPingTimeout)
+	reconnectPingTimeout = 200 * time.Millisecond
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachable := closed.Addr().String()
+	closed.Close()
+	msg = reconnected(submit(unreachable))
+	if msg.Err == nil || msg.EmulatorHost != unreachable {
+		t.Errorf("reconnect = %+v, want an error for %s", msg, unreachable)
+	}
+	if got := pubsub.GetEmulatorHost(); got != srv.Addr {
+		t.Errorf("PUBSUB_EMULATOR_HOST = %q, want %q restored", got, srv.Addr)
+	}
+}
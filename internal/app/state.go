@@ -0,0 +1,174 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StateEnvVar overrides where UI state is saved between runs. "off"
+// disables saving.
+const StateEnvVar = "PUBSUB_TUI_STATE_FILE"
+
+// stateVersion is written to the state file. Older and newer files are
+// still read field by field, so adding fields needs no bump.
+const stateVersion = 1
+
+// Left column width as a fraction of the terminal width
+const (
+	defaultLeftRatio = 1.0 / 3
+	minLeftRatio     = 0.2
+	maxLeftRatio     = 0.6
+	leftRatioStep    = 0.05
+)
+
+// layoutMode is how the panels are arranged
+type layoutMode string
+
+const (
+	layoutSplit layoutMode = "split" // Both columns, every panel shown
+	layoutZoom  layoutMode = "zoom"  // Only the focused panel, full size
+)
+
+// uiState is the UI layout saved between runs. Unknown fields are ignored
+// and missing or invalid ones keep their defaults.
+type uiState struct {
+	Version   int     `json:"version"`
+	Focus     string  `json:"focus,omitempty"`
+	Layout    string  `json:"layout,omitempty"`
+	LeftRatio float64 `json:"left_ratio,omitempty"`
+}
+
+// StatePathFromEnv returns the state file path from PUBSUB_TUI_STATE_FILE,
+// defaulting to pubsub-tui/state.json in the user config directory. It
+// returns "" when saving is disabled or no config directory exists.
+func StatePathFromEnv() string {
+	v := strings.TrimSpace(os.Getenv(StateEnvVar))
+	if strings.EqualFold(v, "off") {
+		return ""
+	}
+	if v != "" {
+		return v
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pubsub-tui", "state.json")
+}
+
+// loadState reads saved UI state. A missing file is not an error.
+func loadState(path string) (uiState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return uiState{}, nil
+	}
+	if err != nil {
+		return uiState{}, fmt.Errorf("failed to read UI state: %w", err)
+	}
+	var s uiState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return uiState{}, fmt.Errorf("failed to parse UI state %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// validFocus reports whether name is a panel that can hold focus
+func validFocus(name string) (FocusPanel, bool) {
+	switch f := FocusPanel(name); f {
+	case FocusTopics, FocusSubscriptions, FocusPublisher, FocusSubscriber:
+		return f, true
+	}
+	return "", false
+}
+
+// validLayout reports whether name is a known layout mode
+func validLayout(name string) (layoutMode, bool) {
+	switch l := layoutMode(name); l {
+	case layoutSplit, layoutZoom:
+		return l, true
+	}
+	return "", false
+}
+
+// clampLeftRatio keeps the left column ratio within its limits, using the
+// default for unset or non-finite values
+func clampLeftRatio(r float64) float64 {
+	switch {
+	case r != r || r <= 0: // NaN or unset
+		return defaultLeftRatio
+	case r < minLeftRatio:
+		return minLeftRatio
+	case r > maxLeftRatio:
+		return maxLeftRatio
+	}
+	return r
+}
+
+// applyState restores validated UI state, ignoring invalid values
+func (m *Model) applyState(s uiState) {
+	if f, ok := validFocus(s.Focus); ok {
+		m.focus = f
+	}
+	if l, ok := validLayout(s.Layout); ok {
+		m.layout = l
+	}
+	m.leftRatio = clampLeftRatio(s.LeftRatio)
+	m.updateFocus()
+}
+
+// toggleZoom switches between showing every panel and only the focused one
+func (m *Model) toggleZoom() {
+	if m.layout == layoutZoom {
+		m.layout = layoutSplit
+	} else {
+		m.layout = layoutZoom
+	}
+	if m.ready {
+		m.updateComponentSizes()
+	}
+}
+
+// resizeLeft widens (positive steps) or narrows the left column
+func (m *Model) resizeLeft(steps int) {
+	m.leftRatio = clampLeftRatio(m.leftRatio + float64(steps)*leftRatioStep)
+	if m.ready {
+		m.updateComponentSizes()
+	}
+}
+
+// SaveState writes the focused panel, layout and column split to the state
+// file.
+// It does nothing when no state path is configured.
+func (m Model) SaveState() error {
+	path := m.options.StatePath
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(uiState{
+		Version:   stateVersion,
+		Focus:     string(m.focus),
+		Layout:    string(m.layout),
+		LeftRatio: m.leftRatio,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save UI state: %w", err)
+	}
+
+	// Write a temporary file first so a crash never leaves a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save UI state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save UI state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestState_SaveAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pubsub-tui", "state.json")
+
+	m := New(nil, "test-project", Options{StatePath: path})
+	if m.focus != FocusTopics || m.leftRatio != defaultLeftRatio {
+		t.Fatalf("without a state file got focus %q ratio %v, want defaults", m.focus, m.leftRatio)
+	}
+
+	m.focus = FocusSubscriber
+	m.toggleZoom()
+	m.resizeLeft(2)
+	if err := m.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	restored := New(nil, "test-project", Options{StatePath: path})
+	if restored.focus != FocusSubscriber {
+		t.Errorf("focus = %q, want %q", restored.focus, FocusSubscriber)
+	}
+	if !restored.subscriber.IsFocused() || restored.topics.IsFocused() {
+		t.Error("restored focus is not applied to the panels")
+	}
+	if restored.layout != layoutZoom {
+		t.Errorf("layout = %q, want %q", restored.layout, layoutZoom)
+	}
+	if restored.leftRatio != m.leftRatio {
+		t.Errorf("leftRatio = %v, want %v", restored.leftRatio, m.leftRatio)
+	}
+	if restored.stateErr != nil {
+		t.Errorf("stateErr = %v, want nil", restored.stateErr)
+	}
+}
+
+func TestState_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantFocus  FocusPanel
+		wantLayout layoutMode
+		wantRatio  float64
+		wantErr    bool
+	}{
+		{"unknown focus and large ratio", `{"version":1,"focus":"sidebar","left_ratio":0.9}`, FocusTopics, layoutSplit, maxLeftRatio, false},
+		{"small ratio", `{"focus":"publisher","layout":"zoom","left_ratio":0.05}`, FocusPublisher, layoutZoom, minLeftRatio, false},
+		{"newer schema", `{"version":7,"focus":"subscriptions","layout":"stacked","extra":{"a":1}}`, FocusSubscriptions, layoutSplit, defaultLeftRatio, false},
+		{"wrong field type", `{"focus":3}`, FocusTopics, layoutSplit, defaultLeftRatio, true},
+		{"corrupt", `{"focus":`, FocusTopics, layoutSplit, defaultLeftRatio, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			m := New(nil, "test-project", Options{StatePath: path})
+			if m.focus != tt.wantFocus {
+				t.Errorf("focus = %q, want %q", m.focus, tt.wantFocus)
+			}
+			if m.layout != tt.wantLayout {
+				t.Errorf("layout = %q, want %q", m.layout, tt.wantLayout)
+			}
+			if m.leftRatio != tt.wantRatio {
+				t.Errorf("leftRatio = %v, want %v", m.leftRatio, tt.wantRatio)
+			}
+			if (m.stateErr != nil) != tt.wantErr {
+				t.Errorf("stateErr = %v, wantErr %v", m.stateErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModel_ResizeLeft(t *testing.T) {
+	m := newTestModel()
+	m.leftRatio = defaultLeftRatio
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}})
+	if want := defaultLeftRatio + leftRatioStep; math.Abs(m.leftRatio-want) > 1e-9 {
+		t.Errorf("after > leftRatio = %v, want %v", m.leftRatio, want)
+	}
+
+	for i := 0; i < 20; i++ {
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	}
+	if m.leftRatio != minLeftRatio {
+		t.Errorf("after repeated < leftRatio = %v, want %v", m.leftRatio, minLeftRatio)
+	}
+}
+
+func TestModel_Zoom(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusTopics
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}})
+	if m.layout != layoutZoom {
+		t.Fatalf("after Z layout = %q, want %q", m.layout, layoutZoom)
+	}
+	if !strings.Contains(m.View(), "Topics") || strings.Contains(m.View(), "Publisher") {
+		t.Error("zoomed view should show only the focused Topics panel")
+	}
+
+	// Focus moves to the publisher, which is now the one shown
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	if !m.publisher.IsFocused() || m.topics.IsFocused() {
+		t.Error("publisher should have focus after 3")
+	}
+	if strings.Contains(m.View(), "Topics") {
+		t.Error("zoomed view should no longer show the Topics panel")
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}})
+	if m.layout != layoutSplit || !strings.Contains(m.View(), "Topics") {
+		t.Errorf("after a second Z layout = %q, want every panel shown", m.layout)
+	}
+}
+
+func TestStatePathFromEnv(t *testing.T) {
+	t.Setenv(StateEnvVar, "off")
+	if got := StatePathFromEnv(); got != "" {
+		t.Errorf("StatePathFromEnv() with off = %q, want empty", got)
+	}
+
+	t.Setenv(StateEnvVar, "/tmp/custom.json")
+	if got := StatePathFromEnv(); got != "/tmp/custom.json" {
+		t.Errorf("StatePathFromEnv() = %q, want /tmp/custom.json", got)
+	}
+}
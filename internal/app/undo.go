@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deletedResource is a deleted topic or subscription, remembered so the
+// delete can be undone by recreating it. Only the last single delete is kept;
+// bulk deletes cannot be undone.
+type deletedResource struct {
+	subscription string                     // Empty when a topic was deleted
+	topic        string                     // The topic, or the topic the subscription was attached to
+	config       *pubsub.SubscriptionConfig // The subscription's full config
+}
+
+// rememberDeletedSubscription records a deleted subscription for undo. It is
+// skipped, with a warning, when the config could not be captured before
+// deletion, since recreating the subscription without it would silently
+// lose settings such as its push endpoint or dead-letter policy. It is also
+// skipped when its topic was deleted so it could not be recreated.
+func (m *Model) rememberDeletedSubscription(msg common.SubscriptionDeletedMsg) tea.Cmd {
+	if msg.Err != nil || msg.TopicName == pubsub.UnknownTopic || msg.TopicName == pubsub.DeletedTopic {
+		return nil
+	}
+	if msg.Config == nil || msg.TopicName == "" {
+		return func() tea.Msg {
+			return common.Warning(fmt.Sprintf("Undo unavailable for subscription %s: its config could not be read before deleting", msg.SubscriptionName))
+		}
+	}
+	m.lastDeleted = &deletedResource{
+		subscription: msg.SubscriptionName,
+		topic:        msg.TopicName,
+		config:       msg.Config,
+	}
+	return nil
+}
+
+// undoDelete recreates the last deleted topic, or the last deleted
+// subscription with its full config
+func (m *Model) undoDelete() tea.Cmd {
+	d := m.lastDeleted
+	if d == nil {
+		return func() tea.Msg {
+			return common.Warning("Nothing to undo")
+		}
+	}
+	m.lastDeleted = nil
+
+	if d.subscription == "" {
+		return tea.Batch(
+			m.createTopic(d.topic),
+			func() tea.Msg {
+				return common.Network(fmt.Sprintf("Undo delete: recreating topic %s", d.topic))
+			},
+		)
+	}
+
+	return tea.Batch(
+		m.recreateSubscription(d.subscription, d.config),
+		func() tea.Msg {
+			return common.Network(fmt.Sprintf("Undo delete: recreating subscription %s on topic %s with its previous config", d.subscription, d.topic))
+		},
+	)
+}
+
+// recreateSubscription creates a deleted subscription from its captured config
+func (m *Model) recreateSubscription(subName string, cfg *pubsub.SubscriptionConfig) tea.Cmd {
+	return retryOnAuth("creating subscription "+subName, func() tea.Msg {
+		ctx := context.Background()
+		err := m.client.RecreateSubscription(ctx, subName, cfg)
+		return common.SubscriptionCreatedMsg{
+			SubscriptionName: subName,
+			TopicName:        cfg.TopicName,
+			Err:              err,
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/palette"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriptions"
+	"github.com/anmaso/pubsub-tui/internal/components/topics"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestModel() Model {
+	return Model{
+		topics:        topics.New(),
+		subscriptions: subscriptions.New(),
+		publisher:     publisher.New(),
+		subscriber:    subscriber.New(),
+		palette:       palette.New(),
+	}
+}
+
+func update(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+func TestModel_RememberDeleted(t *testing.T) {
+	m := newTestModel()
+
+	m = update(t, m, common.TopicDeletedMsg{TopicName: "orders"})
+	if m.lastDeleted == nil || m.lastDeleted.topic != "orders" || m.lastDeleted.subscription != "" {
+		t.Fatalf("lastDeleted = %+v, want topic orders", m.lastDeleted)
+	}
+
+	cfg := &pubsub.SubscriptionConfig{TopicName: "orders", Filter: `attributes.type = "order"`}
+	m = update(t, m, common.SubscriptionDeletedMsg{SubscriptionName: "orders-sub", TopicName: "orders", Filter: cfg.Filter, Config: cfg})
+	want := deletedResource{subscription: "orders-sub", topic: "orders", config: cfg}
+	if m.lastDeleted == nil || *m.lastDeleted != want {
+		t.Fatalf("lastDeleted = %+v, want %+v", m.lastDeleted, want)
+	}
+
+	// Failed deletes and deletes without a captured config are not remembered
+	m = update(t, m, common.TopicDeletedMsg{TopicName: "events", Err: errors.New("permission denied")})
+	if m.lastDeleted == nil || *m.lastDeleted != want {
+		t.Errorf("lastDeleted = %+v, want it unchanged", m.lastDeleted)
+	}
+
+	// Without the full config, undo is disabled and the log says why
+	next, cmd := m.Update(common.SubscriptionDeletedMsg{SubscriptionName: "events-sub", TopicName: "events"})
+	m = next.(Model)
+	if m.lastDeleted == nil || *m.lastDeleted != want {
+		t.Errorf("lastDeleted = %+v, want it unchanged", m.lastDeleted)
+	}
+	var warned bool
+	for _, msg := range cmdMsgs(cmd) {
+		if log, ok := msg.(common.LogMsg); ok && log.Level == common.LogWarning && strings.Contains(log.Message, "Undo unavailable for subscription events-sub") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Error("deleting without a captured config should warn that undo is unavailable")
+	}
+
+	// Bulk deletes are not remembered
+	m.bulk = &bulkDelete{panel: FocusTopics, current: bulkDeleteItem{name: "events", isTopic: true}}
+	m = update(t, m, common.TopicDeletedMsg{TopicName: "events"})
+	if m.lastDeleted == nil || *m.lastDeleted != want {
+		t.Errorf("lastDeleted = %+v after a bulk delete, want it unchanged", m.lastDeleted)
+	}
+}
+
+func TestModel_UndoDelete(t *testing.T) {
+	m := newTestModel()
+
+	cmd := m.undoDelete()
+	if cmd == nil {
+		t.Fatal("undoDelete() with nothing deleted should log a warning")
+	}
+	if got, ok := cmd().(common.LogMsg); !ok || got.Level != common.LogWarning {
+		t.Errorf("undoDelete() = %+v, want a warning", got)
+	}
+
+	m.lastDeleted = &deletedResource{subscription: "orders-sub", topic: "orders", config: &pubsub.SubscriptionConfig{TopicName: "orders"}}
+	if cmd := m.undoDelete(); cmd == nil {
+		t.Fatal("undoDelete() should recreate the subscription")
+	}
+	if m.lastDeleted != nil {
+		t.Error("undo should only be possible once")
+	}
+}
@@ -3,12 +3,16 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/dialog"
 	"github.com/anmaso/pubsub-tui/internal/components/publisher"
 	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
 	"github.com/anmaso/pubsub-tui/internal/components/subscriptions"
 	"github.com/anmaso/pubsub-tui/internal/components/topics"
+	"github.com/anmaso/pubsub-tui/internal/debuglog"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,11 +24,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle help popup first
-		if m.showHelp {
-			// Close help on any key
-			m.showHelp = false
-			return m, nil
+		// An open dialog captures all keys except Ctrl+C
+		if m.dialog.IsVisible() && msg.Type != tea.KeyCtrlC {
+			return m, m.handleDialogKey(msg)
+		}
+
+		// The help overlay captures all keys except Ctrl+C
+		if m.showHelp && msg.Type != tea.KeyCtrlC {
+			return m, m.handleHelpKey(msg)
+		}
+
+		// The command palette captures all keys except Ctrl+C
+		if m.palette.IsVisible() && msg.Type != tea.KeyCtrlC {
+			var cmd tea.Cmd
+			m.palette, cmd = m.palette.Update(msg)
+			return m, cmd
 		}
 
 		// Check if any panel has an active input field
@@ -33,17 +47,47 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.publisher.IsInputActive() ||
 			m.subscriber.IsInputActive()
 
+		// g followed by a panel letter jumps to the panel
+		if m.pendingGo {
+			used, cmd := m.handleGoKey(msg)
+			if used {
+				return m, nil
+			}
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		} else if msg.String() == goPrefix && !inputActive {
+			m.pendingGo = true
+			return m, nil
+		}
+
 		// Global key handling
 		switch {
-		case key.Matches(msg, keys.Quit):
-			m.stopSubscription()
+		case key.Matches(msg, keys.Quit) && (!inputActive || msg.Type == tea.KeyCtrlC):
+			// A second quit while draining exits without waiting
+			if m.quitting {
+				return m, tea.Quit
+			}
 			m.publisher.StopFileWatch()
-			return m, tea.Quit
+			m.cancelScheduledPublishes()
+			if m.activeSubscription == nil {
+				m.stopSubscription()
+				return m, tea.Quit
+			}
+			m.quitting = true
+			return m, m.drainSubscription()
+
+		case key.Matches(msg, keys.Help) && !inputActive:
+			m.openHelp()
+			return m, nil
 
-		case key.Matches(msg, keys.Help):
-			m.showHelp = true
+		case key.Matches(msg, keys.Palette) && !inputActive:
+			m.openPalette()
 			return m, nil
 
+		case key.Matches(msg, keys.Undo) && !inputActive:
+			return m, m.undoDelete()
+
 		case key.Matches(msg, keys.Tab):
 			// Cycle focus forward
 			m.cycleFocus()
@@ -74,12 +118,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateFocus()
 			return m, nil
 
+		case key.Matches(msg, keys.RetryAuth) && m.authFailure != nil:
+			return m, m.retryAuthFailure()
+
+		case key.Matches(msg, keys.DismissAuth) && m.authFailure != nil:
+			m.clearAuthFailure()
+			return m, nil
+
+		case key.Matches(msg, keys.CancelBulk) && m.bulk != nil && !inputActive:
+			return m, m.cancelBulkDelete()
+
+		case key.Matches(msg, keys.Reconnect) && !inputActive:
+			m.startReconnect()
+			return m, nil
+
+		case key.Matches(msg, keys.Narrow) && !inputActive:
+			m.resizeLeft(-1)
+			return m, nil
+
+		case key.Matches(msg, keys.Widen) && !inputActive:
+			m.resizeLeft(1)
+			return m, nil
+
+		case key.Matches(msg, keys.Zoom) && !inputActive:
+			m.toggleZoom()
+			return m, nil
+
 		default:
 			// Route to focused component
 			cmd := m.routeKeyToFocused(msg)
 			if cmd != nil {
 				cmds = append(cmds, cmd)
 			}
+			if m.focus == FocusSubscriber {
+				// Keys may ack messages
+				m.syncSnapshot()
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -87,6 +161,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.ready = true
 		m.updateComponentSizes()
+		m.dialog.SetSize(msg.Width, msg.Height)
+		m.palette.SetSize(msg.Width, msg.Height)
+		if m.showHelp {
+			m.sizeHelp()
+		}
 		return m, nil
 
 	case common.TopicsLoadedMsg:
@@ -95,10 +174,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		// Marks of deleted topics are dropped
+		m.publisher.SetMarkedTopics(m.topics.MarkedTopics())
 
 		if msg.Err != nil {
 			cmds = append(cmds, func() tea.Msg {
-				return common.Error(fmt.Sprintf("Failed to load topics: %v", msg.Err))
+				return common.ErrorLog("Failed to load topics", msg.Err)
 			})
 		} else {
 			cmds = append(cmds, func() tea.Msg {
@@ -115,12 +196,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if msg.Err != nil {
 			cmds = append(cmds, func() tea.Msg {
-				return common.Error(fmt.Sprintf("Failed to load subscriptions: %v", msg.Err))
+				return common.ErrorLog("Failed to load subscriptions", msg.Err)
 			})
 		} else {
+			m.topics.SetSubscriptions(msg.Subscriptions)
 			cmds = append(cmds, func() tea.Msg {
 				return common.Success(fmt.Sprintf("Loaded %d subscriptions", len(msg.Subscriptions)))
 			})
+			for _, sub := range msg.Subscriptions {
+				if sub.ConfigLoaded {
+					continue
+				}
+				name := sub.Name
+				cmds = append(cmds, func() tea.Msg {
+					return common.Warning(fmt.Sprintf("Could not load config for subscription %s; its topic is unknown", name))
+				})
+			}
 		}
 
 	case common.TopicSelectedMsg:
@@ -139,46 +230,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return common.Info(fmt.Sprintf("Selected topic: %s", msg.TopicName))
 		})
 
-	case common.SubscriptionSelectedMsg:
-		// Stop any existing subscription first
-		if m.selectedSubscription != "" && m.selectedSubscription != msg.SubscriptionName {
-			m.stopSubscription()
-			cmds = append(cmds, func() tea.Msg {
-				return common.Info(fmt.Sprintf("Stopped previous subscription: %s", m.selectedSubscription))
-			})
+	case common.TopicsMarkedMsg:
+		m.publisher.SetMarkedTopics(msg.Topics)
+		text := "Cleared publish marks: Enter publishes to the selected topic"
+		if len(msg.Topics) > 0 {
+			text = fmt.Sprintf("Publishing to %d marked topic(s): %s", len(msg.Topics), strings.Join(msg.Topics, ", "))
 		}
+		cmds = append(cmds, func() tea.Msg {
+			return common.Info(text)
+		})
 
-		m.selectedSubscription = msg.SubscriptionName
-
-		// Update subscriptions panel with active subscription
-		m.subscriptions.SetActiveSubscription(msg.SubscriptionName)
-
-		// Update subscriber - pass message through Update to start spinner
-		var cmd tea.Cmd
-		m.subscriber, cmd = m.subscriber.Update(msg)
-		if cmd != nil {
-			cmds = append(cmds, cmd)
+	case common.SubscriptionSelectedMsg:
+		// Switching away from an active subscription drops its captured
+		// messages, so ask first
+		if m.selectedSubscription != "" && m.selectedSubscription != msg.SubscriptionName {
+			m.confirmDisconnect(msg)
+			break
 		}
+		cmds = append(cmds, m.connectSubscription(msg))
 
-		// Start subscription stream
-		cmd = m.startSubscription(msg.SubscriptionName, msg.TopicName)
-		if cmd != nil {
-			cmds = append(cmds, cmd)
+	case common.ConfirmDisconnectMsg:
+		if !msg.Confirmed {
+			cmds = append(cmds, func() tea.Msg {
+				return common.Info(fmt.Sprintf("Kept subscription: %s", m.selectedSubscription))
+			})
+			break
 		}
-
-		cmds = append(cmds, func() tea.Msg {
-			return common.Network(fmt.Sprintf("Started subscription: %s", msg.SubscriptionName))
-		})
+		cmds = append(cmds, m.connectSubscription(common.SubscriptionSelectedMsg{
+			SubscriptionName: msg.NewSubscriptionName,
+			SubscriptionFull: msg.NewSubscriptionFull,
+			TopicName:        msg.NewTopicName,
+		}))
 
 	case common.StopSubscriptionMsg:
 		// Stop the active subscription
 		subName := m.selectedSubscription
 		m.stopSubscription()
 		m.selectedSubscription = ""
+		m.sessionAcks = 0
+		m.reconnectAttempts = 0
 
 		// Notify both panels
 		m.subscriptions.SetActiveSubscription("")
+		m.topics.SetActiveSubscription("")
 		m.subscriber.ClearSubscription()
+		m.syncSnapshot()
 
 		if subName != "" {
 			cmds = append(cmds, func() tea.Msg {
@@ -199,6 +295,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return common.Network(fmt.Sprintf("Deleting topic: %s", msg.TopicName))
 		})
 
+	case topics.BulkDeleteTopicsMsg:
+		if m.bulk != nil {
+			cmds = append(cmds, bulkInProgress)
+			break
+		}
+
+		m.bulk = m.newBulkTopicDelete(msg.TopicNames, msg.WithSubscriptions)
+		count := m.bulk.total
+		cmds = append(cmds, func() tea.Msg {
+			if msg.WithSubscriptions {
+				return common.Network(fmt.Sprintf("Bulk deleting %d topics with their subscriptions (%d deletions)", len(msg.TopicNames), count))
+			}
+			return common.Network(fmt.Sprintf("Bulk deleting %d topics", len(msg.TopicNames)))
+		})
+		cmds = append(cmds, m.nextBulkDelete())
+
 	case common.TopicCreatedMsg:
 		var cmd tea.Cmd
 		m.topics, cmd = m.topics.Update(msg)
@@ -211,7 +323,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		} else {
 			cmds = append(cmds, func() tea.Msg {
-				return common.Error(fmt.Sprintf("Failed to create topic: %v", msg.Err))
+				return common.ErrorLog("Failed to create topic", msg.Err)
 			})
 		}
 
@@ -227,17 +339,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		} else {
 			cmds = append(cmds, func() tea.Msg {
-				return common.Error(fmt.Sprintf("Failed to delete topic: %v", msg.Err))
+				return common.ErrorLog("Failed to delete topic", msg.Err)
 			})
 		}
+		if m.bulk != nil && m.bulk.recordBulkResult(msg.TopicName, true, msg.Err) {
+			cmds = append(cmds, m.nextBulkDelete())
+		} else if msg.Err == nil {
+			m.lastDeleted = &deletedResource{topic: msg.TopicName}
+		}
 
 	// Subscription CRUD messages
 	case subscriptions.CreateSubscriptionMsg:
-		cmds = append(cmds, m.createSubscription(msg.SubscriptionName, msg.TopicName))
+		cmds = append(cmds, m.createSubscription(msg.SubscriptionName, msg.TopicName, msg.Filter))
 		cmds = append(cmds, func() tea.Msg {
+			if msg.Filter != "" {
+				return common.Network(fmt.Sprintf("Creating subscription: %s (filter: %s)", msg.SubscriptionName, msg.Filter))
+			}
 			return common.Network(fmt.Sprintf("Creating subscription: %s", msg.SubscriptionName))
 		})
 
+	case subscriptions.BulkDeleteSubscriptionsMsg:
+		if m.bulk != nil {
+			cmds = append(cmds, bulkInProgress)
+			break
+		}
+
+		m.bulk = newBulkSubscriptionDelete(msg.SubscriptionNames)
+		cmds = append(cmds, func() tea.Msg {
+			return common.Network(fmt.Sprintf("Bulk deleting %d subscriptions", len(msg.SubscriptionNames)))
+		})
+		cmds = append(cmds, m.nextBulkDelete())
+
 	case subscriptions.DeleteSubscriptionMsg:
 		cmds = append(cmds, m.deleteSubscription(msg.SubscriptionName))
 		cmds = append(cmds, func() tea.Msg {
@@ -256,7 +388,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		} else {
 			cmds = append(cmds, func() tea.Msg {
-				return common.Error(fmt.Sprintf("Failed to create subscription: %v", msg.Err))
+				return common.ErrorLog("Failed to create subscription", msg.Err)
 			})
 		}
 
@@ -272,11 +404,101 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		} else {
 			cmds = append(cmds, func() tea.Msg {
-				return common.Error(fmt.Sprintf("Failed to delete subscription: %v", msg.Err))
+				return common.ErrorLog("Failed to delete subscription", msg.Err)
+			})
+		}
+		if m.bulk != nil && m.bulk.recordBulkResult(msg.SubscriptionName, false, msg.Err) {
+			cmds = append(cmds, m.nextBulkDelete())
+		} else {
+			cmds = append(cmds, m.rememberDeletedSubscription(msg))
+		}
+
+	// Snapshot messages
+	case subscriptions.LoadSnapshotsMsg:
+		cmds = append(cmds, m.loadSnapshots())
+
+	case subscriptions.CreateSnapshotMsg:
+		cmds = append(cmds, m.createSnapshot(msg.SnapshotName, msg.SubscriptionName))
+		cmds = append(cmds, func() tea.Msg {
+			return common.Network(fmt.Sprintf("Creating snapshot %s of subscription %s", msg.SnapshotName, msg.SubscriptionName))
+		})
+
+	case subscriptions.DeleteSnapshotMsg:
+		cmds = append(cmds, m.deleteSnapshot(msg.SnapshotName))
+		cmds = append(cmds, func() tea.Msg {
+			return common.Network(fmt.Sprintf("Deleting snapshot: %s", msg.SnapshotName))
+		})
+
+	case subscriptions.SeekSnapshotMsg:
+		cmds = append(cmds, m.seekToSnapshot(msg.SubscriptionName, msg.SnapshotName))
+		cmds = append(cmds, func() tea.Msg {
+			return common.Network(fmt.Sprintf("Seeking subscription %s to snapshot %s", msg.SubscriptionName, msg.SnapshotName))
+		})
+
+	case common.SnapshotSeekedMsg:
+		var cmd tea.Cmd
+		m.subscriptions, cmd = m.subscriptions.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if msg.Err == nil {
+			cmds = append(cmds, func() tea.Msg {
+				return common.Success(fmt.Sprintf("Seeked subscription %s to snapshot %s", msg.SubscriptionName, msg.SnapshotName))
+			})
+		} else {
+			cmds = append(cmds, func() tea.Msg {
+				return common.ErrorLog(fmt.Sprintf("Failed to seek %s to snapshot %s", msg.SubscriptionName, msg.SnapshotName), msg.Err)
+			})
+		}
+
+	case common.SnapshotsLoadedMsg:
+		var cmd tea.Cmd
+		m.subscriptions, cmd = m.subscriptions.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if msg.Err != nil {
+			cmds = append(cmds, func() tea.Msg {
+				return common.ErrorLog("Failed to list snapshots", msg.Err)
+			})
+		}
+
+	case common.SnapshotCreatedMsg:
+		var cmd tea.Cmd
+		m.subscriptions, cmd = m.subscriptions.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if msg.Err == nil {
+			cmds = append(cmds, func() tea.Msg {
+				return common.Success(fmt.Sprintf("Created snapshot %s of subscription %s", msg.SnapshotName, msg.SubscriptionName))
+			})
+		} else {
+			cmds = append(cmds, func() tea.Msg {
+				return common.ErrorLog("Failed to create snapshot", msg.Err)
+			})
+		}
+
+	case common.SnapshotDeletedMsg:
+		var cmd tea.Cmd
+		m.subscriptions, cmd = m.subscriptions.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if msg.Err == nil {
+			cmds = append(cmds, func() tea.Msg {
+				return common.Success(fmt.Sprintf("Deleted snapshot: %s", msg.SnapshotName))
+			})
+		} else {
+			cmds = append(cmds, func() tea.Msg {
+				return common.ErrorLog("Failed to delete snapshot", msg.Err)
 			})
 		}
 
 	// Refresh messages
+	case paletteKeyMsg:
+		return m.runPaletteKey(msg)
+
 	case common.RefreshTopicsMsg:
 		cmds = append(cmds, m.loadTopics())
 
@@ -304,6 +526,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case publisher.FileSavedMsg:
+		var cmd tea.Cmd
+		m.publisher, cmd = m.publisher.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case publisher.FileWatchErrorMsg:
 		var cmd tea.Cmd
 		m.publisher, cmd = m.publisher.Update(msg)
@@ -311,12 +540,141 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case publisher.QuickPublishRequestMsg:
+		m.dialog.ShowForm(
+			publisher.QuickPublishDialogID,
+			"Quick Publish",
+			"Publish to topic: "+msg.Topic,
+			publisher.QuickPublishFields(),
+			func(values []string) error {
+				_, _, err := publisher.ParseQuickPublish(values)
+				return err
+			},
+			msg.Topic,
+		)
+
+	case ClientReconnectedMsg:
+		cmds = append(cmds, m.handleReconnected(msg))
+
+	case subscriber.AutoAckConfirmRequestMsg:
+		m.confirmAutoAck(msg)
+
+	case dialog.ResultMsg:
+		cmds = append(cmds, m.handleDialogResult(msg))
+
+	case publisher.SchedulePublishMsg:
+		id, cmd := m.schedulePublish(msg)
+		cmds = append(cmds, cmd)
+		cmds = append(cmds, func() tea.Msg {
+			return common.Info(fmt.Sprintf("Scheduled publish #%d in %ds to topic: %s", id, int(msg.Delay.Seconds()), msg.Topic))
+		})
+
+	case ScheduledPublishDueMsg:
+		scheduled, ok := m.scheduled[msg.ID]
+		if !ok {
+			// Cancelled
+			break
+		}
+		delete(m.scheduled, msg.ID)
+		m.publisher.SetScheduledCount(len(m.scheduled))
+
+		cmds = append(cmds, func() tea.Msg {
+			return common.Network(fmt.Sprintf("Publishing scheduled message #%d to topic: %s", msg.ID, scheduled.topic))
+		})
+		cmds = append(cmds, func() tea.Msg {
+			return publisher.PublishRequestMsg{
+				Topic:       scheduled.topic,
+				Content:     scheduled.content,
+				Attributes:  scheduled.attributes,
+				OrderingKey: scheduled.orderingKey,
+			}
+		})
+
+	case publisher.CancelPublishMsg:
+		cmds = append(cmds, m.cancelPublishes())
+
 	case publisher.PublishRequestMsg:
 		// Execute publish
-		cmd := m.publishMessage(msg.Topic, msg.Content)
+		cmd := m.publishMessage(msg.Topic, msg.Content, msg.Attributes, msg.OrderingKey)
 		cmds = append(cmds, cmd)
 
+	case publishRetryMsg:
+		cmds = append(cmds, m.retryPublish(msg))
+
+	case publishAttemptMsg:
+		cmds = append(cmds, m.publishAttempt(msg.req))
+
+	case publisher.FanOutPublishMsg:
+		topics := msg.Topics
+		cmds = append(cmds,
+			func() tea.Msg {
+				return common.Network(fmt.Sprintf("Publishing to %d topic(s): %s", len(topics), strings.Join(topics, ", ")))
+			},
+			m.publishFanOut(msg.Topics, msg.Content),
+		)
+
+	case publisher.FanOutPublishResultMsg:
+		for _, result := range msg.Results {
+			if result.Err == nil {
+				m.options.Metrics.IncPublished()
+			}
+		}
+		var cmd tea.Cmd
+		m.publisher, cmd = m.publisher.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case publisher.BatchPublishMsg:
+		topic, count := msg.Topic, msg.Count
+		cmds = append(cmds,
+			func() tea.Msg {
+				return common.Network(fmt.Sprintf("Publishing %d messages to topic: %s", count, topic))
+			},
+			m.publishBatch(msg.Topic, msg.Content, msg.Count),
+		)
+
+	case publisher.BatchPublishResultMsg:
+		for i := 0; i < msg.Published; i++ {
+			m.options.Metrics.IncPublished()
+		}
+		var cmd tea.Cmd
+		m.publisher, cmd = m.publisher.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case subscriber.RepublishRequestMsg:
+		// Bridge the subscriber selection to the publish path
+		if m.selectedTopic == "" {
+			cmds = append(cmds, func() tea.Msg {
+				return common.Error("Republish failed: select a target topic first")
+			})
+			break
+		}
+
+		topic := m.selectedTopic
+		received := msg.Message
+		m.publisher.SetPublishing(true)
+		m.publisher.SetProgress("Republishing...")
+		cmds = append(cmds,
+			func() tea.Msg {
+				return common.Network(fmt.Sprintf("Republishing message %s to topic: %s", received.ID, topic))
+			},
+			func() tea.Msg {
+				return publisher.PublishRequestMsg{
+					Topic:       topic,
+					Content:     received.Data,
+					Attributes:  received.Attributes,
+					OrderingKey: received.OrderingKey,
+				}
+			},
+		)
+
 	case publisher.PublishResultMsg:
+		if msg.Err == nil {
+			m.options.Metrics.IncPublished()
+		}
 		var cmd tea.Cmd
 		m.publisher, cmd = m.publisher.Update(msg)
 		if cmd != nil {
@@ -324,6 +682,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case subscriber.MessageReceivedMsg:
+		m.options.Metrics.IncReceived(m.subscriber.SubscriptionName())
 		var cmd tea.Cmd
 		m.subscriber, cmd = m.subscriber.Update(msg)
 		if cmd != nil {
@@ -332,8 +691,88 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Continue polling for messages
 		if m.activeSubscription != nil {
+			m.reconnectAttempts = 0
+			m.subscriber.SetDropped(m.activeSubscription.Dropped())
 			cmds = append(cmds, m.pollMessages())
 		}
+		m.syncSnapshot()
+
+	case subscriber.MessageDumpedMsg:
+		var cmd tea.Cmd
+		m.subscriber, cmd = m.subscriber.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case subscriber.TopicSchemaMsg:
+		var cmd tea.Cmd
+		m.subscriber, cmd = m.subscriber.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case SubscriptionDrainedMsg:
+		m.stopSubscription()
+		if msg.Unacked > 0 {
+			m.exitSummary = fmt.Sprintf("%d message(s) on %s were left unacked and will be redelivered", msg.Unacked, msg.SubscriptionName)
+		}
+		return m, tea.Quit
+
+	case subscriber.SetMaxOutstandingMsg:
+		old := m.options.ReceiveConfig.MaxOutstandingMessages
+		m.options.ReceiveConfig.MaxOutstandingMessages = msg.Messages
+		m.subscriber.SetMaxOutstanding(msg.Messages)
+
+		// Flow control can't change mid-receive, so restart the stream;
+		// messages already shown stay in the subscriber panel
+		subName := m.selectedSubscription
+		if m.activeSubscription == nil || subName == "" {
+			cmds = append(cmds, func() tea.Msg {
+				return common.Info(fmt.Sprintf("Max outstanding messages: %d -> %d (applies to the next subscription)", old, msg.Messages))
+			})
+			break
+		}
+		m.reconnectAttempts = 0
+		cmds = append(cmds,
+			m.startSubscription(subName, m.subscriber.TopicName()),
+			func() tea.Msg {
+				return common.Network(fmt.Sprintf("Max outstanding messages: %d -> %d, restarted subscription: %s", old, msg.Messages, subName))
+			},
+		)
+
+	case subscriber.MessageAckedMsg:
+		m.options.Metrics.IncAcked(msg.SubscriptionName)
+		if msg.SubscriptionName == m.selectedSubscription {
+			m.sessionAcks++
+		}
+
+	case common.DebounceMsg:
+		// Each panel applies only the messages its own debouncer scheduled
+		m.topics, _ = m.topics.Update(msg)
+		m.subscriptions, _ = m.subscriptions.Update(msg)
+		m.subscriber, _ = m.subscriber.Update(msg)
+
+	case HealthTickMsg:
+		if msg.Client == m.client {
+			cmds = append(cmds, m.checkHealth())
+		}
+
+	case HealthCheckedMsg:
+		cmds = append(cmds, m.handleHealthChecked(msg))
+
+	case common.StatusTickMsg:
+		m.topics.ExpireStatus(msg.Time)
+		m.subscriptions.ExpireStatus(msg.Time)
+		m.publisher.ExpireStatus(msg.Time)
+		m.subscriber.ExpireStatus(msg.Time)
+		cmds = append(cmds, common.StatusTick())
+
+	case subscriber.AgeTickMsg:
+		var cmd tea.Cmd
+		m.subscriber, cmd = m.subscriber.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 
 	case subscriber.SubscriptionErrorMsg:
 		var cmd tea.Cmd
@@ -342,7 +781,75 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+		subName := m.selectedSubscription
+		if subName == "" {
+			break
+		}
+
+		// Retry transient errors with backoff; give up on permanent ones
+		if !pubsub.IsTransientError(msg.Error) {
+			m.stopSubscription()
+			m.subscriber.SetError(msg.Error)
+			if pubsub.IsAuthError(msg.Error) {
+				// Offer to reconnect once the user has re-authenticated
+				reconnect := ReconnectSubscriptionMsg{
+					SubscriptionName: subName,
+					TopicName:        m.subscriber.TopicName(),
+					Attempt:          m.reconnectAttempts,
+				}
+				m.setAuthFailure(authFailure{
+					action: "receiving from " + subName,
+					err:    msg.Error,
+					retry: func() tea.Msg {
+						return reconnect
+					},
+				})
+				cmds = append(cmds, authWarning("receiving from "+subName))
+			}
+			break
+		}
+		if m.reconnectAttempts >= maxReconnectAttempts {
+			m.stopSubscription()
+			m.subscriber.SetError(msg.Error)
+			attempts := m.reconnectAttempts
+			cmds = append(cmds, func() tea.Msg {
+				return common.Error(fmt.Sprintf("Giving up on subscription %s after %d reconnect attempts", subName, attempts))
+			})
+			break
+		}
+
+		cmds = append(cmds, m.scheduleReconnect(subName, m.subscriber.TopicName()))
+		attempt := m.reconnectAttempts
+		delay := reconnectDelay(attempt)
+		cmds = append(cmds, func() tea.Msg {
+			return common.Warning(fmt.Sprintf("Reconnecting to %s in %s (attempt %d/%d)", subName, delay, attempt, maxReconnectAttempts))
+		})
+
+	case AuthErrorMsg:
+		m.setAuthFailure(authFailure{action: msg.Action, err: msg.Err, retry: msg.Retry})
+		cmds = append(cmds, authWarning(msg.Action))
+
+		// The result is still handled as usual, e.g. to log the error
+		next, cmd := m.Update(msg.Result)
+		m = next.(Model)
+		cmds = append(cmds, cmd)
+
+	case ReconnectSubscriptionMsg:
+		// Ignore stale reconnects if the user switched or stopped the subscription
+		if msg.SubscriptionName != m.selectedSubscription || msg.Attempt != m.reconnectAttempts {
+			break
+		}
+
+		cmds = append(cmds, m.startSubscription(msg.SubscriptionName, msg.TopicName))
+		cmds = append(cmds, func() tea.Msg {
+			return common.Network(fmt.Sprintf("Reconnecting subscription: %s (attempt %d/%d)", msg.SubscriptionName, msg.Attempt, maxReconnectAttempts))
+		})
+
 	case common.LogMsg:
+		debuglog.Printf("%s: %s", msg.Level, msg.Message)
+		if msg.Level == common.LogError {
+			m.options.Metrics.IncErrors()
+		}
 		var cmd tea.Cmd
 		m.activity, cmd = m.activity.Update(msg)
 		if cmd != nil {
@@ -390,53 +897,168 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleDialogKey routes a key press to the open dialog
+func (m *Model) handleDialogKey(msg tea.KeyMsg) tea.Cmd {
+	var result *dialog.ResultMsg
+	m.dialog, result = m.dialog.Update(msg.String(), msg.Type.String())
+	if result != nil {
+		res := *result
+		return func() tea.Msg {
+			return res
+		}
+	}
+
+	m.dialog.UpdateInput(msg)
+	return nil
+}
+
+// handleDialogResult acts on a completed dialog
+func (m *Model) handleDialogResult(msg dialog.ResultMsg) tea.Cmd {
+	// Disconnecting reports both answers so a refusal can be logged
+	if msg.ID == disconnectDialogID {
+		sel, _ := msg.Result.Context.(common.SubscriptionSelectedMsg)
+		return func() tea.Msg {
+			return common.ConfirmDisconnectMsg{
+				NewSubscriptionName: sel.SubscriptionName,
+				NewSubscriptionFull: sel.SubscriptionFull,
+				NewTopicName:        sel.TopicName,
+				Confirmed:           msg.Result.Confirmed,
+			}
+		}
+	}
+
+	if !msg.Result.Confirmed {
+		return nil
+	}
+
+	switch msg.ID {
+	case publisher.QuickPublishDialogID:
+		topic, _ := msg.Result.Context.(string)
+		data, attrs, err := publisher.ParseQuickPublish(msg.Result.Values)
+		if err != nil {
+			return func() tea.Msg {
+				return common.Error("Quick publish failed: " + err.Error())
+			}
+		}
+
+		m.publisher.SetPublishing(true)
+		m.publisher.SetProgress("Publishing...")
+		return tea.Batch(
+			func() tea.Msg {
+				return common.Network(fmt.Sprintf("Quick publishing to topic: %s", topic))
+			},
+			m.publishMessage(topic, data, attrs, ""),
+		)
+
+	case reconnectDialogID:
+		return m.reconnectTo(strings.TrimSpace(msg.Result.Values[0]))
+
+	case subscriber.AutoAckDialogID:
+		mode, _ := msg.Result.Context.(subscriber.AckMode)
+		m.subscriber.ConfirmAutoAck(mode)
+		return func() tea.Msg {
+			return common.Warning("Ack mode: " + mode.String() + "; received messages are acknowledged automatically")
+		}
+	}
+
+	return nil
+}
+
 // createTopic creates a new topic
 func (m *Model) createTopic(topicName string) tea.Cmd {
-	return func() tea.Msg {
+	return retryOnAuth("creating topic "+topicName, func() tea.Msg {
 		ctx := context.Background()
 		err := m.client.CreateTopic(ctx, topicName)
 		return common.TopicCreatedMsg{
 			TopicName: topicName,
 			Err:       err,
 		}
-	}
+	})
 }
 
 // deleteTopic deletes a topic
 func (m *Model) deleteTopic(topicName string) tea.Cmd {
-	return func() tea.Msg {
+	return retryOnAuth("deleting topic "+topicName, func() tea.Msg {
 		ctx := context.Background()
 		err := m.client.DeleteTopic(ctx, topicName)
 		return common.TopicDeletedMsg{
 			TopicName: topicName,
 			Err:       err,
 		}
-	}
+	})
 }
 
-// createSubscription creates a new subscription
-func (m *Model) createSubscription(subName, topicName string) tea.Cmd {
-	return func() tea.Msg {
+// createSubscription creates a new subscription, optionally with a message filter
+func (m *Model) createSubscription(subName, topicName, filter string) tea.Cmd {
+	return retryOnAuth("creating subscription "+subName, func() tea.Msg {
 		ctx := context.Background()
-		err := m.client.CreateSubscription(ctx, subName, topicName)
+		err := m.client.CreateSubscriptionWithFilter(ctx, subName, topicName, filter)
 		return common.SubscriptionCreatedMsg{
 			SubscriptionName: subName,
 			TopicName:        topicName,
 			Err:              err,
 		}
-	}
+	})
 }
 
 // deleteSubscription deletes a subscription
 func (m *Model) deleteSubscription(subName string) tea.Cmd {
-	return func() tea.Msg {
+	return retryOnAuth("deleting subscription "+subName, func() tea.Msg {
 		ctx := context.Background()
+
+		// Capture the config first so the delete can be undone
+		cfg, cfgErr := m.client.GetSubscriptionConfig(ctx, subName)
+
 		err := m.client.DeleteSubscription(ctx, subName)
-		return common.SubscriptionDeletedMsg{
+		msg := common.SubscriptionDeletedMsg{
 			SubscriptionName: subName,
 			Err:              err,
 		}
-	}
+		if cfgErr == nil {
+			msg.TopicName = cfg.TopicName
+			msg.Filter = cfg.Filter
+			msg.Config = cfg
+		}
+		return msg
+	})
+}
+
+// createSnapshot snapshots a subscription
+func (m *Model) createSnapshot(snapName, subName string) tea.Cmd {
+	return retryOnAuth("creating snapshot "+snapName, func() tea.Msg {
+		ctx := context.Background()
+		err := m.client.CreateSnapshot(ctx, snapName, subName)
+		return common.SnapshotCreatedMsg{
+			SnapshotName:     snapName,
+			SubscriptionName: subName,
+			Err:              err,
+		}
+	})
+}
+
+// seekToSnapshot seeks a subscription to a snapshot
+func (m *Model) seekToSnapshot(subName, snapName string) tea.Cmd {
+	return retryOnAuth("seeking "+subName+" to "+snapName, func() tea.Msg {
+		ctx := context.Background()
+		err := m.client.SeekToSnapshot(ctx, subName, snapName)
+		return common.SnapshotSeekedMsg{
+			SnapshotName:     snapName,
+			SubscriptionName: subName,
+			Err:              err,
+		}
+	})
+}
+
+// deleteSnapshot deletes a snapshot
+func (m *Model) deleteSnapshot(snapName string) tea.Cmd {
+	return retryOnAuth("deleting snapshot "+snapName, func() tea.Msg {
+		ctx := context.Background()
+		err := m.client.DeleteSnapshot(ctx, snapName)
+		return common.SnapshotDeletedMsg{
+			SnapshotName: snapName,
+			Err:          err,
+		}
+	})
 }
 
 // cycleFocus moves focus to the next panel
@@ -471,6 +1093,16 @@ func (m *Model) cycleFocusReverse() {
 
 // updateFocus updates the focused state of child components
 func (m *Model) updateFocus() {
+	// A zoomed layout resizes for the newly focused panel, which sets focus
+	if m.layout == layoutZoom && m.ready {
+		m.updateComponentSizes()
+		return
+	}
+	m.setPanelFocus()
+}
+
+// setPanelFocus tells each panel whether it has focus
+func (m *Model) setPanelFocus() {
 	m.topics.SetFocused(m.focus == FocusTopics)
 	m.subscriptions.SetFocused(m.focus == FocusSubscriptions)
 	m.publisher.SetFocused(m.focus == FocusPublisher)
@@ -513,23 +1145,35 @@ func (m *Model) routeToFocused(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+// Layout limits. Below the minimum terminal size the panels are clamped to
+// their minimums and overflow the screen, so View shows a notice instead.
+const (
+	minLeftWidth    = 25 // Topics, Subscriptions and Activity column
+	minRightWidth   = 30 // Publisher and Subscriber column
+	minPanelsHeight = 15 // Height of the stacked panels
+	footerHeight    = 2
+
+	minTerminalWidth  = minLeftWidth + minRightWidth
+	minTerminalHeight = minPanelsHeight + footerHeight
+)
+
 // updateComponentSizes recalculates and sets component sizes
 func (m *Model) updateComponentSizes() {
-	// Left panel: 1/3 width
-	// Right panel: 2/3 width
-	leftWidth := m.width / 3
-	if leftWidth < 25 {
-		leftWidth = 25
+	// Left panel: leftRatio of the width (1/3 by default)
+	// Right panel: the rest
+	leftWidth := int(float64(m.width) * clampLeftRatio(m.leftRatio))
+	if leftWidth < minLeftWidth {
+		leftWidth = minLeftWidth
 	}
 	rightWidth := m.width - leftWidth
-	if rightWidth < 30 {
-		rightWidth = 30
+	if rightWidth < minRightWidth {
+		rightWidth = minRightWidth
 	}
 
-	// Available height (minus footer: 2 lines)
-	availableHeight := m.height - 2
-	if availableHeight < 15 {
-		availableHeight = 15
+	// Available height (minus footer)
+	availableHeight := m.height - footerHeight - m.bannerHeight()
+	if availableHeight < minPanelsHeight {
+		availableHeight = minPanelsHeight
 	}
 
 	// Left panel heights: Topics 33%, Subscriptions 33%, Activity 34%
@@ -549,53 +1193,118 @@ func (m *Model) updateComponentSizes() {
 	m.publisher.SetSize(rightWidth, publisherHeight)
 	m.subscriber.SetSize(rightWidth, subscriberHeight)
 
+	// Zoomed: the focused panel takes the whole panel area
+	if m.layout == layoutZoom {
+		switch m.focus {
+		case FocusTopics:
+			m.topics.SetSize(m.width, availableHeight)
+		case FocusSubscriptions:
+			m.subscriptions.SetSize(m.width, availableHeight)
+		case FocusPublisher:
+			m.publisher.SetSize(m.width, availableHeight)
+		case FocusSubscriber:
+			m.subscriber.SetSize(m.width, availableHeight)
+		}
+	}
+
 	// Update focus state
-	m.updateFocus()
+	m.setPanelFocus()
 }
 
 // Key bindings
 type keyMap struct {
-	Quit     key.Binding
-	Tab      key.Binding
-	ShiftTab key.Binding
-	Panel1   key.Binding
-	Panel2   key.Binding
-	Panel3   key.Binding
-	Panel4   key.Binding
-	Help     key.Binding
+	Quit        key.Binding
+	Tab         key.Binding
+	ShiftTab    key.Binding
+	Panel1      key.Binding
+	Panel2      key.Binding
+	Panel3      key.Binding
+	Panel4      key.Binding
+	Help        key.Binding
+	Palette     key.Binding
+	Undo        key.Binding
+	CancelBulk  key.Binding
+	Reconnect   key.Binding
+	Narrow      key.Binding
+	Widen       key.Binding
+	Zoom        key.Binding
+	RetryAuth   key.Binding
+	DismissAuth key.Binding
 }
 
-var keys = keyMap{
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
-	Tab: key.NewBinding(
-		key.WithKeys("tab"),
-		key.WithHelp("tab", "next panel"),
-	),
-	ShiftTab: key.NewBinding(
-		key.WithKeys("shift+tab"),
-		key.WithHelp("shift+tab", "prev panel"),
-	),
-	Panel1: key.NewBinding(
-		key.WithKeys("1"),
-		key.WithHelp("1", "topics"),
-	),
-	Panel2: key.NewBinding(
-		key.WithKeys("2"),
-		key.WithHelp("2", "subscriptions"),
-	),
-	Panel3: key.NewBinding(
-		key.WithKeys("3"),
-		key.WithHelp("3", "publisher"),
-	),
-	Panel4: key.NewBinding(
-		key.WithKeys("4"),
-		key.WithHelp("4", "subscriber"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "help"),
-	),
+// defaultKeys returns the built-in key bindings
+func defaultKeys() keyMap {
+	return keyMap{
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q/ctrl+c", "Quit (waits briefly for acks; again to force)"),
+		),
+		Tab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "Cycle focus forward"),
+		),
+		ShiftTab: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "Cycle focus backward"),
+		),
+		Panel1: key.NewBinding(
+			key.WithKeys("1"),
+			key.WithHelp("1", "Jump to Topics panel"),
+		),
+		Panel2: key.NewBinding(
+			key.WithKeys("2"),
+			key.WithHelp("2", "Jump to Subscriptions panel"),
+		),
+		Panel3: key.NewBinding(
+			key.WithKeys("3"),
+			key.WithHelp("3", "Jump to Publisher panel"),
+		),
+		Panel4: key.NewBinding(
+			key.WithKeys("4"),
+			key.WithHelp("4", "Jump to Subscriber panel"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "Show this help (↑↓ PgUp/PgDn scroll, esc/q close)"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys(":", "ctrl+p"),
+			key.WithHelp(":/ctrl+p", "Command palette: search and run actions"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "Undo the last delete (recreates the topic/sub)"),
+		),
+		CancelBulk: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "Cancel the bulk delete in progress"),
+		),
+		Reconnect: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "Reconnect (asks for the emulator host)"),
+		),
+		Narrow: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "Narrow the left column"),
+		),
+		Widen: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "Widen the left column"),
+		),
+		Zoom: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", "Zoom: show only the focused panel (again to show all)"),
+		),
+		RetryAuth: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "Retry the operation that failed to authenticate"),
+		),
+		DismissAuth: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "Dismiss the re-authentication banner"),
+		),
+	}
 }
+
+// keys are the active global key bindings, see LoadKeyConfig
+var keys = defaultKeys()
@@ -0,0 +1,418 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/components/dialog"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestModel_ConfirmDisconnect(t *testing.T) {
+	m := newTestModel()
+	m.dialog = dialog.New()
+	m.selectedSubscription = "orders-sub"
+
+	sel := common.SubscriptionSelectedMsg{SubscriptionName: "events-sub", TopicName: "events"}
+	m = update(t, m, sel)
+	if !m.dialog.IsVisible() {
+		t.Fatal("selecting another subscription while one is active should ask first")
+	}
+	if m.selectedSubscription != "orders-sub" {
+		t.Fatalf("selectedSubscription = %q before confirming, want orders-sub", m.selectedSubscription)
+	}
+
+	// y reports a confirmation carrying the new subscription
+	cmd := m.handleDialogKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	result, ok := cmd().(dialog.ResultMsg)
+	if !ok {
+		t.Fatal("y should complete the dialog")
+	}
+	got, ok := m.handleDialogResult(result)().(common.ConfirmDisconnectMsg)
+	want := common.ConfirmDisconnectMsg{NewSubscriptionName: "events-sub", NewTopicName: "events", Confirmed: true}
+	if !ok || got != want {
+		t.Errorf("confirm result = %+v, want %+v", got, want)
+	}
+
+	// n keeps the active subscription
+	m = update(t, m, sel)
+	cmd = m.handleDialogKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	result = cmd().(dialog.ResultMsg)
+	got, _ = m.handleDialogResult(result)().(common.ConfirmDisconnectMsg)
+	if got.Confirmed {
+		t.Error("n should refuse the switch")
+	}
+	m = update(t, m, got)
+	if m.selectedSubscription != "orders-sub" || m.dialog.IsVisible() {
+		t.Errorf("selectedSubscription = %q after refusing, want orders-sub kept", m.selectedSubscription)
+	}
+}
+
+func TestModel_ConfirmAutoAck(t *testing.T) {
+	m := newTestModel()
+	m.dialog = dialog.New()
+
+	m = update(t, m, subscriber.AutoAckConfirmRequestMsg{Subscription: "orders-sub", Mode: subscriber.AckImmediate})
+	if !m.dialog.IsVisible() {
+		t.Fatal("an auto-ack request should ask first")
+	}
+
+	cmd := m.handleDialogKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = update(t, m, cmd())
+	if m.subscriber.AckMode() != subscriber.AckImmediate {
+		t.Errorf("AckMode() = %v after confirming, want on receive", m.subscriber.AckMode())
+	}
+}
+
+func TestModel_ResizeOverlays(t *testing.T) {
+	m := newTestModel()
+	m.dialog = dialog.New()
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// Help resized while open fits the new terminal
+	m.openHelp()
+	for _, size := range []tea.WindowSizeMsg{{Width: 50, Height: 20}, {Width: 100, Height: 30}} {
+		m = update(t, m, size)
+		view := m.View()
+		if w, h := lipgloss.Width(view), lipgloss.Height(view); w > size.Width || h > size.Height {
+			t.Errorf("help at %dx%d renders %dx%d", size.Width, size.Height, w, h)
+		}
+	}
+	m = update(t, m, tea.WindowSizeMsg{Width: 15, Height: 4})
+	if view := m.View(); !strings.Contains(strings.Join(strings.Fields(view), " "), "too small") {
+		t.Errorf("help on a tiny terminal = %q, want a too-small notice", view)
+	}
+	m.showHelp = false
+
+	// So does a dialog
+	m.dialog.ShowInput("test", "Create Topic", "Enter a name for the new topic", "name", nil)
+	for _, size := range []tea.WindowSizeMsg{{Width: 40, Height: 20}, {Width: 120, Height: 40}} {
+		m = update(t, m, size)
+		view := m.View()
+		if w, h := lipgloss.Width(view), lipgloss.Height(view); w > size.Width || h > size.Height {
+			t.Errorf("dialog at %dx%d renders %dx%d", size.Width, size.Height, w, h)
+		}
+		if !strings.Contains(view, "Create Topic") {
+			t.Errorf("dialog at %dx%d is not shown", size.Width, size.Height)
+		}
+	}
+	m = update(t, m, tea.WindowSizeMsg{Width: 25, Height: 8})
+	if view := m.View(); !strings.Contains(strings.Join(strings.Fields(view), " "), "too small") {
+		t.Errorf("dialog on a tiny terminal = %q, want a too-small notice", view)
+	}
+}
+
+func TestModel_View_TooSmall(t *testing.T) {
+	m := newTestModel()
+	notice := "Terminal too small"
+
+	for _, size := range []tea.WindowSizeMsg{
+		{Width: minTerminalWidth - 1, Height: 40},
+		{Width: 120, Height: minTerminalHeight - 1},
+	} {
+		m = update(t, m, size)
+		view := m.View()
+		if !strings.Contains(strings.Join(strings.Fields(view), " "), notice) {
+			t.Errorf("View() at %dx%d should show the too-small notice", size.Width, size.Height)
+		}
+		if h := lipgloss.Height(view); h > size.Height {
+			t.Errorf("notice at %dx%d is %d lines high", size.Width, size.Height, h)
+		}
+	}
+
+	// Enlarging resumes the normal layout
+	m = update(t, m, tea.WindowSizeMsg{Width: minTerminalWidth, Height: minTerminalHeight})
+	if view := m.View(); strings.Contains(view, notice) || !strings.Contains(view, "Topics") {
+		t.Error("View() at the minimum size should show the panels")
+	}
+}
+
+func TestModel_FanOutPublish(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusTopics
+	m.topics.SetFocused(true)
+	m = update(t, m, common.TopicsLoadedMsg{Topics: []common.TopicData{{Name: "orders"}, {Name: "billing"}, {Name: "audit"}}})
+
+	// Mark orders and audit; the marks reach the publisher
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	for _, key := range []tea.KeyMsg{space, {Type: tea.KeyDown}, {Type: tea.KeyDown}, space} {
+		next, cmd := m.Update(key)
+		m = next.(Model)
+		for _, msg := range cmdMsgs(cmd) {
+			if marked, ok := msg.(common.TopicsMarkedMsg); ok {
+				m = update(t, m, marked)
+			}
+		}
+	}
+	if got := strings.Join(m.publisher.MarkedTopics(), ","); got != "orders,audit" {
+		t.Fatalf("publisher marked topics = %q, want orders,audit", got)
+	}
+
+	// Each topic's result is logged; a failure is reported in the status
+	_, cmd := m.publisher.Update(publisher.FanOutPublishResultMsg{Results: []publisher.TopicPublishResult{
+		{Topic: "orders", MessageID: "1"},
+		{Topic: "audit", Err: errors.New("not found")},
+	}})
+	var logged []string
+	for _, msg := range cmdMsgs(cmd) {
+		if entry, ok := msg.(common.LogMsg); ok {
+			logged = append(logged, entry.Message)
+		}
+	}
+	if len(logged) != 3 || !strings.Contains(logged[0], "orders") || !strings.Contains(logged[1], "audit") {
+		t.Errorf("logged %q, want a result per topic and a summary", logged)
+	}
+
+	// Deleted topics lose their marks
+	m = update(t, m, common.TopicsLoadedMsg{Topics: []common.TopicData{{Name: "orders"}, {Name: "billing"}}})
+	if got := strings.Join(m.publisher.MarkedTopics(), ","); got != "orders" {
+		t.Errorf("publisher marked topics after reload = %q, want orders", got)
+	}
+}
+
+// cmdMsgs runs a command and returns its messages, running the commands of
+// a tea.Batch or tea.Sequence in order
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	cmds := reflect.ValueOf(msg)
+	if cmds.Kind() != reflect.Slice {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for i := 0; i < cmds.Len(); i++ {
+		if c, ok := cmds.Index(i).Interface().(tea.Cmd); ok {
+			msgs = append(msgs, cmdMsgs(c)...)
+		}
+	}
+	return msgs
+}
+
+func TestModel_ScheduledPublish(t *testing.T) {
+	m := newTestModel()
+	m.scheduled = make(map[int]scheduledPublish)
+
+	id, _ := m.schedulePublish(publisher.SchedulePublishMsg{
+		Topic:       "orders",
+		Content:     []byte(`{"id":1}`),
+		Attributes:  map[string]string{"type": "order"},
+		OrderingKey: "customer-1",
+		Delay:       time.Minute,
+	})
+	if m.publisher.ScheduledCount() != 1 {
+		t.Fatalf("ScheduledCount() = %d, want 1", m.publisher.ScheduledCount())
+	}
+
+	// The due publish keeps the attributes and ordering key
+	next, cmd := m.Update(ScheduledPublishDueMsg{ID: id})
+	m = next.(Model)
+	var req *publisher.PublishRequestMsg
+	for _, msg := range cmdMsgs(cmd) {
+		if r, ok := msg.(publisher.PublishRequestMsg); ok {
+			req = &r
+		}
+	}
+	want := publisher.PublishRequestMsg{
+		Topic:       "orders",
+		Content:     []byte(`{"id":1}`),
+		Attributes:  map[string]string{"type": "order"},
+		OrderingKey: "customer-1",
+	}
+	if req == nil || !reflect.DeepEqual(*req, want) {
+		t.Errorf("publish request = %+v, want %+v", req, want)
+	}
+	if m.publisher.ScheduledCount() != 0 {
+		t.Errorf("ScheduledCount() = %d after publishing, want 0", m.publisher.ScheduledCount())
+	}
+}
+
+func TestModel_Republish(t *testing.T) {
+	m := newTestModel()
+	received := &pubsub.ReceivedMessage{
+		ID:          "msg-1",
+		Data:        []byte(`{"id":1}`),
+		Attributes:  map[string]string{"type": "order"},
+		OrderingKey: "customer-1",
+	}
+
+	// Without a selected topic there is nowhere to publish
+	next, cmd := m.Update(subscriber.RepublishRequestMsg{Message: received})
+	m = next.(Model)
+	msgs := cmdMsgs(cmd)
+	if len(msgs) != 1 {
+		t.Fatalf("republish without a topic sent %#v, want an error", msgs)
+	}
+	if log, ok := msgs[0].(common.LogMsg); !ok || log.Level != common.LogError {
+		t.Errorf("republish without a topic sent %#v, want an error", msgs[0])
+	}
+
+	// The data, attributes and ordering key go to the selected topic
+	m.selectedTopic = "audit"
+	next, cmd = m.Update(subscriber.RepublishRequestMsg{Message: received})
+	m = next.(Model)
+	var req *publisher.PublishRequestMsg
+	for _, msg := range cmdMsgs(cmd) {
+		if r, ok := msg.(publisher.PublishRequestMsg); ok {
+			req = &r
+		}
+	}
+	want := publisher.PublishRequestMsg{
+		Topic:       "audit",
+		Content:     []byte(`{"id":1}`),
+		Attributes:  map[string]string{"type": "order"},
+		OrderingKey: "customer-1",
+	}
+	if req == nil || !reflect.DeepEqual(*req, want) {
+		t.Errorf("publish request = %+v, want %+v", req, want)
+	}
+
+	// The result logs the new message ID
+	next, cmd = m.Update(publisher.PublishResultMsg{Topic: "audit", MessageID: "msg-2", Content: received.Data})
+	m = next.(Model)
+	logged := false
+	for _, msg := range cmdMsgs(cmd) {
+		if log, ok := msg.(common.LogMsg); ok && strings.Contains(log.Message, "msg-2") {
+			logged = true
+		}
+	}
+	if !logged {
+		t.Error("the republish result should log the new message ID")
+	}
+}
+
+func TestModel_HelpKeyWhileTyping(t *testing.T) {
+	m := newTestModel()
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.focus = FocusTopics
+	question := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
+
+	// ? in a filter opens the regex examples, not the help overlay
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = update(t, m, question)
+	if m.showHelp || !strings.Contains(m.topics.View(), "Go regular expressions") {
+		t.Error("? in an empty filter should show the regex examples")
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc}) // Closes the examples
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc}) // Leaves the filter
+	m = update(t, m, question)
+	if !m.showHelp {
+		t.Error("? outside an input should open the help overlay")
+	}
+}
+
+func TestModel_SessionAcks(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusSubscriber
+	m.subscriber.SetSize(100, 40)
+	m.subscriber.SetFocused(true)
+	m.selectedSubscription = "orders-sub"
+	m.subscriber.SetSubscription("orders-sub", "orders")
+
+	// send updates the model and feeds back the acks it reports
+	send := func(msg tea.Msg) {
+		t.Helper()
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		for _, out := range cmdMsgs(cmd) {
+			if acked, ok := out.(subscriber.MessageAckedMsg); ok {
+				m = update(t, m, acked)
+			}
+		}
+	}
+	receive := func(id string) {
+		t.Helper()
+		send(subscriber.MessageReceivedMsg{Message: pubsub.NewReceivedMessage(id, []byte(`{}`), func() {})})
+	}
+
+	// Manual acks
+	receive("msg-1")
+	receive("msg-2")
+	receive("msg-3")
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if m.sessionAcks != 1 {
+		t.Fatalf("sessionAcks = %d after a manual ack, want 1", m.sessionAcks)
+	}
+
+	// Acking every displayed message counts each one
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	if m.sessionAcks != 3 {
+		t.Fatalf("sessionAcks = %d after acking the rest, want 3", m.sessionAcks)
+	}
+
+	// Auto-ack counts on receive
+	m.subscriber.SetAckMode(subscriber.AckImmediate)
+	receive("msg-4")
+	if m.sessionAcks != 4 {
+		t.Fatalf("sessionAcks = %d after an auto-ack, want 4", m.sessionAcks)
+	}
+	m.width = 200
+	if footer := m.renderFooter(); !strings.Contains(footer, "acked: 4") {
+		t.Errorf("footer %q should show the acks", footer)
+	}
+
+	// Acks reported for another subscription are not counted, and
+	// stopping resets the count
+	send(subscriber.MessageAckedMsg{SubscriptionName: "events-sub", MessageID: "x"})
+	if m.sessionAcks != 4 {
+		t.Errorf("sessionAcks = %d, want acks of other subscriptions ignored", m.sessionAcks)
+	}
+	send(common.StopSubscriptionMsg{})
+	if m.sessionAcks != 0 {
+		t.Errorf("sessionAcks = %d after stopping, want 0", m.sessionAcks)
+	}
+}
+
+func TestModel_ReconnectRedelivery(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusSubscriber
+	m.subscriber.SetSize(100, 40)
+	m.subscriber.SetFocused(true)
+	m.selectedSubscription = "orders-sub"
+	m.subscriber.SetSubscription("orders-sub", "orders")
+
+	var acked []string
+	receive := func(id, handle string) {
+		t.Helper()
+		m = update(t, m, subscriber.MessageReceivedMsg{Message: pubsub.NewReceivedMessage(id, []byte(`{}`), func() {
+			acked = append(acked, handle)
+		})})
+	}
+	receive("msg-1", "old")
+	receive("msg-2", "old")
+
+	// A dropped stream is reconnected automatically, keeping the messages
+	m = update(t, m, subscriber.SubscriptionErrorMsg{Error: status.Error(codes.Unavailable, "stream reset")})
+	if m.reconnectAttempts != 1 || m.subscriber.MessageCount() != 2 {
+		t.Fatalf("reconnectAttempts = %d, messages = %d; want a reconnect keeping 2 messages", m.reconnectAttempts, m.subscriber.MessageCount())
+	}
+
+	// Pub/Sub redelivers the unacked message, which replaces its row
+	receive("msg-1", "new")
+	if m.subscriber.MessageCount() != 2 {
+		t.Fatalf("MessageCount() = %d after redelivery, want 2", m.subscriber.MessageCount())
+	}
+
+	// Acking the row uses the handle from the new stream
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if sel := m.subscriber.SelectedMessage(); sel == nil || sel.ID != "msg-1" {
+		t.Fatalf("selected %+v, want msg-1", sel)
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if strings.Join(acked, ",") != "new" {
+		t.Errorf("acked handles = %v, want the new one", acked)
+	}
+}
@@ -1,7 +1,7 @@
 package app
 
 import (
-	"strings"
+	"fmt"
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 
@@ -14,6 +14,11 @@ func (m Model) View() string {
 		return "Initializing..."
 	}
 
+	// Overlays size themselves; the panels need a minimum terminal size
+	if (m.width < minTerminalWidth || m.height < minTerminalHeight) && !m.dialog.IsVisible() && !m.palette.IsVisible() && !m.showHelp {
+		return common.TooSmallView(m.width, m.height, minTerminalWidth, minTerminalHeight)
+	}
+
 	// Build left panel (Topics, Subscriptions, Activity stacked vertically)
 	leftPanel := lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -35,16 +40,28 @@ func (m Model) View() string {
 		leftPanel,
 		rightPanel,
 	)
+	if m.layout == layoutZoom {
+		mainContent = m.focusedView()
+	}
 
 	// Build footer
 	footer := m.renderFooter()
 
-	// Combine main content and footer
-	baseView := lipgloss.JoinVertical(
-		lipgloss.Left,
-		mainContent,
-		footer,
-	)
+	// Combine main content and footer, with the re-auth banner between them
+	sections := []string{mainContent}
+	if m.authFailure != nil {
+		sections = append(sections, m.renderAuthBanner())
+	}
+	baseView := lipgloss.JoinVertical(lipgloss.Left, append(sections, footer)...)
+
+	// Show an open dialog on top of everything else
+	if m.dialog.IsVisible() {
+		return m.dialog.View()
+	}
+
+	if m.palette.IsVisible() {
+		return m.palette.View()
+	}
 
 	// Show help popup as overlay if active
 	if m.showHelp {
@@ -54,60 +71,98 @@ func (m Model) View() string {
 	return baseView
 }
 
-// renderFooter renders the application footer with dynamic shortcuts based on focused panel
+// focusedView renders the focused panel alone, for the zoomed layout
+func (m Model) focusedView() string {
+	switch m.focus {
+	case FocusSubscriptions:
+		return m.subscriptions.View()
+	case FocusPublisher:
+		return m.publisher.View()
+	case FocusSubscriber:
+		return m.subscriber.View()
+	}
+	return m.topics.View()
+}
+
+// renderFooter renders the application footer with dynamic shortcuts based on
+// focused panel. On a narrow terminal low-priority segments are dropped
+// first; the help overlay lists them.
 func (m Model) renderFooter() string {
-	var parts []string
+	footer, _ := m.fitFooter()
+	return common.FooterStyle.Render(footer)
+}
 
-	// Global shortcuts (always shown)
-	parts = append(parts, common.FooterKeyStyle.Render("1-4")+common.FooterDescStyle.Render(":panel"))
-	parts = append(parts, common.FooterKeyStyle.Render("Tab")+common.FooterDescStyle.Render(":cycle"))
-	parts = append(parts, common.FooterKeyStyle.Render("?")+common.FooterDescStyle.Render(":help"))
-	parts = append(parts, common.FooterKeyStyle.Render("q")+common.FooterDescStyle.Render(":quit"))
+// hiddenFooter returns the footer content dropped to fit the terminal
+func (m Model) hiddenFooter() []string {
+	_, hidden := m.fitFooter()
+	return hidden
+}
+
+// fitFooter lays out the footer for the terminal width, returning it and
+// the text of the segments that did not fit
+func (m Model) fitFooter() (string, []string) {
+	// Global shortcuts; help and quit are always shown
+	left := []footerSegment{
+		footerKey("1-4", ":panel", footerPriorityGlobal),
+		footerKey("Tab", ":cycle", footerPriorityLow),
+		footerKey("g t/s/p/m", ":go to panel", footerPriorityLow),
+		footerKey("?", ":help", footerPriorityRequired),
+		footerKey(":", ":actions", footerPriorityLow),
+		footerKey("q", ":quit", footerPriorityRequired),
+	}
+	if m.lastDeleted != nil {
+		left = append(left, footerKey("u", ":undo delete", footerPriorityPanel))
+	}
+	if m.bulk != nil {
+		left = append(left, footerKey("X", ":cancel delete", footerPriorityPanel))
+	}
+	if m.layout == layoutZoom {
+		left = append(left, footerKey("Z", ":show all panels", footerPriorityPanel))
+	}
 
 	// Panel-specific shortcuts
 	panelShortcuts := m.getPanelShortcuts()
 	if len(panelShortcuts) > 0 {
-		parts = append(parts, common.FooterDescStyle.Render("│"))
-		parts = append(parts, panelShortcuts...)
+		left = append(left, footerSegment{
+			text:      common.FooterDescStyle.Render("│"),
+			priority:  footerPriorityPanel,
+			sep:       " ",
+			separator: true,
+		})
+		for _, shortcut := range panelShortcuts {
+			left = append(left, footerSegment{text: shortcut, priority: footerPriorityPanel, sep: " "})
+		}
 	}
 
 	// Subscription status
-	var statusInfo string
-	if m.selectedSubscription != "" {
-		statusInfo = common.LogNetworkStyle.Render("● ") +
-			common.FooterDescStyle.Render(m.selectedSubscription)
-	}
-
-	// Project info
-	projectInfo := common.FooterDescStyle.Render("Project: ") +
-		common.FooterProjectStyle.Render(m.projectID)
-
-	// Build footer line
-	helpText := strings.Join(parts, " ")
-
-	// Calculate spacing
-	helpLen := lipgloss.Width(helpText)
-	statusLen := lipgloss.Width(statusInfo)
-	projectLen := lipgloss.Width(projectInfo)
-
-	totalRight := statusLen + projectLen
-	if statusLen > 0 {
-		totalRight += 3 // separator
-	}
-
-	spacing := m.width - helpLen - totalRight - 4
-	if spacing < 1 {
-		spacing = 1
+	var right []footerSegment
+	if m.selectedSubscription != "" && !m.options.Footer.HideSubscription {
+		right = append(right, footerSegment{
+			text: common.LogNetworkStyle.Render("● ") +
+				common.FooterDescStyle.Render(m.selectedSubscription),
+			priority: footerPriorityStatus,
+		})
+		right = append(right, footerSegment{
+			text:     common.LogSuccessStyle.Render(fmt.Sprintf("acked: %d", m.sessionAcks)),
+			priority: footerPriorityStatus,
+			sep:      " ",
+		})
 	}
 
-	var footer string
-	if statusInfo != "" {
-		footer = helpText + strings.Repeat(" ", spacing) + statusInfo + " │ " + projectInfo
-	} else {
-		footer = helpText + strings.Repeat(" ", spacing+3) + projectInfo
+	// Connection health, then environment info: make the emulator stand out
+	// from real GCP
+	right = append(right, footerSegment{text: m.renderHealth(), priority: footerPriorityRequired, sep: " │ "})
+	if !m.options.Footer.HideProject {
+		projectInfo := common.FooterDescStyle.Render("GCP: ") +
+			common.FooterProjectStyle.Render(m.projectID)
+		if m.IsEmulator() {
+			projectInfo = common.FooterEmulatorStyle.Render(m.environmentLabel())
+		}
+		right = append(right, footerSegment{text: projectInfo, priority: footerPriorityProject, sep: " "})
 	}
 
-	return common.FooterStyle.Render(footer)
+	width := m.width - common.FooterStyle.GetHorizontalFrameSize()
+	return fitFooter(left, right, width)
 }
 
 // getPanelShortcuts returns shortcuts specific to the currently focused panel
@@ -116,34 +171,99 @@ func (m Model) getPanelShortcuts() []string {
 
 	switch m.focus {
 	case FocusTopics:
+		newKey := common.FooterKeyStyle.Render("n") + common.FooterDescStyle.Render(":new")
+		if m.topics.IsSearching() {
+			newKey = common.FooterKeyStyle.Render("n/N") + common.FooterDescStyle.Render(":next/prev match")
+		}
 		shortcuts = append(shortcuts,
 			common.FooterKeyStyle.Render("↑↓")+common.FooterDescStyle.Render(":nav"),
 			common.FooterKeyStyle.Render("Enter")+common.FooterDescStyle.Render(":select"),
-			common.FooterKeyStyle.Render("n")+common.FooterDescStyle.Render(":new"),
+			newKey,
 			common.FooterKeyStyle.Render("d")+common.FooterDescStyle.Render(":delete"),
-			common.FooterKeyStyle.Render("/")+common.FooterDescStyle.Render(":filter"),
+			common.FooterKeyStyle.Render("D")+common.FooterDescStyle.Render(":del all"),
+			common.FooterKeyStyle.Render("/")+common.FooterDescStyle.Render(":search"),
+			common.FooterKeyStyle.Render("F")+common.FooterDescStyle.Render(":filter"),
+			common.FooterKeyStyle.Render("f")+common.FooterDescStyle.Render(":prefix"),
+			common.FooterKeyStyle.Render("space")+common.FooterDescStyle.Render(":mark"),
+			common.FooterKeyStyle.Render("t")+common.FooterDescStyle.Render(":tree"),
 		)
+		if m.topics.IsTreeView() {
+			shortcuts = append(shortcuts,
+				common.FooterKeyStyle.Render("←→")+common.FooterDescStyle.Render(":collapse/expand"))
+		}
 
 	case FocusSubscriptions:
+		if m.subscriptions.IsBrowsingSnapshots() {
+			shortcuts = append(shortcuts,
+				common.FooterKeyStyle.Render("↑↓")+common.FooterDescStyle.Render(":nav"),
+				common.FooterKeyStyle.Render("Enter")+common.FooterDescStyle.Render(":seek"),
+				common.FooterKeyStyle.Render("d")+common.FooterDescStyle.Render(":delete"),
+				common.FooterKeyStyle.Render("S")+common.FooterDescStyle.Render(":reload"),
+				common.FooterKeyStyle.Render("Esc")+common.FooterDescStyle.Render(":close"),
+			)
+			break
+		}
 		// Show Esc:stop only when there's an active subscription
 		if m.subscriptions.GetActiveSubscription() != "" {
 			shortcuts = append(shortcuts,
 				common.FooterKeyStyle.Render("Esc")+common.FooterDescStyle.Render(":stop"),
 			)
 		}
+		newKey := common.FooterKeyStyle.Render("n") + common.FooterDescStyle.Render(":new")
+		if m.subscriptions.IsSearching() {
+			newKey = common.FooterKeyStyle.Render("n/N") + common.FooterDescStyle.Render(":next/prev match")
+		}
 		shortcuts = append(shortcuts,
 			common.FooterKeyStyle.Render("↑↓")+common.FooterDescStyle.Render(":nav"),
 			common.FooterKeyStyle.Render("Enter")+common.FooterDescStyle.Render(":start/stop"),
-			common.FooterKeyStyle.Render("n")+common.FooterDescStyle.Render(":new"),
+			newKey,
 			common.FooterKeyStyle.Render("d")+common.FooterDescStyle.Render(":delete"),
-			common.FooterKeyStyle.Render("/")+common.FooterDescStyle.Render(":filter"),
+			common.FooterKeyStyle.Render("D")+common.FooterDescStyle.Render(":del all"),
+			common.FooterKeyStyle.Render("/")+common.FooterDescStyle.Render(":search"),
+			common.FooterKeyStyle.Render("F")+common.FooterDescStyle.Render(":filter"),
+			common.FooterKeyStyle.Render("f")+common.FooterDescStyle.Render(":prefix"),
+			common.FooterKeyStyle.Render("s")+common.FooterDescStyle.Render(":snapshot"),
+			common.FooterKeyStyle.Render("S")+common.FooterDescStyle.Render(":snapshots"),
+			common.FooterKeyStyle.Render("i")+common.FooterDescStyle.Render(":details"),
 		)
+		if len(m.subscriptions.OrphanedNames()) > 0 {
+			shortcuts = append(shortcuts,
+				common.FooterKeyStyle.Render("O")+common.FooterDescStyle.Render(":del orphaned"))
+		}
 
 	case FocusPublisher:
+		if m.publisher.IsEditing() {
+			shortcuts = append(shortcuts,
+				common.FooterKeyStyle.Render("^s")+common.FooterDescStyle.Render(":apply"),
+				common.FooterKeyStyle.Render("Esc")+common.FooterDescStyle.Render(":discard"),
+			)
+			break
+		}
+		if m.publisher.IsShowingHistory() {
+			shortcuts = append(shortcuts,
+				common.FooterKeyStyle.Render("↑↓")+common.FooterDescStyle.Render(":nav"),
+				common.FooterKeyStyle.Render("Enter")+common.FooterDescStyle.Render(":load payload"),
+				common.FooterKeyStyle.Render("h/Esc")+common.FooterDescStyle.Render(":close"),
+			)
+			break
+		}
+		// Show Esc:cancel only while a publish is in progress
+		if m.publisher.IsPublishing() {
+			shortcuts = append(shortcuts,
+				common.FooterKeyStyle.Render("Esc")+common.FooterDescStyle.Render(":cancel"),
+			)
+		}
 		shortcuts = append(shortcuts,
 			common.FooterKeyStyle.Render("↑↓")+common.FooterDescStyle.Render(":nav"),
 			common.FooterKeyStyle.Render("Enter")+common.FooterDescStyle.Render(":publish"),
 			common.FooterKeyStyle.Render("v")+common.FooterDescStyle.Render(":vars"),
+			common.FooterKeyStyle.Render("E")+common.FooterDescStyle.Render(":edit"),
+			common.FooterKeyStyle.Render("S")+common.FooterDescStyle.Render(":save"),
+			common.FooterKeyStyle.Render("P")+common.FooterDescStyle.Render(":quick"),
+			common.FooterKeyStyle.Render("L")+common.FooterDescStyle.Render(":later"),
+			common.FooterKeyStyle.Render("B")+common.FooterDescStyle.Render(":batch"),
+			common.FooterKeyStyle.Render("h")+common.FooterDescStyle.Render(":history"),
+			common.FooterKeyStyle.Render("o")+common.FooterDescStyle.Render(":sort"),
 		)
 
 	case FocusSubscriber:
@@ -155,10 +275,25 @@ func (m Model) getPanelShortcuts() []string {
 		}
 		shortcuts = append(shortcuts,
 			common.FooterKeyStyle.Render("↑↓")+common.FooterDescStyle.Render(":nav"),
+			common.FooterKeyStyle.Render("gg/G")+common.FooterDescStyle.Render(":first/last"),
+			common.FooterKeyStyle.Render("F")+common.FooterDescStyle.Render(":follow"),
 			common.FooterKeyStyle.Render("a")+common.FooterDescStyle.Render(":ack"),
-			common.FooterKeyStyle.Render("A")+common.FooterDescStyle.Render(":auto-ack"),
+			common.FooterKeyStyle.Render(".")+common.FooterDescStyle.Render(":ack-stay"),
+			common.FooterKeyStyle.Render("*")+common.FooterDescStyle.Render(":ack shown"),
+			common.FooterKeyStyle.Render("n")+common.FooterDescStyle.Render(":nack"),
+			common.FooterKeyStyle.Render("A")+common.FooterDescStyle.Render(":ack mode"),
+			common.FooterKeyStyle.Render("p")+common.FooterDescStyle.Render(":republish"),
+			common.FooterKeyStyle.Render("w")+common.FooterDescStyle.Render(":write"),
+			common.FooterKeyStyle.Render("z")+common.FooterDescStyle.Render(":tz"),
+			common.FooterKeyStyle.Render("t")+common.FooterDescStyle.Render(":age"),
+			common.FooterKeyStyle.Render("r")+common.FooterDescStyle.Render(":raw"),
+			common.FooterKeyStyle.Render("m")+common.FooterDescStyle.Render(":max"),
+			common.FooterKeyStyle.Render("@")+common.FooterDescStyle.Render(":attr"),
+			common.FooterKeyStyle.Render("W")+common.FooterDescStyle.Render(":age window"),
+			common.FooterKeyStyle.Render("x/d")+common.FooterDescStyle.Render(":mark/diff"),
 			common.FooterKeyStyle.Render("/")+common.FooterDescStyle.Render(":filter"),
 			common.FooterKeyStyle.Render("^d/^u")+common.FooterDescStyle.Render(":scroll"),
+			common.FooterKeyStyle.Render("^j/^k")+common.FooterDescStyle.Render(":scroll list"),
 		)
 	}
 
@@ -167,62 +302,21 @@ func (m Model) getPanelShortcuts() []string {
 
 // renderHelpOverlay renders the help dialog as an overlay on top of the base view
 func (m Model) renderHelpOverlay(baseView string) string {
-	// Build help content - each line exactly 66 characters (fits in 70-char box with padding)
-	helpLines := []string{
-		"",
-		"NAVIGATION",
-		"",
-		"1-4         Jump to panel (Topics/Subscriptions/Publisher/Sub)",
-		"Tab         Cycle focus forward",
-		"Shift+Tab   Cycle focus backward",
-		"q           Quit application",
-		"?           Show this help",
-		"",
-		"TOPICS PANEL (1)",
-		"",
-		"j/k or ↑↓   Navigate list",
-		"Enter       Select topic for publisher",
-		"n           Create new topic",
-		"d           Delete selected topic",
-		"/           Filter topics by regex",
-		"",
-		"SUBSCRIPTIONS PANEL (2)",
-		"",
-		"j/k or ↑↓   Navigate list",
-		"Enter       Start/stop subscription in subscriber panel",
-		"n           Create new subscription",
-		"d           Delete selected subscription",
-		"/           Filter subscriptions by regex",
-		"",
-		"PUBLISHER PANEL (3)",
-		"",
-		"j/k or ↑↓   Navigate message templates",
-		"Enter       Publish message to topic",
-		"v           Edit variables for substitution",
-		"            (use ${varName} in JSON templates)",
-		"",
-		"SUBSCRIBER PANEL (4)",
-		"",
-		"j/k or ↑↓   Navigate messages",
-		"a           Acknowledge selected message (moves to next)",
-		"A           Toggle auto-acknowledge mode",
-		"/           Filter messages by regex",
-		"Ctrl+d/u    Scroll message detail up/down",
-		"",
+	if m.width < helpMinWidth+helpBoxChrome || m.height < helpBoxHeaders+1 {
+		return common.TooSmallView(m.width, m.height, helpMinWidth+helpBoxChrome, helpBoxHeaders+1)
 	}
 
-	// Join content
-	contentText := strings.Join(helpLines, "\n")
+	width := m.helpWidth()
 
 	// Style for the content
 	contentStyle := lipgloss.NewStyle().
-		Width(66).
+		Width(width).
 		Foreground(common.ColorPrimary).
 		Background(lipgloss.Color("#0a0a0a"))
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
-		Width(66).
+		Width(width).
 		Align(lipgloss.Center).
 		Foreground(common.ColorPrimary).
 		Background(lipgloss.Color("#0a0a0a")).
@@ -230,15 +324,26 @@ func (m Model) renderHelpOverlay(baseView string) string {
 
 	// Footer
 	footerStyle := lipgloss.NewStyle().
-		Width(66).
+		Width(width).
 		Align(lipgloss.Center).
 		Foreground(common.ColorPrimary).
 		Background(lipgloss.Color("#0a0a0a"))
 
+	// Environment the commands are sent to
+	envStyle := titleStyle.Bold(false).Foreground(common.ColorSecondary)
+	if m.IsEmulator() {
+		envStyle = envStyle.Foreground(common.ColorWarning)
+	}
+	env := m.environmentLabel()
+	if lipgloss.Width(env) > width {
+		env = env[:width-3] + "..."
+	}
+
 	// Build the complete content
 	fullContent := titleStyle.Render("PUBSUB-TUI HELP") + "\n" +
-		contentStyle.Render(contentText) + "\n" +
-		footerStyle.Render("Press any key to close")
+		envStyle.Render(env) + "\n" +
+		contentStyle.Render(m.help.View()) + "\n" +
+		footerStyle.Render(m.helpFooter())
 
 	// Apply border around everything
 	helpBox := lipgloss.NewStyle().
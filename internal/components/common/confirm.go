@@ -0,0 +1,10 @@
+package common
+
+import "fmt"
+
+// BulkDeletePhrase returns what must be typed to confirm deleting count
+// resources from real GCP, e.g. "delete 12". Unlike y, it cannot be typed
+// by accident and names how many resources go.
+func BulkDeletePhrase(count int) string {
+	return fmt.Sprintf("delete %d", count)
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FilterDebounceDelay is how long typing must pause before a panel
+// re-applies its filter
+const FilterDebounceDelay = 150 * time.Millisecond
+
+// DebounceMsg is delivered once input pauses. Only the Debouncer that
+// scheduled it applies it, and only if no input arrived since.
+type DebounceMsg struct {
+	Source string // Debouncer owner, e.g. "topics"
+	ID     int
+}
+
+// Debouncer coalesces bursts of input, such as filter keystrokes, into one
+// pass once typing pauses. Each Schedule makes the earlier messages stale.
+type Debouncer struct {
+	source string
+	delay  time.Duration
+	seq    int
+}
+
+// NewDebouncer creates a debouncer for source, the owner of its messages
+func NewDebouncer(source string, delay time.Duration) Debouncer {
+	return Debouncer{source: source, delay: delay}
+}
+
+// Schedule returns a command that delivers a DebounceMsg after the delay
+func (d *Debouncer) Schedule() tea.Cmd {
+	msg := d.next()
+	return tea.Tick(d.delay, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// Cancel makes pending messages stale, for input applied right away
+func (d *Debouncer) Cancel() {
+	d.next()
+}
+
+// next starts a new burst and returns the message that ends it
+func (d *Debouncer) next() DebounceMsg {
+	d.seq++
+	return d.Msg()
+}
+
+// Msg returns the message the latest Schedule delivers
+func (d Debouncer) Msg() DebounceMsg {
+	return DebounceMsg{Source: d.source, ID: d.seq}
+}
+
+// Due reports whether msg is this debouncer's latest message
+func (d Debouncer) Due(msg DebounceMsg) bool {
+	return msg == d.Msg()
+}
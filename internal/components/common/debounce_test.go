@@ -0,0 +1,38 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	d := NewDebouncer("topics", time.Millisecond)
+	other := NewDebouncer("subscriptions", time.Millisecond)
+
+	first := d.Schedule()().(DebounceMsg)
+	if !d.Due(first) {
+		t.Fatal("the only scheduled message should be due")
+	}
+
+	// A later keystroke makes the first message stale
+	second := d.Schedule()().(DebounceMsg)
+	if d.Due(first) {
+		t.Error("an earlier message should be stale")
+	}
+	if !d.Due(second) || d.Msg() != second {
+		t.Errorf("the latest message %+v should be due", second)
+	}
+
+	// Messages of another source never apply, even with the same ID
+	other.Schedule()
+	other.Schedule()
+	if d.Due(other.Msg()) || other.Due(second) {
+		t.Error("a message should only be due for the debouncer that scheduled it")
+	}
+
+	// Input applied right away cancels the pending pass
+	d.Cancel()
+	if d.Due(second) {
+		t.Error("Cancel() should make the pending message stale")
+	}
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		name string
+		age  time.Duration
+		want string
+	}{
+		{name: "negative (clock skew)", age: -2 * time.Second, want: "0s"},
+		{name: "sub-second", age: 500 * time.Millisecond, want: "0s"},
+		{name: "seconds", age: 45 * time.Second, want: "45s"},
+		{name: "just under a minute", age: 59*time.Second + 900*time.Millisecond, want: "59s"},
+		{name: "minutes", age: 2*time.Minute + 30*time.Second, want: "2m"},
+		{name: "hours", age: 3*time.Hour + 59*time.Minute, want: "3h"},
+		{name: "days", age: 50 * time.Hour, want: "2d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAge(tt.age); got != tt.want {
+				t.Errorf("FormatAge(%v) = %q, want %q", tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{20 * 1024, "20 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSize(tt.size); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
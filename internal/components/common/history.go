@@ -0,0 +1,72 @@
+package common
+
+import "strings"
+
+// MaxFilterHistory is the number of filter patterns remembered per panel
+const MaxFilterHistory = 20
+
+// FilterHistory remembers recently applied filter patterns and lets the
+// user step back through them, shell-style. The zero value is ready to use.
+type FilterHistory struct {
+	entries []string // Oldest first
+	pos     int      // Index being recalled; len(entries) when not recalling
+	draft   string   // Input typed before recall started
+}
+
+// Add records a pattern, skipping empty input and consecutive repeats, and
+// ends any recall in progress
+func (h *FilterHistory) Add(pattern string) {
+	defer h.Reset()
+
+	if strings.TrimSpace(pattern) == "" {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == pattern {
+		return
+	}
+
+	h.entries = append(h.entries, pattern)
+	if len(h.entries) > MaxFilterHistory {
+		h.entries = append([]string(nil), h.entries[len(h.entries)-MaxFilterHistory:]...)
+	}
+}
+
+// Prev returns the previous (older) pattern. current is saved on the first
+// step so Next can restore it. Returns false when there is nothing older.
+func (h *FilterHistory) Prev(current string) (string, bool) {
+	if h.pos > len(h.entries) {
+		h.pos = len(h.entries)
+	}
+	if h.pos == 0 {
+		return "", false
+	}
+	if h.pos == len(h.entries) {
+		h.draft = current
+	}
+	h.pos--
+	return h.entries[h.pos], true
+}
+
+// Next returns the next (newer) pattern, or the saved draft after the
+// newest entry. Returns false when not recalling.
+func (h *FilterHistory) Next() (string, bool) {
+	if h.pos >= len(h.entries) {
+		return "", false
+	}
+	h.pos++
+	if h.pos == len(h.entries) {
+		return h.draft, true
+	}
+	return h.entries[h.pos], true
+}
+
+// Reset ends any recall in progress
+func (h *FilterHistory) Reset() {
+	h.pos = len(h.entries)
+	h.draft = ""
+}
+
+// Entries returns the remembered patterns, oldest first
+func (h FilterHistory) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
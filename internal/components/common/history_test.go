@@ -0,0 +1,82 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFilterHistory_Recall(t *testing.T) {
+	var h FilterHistory
+	h.Add("orders")
+	h.Add("^prod-")
+	h.Add("events$")
+
+	// Up walks from newest to oldest, stopping at the oldest
+	for _, want := range []string{"events$", "^prod-", "orders"} {
+		got, ok := h.Prev("draft")
+		if !ok || got != want {
+			t.Fatalf("Prev() = %q, %v, want %q, true", got, ok, want)
+		}
+	}
+	if _, ok := h.Prev("draft"); ok {
+		t.Error("Prev() past the oldest entry should return false")
+	}
+
+	// Down walks back to newest, then restores the typed draft
+	for _, want := range []string{"^prod-", "events$", "draft"} {
+		got, ok := h.Next()
+		if !ok || got != want {
+			t.Fatalf("Next() = %q, %v, want %q, true", got, ok, want)
+		}
+	}
+	if _, ok := h.Next(); ok {
+		t.Error("Next() when not recalling should return false")
+	}
+}
+
+func TestFilterHistory_Add(t *testing.T) {
+	var h FilterHistory
+	h.Add("orders")
+	h.Add("orders") // consecutive repeat
+	h.Add("")
+	h.Add("   ")
+	h.Add("events")
+	h.Add("orders") // not consecutive, kept
+
+	want := []string{"orders", "events", "orders"}
+	if got := h.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterHistory_Cap(t *testing.T) {
+	var h FilterHistory
+	for i := 0; i < MaxFilterHistory+5; i++ {
+		h.Add(fmt.Sprintf("pattern-%d", i))
+	}
+
+	entries := h.Entries()
+	if len(entries) != MaxFilterHistory {
+		t.Fatalf("len(Entries()) = %d, want %d", len(entries), MaxFilterHistory)
+	}
+	if entries[0] != "pattern-5" {
+		t.Errorf("oldest entry = %q, want pattern-5", entries[0])
+	}
+	if got, _ := h.Prev(""); got != fmt.Sprintf("pattern-%d", MaxFilterHistory+4) {
+		t.Errorf("Prev() = %q, want the newest pattern", got)
+	}
+}
+
+func TestFilterHistory_AddResetsRecall(t *testing.T) {
+	var h FilterHistory
+	h.Add("a")
+	h.Add("b")
+	h.Prev("")
+	h.Prev("")
+
+	h.Add("c")
+	if got, _ := h.Prev(""); got != "c" {
+		t.Errorf("Prev() after Add = %q, want c", got)
+	}
+}
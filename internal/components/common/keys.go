@@ -0,0 +1,223 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HelpProvider is implemented by panels that list their key bindings in the
+// help overlay
+type HelpProvider interface {
+	FullHelp() []key.Binding
+}
+
+// KeyBindings returns the key.Binding fields of a keyMap struct in
+// declaration order, so new bindings appear in the help without being listed
+// separately. Disabled bindings are skipped.
+func KeyBindings(keyMap interface{}) []key.Binding {
+	v := reflect.ValueOf(keyMap)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var bindings []key.Binding
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		b, ok := v.Field(i).Interface().(key.Binding)
+		if ok && b.Enabled() {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
+}
+
+// FindKey returns the binding of a keyMap struct by field name, matched
+// case-insensitively like OverrideKeys
+func FindKey(keyMap interface{}, name string) (key.Binding, bool) {
+	v := reflect.ValueOf(keyMap)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return key.Binding{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.IsExported() && strings.EqualFold(f.Name, name) {
+			b, ok := v.Field(i).Interface().(key.Binding)
+			return b, ok
+		}
+	}
+	return key.Binding{}, false
+}
+
+// KeyPress returns the key message bubbletea sends for a key as named in a
+// binding, e.g. "n", "ctrl+a", "esc" or "alt+x"
+func KeyPress(k string) tea.KeyMsg {
+	name, alt := k, false
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		name, alt = rest, true
+	}
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t != tea.KeyRunes && (tea.KeyMsg{Type: t}).String() == name {
+			msg := tea.KeyMsg{Type: t, Alt: alt}
+			if t == tea.KeySpace {
+				msg.Runes = []rune{' '}
+			}
+			return msg
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name), Alt: alt}
+}
+
+// OverrideKeys replaces the keys of bindings in keyMap, a pointer to a
+// keyMap struct. Overrides are keyed by binding field name, matched
+// case-insensitively, and each replaced binding's help shows its new keys.
+// It returns the sorted names that match no binding, and an error when a new
+// key is already bound to another action in keyMap; keyMap is then left
+// partly updated, so callers apply overrides to a fresh copy.
+func OverrideKeys(keyMap interface{}, overrides map[string][]string) ([]string, error) {
+	v := reflect.ValueOf(keyMap)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("key map must be a pointer to a struct, got %T", keyMap)
+	}
+	v = v.Elem()
+
+	fields := make(map[string]int)
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.IsExported() && f.Type == reflect.TypeOf(key.Binding{}) {
+			fields[strings.ToLower(f.Name)] = i
+		}
+	}
+
+	var unknown []string
+	overridden := make(map[int]bool)
+	for name, keys := range overrides {
+		i, ok := fields[strings.ToLower(name)]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s: no keys given", name)
+		}
+		b := v.Field(i).Addr().Interface().(*key.Binding)
+		b.SetKeys(keys...)
+		b.SetHelp(helpLabel(b.Help().Key, keys), b.Help().Desc)
+		overridden[i] = true
+	}
+	sort.Strings(unknown)
+
+	// Defaults share keys between actions that apply in different modes, so
+	// only the overridden bindings are checked
+	for i := range overridden {
+		for _, k := range v.Field(i).Interface().(key.Binding).Keys() {
+			for _, j := range fields {
+				if j != i && hasKey(v.Field(j).Interface().(key.Binding), k) {
+					return unknown, fmt.Errorf("key %q of %s is already bound to %s", k, v.Type().Field(i).Name, v.Type().Field(j).Name)
+				}
+			}
+		}
+	}
+	return unknown, nil
+}
+
+// helpLabel returns the help label for keys. A label like "gg", where the
+// key is pressed twice, keeps that form.
+func helpLabel(old string, keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = KeyLabel(k)
+	}
+	if first, _, _ := strings.Cut(old, "/"); len(first) == 2 && first[0] == first[1] {
+		labels[0] += labels[0]
+	}
+	return strings.Join(labels, "/")
+}
+
+// hasKey reports whether b is bound to k
+func hasKey(b key.Binding, k string) bool {
+	for _, bk := range b.Keys() {
+		if bk == k {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyLabel returns how help text shows a key
+func KeyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case " ":
+		return "space"
+	}
+	return k
+}
+
+// PageList moves a list's cursor by whole pages, up for negative pages,
+// stopping at the first and last items
+func PageList(l *list.Model, pages int) {
+	count := len(l.VisibleItems())
+	if count == 0 {
+		return
+	}
+	index := l.Index() + pages*l.Paginator.PerPage
+	if index < 0 {
+		index = 0
+	}
+	if index >= count {
+		index = count - 1
+	}
+	l.Select(index)
+}
+
+// JumpList selects a list's first item, or its last with last set. An empty
+// list is left alone.
+func JumpList(l *list.Model, last bool) {
+	count := len(l.VisibleItems())
+	if count == 0 {
+		return
+	}
+	if last {
+		l.Select(count - 1)
+		return
+	}
+	l.Select(0)
+}
+
+// PendingG tracks the first g of the gg sequence. The zero value has nothing
+// pending.
+type PendingG struct {
+	pending bool
+}
+
+// Press records the first g
+func (p *PendingG) Press() {
+	p.pending = true
+}
+
+// Complete drops a pending g and reports whether msg, matching second,
+// completes the sequence. Any other key just drops it.
+func (p *PendingG) Complete(msg tea.KeyMsg, second key.Binding) bool {
+	if !p.pending {
+		return false
+	}
+	p.pending = false
+	return key.Matches(msg, second)
+}
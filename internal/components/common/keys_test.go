@@ -0,0 +1,162 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyBindings(t *testing.T) {
+	km := struct {
+		Quit     key.Binding
+		Disabled key.Binding
+		Help     key.Binding
+		name     string
+		hidden   key.Binding
+	}{
+		Quit:     key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		Disabled: key.NewBinding(key.WithKeys("x"), key.WithDisabled()),
+		Help:     key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		name:     "ignored",
+		hidden:   key.NewBinding(key.WithKeys("h")),
+	}
+
+	for _, v := range []interface{}{km, &km} {
+		got := KeyBindings(v)
+		if len(got) != 2 {
+			t.Fatalf("KeyBindings() returned %d bindings, want 2", len(got))
+		}
+		if got[0].Help().Key != "q" || got[1].Help().Key != "?" {
+			t.Errorf("KeyBindings() = %q, %q, want declaration order q, ?", got[0].Help().Key, got[1].Help().Key)
+		}
+	}
+
+	if got := KeyBindings("not a struct"); got != nil {
+		t.Errorf("KeyBindings(string) = %v, want nil", got)
+	}
+}
+
+func TestOverrideKeys(t *testing.T) {
+	type keyMap struct {
+		Top    key.Binding
+		Create key.Binding
+		Next   key.Binding
+	}
+	defaults := func() keyMap {
+		return keyMap{
+			Top:    key.NewBinding(key.WithKeys("g"), key.WithHelp("gg", "first item")),
+			Create: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "create")),
+			Next:   key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match")),
+		}
+	}
+
+	km := defaults()
+	unknown, err := OverrideKeys(&km, map[string][]string{"top": {"t"}, "create": {"c", "up"}, "bogus": {"x"}})
+	if err != nil {
+		t.Fatalf("OverrideKeys() error = %v", err)
+	}
+	if len(unknown) != 1 || unknown[0] != "bogus" {
+		t.Errorf("unknown = %v, want [bogus]", unknown)
+	}
+	if got := km.Top.Help().Key; got != "tt" {
+		t.Errorf("Top help = %q, want tt (pressed twice like gg)", got)
+	}
+	if got := km.Create.Help().Key; got != "c/↑" || km.Create.Help().Desc != "create" {
+		t.Errorf("Create help = %q %q, want c/↑ create", got, km.Create.Help().Desc)
+	}
+
+	// Keys shared by the defaults are allowed, new conflicts are not
+	km = defaults()
+	if _, err := OverrideKeys(&km, map[string][]string{"Top": {"n"}}); err == nil {
+		t.Error("OverrideKeys() should reject a key bound to another action")
+	}
+	if _, err := OverrideKeys(&km, map[string][]string{"Top": {}}); err == nil {
+		t.Error("OverrideKeys() should reject an action without keys")
+	}
+}
+
+func TestPageList(t *testing.T) {
+	items := make([]list.Item, 25)
+	for i := range items {
+		items[i] = testItem(fmt.Sprintf("item-%d", i))
+	}
+	l := list.New(items, list.NewDefaultDelegate(), 40, 20)
+	perPage := l.Paginator.PerPage
+
+	tests := []struct {
+		pages int
+		want  int
+	}{
+		{1, perPage},
+		{1, 2 * perPage},
+		{-1, perPage},
+		{10, 24},
+		{-10, 0},
+	}
+	for _, tt := range tests {
+		PageList(&l, tt.pages)
+		if got := l.Index(); got != tt.want {
+			t.Errorf("PageList(%d): Index() = %d, want %d", tt.pages, got, tt.want)
+		}
+	}
+
+	// An empty list stays put
+	empty := list.New(nil, list.NewDefaultDelegate(), 40, 20)
+	PageList(&empty, 1)
+	if got := empty.Index(); got != 0 {
+		t.Errorf("PageList on an empty list: Index() = %d, want 0", got)
+	}
+}
+
+func TestJumpList(t *testing.T) {
+	items := make([]list.Item, 25)
+	for i := range items {
+		items[i] = testItem(fmt.Sprintf("item-%d", i))
+	}
+	l := list.New(items, list.NewDefaultDelegate(), 40, 20)
+
+	JumpList(&l, true)
+	if got := l.Index(); got != 24 {
+		t.Errorf("JumpList(last): Index() = %d, want 24", got)
+	}
+	JumpList(&l, false)
+	if got := l.Index(); got != 0 {
+		t.Errorf("JumpList(first): Index() = %d, want 0", got)
+	}
+
+	// An empty list stays put
+	empty := list.New(nil, list.NewDefaultDelegate(), 40, 20)
+	JumpList(&empty, true)
+	if got := empty.Index(); got != 0 {
+		t.Errorf("JumpList on an empty list: Index() = %d, want 0", got)
+	}
+}
+
+func TestPendingG(t *testing.T) {
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+	k := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}}
+	top := key.NewBinding(key.WithKeys("g"))
+
+	var p PendingG
+	if p.Complete(g, top) {
+		t.Error("g without a pending g should not complete gg")
+	}
+	p.Press()
+	if p.Complete(k, top) {
+		t.Error("k should not complete gg")
+	}
+	if p.Complete(g, top) {
+		t.Error("another key should drop the pending g")
+	}
+	p.Press()
+	if !p.Complete(g, top) {
+		t.Error("g after g should complete gg")
+	}
+}
+
+type testItem string
+
+func (i testItem) FilterValue() string { return string(i) }
@@ -1,7 +1,11 @@
 package common
 
 import (
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
 )
 
 // TopicSelectedMsg is sent when a topic is selected in the topics panel
@@ -10,6 +14,12 @@ type TopicSelectedMsg struct {
 	TopicFull string
 }
 
+// TopicsMarkedMsg is sent when topics are marked or unmarked for publishing
+// one message to several topics. Topics is empty once every mark is cleared.
+type TopicsMarkedMsg struct {
+	Topics []string
+}
+
 // SubscriptionSelectedMsg is sent when a subscription is selected
 type SubscriptionSelectedMsg struct {
 	SubscriptionName string
@@ -28,6 +38,22 @@ const (
 	LogNetwork
 )
 
+// String returns the level name, e.g. for the debug log
+func (l LogLevel) String() string {
+	switch l {
+	case LogSuccess:
+		return "success"
+	case LogWarning:
+		return "warning"
+	case LogError:
+		return "error"
+	case LogNetwork:
+		return "network"
+	default:
+		return "info"
+	}
+}
+
 // LogMsg represents a message to be added to the activity log
 type LogMsg struct {
 	Level   LogLevel
@@ -69,6 +95,28 @@ func Network(message string) LogMsg {
 	return NewLogMsg(LogNetwork, message)
 }
 
+// categorizedError is implemented by errors that carry a category,
+// such as those returned by the pubsub package
+type categorizedError interface {
+	error
+	Category() string
+	Transient() bool
+}
+
+// ErrorLog creates a log message for a failed operation. Categorized errors
+// are tagged with their category, and transient ones are logged as warnings.
+func ErrorLog(message string, err error) LogMsg {
+	var ce categorizedError
+	if errors.As(err, &ce) {
+		level := LogError
+		if ce.Transient() {
+			level = LogWarning
+		}
+		return NewLogMsg(level, fmt.Sprintf("%s [%s]: %v", message, ce.Category(), err))
+	}
+	return Error(fmt.Sprintf("%s: %v", message, err))
+}
+
 // TopicsLoadedMsg is sent when topics are loaded from GCP
 type TopicsLoadedMsg struct {
 	Topics []TopicData
@@ -93,6 +141,48 @@ type SubscriptionData struct {
 	FullName  string
 	TopicName string
 	TopicFull string
+	Filter    string
+	Orphaned  bool // Whether the topic was deleted
+
+	ConfigLoaded bool // Whether delivery info below is known
+	IsPush       bool
+	PushEndpoint string
+
+	ExpirationTTL time.Duration // Inactivity before the subscription is deleted; 0 never
+}
+
+// SnapshotData represents snapshot data for UI display
+type SnapshotData struct {
+	Name       string
+	FullName   string
+	TopicName  string
+	Expiration time.Time
+}
+
+// SnapshotsLoadedMsg is sent when snapshots are loaded from GCP
+type SnapshotsLoadedMsg struct {
+	Snapshots []SnapshotData
+	Err       error
+}
+
+// SnapshotCreatedMsg is sent when a snapshot is created
+type SnapshotCreatedMsg struct {
+	SnapshotName     string
+	SubscriptionName string
+	Err              error
+}
+
+// SnapshotSeekedMsg is sent when a subscription has been seeked to a snapshot
+type SnapshotSeekedMsg struct {
+	SnapshotName     string
+	SubscriptionName string
+	Err              error
+}
+
+// SnapshotDeletedMsg is sent when a snapshot is deleted
+type SnapshotDeletedMsg struct {
+	SnapshotName string
+	Err          error
 }
 
 // WindowSizeMsg is sent when the window size changes (re-exported for convenience)
@@ -128,7 +218,12 @@ type SubscriptionCreatedMsg struct {
 // SubscriptionDeletedMsg is sent when a subscription is deleted
 type SubscriptionDeletedMsg struct {
 	SubscriptionName string
-	Err              error
+	TopicName        string // Topic it was attached to, captured before deletion
+	Filter           string // Message filter, captured before deletion
+	// Full config captured before deletion, so undo can recreate it; nil
+	// when it could not be read
+	Config *pubsub.SubscriptionConfig
+	Err    error
 }
 
 // RefreshTopicsMsg requests a refresh of the topics list
@@ -137,10 +232,13 @@ type RefreshTopicsMsg struct{}
 // RefreshSubscriptionsMsg requests a refresh of the subscriptions list
 type RefreshSubscriptionsMsg struct{}
 
-// ConfirmDisconnectMsg is sent to confirm disconnecting an active subscription
+// ConfirmDisconnectMsg is sent when the user answers whether to disconnect
+// the active subscription and connect to a newly selected one
 type ConfirmDisconnectMsg struct {
-	NewTopicName string
-	Confirmed    bool
+	NewSubscriptionName string
+	NewSubscriptionFull string
+	NewTopicName        string
+	Confirmed           bool
 }
 
 // StopSubscriptionMsg is sent to stop the active subscription
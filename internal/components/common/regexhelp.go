@@ -0,0 +1,71 @@
+package common
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RegexHelpKey opens the regex examples when typed into an empty filter.
+// No pattern starts with ?, so it is typed normally anywhere else.
+const RegexHelpKey = "?"
+
+// regexExamples are Go regex patterns useful for filtering, with what they
+// match
+var regexExamples = [][2]string{
+	{"^orders", "starts with orders"},
+	{"-dlq$", "ends with -dlq"},
+	{"(?i)orders", "orders in any case"},
+	{"orders|billing", "orders or billing"},
+	{"^orders-.*-v2$", "orders-, anything, then -v2"},
+	{`\.`, "a literal dot"},
+}
+
+// RegexHelp tracks the regex examples popup of a filter input. The zero
+// value is ready to use.
+type RegexHelp struct {
+	visible bool
+}
+
+// Visible returns whether the examples are shown
+func (h RegexHelp) Visible() bool {
+	return h.visible
+}
+
+// Hide closes the examples
+func (h *RegexHelp) Hide() {
+	h.visible = false
+}
+
+// HandleKey opens the examples when ? is typed into an empty filter, and
+// closes them on any key while open. It returns whether the key was used,
+// in which case the filter should ignore it.
+func (h *RegexHelp) HandleKey(msg tea.KeyMsg, value string) bool {
+	if h.visible {
+		h.visible = false
+		return true
+	}
+	if value == "" && msg.String() == RegexHelpKey {
+		h.visible = true
+		return true
+	}
+	return false
+}
+
+// RenderRegexHelp renders the regex examples in place of a panel's list,
+// cutting lines longer than width
+func RenderRegexHelp(width int) string {
+	var b strings.Builder
+	b.WriteString(BrightText.Render("Filters are Go regular expressions"))
+	b.WriteString("\n")
+	for _, ex := range regexExamples {
+		b.WriteString("\n")
+		b.WriteString(FilterInputStyle.Render(ex[0]))
+		b.WriteString(strings.Repeat(" ", 16-len(ex[0])))
+		b.WriteString(MutedText.Render(ex[1]))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(MutedText.Render("Press any key to return to the filter"))
+	return lipgloss.NewStyle().MaxWidth(width).Render(b.String())
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRegexHelp(t *testing.T) {
+	var h RegexHelp
+	question := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}
+
+	// ? is typed normally once the filter has text
+	if h.HandleKey(question, "colou") || h.Visible() {
+		t.Error("? after text should be typed into the filter")
+	}
+	if !h.HandleKey(question, "") || !h.Visible() {
+		t.Fatal("? in an empty filter should open the examples")
+	}
+
+	// Any key closes them and is not passed on
+	if !h.HandleKey(tea.KeyMsg{Type: tea.KeyEnter}, "") || h.Visible() {
+		t.Error("enter should only close the examples")
+	}
+	if h.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, "") {
+		t.Error("keys go to the filter once the examples are closed")
+	}
+}
+
+func TestRenderRegexHelp(t *testing.T) {
+	for _, line := range strings.Split(RenderRegexHelp(20), "\n") {
+		if w := lipgloss.Width(line); w > 20 {
+			t.Errorf("line %q is %d wide, want at most 20", line, w)
+		}
+	}
+	if !strings.Contains(RenderRegexHelp(80), "(?i)orders") {
+		t.Error("examples should include a case-insensitive pattern")
+	}
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"io"
+
+	"github.com/anmaso/pubsub-tui/internal/utils"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SearchMatchText highlights list items that match a search
+var SearchMatchText = lipgloss.NewStyle().
+	Foreground(ColorWarning).
+	Bold(true)
+
+// SearchMatcher is implemented by list items that can match a search
+type SearchMatcher interface {
+	SearchMatch() bool
+}
+
+// SearchDelegate renders items like its DefaultDelegate, highlighting the
+// unselected items that match a search
+type SearchDelegate struct {
+	list.DefaultDelegate
+}
+
+// Render renders an item, using SearchMatchText for unselected matches
+func (d SearchDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if s, ok := item.(SearchMatcher); ok && s.SearchMatch() {
+		d.Styles.NormalTitle = SearchMatchText
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// NextMatch returns the index of the next of count items after from (before
+// it when backward) for which match is true, wrapping around the ends of the
+// list. The item at from is checked last. Returns -1 when nothing matches.
+func NextMatch(count, from int, backward bool, match func(int) bool) int {
+	step := 1
+	if backward {
+		step = -1
+	}
+	for i := 1; i <= count; i++ {
+		index := ((from+step*i)%count + count) % count
+		if match(index) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Search is a regex search over a list panel. Unlike a filter it keeps every
+// item listed: the panel marks matching items (see SearchMatcher) and the
+// selection jumps between them.
+type Search struct {
+	Input  textinput.Model
+	text   string
+	cache  utils.FilterCache // Compiled text
+	prev   string            // Search to restore if the prompt is cancelled
+	origin int               // Selection when the prompt was opened
+}
+
+// NewSearch creates a search with its prompt
+func NewSearch() Search {
+	ti := textinput.New()
+	ti.Placeholder = "regex search..."
+	ti.Prompt = "search: "
+	ti.PromptStyle = FilterPromptStyle
+	ti.TextStyle = FilterInputStyle
+	return Search{Input: ti}
+}
+
+// Text returns the search pattern
+func (s Search) Text() string {
+	return s.text
+}
+
+// Active reports whether a search is highlighting matches
+func (s Search) Active() bool {
+	return s.text != ""
+}
+
+// Start opens the prompt, remembering the search and the selected index to
+// restore if it is cancelled
+func (s *Search) Start(selected int) {
+	s.prev = s.text
+	s.origin = selected
+	s.Input.SetValue(s.text)
+	s.Input.CursorEnd()
+	s.Input.Focus()
+}
+
+// Match reports whether name matches the search. Nothing matches an empty
+// or invalid pattern.
+func (s *Search) Match(name string) bool {
+	if s.text == "" {
+		return false
+	}
+	result := s.cache.Get(s.text).Match(name)
+	return result.Error == nil && result.Matches
+}
+
+// Err returns the error of an invalid search pattern, if any
+func (s *Search) Err() error {
+	if s.text == "" {
+		return nil
+	}
+	return s.cache.Get(s.text).Err()
+}
+
+// Update handles a key while the prompt is open. refresh re-marks the list
+// items after the pattern changes, and the selection follows the first
+// match as the pattern is typed. Esc restores the previous search and
+// selection; Enter keeps the search, and an empty pattern clears it.
+// Returns whether the prompt closed and, when Enter leaves the selection on
+// an item that does not match, that nothing matched.
+func (s *Search) Update(msg tea.KeyMsg, l *list.Model, refresh func()) (closed, noMatch bool, cmd tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		s.Input.Blur()
+		s.text = s.prev
+		refresh()
+		l.Select(s.origin)
+		return true, false, nil
+
+	case tea.KeyEnter:
+		s.Input.Blur()
+		return true, s.text != "" && !selectionMatches(*l), nil
+	}
+
+	s.Input, cmd = s.Input.Update(msg)
+	if value := s.Input.Value(); value != s.text {
+		s.text = value
+		refresh()
+		l.Select(s.origin)
+		JumpToMatch(l, s.origin-1, false)
+	}
+	return false, false, cmd
+}
+
+// Next moves the selection to the next or previous match. It returns a
+// status for the panel naming its items, e.g. "No topics match: x", or ""
+// when the selection moved without wrapping.
+func (s Search) Next(l *list.Model, backward bool, items string) (status string, isError bool) {
+	found, wrapped := JumpToMatch(l, l.Index(), backward)
+	switch {
+	case !found:
+		return "No " + items + " match: " + s.text, true
+	case wrapped && backward:
+		return "Search wrapped to the bottom", false
+	case wrapped:
+		return "Search wrapped to the top", false
+	}
+	return "", false
+}
+
+// JumpToMatch selects the next item after from (before it when backward)
+// that matches the search, wrapping around the list. It reports whether the
+// search wrapped, and leaves the selection alone when nothing matches.
+func JumpToMatch(l *list.Model, from int, backward bool) (found, wrapped bool) {
+	items := l.Items()
+	index := NextMatch(len(items), from, backward, func(i int) bool {
+		item, ok := items[i].(SearchMatcher)
+		return ok && item.SearchMatch()
+	})
+	if index < 0 {
+		return false, false
+	}
+	l.Select(index)
+	if backward {
+		return true, index >= from
+	}
+	return true, index <= from
+}
+
+// selectionMatches reports whether the selected item matches the search
+func selectionMatches(l list.Model) bool {
+	item, ok := l.SelectedItem().(SearchMatcher)
+	return ok && item.SearchMatch()
+}
@@ -0,0 +1,117 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNextMatch(t *testing.T) {
+	// Items 1, 4 and 7 of 9 match
+	match := func(i int) bool { return i%3 == 1 }
+
+	tests := []struct {
+		name     string
+		from     int
+		backward bool
+		want     int
+	}{
+		{"next", 1, false, 4},
+		{"next from a non-match", 2, false, 4},
+		{"next wraps to the top", 7, false, 1},
+		{"next wraps from the last item", 8, false, 1},
+		{"previous", 7, true, 4},
+		{"previous wraps to the bottom", 1, true, 7},
+		{"previous wraps from the first item", 0, true, 7},
+		{"before the first item", -1, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextMatch(9, tt.from, tt.backward, match); got != tt.want {
+				t.Errorf("NextMatch(9, %d, %v) = %d, want %d", tt.from, tt.backward, got, tt.want)
+			}
+		})
+	}
+
+	// A single match is found again from itself
+	only := func(i int) bool { return i == 5 }
+	if got := NextMatch(9, 5, false, only); got != 5 {
+		t.Errorf("NextMatch() from the only match = %d, want 5", got)
+	}
+	if got := NextMatch(9, 0, false, func(int) bool { return false }); got != -1 {
+		t.Errorf("NextMatch() without matches = %d, want -1", got)
+	}
+	if got := NextMatch(0, 0, false, match); got != -1 {
+		t.Errorf("NextMatch() on an empty list = %d, want -1", got)
+	}
+}
+
+// searchItem is a list item marked by a Search
+type searchItem struct {
+	name  string
+	match bool
+}
+
+func (i searchItem) FilterValue() string { return i.name }
+func (i searchItem) SearchMatch() bool   { return i.match }
+
+func TestSearch(t *testing.T) {
+	names := []string{"billing", "orders", "payments", "orders-dlq"}
+	l := list.New(nil, list.NewDefaultDelegate(), 40, 20)
+	s := NewSearch()
+	refresh := func() {
+		items := make([]list.Item, len(names))
+		for i, name := range names {
+			items[i] = searchItem{name: name, match: s.Match(name)}
+		}
+		l.SetItems(items)
+	}
+	refresh()
+	typeText := func(text string) {
+		for _, r := range text {
+			s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}, &l, refresh)
+		}
+	}
+
+	// The selection follows the first match after the starting point
+	l.Select(2)
+	s.Start(l.Index())
+	typeText("ord")
+	if l.Index() != 3 {
+		t.Errorf("typing: Index() = %d, want 3", l.Index())
+	}
+	closed, noMatch, _ := s.Update(tea.KeyMsg{Type: tea.KeyEnter}, &l, refresh)
+	if !closed || noMatch || !s.Active() {
+		t.Fatalf("Enter: closed %v, noMatch %v, active %v, want a kept search", closed, noMatch, s.Active())
+	}
+
+	if status, _ := s.Next(&l, false, "topics"); l.Index() != 1 || status != "Search wrapped to the top" {
+		t.Errorf("Next: Index() = %d, status %q, want 1 wrapped to the top", l.Index(), status)
+	}
+	if status, _ := s.Next(&l, true, "topics"); l.Index() != 3 || status != "Search wrapped to the bottom" {
+		t.Errorf("previous: Index() = %d, status %q, want 3 wrapped to the bottom", l.Index(), status)
+	}
+
+	// Esc restores the previous search and selection
+	s.Start(l.Index())
+	typeText("zzz")
+	if status, isError := s.Next(&l, false, "topics"); status != "No topics match: ordzzz" || !isError {
+		t.Errorf("Next without matches: status %q, isError %v", status, isError)
+	}
+	s.Update(tea.KeyMsg{Type: tea.KeyEsc}, &l, refresh)
+	if s.Text() != "ord" || l.Index() != 3 {
+		t.Errorf("Esc: search %q, Index() %d, want ord at 3", s.Text(), l.Index())
+	}
+
+	// Enter reports a selection that does not match
+	l.Select(0)
+	s.Start(l.Index())
+	typeText("x")
+	if _, noMatch, _ := s.Update(tea.KeyMsg{Type: tea.KeyEnter}, &l, refresh); !noMatch {
+		t.Error("Enter on a non-matching selection should report no match")
+	}
+	if s.Err() != nil {
+		t.Errorf("Err() = %v, want nil", s.Err())
+	}
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Panel status messages clear themselves after these durations. Errors stay
+// longer so they are not missed.
+const (
+	StatusSuccessDuration = 4 * time.Second
+	StatusErrorDuration   = 10 * time.Second
+	StatusTickInterval    = time.Second
+)
+
+// StatusTickMsg asks the panels to clear status messages that have expired
+type StatusTickMsg struct {
+	Time time.Time
+}
+
+// StatusTick returns a command that sends a StatusTickMsg after the tick
+// interval
+func StatusTick() tea.Cmd {
+	return tea.Tick(StatusTickInterval, func(t time.Time) tea.Msg {
+		return StatusTickMsg{Time: t}
+	})
+}
+
+// StatusExpiry returns when a status message set at now should clear.
+// Progress messages are set without an expiry instead, since the result
+// replaces them.
+func StatusExpiry(isError bool, now time.Time) time.Time {
+	if isError {
+		return now.Add(StatusErrorDuration)
+	}
+	return now.Add(StatusSuccessDuration)
+}
+
+// StatusExpired reports whether a status message with the given expiry
+// should be cleared at now
+func StatusExpired(expiry, now time.Time) bool {
+	return !expiry.IsZero() && !now.Before(expiry)
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		isError bool
+		want    time.Time
+	}{
+		{"success", false, now.Add(StatusSuccessDuration)},
+		{"error", true, now.Add(StatusErrorDuration)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusExpiry(tt.isError, now); !got.Equal(tt.want) {
+				t.Errorf("StatusExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if StatusErrorDuration <= StatusSuccessDuration {
+		t.Error("errors should stay longer than successes")
+	}
+}
+
+func TestStatusExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if StatusExpired(time.Time{}, now) {
+		t.Error("zero expiry should never expire")
+	}
+	if StatusExpired(now.Add(time.Second), now) {
+		t.Error("status should not expire before its expiry")
+	}
+	if !StatusExpired(now, now) {
+		t.Error("status should expire at its expiry")
+	}
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -112,7 +113,7 @@ func BorderedPanel(title string, content string, focused bool, width, height int
 			line = line + strings.Repeat(" ", innerWidth-lineWidth)
 		} else if lineWidth > innerWidth {
 			// Truncate - this is simplified, proper truncation would need rune handling
-			line = truncateString(line, innerWidth)
+			line = TruncateString(line, innerWidth)
 		}
 		lines = append(lines, vertical+line+vertical)
 	}
@@ -122,6 +123,17 @@ func BorderedPanel(title string, content string, focused bool, width, height int
 	return result
 }
 
+// TooSmallView renders a notice, centered in width x height, that the
+// terminal must be at least needWidth x needHeight
+func TooSmallView(width, height, needWidth, needHeight int) string {
+	notice := fmt.Sprintf("Terminal too small (need at least %dx%d)", needWidth, needHeight)
+	if width < 1 {
+		width = 1
+	}
+	style := LogWarningStyle.Copy().Width(width).Align(lipgloss.Center)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, style.Render(notice))
+}
+
 // repeatString repeats a styled string n times
 func repeatString(s string, n int) string {
 	if n <= 0 {
@@ -130,8 +142,8 @@ func repeatString(s string, n int) string {
 	return strings.Repeat(s, n)
 }
 
-// truncateString truncates a string to fit within maxWidth
-func truncateString(s string, maxWidth int) string {
+// TruncateString truncates a string to fit within maxWidth
+func TruncateString(s string, maxWidth int) string {
 	if lipgloss.Width(s) <= maxWidth {
 		return s
 	}
@@ -215,6 +227,17 @@ var (
 
 	FooterProjectStyle = lipgloss.NewStyle().
 				Foreground(ColorSecondary)
+
+	FooterEmulatorStyle = lipgloss.NewStyle().
+				Foreground(ColorWarning).
+				Bold(true)
+
+	// AuthBannerStyle for the re-authentication banner above the footer
+	AuthBannerStyle = lipgloss.NewStyle().
+			Foreground(ColorTextBright).
+			Background(ColorError).
+			Bold(true).
+			Padding(0, 1)
 )
 
 // Filter styles
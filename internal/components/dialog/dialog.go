@@ -15,12 +15,25 @@ type Type int
 const (
 	TypeConfirm Type = iota // Yes/No confirmation
 	TypeInput               // Text input
+	TypeForm                // Multiple labeled text inputs
 )
 
+// Field describes an input field in a form dialog
+type Field struct {
+	Label       string
+	Placeholder string
+	Value       string // Initial value, if any
+}
+
+// ValidateFunc checks form values before the dialog is confirmed.
+// A non-nil error is shown inline and keeps the dialog open.
+type ValidateFunc func(values []string) error
+
 // Result represents the result of a dialog
 type Result struct {
 	Confirmed bool
 	Value     string
+	Values    []string    // Form field values, in field order
 	Context   interface{} // Optional context data
 }
 
@@ -30,6 +43,15 @@ type ResultMsg struct {
 	Result Result
 }
 
+// Dialog box sizing
+const (
+	maxBoxWidth   = 50 // Widest the box gets, excluding its border
+	minBoxWidth   = 30 // Narrower terminals show a "too small" notice instead
+	boxBorder     = 2  // Border columns around the box
+	boxPadding    = 4  // Padding columns inside the border
+	maxInputWidth = 40 // Widest a text input gets
+)
+
 // Model represents a dialog box
 type Model struct {
 	id          string
@@ -40,6 +62,17 @@ type Model struct {
 	visible     bool
 	context     interface{}
 	placeholder string
+
+	// Form state
+	fields     []textinput.Model
+	labels     []string
+	focusIndex int
+	validate   ValidateFunc
+	err        string
+
+	// Terminal size the dialog is centered in
+	width  int
+	height int
 }
 
 // New creates a new dialog model
@@ -47,7 +80,7 @@ func New() Model {
 	ti := textinput.New()
 	ti.Focus()
 	ti.CharLimit = 255
-	ti.Width = 40
+	ti.Width = maxInputWidth
 
 	return Model{
 		input: ti,
@@ -76,6 +109,88 @@ func (m *Model) ShowInput(id, title, message, placeholder string, context interf
 	m.input.SetValue("")
 	m.input.Placeholder = placeholder
 	m.input.Focus()
+	m.sizeInputs()
+}
+
+// ShowForm shows a form dialog with one text input per field. validate may
+// be nil; otherwise it is called on confirm and errors are shown inline.
+func (m *Model) ShowForm(id, title, message string, fields []Field, validate ValidateFunc, context interface{}) {
+	m.id = id
+	m.dialogType = TypeForm
+	m.title = title
+	m.message = message
+	m.visible = true
+	m.context = context
+	m.validate = validate
+	m.err = ""
+	m.focusIndex = 0
+
+	m.fields = make([]textinput.Model, len(fields))
+	m.labels = make([]string, len(fields))
+	for i, f := range fields {
+		ti := textinput.New()
+		ti.CharLimit = 0
+		ti.Placeholder = f.Placeholder
+		ti.SetValue(f.Value)
+		m.fields[i] = ti
+		m.labels[i] = f.Label
+	}
+	if len(m.fields) > 0 {
+		m.fields[0].Focus()
+	}
+	m.sizeInputs()
+}
+
+// SetSize sets the terminal size the dialog is centered in, narrowing the
+// box and its inputs on small terminals
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.sizeInputs()
+}
+
+// boxWidth returns the width of the dialog box, excluding its border
+func (m Model) boxWidth() int {
+	width := m.width - boxBorder
+	if width > maxBoxWidth || m.width == 0 {
+		width = maxBoxWidth
+	}
+	return width
+}
+
+// sizeInputs fits the text inputs inside the dialog box
+func (m *Model) sizeInputs() {
+	// Leave room for the "> " prompt and the cursor
+	width := m.boxWidth() - boxPadding - 3
+	if width > maxInputWidth {
+		width = maxInputWidth
+	}
+	if width < 1 {
+		width = 1
+	}
+	m.input.Width = width
+	for i := range m.fields {
+		m.fields[i].Width = width
+	}
+}
+
+// focusField moves input focus to the field at index i, wrapping around
+func (m *Model) focusField(i int) {
+	if len(m.fields) == 0 {
+		return
+	}
+	m.fields[m.focusIndex].Blur()
+	m.focusIndex = (i + len(m.fields)) % len(m.fields)
+	m.fields[m.focusIndex].Focus()
+}
+
+// formValues returns the current value of each form field
+func (m Model) formValues() []string {
+	values := make([]string, len(m.fields))
+	for i, f := range m.fields {
+		values[i] = f.Value()
+	}
+	return values
 }
 
 // Hide hides the dialog
@@ -93,8 +208,9 @@ func (m Model) ID() string {
 	return m.id
 }
 
-// View renders the dialog
-func (m Model) View(width, height int) string {
+// View renders the dialog centered in the terminal, or a notice when the
+// terminal is too small to show it
+func (m Model) View() string {
 	if !m.visible {
 		return ""
 	}
@@ -113,31 +229,46 @@ func (m Model) View(width, height int) string {
 	content.WriteString("\n\n")
 
 	// Input or buttons
-	if m.dialogType == TypeInput {
+	switch m.dialogType {
+	case TypeInput:
 		content.WriteString(m.input.View())
 		content.WriteString("\n\n")
 		content.WriteString(common.MutedText.Render("Enter: confirm  Esc: cancel"))
-	} else {
+	case TypeForm:
+		for i, f := range m.fields {
+			content.WriteString(common.MutedText.Render(m.labels[i]))
+			content.WriteString("\n")
+			content.WriteString(f.View())
+			content.WriteString("\n\n")
+		}
+		if m.err != "" {
+			content.WriteString(common.LogErrorStyle.Render(m.err))
+			content.WriteString("\n\n")
+		}
+		content.WriteString(common.MutedText.Render("Tab: next field  Enter: confirm  Esc: cancel"))
+	default:
 		content.WriteString(common.MutedText.Render("y: yes  n/Esc: no"))
 	}
 
 	dialogContent := content.String()
 
 	// Dialog box style
-	dialogWidth := 50
-
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(common.ColorPrimary).
 		Padding(1, 2).
-		Width(dialogWidth)
+		Width(m.boxWidth())
 
 	dialog := boxStyle.Render(dialogContent)
 
+	if m.width < minBoxWidth+boxBorder || lipgloss.Height(dialog) > m.height {
+		return common.TooSmallView(m.width, m.height, minBoxWidth+boxBorder, lipgloss.Height(dialog))
+	}
+
 	// Center the dialog
 	dialogRendered := lipgloss.Place(
-		width,
-		height,
+		m.width,
+		m.height,
 		lipgloss.Center,
 		lipgloss.Center,
 		dialog,
@@ -176,6 +307,39 @@ func (m Model) Update(keyStr string, keyType string) (Model, *ResultMsg) {
 				},
 			}
 		}
+	} else if m.dialogType == TypeForm {
+		switch keyType {
+		case "tab", "down":
+			m.focusField(m.focusIndex + 1)
+		case "shift+tab", "up":
+			m.focusField(m.focusIndex - 1)
+		case "enter":
+			values := m.formValues()
+			if m.validate != nil {
+				if err := m.validate(values); err != nil {
+					m.err = err.Error()
+					return m, nil
+				}
+			}
+			m.visible = false
+			return m, &ResultMsg{
+				ID: m.id,
+				Result: Result{
+					Confirmed: true,
+					Values:    values,
+					Context:   m.context,
+				},
+			}
+		case "esc":
+			m.visible = false
+			return m, &ResultMsg{
+				ID: m.id,
+				Result: Result{
+					Confirmed: false,
+					Context:   m.context,
+				},
+			}
+		}
 	} else {
 		switch keyType {
 		case "enter":
@@ -206,9 +370,23 @@ func (m Model) Update(keyStr string, keyType string) (Model, *ResultMsg) {
 	return m, nil
 }
 
-// UpdateInput updates the text input
+// UpdateInput updates the text input, or the focused field of a form
 func (m *Model) UpdateInput(msg interface{}) {
-	if m.dialogType == TypeInput && m.visible {
+	if !m.visible {
+		return
+	}
+	switch m.dialogType {
+	case TypeInput:
 		m.input, _ = m.input.Update(msg)
+	case TypeForm:
+		if len(m.fields) == 0 {
+			return
+		}
+		before := m.fields[m.focusIndex].Value()
+		m.fields[m.focusIndex], _ = m.fields[m.focusIndex].Update(msg)
+		if m.fields[m.focusIndex].Value() != before {
+			// Clear the validation error once the user edits
+			m.err = ""
+		}
 	}
 }
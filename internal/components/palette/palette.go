@@ -0,0 +1,276 @@
+package palette
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Action is a command palette entry. Choosing it sends Msg.
+type Action struct {
+	Name string  // Shown and searched, e.g. "Create topic"
+	Key  string  // Key that runs the action directly, shown as a hint
+	Msg  tea.Msg // Sent when the action is chosen
+}
+
+// Palette box sizing
+const (
+	maxBoxWidth = 60 // Widest the box gets, excluding its border
+	minBoxWidth = 30 // Narrower terminals show a "too small" notice instead
+	boxBorder   = 2  // Border columns around the box
+	boxPadding  = 2  // Padding columns inside the border
+	maxRows     = 12 // Most actions listed at once
+)
+
+// Model is a command palette: a searchable list of actions shown over the
+// other panels
+type Model struct {
+	input   textinput.Model
+	actions []Action
+	matches []int // Indexes of the actions matching the input, best first
+	cursor  int   // Selected entry of matches
+	offset  int   // First entry of matches shown
+	visible bool
+
+	// Terminal size the palette is centered in
+	width  int
+	height int
+}
+
+// New creates a hidden command palette
+func New() Model {
+	ti := textinput.New()
+	ti.Prompt = ": "
+	ti.Placeholder = "type to search actions..."
+	ti.CharLimit = 64
+	ti.PromptStyle = common.FilterPromptStyle
+	ti.TextStyle = common.FilterInputStyle
+	return Model{input: ti}
+}
+
+// Show opens the palette with actions, clearing the previous search
+func (m *Model) Show(actions []Action) {
+	m.actions = actions
+	m.visible = true
+	m.input.SetValue("")
+	m.input.Focus()
+	m.refilter()
+}
+
+// Hide closes the palette
+func (m *Model) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the palette is open
+func (m Model) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the terminal size the palette is centered in
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = m.boxWidth() - boxPadding - 4
+}
+
+// boxWidth returns the width of the palette box, excluding its border
+func (m Model) boxWidth() int {
+	width := m.width - boxBorder
+	if width > maxBoxWidth || m.width == 0 {
+		width = maxBoxWidth
+	}
+	return width
+}
+
+// Selected returns the selected action, and false when nothing matches
+func (m Model) Selected() (Action, bool) {
+	if m.cursor >= len(m.matches) {
+		return Action{}, false
+	}
+	return m.actions[m.matches[m.cursor]], true
+}
+
+// Update handles a key while the palette is open. Esc closes it; Enter
+// closes it and returns a command sending the selected action's message.
+func (m Model) Update(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.Hide()
+		return m, nil
+
+	case tea.KeyEnter:
+		action, ok := m.Selected()
+		if !ok {
+			return m, nil
+		}
+		m.Hide()
+		return m, func() tea.Msg {
+			return action.Msg
+		}
+
+	case tea.KeyUp, tea.KeyCtrlP:
+		m.moveCursor(-1)
+		return m, nil
+
+	case tea.KeyDown, tea.KeyCtrlN:
+		m.moveCursor(1)
+		return m, nil
+	}
+
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != before {
+		m.refilter()
+	}
+	return m, cmd
+}
+
+// moveCursor moves the selection by delta, wrapping around, and scrolls it
+// into view
+func (m *Model) moveCursor(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.cursor = (m.cursor + delta + len(m.matches)) % len(m.matches)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+maxRows {
+		m.offset = m.cursor - maxRows + 1
+	}
+}
+
+// refilter lists the actions matching the input and selects the best
+func (m *Model) refilter() {
+	query := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	type match struct {
+		index int
+		score int
+	}
+	var found []match
+	for i, a := range m.actions {
+		if score, ok := fuzzyScore(strings.ToLower(a.Name), query); ok {
+			found = append(found, match{i, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].score < found[j].score
+	})
+
+	m.matches = m.matches[:0]
+	for _, f := range found {
+		m.matches = append(m.matches, f.index)
+	}
+	m.cursor = 0
+	m.offset = 0
+}
+
+// fuzzyScore reports whether the letters of query appear in name in order,
+// and how well: names containing query as a word start score 0, anywhere
+// else as a substring 1, and scattered letters 2 plus their spread
+func fuzzyScore(name, query string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	if i := strings.Index(name, query); i >= 0 {
+		if i == 0 || name[i-1] == ' ' {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	first, last := -1, -1
+	pos := 0
+	for _, r := range query {
+		i := strings.IndexRune(name[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		if first < 0 {
+			first = pos + i
+		}
+		last = pos + i
+		pos += i + len(string(r))
+	}
+	return 2 + last - first, true
+}
+
+// View renders the palette centered in the terminal, or a notice when the
+// terminal is too small to show it
+func (m Model) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	width := m.boxWidth() - boxPadding
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Foreground(common.ColorPrimary).Bold(true).Render("Command Palette"))
+	content.WriteString("\n\n")
+	content.WriteString(m.input.View())
+	content.WriteString("\n\n")
+
+	if len(m.matches) == 0 {
+		content.WriteString(common.MutedText.Render("No matching actions"))
+		content.WriteString("\n")
+	}
+	end := m.offset + maxRows
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+	for i := m.offset; i < end; i++ {
+		content.WriteString(m.renderRow(m.actions[m.matches[i]], i == m.cursor, width))
+		content.WriteString("\n")
+	}
+	if len(m.matches) > maxRows {
+		content.WriteString(common.MutedText.Render(fmt.Sprintf("  (%d/%d)", m.cursor+1, len(m.matches))))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(common.MutedText.Render("↑/↓: select  Enter: run  Esc: close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(common.ColorPrimary).
+		Padding(0, 1).
+		Width(m.boxWidth()).
+		Render(content.String())
+
+	if m.width < minBoxWidth+boxBorder || lipgloss.Height(box) > m.height {
+		return common.TooSmallView(m.width, m.height, minBoxWidth+boxBorder, lipgloss.Height(box))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderRow renders an action with its key hint right-aligned, cutting the
+// name to fit width
+func (m Model) renderRow(a Action, selected bool, width int) string {
+	hint := a.Key
+	name := a.Name
+	room := width - 2 - lipgloss.Width(hint) - 1
+	if lipgloss.Width(name) > room && room > 3 {
+		name = string([]rune(name)[:room-3]) + "..."
+	}
+	gap := width - 2 - lipgloss.Width(name) - lipgloss.Width(hint)
+	if gap < 1 {
+		gap = 1
+	}
+
+	if selected {
+		return common.SelectedItem.Render("> "+name) + strings.Repeat(" ", gap) + common.FooterKeyStyle.Render(hint)
+	}
+	return common.NormalText.Render("  "+name) + strings.Repeat(" ", gap) + common.MutedText.Render(hint)
+}
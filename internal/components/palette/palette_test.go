@@ -0,0 +1,105 @@
+package palette
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type refreshMsg struct{}
+type createMsg struct{}
+type quitMsg struct{}
+
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestModel_Dispatch(t *testing.T) {
+	actions := []Action{
+		{Name: "Refresh topics", Msg: refreshMsg{}},
+		{Name: "Create topic", Key: "n", Msg: createMsg{}},
+		{Name: "Quit", Key: "q", Msg: quitMsg{}},
+	}
+	m := New()
+	m.SetSize(100, 40)
+	m.Show(actions)
+
+	// Enter sends the selected action's message and closes the palette
+	m = typeText(m, "create")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsVisible() || cmd == nil {
+		t.Fatal("enter should close the palette and run the action")
+	}
+	if _, ok := cmd().(createMsg); !ok {
+		t.Errorf("enter sent %T, want createMsg", cmd())
+	}
+
+	// Showing again clears the search; arrows move the selection
+	m.Show(actions)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if a, _ := m.Selected(); a.Name != "Quit" {
+		t.Errorf("selected %q after moving down twice, want Quit", a.Name)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if a, _ := m.Selected(); a.Name != "Refresh topics" {
+		t.Errorf("selected %q after wrapping, want Refresh topics", a.Name)
+	}
+
+	// Esc closes without running anything
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.IsVisible() || cmd != nil {
+		t.Error("esc should close the palette without running an action")
+	}
+
+	// Enter with no match does nothing
+	m.Show(actions)
+	m = typeText(m, "zzz")
+	if m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !m.IsVisible() {
+		t.Error("enter without a match should keep the palette open")
+	}
+}
+
+func TestModel_FuzzySearch(t *testing.T) {
+	m := New()
+	m.Show([]Action{
+		{Name: "Delete selected topic"},
+		{Name: "Toggle publish history"},
+		{Name: "Create topic"},
+		{Name: "Filter topics"},
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Delete selected topic", "Toggle publish history", "Create topic", "Filter topics"}},
+		// Word starts rank first, keeping their order, then scattered letters
+		{"top", []string{"Delete selected topic", "Create topic", "Filter topics", "Toggle publish history"}},
+		{"TOP", []string{"Delete selected topic", "Create topic", "Filter topics", "Toggle publish history"}},
+		// Other substrings rank before scattered letters, closest first
+		{"lt", []string{"Filter topics", "Delete selected topic", "Toggle publish history"}},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		m.Show(m.actions)
+		m = typeText(m, tt.query)
+		var got []string
+		for _, i := range m.matches {
+			got = append(got, m.actions[i].Name)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q matched %q, want %q", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q matched %q, want %q", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MaxBatchCount bounds how many copies a single batch publish may send
+const MaxBatchCount = 10000
+
+// BatchPublishMsg requests publishing the same message Count times
+type BatchPublishMsg struct {
+	Topic   string
+	Content []byte
+	Count   int
+}
+
+// BatchPublishResultMsg is sent when every message of a batch has completed
+type BatchPublishResultMsg struct {
+	Topic     string
+	Published int
+	Errors    []error
+}
+
+// parseBatchCount parses the number of copies for a batch publish
+func parseBatchCount(input string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || n <= 0 || n > MaxBatchCount {
+		return 0, fmt.Errorf("count must be between 1 and %d", MaxBatchCount)
+	}
+	return n, nil
+}
+
+// StartBatch opens the count prompt for a batch publish
+func (m *Model) StartBatch() {
+	m.batchInput.SetValue("")
+	m.batchInput.Focus()
+	m.focusArea = FocusBatch
+}
+
+// CancelBatch closes the count prompt
+func (m *Model) CancelBatch() {
+	m.batchInput.Blur()
+	m.focusArea = FocusFileList
+}
+
+// handleBatchInput handles keyboard input in the batch count prompt
+func (m Model) handleBatchInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.CancelBatch()
+		return m, nil
+
+	case tea.KeyEnter:
+		count, err := parseBatchCount(m.batchInput.Value())
+		if err != nil {
+			m.SetStatus(err.Error(), true)
+			return m, nil
+		}
+
+		m.CancelBatch()
+		content := m.GetMessageContent()
+		if m.targetTopic == "" || content == "" {
+			m.SetStatus("Nothing to publish: select a topic and a file", true)
+			return m, nil
+		}
+
+		m.SetPublishing(true)
+		m.SetProgress(fmt.Sprintf("Publishing %d messages...", count))
+		topic := m.targetTopic
+		return m, func() tea.Msg {
+			return BatchPublishMsg{
+				Topic:   topic,
+				Content: []byte(content),
+				Count:   count,
+			}
+		}
+
+	default:
+		var cmd tea.Cmd
+		m.batchInput, cmd = m.batchInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleBatchResult reports the outcome of a batch publish
+func (m Model) handleBatchResult(msg BatchPublishResultMsg) (Model, tea.Cmd) {
+	m.SetPublishing(false)
+
+	if len(msg.Errors) == 0 {
+		m.SetStatus(fmt.Sprintf("Published %d messages", msg.Published), false)
+		return m, func() tea.Msg {
+			return common.Success(fmt.Sprintf("Published %d messages to %s", msg.Published, msg.Topic))
+		}
+	}
+
+	if anyCancelled(msg.Errors) {
+		m.SetStatus("Publish cancelled", false)
+		return m, func() tea.Msg {
+			return common.Warning(fmt.Sprintf("Batch publish to %s cancelled after %d of %d messages (the rest may still be delivered)",
+				msg.Topic, msg.Published, msg.Published+len(msg.Errors)))
+		}
+	}
+
+	summary := fmt.Sprintf("Batch publish to %s: %d published, %d failed", msg.Topic, msg.Published, len(msg.Errors))
+	m.SetStatus(summary, true)
+	return m, func() tea.Msg {
+		return common.ErrorLog(summary+", first error", msg.Errors[0])
+	}
+}
+
+// anyCancelled reports whether any of errs comes from a cancelled publish
+func anyCancelled(errs []error) bool {
+	for _, err := range errs {
+		if errors.Is(err, context.Canceled) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FanOutPublishMsg requests publishing the same message to several topics
+type FanOutPublishMsg struct {
+	Topics  []string
+	Content []byte
+}
+
+// TopicPublishResult is the outcome of publishing to one topic of a fan-out
+type TopicPublishResult struct {
+	Topic     string
+	TopicFull string // Full resource name the server resolved the topic to
+	MessageID string
+	Err       error
+}
+
+// FanOutPublishResultMsg is sent when the publish to every topic completed
+type FanOutPublishResultMsg struct {
+	Content []byte
+	Results []TopicPublishResult
+}
+
+// SetMarkedTopics sets the topics marked in the topics panel. While any are
+// marked, Enter publishes to all of them instead of the target topic.
+func (m *Model) SetMarkedTopics(topics []string) {
+	m.markedTopics = topics
+}
+
+// MarkedTopics returns the topics Enter publishes to, if several are marked
+func (m Model) MarkedTopics() []string {
+	return m.markedTopics
+}
+
+// topicLabel names a topic published to by its short name followed by its
+// full resource name, so the log shows exactly where a message went
+func topicLabel(topic, fullName string) string {
+	if fullName == "" || fullName == topic {
+		return topic
+	}
+	return fmt.Sprintf("%s (%s)", topic, fullName)
+}
+
+// handleFanOutResult reports the outcome of a fan-out publish, logging each
+// topic's result to the activity log
+func (m Model) handleFanOutResult(msg FanOutPublishResultMsg) (Model, tea.Cmd) {
+	m.SetPublishing(false)
+
+	var failed []string
+	cancelled := false
+	cmds := make([]tea.Cmd, 0, len(msg.Results)+1)
+	for _, result := range msg.Results {
+		result := result
+		m.recordPublish(result.Topic, result.MessageID, msg.Content, result.Err)
+		if errors.Is(result.Err, context.Canceled) {
+			cancelled = true
+			cmds = append(cmds, func() tea.Msg {
+				return common.Warning("Publish to " + result.Topic + " cancelled (the message may still be delivered)")
+			})
+			continue
+		}
+		if result.Err != nil {
+			failed = append(failed, result.Topic)
+			cmds = append(cmds, func() tea.Msg {
+				return common.ErrorLog("Publish to "+result.Topic+" failed", result.Err)
+			})
+			continue
+		}
+		cmds = append(cmds, func() tea.Msg {
+			return common.Success(fmt.Sprintf("Published message %s to %s", result.MessageID, topicLabel(result.Topic, result.TopicFull)))
+		})
+	}
+
+	if cancelled {
+		m.SetStatus("Publish cancelled", false)
+		return m, tea.Sequence(cmds...)
+	}
+
+	published := len(msg.Results) - len(failed)
+	if len(failed) == 0 {
+		m.SetStatus(fmt.Sprintf("Published to %d topic(s)", published), false)
+		return m, tea.Sequence(cmds...)
+	}
+
+	summary := fmt.Sprintf("Published to %d/%d topics, failed: %s", published, len(msg.Results), strings.Join(failed, ", "))
+	m.SetStatus(summary, true)
+	cmds = append(cmds, func() tea.Msg {
+		return common.Warning(summary)
+	})
+	return m, tea.Sequence(cmds...)
+}
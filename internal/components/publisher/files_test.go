@@ -0,0 +1,177 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestModel_SetTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "order.json")
+	if err := os.WriteFile(path, []byte(`{"id":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	template := utils.JSONFile{Name: path, Path: path}
+
+	m := New()
+	m.SetSize(100, 30)
+	m.SetTemplate(template)
+	if f := m.SelectedFile(); f == nil || f.Path != path {
+		t.Fatalf("SelectedFile() = %v, want the template", f)
+	}
+	if got := m.GetMessageContent(); got != `{"id":1}` {
+		t.Errorf("GetMessageContent() = %q, want the template content", got)
+	}
+
+	// Reloading the working directory keeps the template listed and selected
+	m.SetFiles([]utils.JSONFile{{Name: "a.json", Path: "/work/a.json"}})
+	if len(m.allFiles) != 2 || m.allFiles[0].Path != path {
+		t.Errorf("files = %v, want the template first", m.allFiles)
+	}
+	if f := m.SelectedFile(); f == nil || f.Path != path {
+		t.Errorf("SelectedFile() after reload = %v, want the template", f)
+	}
+
+	// A template inside the working directory is not listed twice
+	m.SetFiles([]utils.JSONFile{template})
+	if len(m.allFiles) != 1 {
+		t.Errorf("files = %v, want the template once", m.allFiles)
+	}
+}
+
+func TestValidateTemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "order.json")
+	if err := os.WriteFile(file, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateTemplateDir(dir); err != nil {
+		t.Errorf("ValidateTemplateDir(dir) error = %v", err)
+	}
+	if err := ValidateTemplateDir(file); err == nil {
+		t.Error("ValidateTemplateDir(file) should fail")
+	}
+	if err := ValidateTemplateDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("ValidateTemplateDir(missing) should fail")
+	}
+}
+
+func TestModel_FilesDirStatus(t *testing.T) {
+	m := New()
+	m.SetFilesDir("samples")
+	m, _ = m.Update(FilesLoadedMsg{})
+	if want := "No JSON files found in samples"; m.status != want {
+		t.Errorf("status = %q, want %q", m.status, want)
+	}
+}
+
+func TestLoadFiles_Recursive(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "orders", "created.json")
+	if err := os.MkdirAll(filepath.Dir(nested), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(nested, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	flat := LoadFiles(dir, false)().(FilesLoadedMsg)
+	if flat.Err != nil || len(flat.Files) != 0 {
+		t.Errorf("flat LoadFiles() = %+v, want no files", flat)
+	}
+
+	deep := LoadFiles(dir, true)().(FilesLoadedMsg)
+	if deep.Err != nil || len(deep.Files) != 1 || deep.Files[0].Name != filepath.Join("orders", "created.json") {
+		t.Errorf("recursive LoadFiles() = %+v, want orders/created.json", deep)
+	}
+}
+
+func TestModel_SortFiles(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	m.SetFiles([]utils.JSONFile{
+		{Name: "a.json", Path: "/work/a.json", Size: 10, Modified: 300},
+		{Name: "b.json", Path: "/work/b.json", Size: 30, Modified: 100},
+		{Name: "c.json", Path: "/work/c.json", Size: 20, Modified: 200},
+	})
+
+	names := func() string {
+		var s string
+		for _, f := range m.allFiles {
+			s += f.Name[:1]
+		}
+		return s
+	}
+
+	for _, want := range []string{"bca", "acb", "abc"} {
+		m.cycleFileSort()
+		if got := names(); got != want {
+			t.Errorf("after sorting by %s files = %s, want %s", m.fileSort, got, want)
+		}
+		if f := m.SelectedFile(); f == nil || f.Name != "a.json" {
+			t.Errorf("sorting by %s lost the selection: %v", m.fileSort, f)
+		}
+	}
+}
+
+func TestFileItem_Description(t *testing.T) {
+	item := FileItem{size: 1536, modified: time.Now().Add(-3 * time.Minute).Unix()}
+	if got, want := item.Description(), "1.5 KB · 3m ago"; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+}
+
+func TestModel_ReloadPrompt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "order.json")
+	if err := os.WriteFile(path, []byte(`{"id":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New()
+	m.SetSize(100, 30)
+	m.SetFiles([]utils.JSONFile{{Name: "order.json", Path: path}})
+	changed := FileEventMsg{Name: path, Op: fsnotify.Write}
+
+	// Without edits the change reloads silently
+	m, _ = m.Update(changed)
+	if m.GetFocusArea() == FocusConfirmReload {
+		t.Fatal("a change without edits should not prompt")
+	}
+
+	m.StartEditing()
+	m.editor.SetValue(`{"id":2}`)
+	m.ApplyEdits()
+	if err := os.WriteFile(path, []byte(`{"id":3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// n keeps the edits
+	m, _ = m.Update(changed)
+	if m.GetFocusArea() != FocusConfirmReload || !m.IsInputActive() {
+		t.Fatal("a change to an edited file should prompt")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.GetFocusArea() != FocusFileList || m.GetMessageContent() != `{"id":2}` {
+		t.Errorf("after n focus = %v content = %q, want the edits kept", m.GetFocusArea(), m.GetMessageContent())
+	}
+
+	// y reloads from disk, also closing an open editor
+	m.StartEditing()
+	m, _ = m.Update(changed)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if m.GetFocusArea() != FocusFileList || m.HasEdits() {
+		t.Errorf("after y focus = %v hasEdits = %v, want the file list without edits", m.GetFocusArea(), m.HasEdits())
+	}
+	if got := m.GetMessageContent(); got != `{"id":3}` {
+		t.Errorf("GetMessageContent() = %q, want the content on disk", got)
+	}
+}
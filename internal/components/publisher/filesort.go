@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"sort"
+
+	"github.com/anmaso/pubsub-tui/internal/utils"
+)
+
+// fileSortOrder is the order of the file list
+type fileSortOrder int
+
+const (
+	sortByName     fileSortOrder = iota // As loaded: by name, the template first
+	sortBySize                          // Largest first
+	sortByModified                      // Most recently modified first
+)
+
+// String returns the name shown when the order changes
+func (o fileSortOrder) String() string {
+	switch o {
+	case sortBySize:
+		return "size"
+	case sortByModified:
+		return "modified time"
+	default:
+		return "name"
+	}
+}
+
+// sortFiles returns files in the current sort order
+func (m Model) sortFiles(files []utils.JSONFile) []utils.JSONFile {
+	if m.fileSort == sortByName {
+		return files
+	}
+	sorted := append([]utils.JSONFile(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if m.fileSort == sortBySize {
+			return sorted[i].Size > sorted[j].Size
+		}
+		return sorted[i].Modified > sorted[j].Modified
+	})
+	return sorted
+}
+
+// cycleFileSort switches the file list to the next sort order, keeping
+// the selection and any edits
+func (m *Model) cycleFileSort() {
+	m.fileSort = (m.fileSort + 1) % 3
+	m.SetFiles(m.loadedFiles)
+}
@@ -0,0 +1,163 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MaxPublishHistory is the number of recent publishes the history keeps
+const MaxPublishHistory = 20
+
+// PublishRecord is one entry of the publish history
+type PublishRecord struct {
+	Topic     string
+	MessageID string
+	Time      time.Time
+	Content   []byte
+	Err       error
+}
+
+// publishHistory is a ring buffer of the most recent publishes. The zero
+// value is ready to use.
+type publishHistory struct {
+	records [MaxPublishHistory]PublishRecord
+	next    int // Slot the next record is written to
+	count   int
+}
+
+// Add records a publish, overwriting the oldest record once full
+func (h *publishHistory) Add(record PublishRecord) {
+	h.records[h.next] = record
+	h.next = (h.next + 1) % MaxPublishHistory
+	if h.count < MaxPublishHistory {
+		h.count++
+	}
+}
+
+// Len returns the number of records kept
+func (h publishHistory) Len() int {
+	return h.count
+}
+
+// At returns the i-th most recent record, 0 being the newest
+func (h publishHistory) At(i int) PublishRecord {
+	return h.records[(h.next-1-i+MaxPublishHistory)%MaxPublishHistory]
+}
+
+// recordPublish adds a publish result to the history
+func (m *Model) recordPublish(topic, messageID string, content []byte, err error) {
+	m.history.Add(PublishRecord{
+		Topic:     topic,
+		MessageID: messageID,
+		Time:      time.Now(),
+		Content:   content,
+		Err:       err,
+	})
+	// Keep the cursor on the same record as new ones arrive
+	if m.showHistory && m.historyCursor > 0 && m.historyCursor < m.history.Len()-1 {
+		m.historyCursor++
+	}
+}
+
+// IsShowingHistory returns whether the history replaces the file list
+func (m Model) IsShowingHistory() bool {
+	return m.showHistory
+}
+
+// ToggleHistory switches the left side between the file list and the
+// publish history
+func (m *Model) ToggleHistory() {
+	m.showHistory = !m.showHistory
+	m.historyCursor = 0
+}
+
+// SelectedRecord returns the history record under the cursor, if any
+func (m Model) SelectedRecord() *PublishRecord {
+	if !m.showHistory || m.historyCursor >= m.history.Len() {
+		return nil
+	}
+	record := m.history.At(m.historyCursor)
+	return &record
+}
+
+// loadRecord loads a history record's payload as edited content, so the
+// next publish sends it again
+func (m *Model) loadRecord(record PublishRecord) {
+	m.editedContent = string(record.Content)
+	m.hasEdits = true
+	m.showHistory = false
+	m.updatePreview()
+	m.SetStatus("Loaded payload published to "+record.Topic+" (Enter republishes)", false)
+}
+
+// handleHistoryNavigation handles keyboard input while the history is shown
+func (m Model) handleHistoryNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.History), key.Matches(msg, keys.CancelEdit):
+		m.ToggleHistory()
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Down):
+		if m.historyCursor < m.history.Len()-1 {
+			m.historyCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Publish):
+		if record := m.SelectedRecord(); record != nil {
+			m.loadRecord(*record)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// historyView renders the publish history, newest first, in place of the
+// file list
+func (m Model) historyView(width, height int) string {
+	if m.history.Len() == 0 {
+		return common.MutedText.Render("Nothing published yet")
+	}
+
+	// Keep the cursor in view
+	start := 0
+	if m.historyCursor >= height {
+		start = m.historyCursor - height + 1
+	}
+	end := start + height
+	if end > m.history.Len() {
+		end = m.history.Len()
+	}
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		record := m.history.At(i)
+		mark, detail := "✓", record.MessageID
+		if record.Err != nil {
+			mark, detail = "✗", record.Err.Error()
+		}
+		line := common.TruncateString(fmt.Sprintf("%s %s %s %s", mark, record.Time.Format("15:04:05"), record.Topic, detail), width)
+		switch {
+		case i == m.historyCursor:
+			lines = append(lines, common.SelectedItem.Render(line))
+		case record.Err != nil:
+			lines = append(lines, common.LogErrorStyle.Render(line))
+		default:
+			lines = append(lines, common.NormalText.Render(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,105 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPublishHistory(t *testing.T) {
+	var h publishHistory
+	if h.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", h.Len())
+	}
+
+	h.Add(PublishRecord{MessageID: "1"})
+	h.Add(PublishRecord{MessageID: "2"})
+	if h.Len() != 2 || h.At(0).MessageID != "2" || h.At(1).MessageID != "1" {
+		t.Fatalf("Len() = %d, At(0) = %q, want 2 records newest first", h.Len(), h.At(0).MessageID)
+	}
+
+	// Wrapping around drops the oldest records
+	for i := 3; i <= MaxPublishHistory+5; i++ {
+		h.Add(PublishRecord{MessageID: fmt.Sprint(i)})
+	}
+	if h.Len() != MaxPublishHistory {
+		t.Fatalf("Len() = %d, want the cap %d", h.Len(), MaxPublishHistory)
+	}
+	for i := 0; i < MaxPublishHistory; i++ {
+		if got, want := h.At(i).MessageID, fmt.Sprint(MaxPublishHistory+5-i); got != want {
+			t.Errorf("At(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestModel_History(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+
+	m, _ = m.Update(PublishResultMsg{Topic: "orders", Content: []byte(`{"id":1}`), MessageID: "m-1"})
+	m, _ = m.Update(PublishResultMsg{Topic: "billing", Content: []byte(`{"id":2}`), Err: errors.New("not found")})
+
+	h := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}}
+	m, _ = m.Update(h)
+	if !m.IsShowingHistory() {
+		t.Fatal("h should show the history")
+	}
+	if record := m.SelectedRecord(); record == nil || record.Topic != "billing" || record.Err == nil {
+		t.Fatalf("SelectedRecord() = %+v, want the failed billing publish first", record)
+	}
+
+	// Enter reloads the payload for republish
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsShowingHistory() {
+		t.Error("loading a payload should return to the file list")
+	}
+	if !m.HasEdits() || m.GetMessageContent() != `{"id":1}` {
+		t.Errorf("GetMessageContent() = %q (edits %v), want the orders payload", m.GetMessageContent(), m.HasEdits())
+	}
+}
+
+func TestModel_PublishResult(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+
+	m, cmd := m.Update(PublishResultMsg{Topic: "orders", TopicFull: "projects/p/topics/orders", MessageID: "m-1"})
+	if m.status != "Published m-1 to projects/p/topics/orders" || m.statusError {
+		t.Errorf("status = %q (error %v), want the message ID and topic", m.status, m.statusError)
+	}
+	logged, _ := cmd().(common.LogMsg)
+	if want := "Published message m-1 to orders (projects/p/topics/orders)"; logged.Message != want {
+		t.Errorf("logged %q, want %q", logged.Message, want)
+	}
+}
+
+func TestModel_CancelPublish(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+
+	// Esc does nothing without a publish in progress
+	if _, cmd := m.Update(esc); cmd != nil {
+		t.Error("esc should do nothing while idle")
+	}
+
+	m.SetPublishing(true)
+	m, cmd := m.Update(esc)
+	if cmd == nil {
+		t.Fatal("esc should cancel the publish in progress")
+	}
+	if _, ok := cmd().(CancelPublishMsg); !ok {
+		t.Error("esc should request CancelPublishMsg")
+	}
+
+	// The cancelled result is not reported as published
+	m, _ = m.Update(PublishResultMsg{Topic: "orders", Err: fmt.Errorf("publish: %w", context.Canceled)})
+	if m.IsPublishing() || m.status != "Publish cancelled" || m.statusError {
+		t.Errorf("status = %q (error %v), want Publish cancelled", m.status, m.statusError)
+	}
+}
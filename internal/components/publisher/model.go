@@ -1,10 +1,17 @@
 package publisher
 
 import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 	"github.com/anmaso/pubsub-tui/internal/utils"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/fsnotify/fsnotify"
@@ -12,21 +19,35 @@ import (
 
 // FileItem implements list.Item for displaying JSON files
 type FileItem struct {
-	name string
-	path string
-	size int64
+	name     string
+	path     string
+	size     int64
+	modified int64 // Unix time
 }
 
 func (f FileItem) Title() string       { return f.name }
-func (f FileItem) Description() string { return "" }
 func (f FileItem) FilterValue() string { return f.name }
 
+// Description shows the file size and how long ago it was modified
+func (f FileItem) Description() string {
+	desc := common.FormatSize(f.size)
+	if f.modified > 0 {
+		desc += " · " + common.FormatAge(time.Since(time.Unix(f.modified, 0))) + " ago"
+	}
+	return desc
+}
+
 // FocusArea represents which area of the publisher is focused
 type FocusArea int
 
 const (
 	FocusFileList FocusArea = iota
 	FocusVariables
+	FocusEditor
+	FocusSaveName
+	FocusSchedule
+	FocusBatch
+	FocusConfirmReload
 )
 
 // Model represents the state of the publisher panel
@@ -34,22 +55,45 @@ type Model struct {
 	fileList       list.Model
 	variablesInput textinput.Model
 	preview        viewport.Model
+	editor         textarea.Model
+	saveInput      textinput.Model
+	scheduleInput  textinput.Model
+	batchInput     textinput.Model
 
+	loadedFiles    []utils.JSONFile // Files as last loaded, before the template and sorting
 	allFiles       []utils.JSONFile
 	selectedFile   *utils.JSONFile
 	fileContent    string // Raw file content
 	previewContent string // Content with substitutions applied
+	editedContent  string // Content edited in the editor (replaces substitution)
+	hasEdits       bool   // Whether editedContent should be published
+
+	template  *utils.JSONFile // File given at startup, kept in the list
+	filesDir  string          // Directory the files are listed from; "" for the working directory
+	recursive bool            // Whether files in subdirectories are listed too
+	fileSort  fileSortOrder   // Order of the file list
+
+	reloadReturn FocusArea // Area to return to when the reload prompt closes
 
 	width     int
 	height    int
 	focused   bool
 	focusArea FocusArea
 
-	targetTopic string // Topic to publish to
-	status      string // Status message
-	statusError bool   // Whether status is an error
+	targetTopic  string    // Topic to publish to
+	markedTopics []string  // Topics marked in the topics panel, if any
+	status       string    // Status message
+	statusError  bool      // Whether status is an error
+	statusExpiry time.Time // When the status clears itself; zero keeps it
+
+	jsonIndent string // Indentation used to format the preview
 
 	publishing bool // Whether a publish is in progress
+	scheduled  int  // Number of scheduled publishes still pending
+
+	history       publishHistory // Recent publishes, newest first
+	showHistory   bool           // Whether the history replaces the file list
+	historyCursor int            // Selected history record, 0 being the newest
 
 	// File watcher for live directory updates
 	watcher  *fsnotify.Watcher
@@ -60,10 +104,11 @@ type Model struct {
 func New() Model {
 	// Create file list with compact style
 	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = false
 	delegate.SetSpacing(0) // No spacing between items
 	delegate.Styles.SelectedTitle = common.SelectedItem
 	delegate.Styles.NormalTitle = common.NormalText
+	delegate.Styles.SelectedDesc = common.FilterPromptStyle.Copy().PaddingLeft(2)
+	delegate.Styles.NormalDesc = common.MutedText.Copy().PaddingLeft(2)
 
 	fl := list.New([]list.Item{}, delegate, 0, 0)
 	fl.Title = "JSON Files"
@@ -81,17 +126,59 @@ func New() Model {
 	vi.TextStyle = common.FilterInputStyle
 	vi.CharLimit = 512
 
+	// Create save-as input
+	si := textinput.New()
+	si.Placeholder = "file-name.json"
+	si.Prompt = "Save as: "
+	si.PromptStyle = common.FilterPromptStyle
+	si.TextStyle = common.FilterInputStyle
+	si.CharLimit = 255
+
+	// Create schedule delay input
+	sc := textinput.New()
+	sc.Placeholder = "seconds [key=value ...] [@ordering-key]"
+	sc.Prompt = "Publish in (s): "
+	sc.PromptStyle = common.FilterPromptStyle
+	sc.TextStyle = common.FilterInputStyle
+	sc.CharLimit = 512
+
+	// Create batch count input
+	bi := textinput.New()
+	bi.Placeholder = "count"
+	bi.Prompt = "Publish copies: "
+	bi.PromptStyle = common.FilterPromptStyle
+	bi.TextStyle = common.FilterInputStyle
+	bi.CharLimit = 5
+
 	// Create preview viewport
 	pv := viewport.New(0, 0)
 
+	// Create message editor
+	ed := textarea.New()
+	ed.ShowLineNumbers = false
+	ed.CharLimit = 0 // No limit on payload size
+	ed.MaxHeight = 0 // No limit on line count
+	ed.Prompt = ""
+
 	return Model{
 		fileList:       fl,
 		variablesInput: vi,
 		preview:        pv,
+		editor:         ed,
+		saveInput:      si,
+		scheduleInput:  sc,
+		batchInput:     bi,
 		focusArea:      FocusFileList,
+		jsonIndent:     utils.DefaultJSONIndent,
 	}
 }
 
+// SetJSONIndent sets the indentation used to format the preview
+func (m *Model) SetJSONIndent(indent string) {
+	m.jsonIndent = indent
+	m.updatePreview()
+}
+
 // SetFocused sets whether the panel is focused
 func (m *Model) SetFocused(focused bool) {
 	m.focused = focused
@@ -100,6 +187,11 @@ func (m *Model) SetFocused(focused bool) {
 	} else {
 		m.variablesInput.Blur()
 	}
+	if focused && m.focusArea == FocusEditor {
+		m.editor.Focus()
+	} else {
+		m.editor.Blur()
+	}
 }
 
 // IsFocused returns whether the panel is focused
@@ -137,6 +229,8 @@ func (m *Model) SetSize(width, height int) {
 	m.fileList.SetSize(leftWidth, fileListHeight)
 	m.preview.Width = rightWidth
 	m.preview.Height = contentHeight
+	m.editor.SetWidth(rightWidth)
+	m.editor.SetHeight(contentHeight - 1) // Leave room for the editor header
 }
 
 // SetTargetTopic sets the topic to publish to
@@ -157,14 +251,17 @@ func (m *Model) SetFiles(files []utils.JSONFile) {
 		previousPath = m.selectedFile.Path
 	}
 
+	m.loadedFiles = files
+	files = m.sortFiles(m.withTemplate(files))
 	m.allFiles = files
 
 	var items []list.Item
 	for _, f := range files {
 		items = append(items, FileItem{
-			name: f.Name,
-			path: f.Path,
-			size: f.Size,
+			name:     f.Name,
+			path:     f.Path,
+			size:     f.Size,
+			modified: f.Modified,
 		})
 	}
 
@@ -174,9 +271,16 @@ func (m *Model) SetFiles(files []utils.JSONFile) {
 	if previousPath != "" {
 		for i := range files {
 			if files[i].Path == previousPath {
-				// Found the previously selected file - restore selection
+				// Found the previously selected file - restore selection,
+				// keeping any edits made to it
+				editedContent, hasEdits := m.editedContent, m.hasEdits
 				m.fileList.Select(i)
 				m.selectFile(&files[i])
+				if hasEdits {
+					m.editedContent = editedContent
+					m.hasEdits = true
+					m.updatePreview()
+				}
 				return
 			}
 		}
@@ -193,12 +297,15 @@ func (m *Model) SetFiles(files []utils.JSONFile) {
 		m.fileContent = ""
 		m.previewContent = ""
 		m.preview.SetContent("")
+		m.DiscardEdits()
 	}
 }
 
-// selectFile selects a file and loads its content
+// selectFile selects a file and loads its content, discarding any edits
 func (m *Model) selectFile(file *utils.JSONFile) {
 	m.selectedFile = file
+	m.editedContent = ""
+	m.hasEdits = false
 
 	// Load file content
 	content, err := utils.ReadFile(file.Path)
@@ -212,20 +319,18 @@ func (m *Model) selectFile(file *utils.JSONFile) {
 	m.updatePreview()
 }
 
-// updatePreview updates the preview with variable substitutions
+// updatePreview updates the preview with variable substitutions,
+// or with the edited content when edits have been applied
 func (m *Model) updatePreview() {
-	if m.fileContent == "" {
+	content := m.GetMessageContent()
+	if content == "" {
 		m.previewContent = ""
 		m.preview.SetContent("")
 		return
 	}
 
-	// Parse variables and substitute
-	vars := ParseVariables(m.variablesInput.Value())
-	substituted := SubstituteVariables(m.fileContent, vars)
-
 	// Try to format as JSON
-	formatted, _ := utils.FormatJSON([]byte(substituted))
+	formatted, _ := utils.FormatJSONIndent([]byte(content), m.jsonIndent)
 	m.previewContent = formatted
 	m.preview.SetContent(formatted)
 }
@@ -235,8 +340,13 @@ func (m Model) SelectedFile() *utils.JSONFile {
 	return m.selectedFile
 }
 
-// GetMessageContent returns the message content with substitutions applied
+// GetMessageContent returns the message content with substitutions applied.
+// If the content has been edited, the edited content is returned instead.
 func (m Model) GetMessageContent() string {
+	if m.hasEdits {
+		return m.editedContent
+	}
+
 	if m.fileContent == "" {
 		return ""
 	}
@@ -249,12 +359,29 @@ func (m Model) GetMessageContent() string {
 func (m *Model) SetStatus(msg string, isError bool) {
 	m.status = msg
 	m.statusError = isError
+	m.statusExpiry = common.StatusExpiry(isError, time.Now())
+}
+
+// SetProgress sets a status message for work in progress. It does not
+// expire, since the result replaces it.
+func (m *Model) SetProgress(msg string) {
+	m.status = msg
+	m.statusError = false
+	m.statusExpiry = time.Time{}
 }
 
 // ClearStatus clears the status message
 func (m *Model) ClearStatus() {
 	m.status = ""
 	m.statusError = false
+	m.statusExpiry = time.Time{}
+}
+
+// ExpireStatus clears the status message once it has been shown long enough
+func (m *Model) ExpireStatus(now time.Time) {
+	if common.StatusExpired(m.statusExpiry, now) {
+		m.ClearStatus()
+	}
 }
 
 // IsPublishing returns whether a publish is in progress
@@ -274,7 +401,141 @@ func (m Model) GetFocusArea() FocusArea {
 
 // IsInputActive returns whether an input field is active
 func (m Model) IsInputActive() bool {
-	return m.focusArea == FocusVariables
+	return m.focusArea == FocusVariables || m.focusArea == FocusEditor ||
+		m.focusArea == FocusSaveName || m.focusArea == FocusSchedule ||
+		m.focusArea == FocusBatch || m.focusArea == FocusConfirmReload
+}
+
+// IsEditing returns whether the message editor is open
+func (m Model) IsEditing() bool {
+	return m.focusArea == FocusEditor
+}
+
+// HasEdits returns whether edited content will be used for the next publish
+func (m Model) HasEdits() bool {
+	return m.hasEdits
+}
+
+// StartEditing opens the editor with the current message content
+func (m *Model) StartEditing() {
+	m.editor.SetValue(m.GetMessageContent())
+	m.editor.Focus()
+	m.focusArea = FocusEditor
+}
+
+// ApplyEdits closes the editor and uses its content for the next publish
+func (m *Model) ApplyEdits() {
+	m.editedContent = m.editor.Value()
+	m.hasEdits = true
+	m.editor.Blur()
+	m.focusArea = FocusFileList
+	m.updatePreview()
+}
+
+// CancelEditing closes the editor, discarding changes made since it was opened
+func (m *Model) CancelEditing() {
+	m.editor.Blur()
+	m.focusArea = FocusFileList
+}
+
+// DiscardEdits drops any applied edits, reverting to the file content
+func (m *Model) DiscardEdits() {
+	m.editedContent = ""
+	m.hasEdits = false
+	m.updatePreview()
+}
+
+// StartSaving opens the save-as prompt with a name derived from the selected file
+func (m *Model) StartSaving() {
+	m.saveInput.SetValue(defaultSaveName(m.selectedFile))
+	m.saveInput.CursorEnd()
+	m.saveInput.Focus()
+	m.focusArea = FocusSaveName
+}
+
+// CancelSaving closes the save-as prompt
+func (m *Model) CancelSaving() {
+	m.saveInput.Blur()
+	m.focusArea = FocusFileList
+}
+
+// StartScheduling opens the delay prompt for a scheduled publish
+func (m *Model) StartScheduling() {
+	m.scheduleInput.SetValue("")
+	m.scheduleInput.Focus()
+	m.focusArea = FocusSchedule
+}
+
+// CancelScheduling closes the delay prompt
+func (m *Model) CancelScheduling() {
+	m.scheduleInput.Blur()
+	m.focusArea = FocusFileList
+}
+
+// SetScheduledCount sets the number of pending scheduled publishes
+func (m *Model) SetScheduledCount(n int) {
+	m.scheduled = n
+}
+
+// ScheduledCount returns the number of pending scheduled publishes
+func (m Model) ScheduledCount() int {
+	return m.scheduled
+}
+
+// parseDelay parses a publish delay given in whole seconds
+func parseDelay(input string) (time.Duration, error) {
+	secs, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || secs <= 0 {
+		return 0, fmt.Errorf("delay must be a positive number of seconds")
+	}
+	return time.Duration(secs) * time.Second, nil
+}
+
+// parseSchedule parses the schedule prompt: the delay in seconds, then
+// optional key=value attributes and an ordering key written as @key, e.g.
+// "30 type=order note='two words' @customer-1"
+func parseSchedule(input string) (SchedulePublishMsg, error) {
+	fields, err := SplitFields(input)
+	if err != nil {
+		return SchedulePublishMsg{}, err
+	}
+	if len(fields) == 0 {
+		return SchedulePublishMsg{}, fmt.Errorf("delay must be a positive number of seconds")
+	}
+	delay, err := parseDelay(fields[0])
+	if err != nil {
+		return SchedulePublishMsg{}, err
+	}
+
+	req := SchedulePublishMsg{Delay: delay}
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "@") {
+			if req.OrderingKey != "" || len(field) == 1 {
+				return SchedulePublishMsg{}, fmt.Errorf("give one non-empty @ordering-key")
+			}
+			req.OrderingKey = field[1:]
+			continue
+		}
+		idx := strings.Index(field, "=")
+		if idx <= 0 {
+			return SchedulePublishMsg{}, fmt.Errorf("invalid attribute %q: expected key=value", field)
+		}
+		if req.Attributes == nil {
+			req.Attributes = make(map[string]string)
+		}
+		req.Attributes[field[:idx]] = field[idx+1:]
+	}
+	return req, nil
+}
+
+// defaultSaveName suggests a file name based on the source file
+// e.g., "order-event.json" -> "order-event-edited.json"
+func defaultSaveName(file *utils.JSONFile) string {
+	if file == nil {
+		return "message-edited.json"
+	}
+	base := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
+	return base + "-edited.json"
 }
 
 // StopFileWatch closes the file watcher if it's running
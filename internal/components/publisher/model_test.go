@@ -0,0 +1,152 @@
+package publisher
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseDelay(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30", want: 30 * time.Second},
+		{input: " 5 ", want: 5 * time.Second},
+		{input: "0", wantErr: true},
+		{input: "-3", wantErr: true},
+		{input: "1.5", wantErr: true},
+		{input: "5s", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseDelay(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDelay(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDelay(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SchedulePublishMsg
+		wantErr bool
+	}{
+		{input: "30", want: SchedulePublishMsg{Delay: 30 * time.Second}},
+		{input: `10 type=order note="two words" @customer-1`, want: SchedulePublishMsg{
+			Delay:       10 * time.Second,
+			Attributes:  map[string]string{"type": "order", "note": "two words"},
+			OrderingKey: "customer-1",
+		}},
+		{input: "5 @key", want: SchedulePublishMsg{Delay: 5 * time.Second, OrderingKey: "key"}},
+		{input: "", wantErr: true},
+		{input: "type=order", wantErr: true},
+		{input: "5 type", wantErr: true},
+		{input: "5 @a @b", wantErr: true},
+		{input: "5 @", wantErr: true},
+		{input: `5 note="open`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSchedule(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSchedule(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSchedule(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_Schedule(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	m.SetTargetTopic("orders")
+	m.editedContent = `{"id": 1}`
+	m.hasEdits = true
+
+	m.StartScheduling()
+	m.scheduleInput.SetValue("15 type=order @customer-1")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should schedule the publish")
+	}
+	got, ok := cmd().(SchedulePublishMsg)
+	want := SchedulePublishMsg{
+		Topic:       "orders",
+		Content:     []byte(`{"id": 1}`),
+		Attributes:  map[string]string{"type": "order"},
+		OrderingKey: "customer-1",
+		Delay:       15 * time.Second,
+	}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("schedule request = %+v, want %+v", got, want)
+	}
+	if m.IsInputActive() {
+		t.Error("scheduling should close the prompt")
+	}
+}
+
+func TestModel_EditBody(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	edit := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}}
+
+	// Nothing to edit without a file
+	m, _ = m.Update(edit)
+	if m.IsEditing() {
+		t.Fatal("E without a file should not open the editor")
+	}
+
+	m.selectedFile = &utils.JSONFile{Name: "order.json"}
+	m.fileContent = `{"id": "${id}"}`
+	m.variablesInput.SetValue("id=42")
+
+	// The editor opens with the substituted content
+	m, _ = m.Update(edit)
+	if !m.IsEditing() || !m.IsInputActive() {
+		t.Fatal("E should open the editor")
+	}
+	if got := m.editor.Value(); got != `{"id": "42"}` {
+		t.Errorf("editor = %q, want the substituted content", got)
+	}
+
+	// Esc discards the changes
+	m.editor.SetValue(`{"id": "discarded"}`)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.IsEditing() || m.HasEdits() || m.GetMessageContent() != `{"id": "42"}` {
+		t.Errorf("after esc editing = %v content = %q, want the substituted content", m.IsEditing(), m.GetMessageContent())
+	}
+
+	// Ctrl+s keeps the edited buffer for the next publish and the preview
+	m, _ = m.Update(edit)
+	m.editor.SetValue(`{"id": "edited"}`)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.IsEditing() || !m.HasEdits() || m.GetMessageContent() != `{"id": "edited"}` {
+		t.Errorf("after ctrl+s editing = %v content = %q, want the edited content", m.IsEditing(), m.GetMessageContent())
+	}
+	if !strings.Contains(m.previewContent, "edited") {
+		t.Errorf("preview %q should show the edited content", m.previewContent)
+	}
+
+	// Reopening the editor starts from the edits
+	m, _ = m.Update(edit)
+	if got := m.editor.Value(); got != `{"id": "edited"}` {
+		t.Errorf("editor = %q, want the applied edits", got)
+	}
+}
@@ -0,0 +1,74 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/dialog"
+)
+
+// QuickPublishDialogID identifies the quick-publish form dialog
+const QuickPublishDialogID = "quick-publish"
+
+// QuickPublishRequestMsg asks the app to open the quick-publish dialog
+type QuickPublishRequestMsg struct {
+	Topic string
+}
+
+// QuickPublishFields returns the form fields for the quick-publish dialog
+func QuickPublishFields() []dialog.Field {
+	return []dialog.Field{
+		{Label: "Data (JSON)", Placeholder: `{"hello": "world"}`},
+		{Label: "Attributes (key=value ...)", Placeholder: `type=order note="two words"`},
+	}
+}
+
+// ParseQuickPublish validates the quick-publish form values and returns the
+// message data and attributes
+func ParseQuickPublish(values []string) ([]byte, map[string]string, error) {
+	if len(values) != 2 {
+		return nil, nil, fmt.Errorf("expected data and attributes, got %d fields", len(values))
+	}
+
+	data := strings.TrimSpace(values[0])
+	if data == "" {
+		return nil, nil, fmt.Errorf("data is required")
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	attrs, err := ParseAttributes(values[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []byte(data), attrs, nil
+}
+
+// ParseAttributes parses space-separated key=value pairs into message
+// attributes; quote values containing spaces, as in note="two words".
+// Unlike ParseVariables, malformed entries are an error. Returns nil for
+// empty input.
+func ParseAttributes(input string) (map[string]string, error) {
+	parts, err := SplitFields(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attributes: %v", err)
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	attrs := make(map[string]string, len(parts))
+	for _, part := range parts {
+		idx := strings.Index(part, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid attribute %q: expected key=value", part)
+		}
+		attrs[part[:idx]] = part[idx+1:]
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,67 @@
+package publisher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAttributes(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "   ", want: nil},
+		{input: "type=order env=dev", want: map[string]string{"type": "order", "env": "dev"}},
+		{input: `note="two words" who='Jane Doe'`, want: map[string]string{"note": "two words", "who": "Jane Doe"}},
+		{input: "filter=a=b empty=", want: map[string]string{"filter": "a=b", "empty": ""}},
+		{input: "type", wantErr: true},
+		{input: "=order", wantErr: true},
+		{input: `note="unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAttributes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttributes(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAttributes(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuickPublish(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		wantData  string
+		wantAttrs map[string]string
+		wantErr   bool
+	}{
+		{name: "data only", values: []string{` {"id": 1} `, ""}, wantData: `{"id": 1}`},
+		{name: "quoted attribute", values: []string{`"hi"`, `note="two words"`}, wantData: `"hi"`, wantAttrs: map[string]string{"note": "two words"}},
+		{name: "missing data", values: []string{" ", "type=order"}, wantErr: true},
+		{name: "invalid JSON", values: []string{"{", ""}, wantErr: true},
+		{name: "invalid attribute", values: []string{"{}", "type"}, wantErr: true},
+		{name: "wrong field count", values: []string{"{}"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, attrs, err := ParseQuickPublish(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuickPublish(%q) error = %v, wantErr %v", tt.values, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(data) != tt.wantData || !reflect.DeepEqual(attrs, tt.wantAttrs) {
+				t.Errorf("ParseQuickPublish(%q) = %s, %v, want %s, %v", tt.values, data, attrs, tt.wantData, tt.wantAttrs)
+			}
+		})
+	}
+}
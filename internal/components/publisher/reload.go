@@ -0,0 +1,90 @@
+package publisher
+
+import (
+	"path/filepath"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadPrompt is shown when the file being edited changes on disk
+const reloadPrompt = "File changed on disk, reload? (y/n)"
+
+// changedWhileEditing reports whether a file event rewrote the selected
+// file while it has unsaved edits, either applied or open in the editor
+func (m Model) changedWhileEditing(msg FileEventMsg) bool {
+	if m.selectedFile == nil || msg.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+		return false
+	}
+	if !m.hasEdits && m.focusArea != FocusEditor {
+		return false
+	}
+	path, err := filepath.Abs(msg.Name)
+	if err != nil {
+		return false
+	}
+	return path == m.selectedFile.Path
+}
+
+// StartReloadPrompt asks whether to reload the selected file from disk,
+// remembering the focused area to return to when the edits are kept
+func (m *Model) StartReloadPrompt() {
+	if m.focusArea == FocusConfirmReload {
+		return
+	}
+	m.reloadReturn = m.focusArea
+	m.variablesInput.Blur()
+	m.editor.Blur()
+	m.saveInput.Blur()
+	m.scheduleInput.Blur()
+	m.batchInput.Blur()
+	m.focusArea = FocusConfirmReload
+}
+
+// resumeAfterReloadPrompt returns to the area focused before the prompt
+func (m *Model) resumeAfterReloadPrompt() {
+	m.focusArea = m.reloadReturn
+	switch m.focusArea {
+	case FocusVariables:
+		m.variablesInput.Focus()
+	case FocusEditor:
+		m.editor.Focus()
+	case FocusSaveName:
+		m.saveInput.Focus()
+	case FocusSchedule:
+		m.scheduleInput.Focus()
+	case FocusBatch:
+		m.batchInput.Focus()
+	}
+}
+
+// handleReloadInput handles keyboard input in the reload prompt: y reloads
+// the file, discarding edits, while n or esc keeps them
+func (m Model) handleReloadInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.selectedFile == nil {
+		// The file was removed while the prompt was open
+		m.resumeAfterReloadPrompt()
+		return m, nil
+	}
+	name := filepath.Base(m.selectedFile.Path)
+	switch msg.String() {
+	case "y", "Y":
+		if m.reloadReturn == FocusEditor {
+			m.reloadReturn = FocusFileList
+		}
+		m.resumeAfterReloadPrompt()
+		m.selectFile(m.selectedFile)
+		m.SetStatus("Reloaded "+name+" from disk", false)
+		return m, func() tea.Msg {
+			return common.Info("Reloaded " + name + " from disk, discarding edits")
+		}
+
+	case "n", "N", "esc":
+		m.resumeAfterReloadPrompt()
+		m.SetStatus("Kept edits; "+name+" changed on disk", false)
+		return m, nil
+	}
+	return m, nil
+}
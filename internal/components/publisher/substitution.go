@@ -1,8 +1,10 @@
 package publisher
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // Variable represents a parsed variable from input
@@ -12,7 +14,7 @@ type Variable struct {
 }
 
 // ParseVariables parses space-separated key=value pairs
-// Example: "user=john env=prod timestamp=2024-01-01"
+// Example: "user=john env=prod timestamp=2024-01-01 name='John Doe'"
 func ParseVariables(input string) []Variable {
 	var vars []Variable
 
@@ -20,8 +22,8 @@ func ParseVariables(input string) []Variable {
 		return vars
 	}
 
-	// Split by spaces, but handle potential edge cases
-	parts := strings.Fields(input)
+	// Split by spaces outside quotes; an unterminated quote runs to the end
+	parts, _ := SplitFields(input)
 
 	for _, part := range parts {
 		// Find first = sign
@@ -47,6 +49,51 @@ func ParseVariables(input string) []Variable {
 	return vars
 }
 
+// SplitFields splits input on spaces like strings.Fields, except inside
+// single or double quotes, which are removed: name="John Doe" is one field.
+// A backslash inside double quotes escapes the next character. An
+// unterminated quote is an error; the fields read so far are returned.
+func SplitFields(input string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField := false
+	var quote rune
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == '"' && r == '\\' && i+1 < len(runes):
+			i++
+			field.WriteRune(runes[i])
+		case quote != 0:
+			field.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case unicode.IsSpace(r):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+
+	if quote != 0 {
+		return fields, fmt.Errorf("unterminated %c quote", quote)
+	}
+	return fields, nil
+}
+
 // isValidKey checks if a key contains only alphanumeric characters and underscores
 func isValidKey(key string) bool {
 	for _, r := range key {
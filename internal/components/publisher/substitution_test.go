@@ -0,0 +1,58 @@
+package publisher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "  a=1   b=2 ", want: []string{"a=1", "b=2"}},
+		{input: `name="John Doe" env=prod`, want: []string{"name=John Doe", "env=prod"}},
+		{input: `name='John Doe'`, want: []string{"name=John Doe"}},
+		{input: `quote='say "hi"'`, want: []string{`quote=say "hi"`}},
+		{input: `path="C:\\tmp \"x\""`, want: []string{`path=C:\tmp "x"`}},
+		{input: `empty="" next=1`, want: []string{"empty=", "next=1"}},
+		{input: `"a b"c`, want: []string{"a bc"}},
+		{input: `name="John Doe`, want: []string{"name=John Doe"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := SplitFields(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitFields(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitFields(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Variable
+	}{
+		{input: "", want: nil},
+		{input: "user=john env=prod", want: []Variable{{"user", "john"}, {"env", "prod"}}},
+		{input: "ts=2024-01-01T10:00:00 expr=a=b", want: []Variable{{"ts", "2024-01-01T10:00:00"}, {"expr", "a=b"}}},
+		{input: `name="John Doe" city='New York'`, want: []Variable{{"name", "John Doe"}, {"city", "New York"}}},
+		{input: "noequals =value bad-key=1 ok=", want: []Variable{{"ok", ""}}},
+		{input: `name="unterminated value`, want: []Variable{{"name", "unterminated value"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseVariables(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseVariables(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package publisher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/utils"
+)
+
+// TemplateDirEnvVar names a directory to list message files from instead
+// of the working directory
+const TemplateDirEnvVar = "PUBSUB_TUI_TEMPLATE_DIR"
+
+// TemplateDirFromEnv returns the directory configured by
+// PUBSUB_TUI_TEMPLATE_DIR, or "" when unset
+func TemplateDirFromEnv() string {
+	return strings.TrimSpace(os.Getenv(TemplateDirEnvVar))
+}
+
+// ValidateTemplateDir checks that dir is an existing directory
+func ValidateTemplateDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// SetFilesDir sets the directory message files are listed from and saved
+// to. An empty dir uses the working directory.
+func (m *Model) SetFilesDir(dir string) {
+	m.filesDir = dir
+}
+
+// FilesDir returns the directory message files are listed from
+func (m Model) FilesDir() string {
+	return m.filesDir
+}
+
+// SetRecursive sets whether files in subdirectories of the files directory
+// are listed, named by their relative path
+func (m *Model) SetRecursive(recursive bool) {
+	m.recursive = recursive
+}
+
+// IsRecursive returns whether files in subdirectories are listed
+func (m Model) IsRecursive() bool {
+	return m.recursive
+}
+
+// watchNewDir starts watching path if it is a new directory that a
+// recursive listing includes, reporting whether it did
+func (m Model) watchNewDir(path string) bool {
+	if m.watcher == nil {
+		return false
+	}
+	info, err := os.Lstat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	dirs, err := utils.ListSubdirs(path)
+	if err != nil {
+		return false
+	}
+	for _, d := range dirs {
+		m.watcher.Add(d)
+	}
+	return len(dirs) > 0
+}
+
+// filesDirLabel describes the files directory for status messages
+func (m Model) filesDirLabel() string {
+	if m.filesDir == "" {
+		return "current directory"
+	}
+	return filepath.Clean(m.filesDir)
+}
+
+// SetTemplate adds a file, which may be outside the working directory, to
+// the file list and selects it. It stays listed when files are reloaded.
+func (m *Model) SetTemplate(file utils.JSONFile) {
+	m.template = &file
+	m.SetFiles(m.loadedFiles)
+	for i := range m.allFiles {
+		if m.allFiles[i].Path == file.Path {
+			m.fileList.Select(i)
+			m.selectFile(&m.allFiles[i])
+			return
+		}
+	}
+}
+
+// withTemplate returns files with the template file first, unless it is
+// already among them
+func (m Model) withTemplate(files []utils.JSONFile) []utils.JSONFile {
+	if m.template == nil {
+		return files
+	}
+	for _, f := range files {
+		if f.Path == m.template.Path {
+			return files
+		}
+	}
+	return append([]utils.JSONFile{*m.template}, files...)
+}
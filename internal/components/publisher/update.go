@@ -1,13 +1,19 @@
 package publisher
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 	"github.com/anmaso/pubsub-tui/internal/utils"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
 )
@@ -20,16 +26,39 @@ type FilesLoadedMsg struct {
 
 // PublishRequestMsg requests a publish operation
 type PublishRequestMsg struct {
-	Topic   string
-	Content []byte
+	Topic       string
+	Content     []byte
+	Attributes  map[string]string
+	OrderingKey string
 }
 
+// SchedulePublishMsg requests a publish after a delay
+type SchedulePublishMsg struct {
+	Topic       string
+	Content     []byte
+	Attributes  map[string]string
+	OrderingKey string
+	Delay       time.Duration
+}
+
+// CancelPublishMsg requests cancelling the publish in progress
+type CancelPublishMsg struct{}
+
 // PublishResultMsg is sent when a publish operation completes
 type PublishResultMsg struct {
+	Topic     string
+	TopicFull string // Full resource name the server resolved the topic to
+	Content   []byte
 	MessageID string
 	Err       error
 }
 
+// FileSavedMsg is sent when the message content has been written to a file
+type FileSavedMsg struct {
+	Path string
+	Err  error
+}
+
 // FileWatchStartedMsg is sent when the file watcher is initialized
 type FileWatchStartedMsg struct {
 	Watcher *fsnotify.Watcher
@@ -54,8 +83,22 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.focusArea == FocusVariables {
+		switch m.focusArea {
+		case FocusVariables:
 			return m.handleVariablesInput(msg)
+		case FocusEditor:
+			return m.handleEditorInput(msg)
+		case FocusSaveName:
+			return m.handleSaveInput(msg)
+		case FocusSchedule:
+			return m.handleScheduleInput(msg)
+		case FocusBatch:
+			return m.handleBatchInput(msg)
+		case FocusConfirmReload:
+			return m.handleReloadInput(msg)
+		}
+		if m.showHistory {
+			return m.handleHistoryNavigation(msg)
 		}
 		return m.handleNavigation(msg)
 
@@ -65,23 +108,56 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		} else {
 			m.SetFiles(msg.Files)
 			if len(msg.Files) == 0 {
-				m.SetStatus("No JSON files found in current directory", false)
+				m.SetStatus("No JSON files found in "+m.filesDirLabel(), false)
 			}
 		}
 		return m, nil
 
 	case PublishResultMsg:
 		m.SetPublishing(false)
+		m.recordPublish(msg.Topic, msg.MessageID, msg.Content, msg.Err)
+		if errors.Is(msg.Err, context.Canceled) {
+			m.SetStatus("Publish cancelled", false)
+			return m, func() tea.Msg {
+				return common.Warning("Publish to " + msg.Topic + " cancelled (the message may still be delivered)")
+			}
+		}
 		if msg.Err != nil {
 			m.SetStatus("Publish failed: "+msg.Err.Error(), true)
 			return m, func() tea.Msg {
-				return common.Error("Publish failed: " + msg.Err.Error())
+				return common.ErrorLog("Publish failed", msg.Err)
 			}
 		}
-		m.SetStatus("Published: "+msg.MessageID, false)
+		// The full path ends with the short name, so it names both
+		target := msg.Topic
+		if msg.TopicFull != "" {
+			target = msg.TopicFull
+		}
+		m.SetStatus(fmt.Sprintf("Published %s to %s", msg.MessageID, target), false)
 		return m, func() tea.Msg {
-			return common.Success("Published message: " + msg.MessageID)
+			return common.Success(fmt.Sprintf("Published message %s to %s", msg.MessageID, topicLabel(msg.Topic, msg.TopicFull)))
+		}
+
+	case BatchPublishResultMsg:
+		return m.handleBatchResult(msg)
+
+	case FanOutPublishResultMsg:
+		return m.handleFanOutResult(msg)
+
+	case FileSavedMsg:
+		if msg.Err != nil {
+			m.SetStatus("Save failed: "+msg.Err.Error(), true)
+			return m, func() tea.Msg {
+				return common.Error("Save failed: " + msg.Err.Error())
+			}
 		}
+		m.SetStatus("Saved to "+filepath.Base(msg.Path), false)
+		return m, tea.Batch(
+			LoadFiles(m.filesDir, m.recursive),
+			func() tea.Msg {
+				return common.Success("Saved message to " + msg.Path)
+			},
+		)
 
 	case common.TopicSelectedMsg:
 		m.SetTargetTopic(msg.TopicName)
@@ -100,12 +176,33 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, WaitForFileEvent(msg.Watcher)
 
 	case FileEventMsg:
+		// Watch directories created inside a recursively listed tree
+		if m.recursive && msg.Op&fsnotify.Create != 0 && m.watchNewDir(msg.Name) {
+			return m, tea.Batch(
+				LoadFiles(m.filesDir, m.recursive),
+				WaitForFileEvent(m.watcher),
+			)
+		}
+
+		// Ask before replacing unsaved edits with the file's new content.
+		// The list still reloads; edits survive it until the user answers.
+		if m.changedWhileEditing(msg) {
+			m.StartReloadPrompt()
+			return m, tea.Batch(
+				LoadFiles(m.filesDir, m.recursive),
+				WaitForFileEvent(m.watcher),
+				func() tea.Msg {
+					return common.Warning(filepath.Base(msg.Name) + " changed on disk while being edited")
+				},
+			)
+		}
+
 		// Check if this is a JSON file
 		if isJSONFile(msg.Name) {
 			// Reload files on any relevant operation
 			if msg.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) != 0 {
 				return m, tea.Batch(
-					LoadFiles(),
+					LoadFiles(m.filesDir, m.recursive),
 					WaitForFileEvent(m.watcher),
 				)
 			}
@@ -135,6 +232,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+	case FocusEditor:
+		var cmd tea.Cmd
+		m.editor, cmd = m.editor.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -168,9 +271,166 @@ func (m Model) handleVariablesInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 	}
 }
 
+// handleEditorInput handles keyboard input when editing the message body
+func (m Model) handleEditorInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.ApplyEdit):
+		// Keep the edited buffer for the next publish
+		m.ApplyEdits()
+		m.SetStatus("Edits applied", false)
+		return m, nil
+
+	case key.Matches(msg, keys.CancelEdit):
+		// Discard changes made in this editing session
+		m.CancelEditing()
+		m.SetStatus("Edits discarded", false)
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.editor, cmd = m.editor.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleSaveInput handles keyboard input in the save-as prompt
+func (m Model) handleSaveInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.CancelSaving()
+		return m, nil
+
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.saveInput.Value())
+		if err := utils.ValidateFileName(name); err != nil {
+			m.SetStatus("Invalid file name: "+err.Error(), true)
+			return m, nil
+		}
+		if !isJSONFile(name) {
+			name += ".json"
+		}
+
+		m.CancelSaving()
+		m.SetProgress("Saving...")
+		return m, SaveFile(m.filesDir, name, m.GetMessageContent())
+
+	default:
+		var cmd tea.Cmd
+		m.saveInput, cmd = m.saveInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleScheduleInput handles keyboard input in the schedule delay prompt
+func (m Model) handleScheduleInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.CancelScheduling()
+		return m, nil
+
+	case tea.KeyEnter:
+		req, err := parseSchedule(m.scheduleInput.Value())
+		if err != nil {
+			m.SetStatus(err.Error(), true)
+			return m, nil
+		}
+
+		m.CancelScheduling()
+		content := m.GetMessageContent()
+		if m.targetTopic == "" || content == "" {
+			m.SetStatus("Nothing to schedule: select a topic and a file", true)
+			return m, nil
+		}
+
+		m.ClearStatus()
+		req.Topic = m.targetTopic
+		req.Content = []byte(content)
+		return m, func() tea.Msg {
+			return req
+		}
+
+	default:
+		var cmd tea.Cmd
+		m.scheduleInput, cmd = m.scheduleInput.Update(msg)
+		return m, cmd
+	}
+}
+
 // handleNavigation handles keyboard input in normal mode
 func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch {
+	case key.Matches(msg, keys.CancelPublish) && m.publishing:
+		m.SetProgress("Cancelling publish...")
+		return m, func() tea.Msg {
+			return CancelPublishMsg{}
+		}
+
+	case key.Matches(msg, keys.Save):
+		if m.GetMessageContent() == "" {
+			m.SetStatus("No content to save", true)
+			return m, nil
+		}
+		m.ClearStatus()
+		m.StartSaving()
+		return m, nil
+
+	case key.Matches(msg, keys.Edit):
+		if m.selectedFile == nil {
+			m.SetStatus("No file selected", true)
+			return m, nil
+		}
+		m.ClearStatus()
+		m.StartEditing()
+		return m, textarea.Blink
+
+	case key.Matches(msg, keys.QuickPublish):
+		if m.targetTopic == "" {
+			m.SetStatus("No topic selected", true)
+			return m, nil
+		}
+		topic := m.targetTopic
+		return m, func() tea.Msg {
+			return QuickPublishRequestMsg{Topic: topic}
+		}
+
+	case key.Matches(msg, keys.Schedule):
+		if m.targetTopic == "" {
+			m.SetStatus("No topic selected", true)
+			return m, nil
+		}
+		if m.selectedFile == nil {
+			m.SetStatus("No file selected", true)
+			return m, nil
+		}
+		m.ClearStatus()
+		m.StartScheduling()
+		return m, nil
+
+	case key.Matches(msg, keys.Batch):
+		if m.targetTopic == "" {
+			m.SetStatus("No topic selected", true)
+			return m, nil
+		}
+		if m.selectedFile == nil {
+			m.SetStatus("No file selected", true)
+			return m, nil
+		}
+		if m.publishing {
+			return m, nil
+		}
+		m.ClearStatus()
+		m.StartBatch()
+		return m, nil
+
+	case key.Matches(msg, keys.History):
+		m.ToggleHistory()
+		return m, nil
+
+	case key.Matches(msg, keys.SortFiles):
+		m.cycleFileSort()
+		m.SetStatus("Files sorted by "+m.fileSort.String(), false)
+		return m, nil
+
 	case key.Matches(msg, keys.Variables):
 		// Focus variables input
 		m.focusArea = FocusVariables
@@ -242,7 +502,7 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 // triggerPublish initiates a publish operation
 func (m Model) triggerPublish() (Model, tea.Cmd) {
-	if m.targetTopic == "" {
+	if m.targetTopic == "" && len(m.markedTopics) == 0 {
 		m.SetStatus("No topic selected", true)
 		return m, nil
 	}
@@ -263,7 +523,16 @@ func (m Model) triggerPublish() (Model, tea.Cmd) {
 	}
 
 	m.SetPublishing(true)
-	m.SetStatus("Publishing...", false)
+	if topics := m.markedTopics; len(topics) > 0 {
+		m.SetProgress(fmt.Sprintf("Publishing to %d topic(s)...", len(topics)))
+		return m, func() tea.Msg {
+			return FanOutPublishMsg{
+				Topics:  topics,
+				Content: []byte(content),
+			}
+		}
+	}
+	m.SetProgress("Publishing...")
 
 	return m, func() tea.Msg {
 		return PublishRequestMsg{
@@ -273,16 +542,31 @@ func (m Model) triggerPublish() (Model, tea.Cmd) {
 	}
 }
 
-// LoadFiles creates a command to load JSON files
-func LoadFiles() tea.Cmd {
+// LoadFiles creates a command to load JSON files from dir, or from the
+// working directory when dir is empty. With recursive set, files in
+// subdirectories are included too.
+func LoadFiles(dir string, recursive bool) tea.Cmd {
 	return func() tea.Msg {
-		files, err := utils.ListJSONFiles("")
+		list := utils.ListJSONFiles
+		if recursive {
+			list = utils.ListJSONFilesRecursive
+		}
+		files, err := list(dir)
 		return FilesLoadedMsg{Files: files, Err: err}
 	}
 }
 
-// StartFileWatch creates a command to start watching a directory for file changes
-func StartFileWatch(dir string) tea.Cmd {
+// SaveFile creates a command that writes content to a new file in dir
+func SaveFile(dir, name, content string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := utils.WriteNewFile(dir, name, []byte(content))
+		return FileSavedMsg{Path: path, Err: err}
+	}
+}
+
+// StartFileWatch creates a command to start watching a directory for file
+// changes. With recursive set, its subdirectories are watched too.
+func StartFileWatch(dir string, recursive bool) tea.Cmd {
 	return func() tea.Msg {
 		// Resolve directory
 		if dir == "" {
@@ -299,11 +583,20 @@ func StartFileWatch(dir string) tea.Cmd {
 			return FileWatchStartedMsg{Err: err}
 		}
 
-		// Add directory to watch
-		err = watcher.Add(dir)
-		if err != nil {
-			watcher.Close()
-			return FileWatchStartedMsg{Err: err}
+		// Add directories to watch
+		dirs := []string{dir}
+		if recursive {
+			dirs, err = utils.ListSubdirs(dir)
+			if err != nil {
+				watcher.Close()
+				return FileWatchStartedMsg{Err: err}
+			}
+		}
+		for _, d := range dirs {
+			if err := watcher.Add(d); err != nil {
+				watcher.Close()
+				return FileWatchStartedMsg{Err: err}
+			}
 		}
 
 		return FileWatchStartedMsg{
@@ -344,42 +637,118 @@ func isJSONFile(name string) bool {
 
 // Key bindings
 type keyMap struct {
-	Variables  key.Binding
-	Publish    key.Binding
-	Select     key.Binding
-	Up         key.Binding
-	Down       key.Binding
-	ScrollUp   key.Binding
-	ScrollDown key.Binding
+	Variables     key.Binding
+	Edit          key.Binding
+	ApplyEdit     key.Binding
+	CancelEdit    key.Binding
+	Save          key.Binding
+	QuickPublish  key.Binding
+	Schedule      key.Binding
+	Batch         key.Binding
+	History       key.Binding
+	SortFiles     key.Binding
+	Publish       key.Binding
+	CancelPublish key.Binding
+	Select        key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	ScrollUp      key.Binding
+	ScrollDown    key.Binding
+}
+
+// defaultKeys returns the built-in key bindings
+func defaultKeys() keyMap {
+	return keyMap{
+		Variables: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "Edit variables for substitution (${varName})"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "Edit message body"),
+		),
+		ApplyEdit: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "Apply edits to the message body"),
+		),
+		CancelEdit: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "Discard edits to the message body"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "Save current message content to a new file"),
+		),
+		QuickPublish: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "Quick publish typed JSON data and attributes"),
+		),
+		Schedule: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "Schedule the current message to publish after N seconds"),
+		),
+		Batch: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "Publish N copies of the current message in batches"),
+		),
+		History: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "Toggle publish history (enter reloads a payload)"),
+		),
+		SortFiles: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "Sort files by name, size or modified time"),
+		),
+		Publish: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "Publish message to topic"),
+		),
+		CancelPublish: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "Cancel the publish in progress"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "Select message template"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "Move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "Move down"),
+		),
+		ScrollUp: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("ctrl+u", "Scroll preview up"),
+		),
+		ScrollDown: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "Scroll preview down"),
+		),
+	}
+}
+
+// keys are the active key bindings: the defaults with any overrides from the
+// key config file
+var keys = defaultKeys()
+
+// SetKeyOverrides replaces the panel's key bindings with the defaults plus
+// overrides, keyed by action name (see OverrideKeys). It returns the actions
+// that match no binding; on error the bindings are unchanged.
+func SetKeyOverrides(overrides map[string][]string) ([]string, error) {
+	km := defaultKeys()
+	unknown, err := common.OverrideKeys(&km, overrides)
+	if err != nil {
+		return nil, err
+	}
+	keys = km
+	return unknown, nil
 }
 
-var keys = keyMap{
-	Variables: key.NewBinding(
-		key.WithKeys("v"),
-		key.WithHelp("v", "variables"),
-	),
-	Publish: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "publish"),
-	),
-	Select: key.NewBinding(
-		key.WithKeys(" "),
-		key.WithHelp("space", "select file"),
-	),
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
-	ScrollUp: key.NewBinding(
-		key.WithKeys("ctrl+u"),
-		key.WithHelp("ctrl+u", "scroll preview up"),
-	),
-	ScrollDown: key.NewBinding(
-		key.WithKeys("ctrl+d"),
-		key.WithHelp("ctrl+d", "scroll preview down"),
-	),
+// Binding returns the panel's key binding for an action name, as used in
+// the key config file
+func Binding(name string) (key.Binding, bool) {
+	return common.FindKey(keys, name)
 }
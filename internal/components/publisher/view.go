@@ -6,6 +6,7 @@ import (
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -13,9 +14,15 @@ import (
 func (m Model) View() string {
 	// Build title
 	title := "3 Publisher"
-	if m.targetTopic != "" {
+	switch {
+	case len(m.markedTopics) > 0:
+		title = fmt.Sprintf("3 Publisher → %d marked topic(s)", len(m.markedTopics))
+	case m.targetTopic != "":
 		title = fmt.Sprintf("3 Publisher → %s", m.targetTopic)
 	}
+	if m.scheduled > 0 {
+		title += fmt.Sprintf(" (%d scheduled)", m.scheduled)
+	}
 
 	// Calculate dimensions for split view
 	contentWidth := m.width - 4   // borders
@@ -51,7 +58,26 @@ func (m Model) View() string {
 
 	// Add status line
 	var status string
-	if m.status != "" {
+	if m.focusArea == FocusEditor {
+		status = common.MutedText.Render("ctrl+s: apply  esc: discard")
+	} else if m.focusArea == FocusSaveName {
+		status = m.saveInput.View()
+		if m.statusError {
+			status += " " + common.LogErrorStyle.Render(m.status)
+		}
+	} else if m.focusArea == FocusSchedule {
+		status = m.scheduleInput.View()
+		if m.statusError {
+			status += " " + common.LogErrorStyle.Render(m.status)
+		}
+	} else if m.focusArea == FocusBatch {
+		status = m.batchInput.View()
+		if m.statusError {
+			status += " " + common.LogErrorStyle.Render(m.status)
+		}
+	} else if m.focusArea == FocusConfirmReload {
+		status = common.LogWarningStyle.Render(reloadPrompt)
+	} else if m.status != "" {
 		style := common.LogSuccessStyle
 		if m.statusError {
 			style = common.LogErrorStyle
@@ -68,22 +94,37 @@ func (m Model) View() string {
 func (m Model) buildLeftPanel(width, height int) string {
 	var content strings.Builder
 
-	// Files section header
-	filesHeader := common.MutedText.Render("Files")
-	if m.focusArea == FocusFileList && m.focused {
-		filesHeader = common.FilterPromptStyle.Render("Files")
-	}
-	if len(m.allFiles) > 0 {
-		filesHeader += common.MutedText.Render(fmt.Sprintf(" (%d)", len(m.allFiles)))
-	}
-	content.WriteString(filesHeader)
-	content.WriteString("\n")
-
-	// File list
-	if len(m.allFiles) == 0 {
-		content.WriteString(common.MutedText.Render("No JSON files"))
+	if m.showHistory {
+		// History replaces the file list
+		historyHeader := common.MutedText.Render("History")
+		if m.focused {
+			historyHeader = common.FilterPromptStyle.Render("History")
+		}
+		historyHeader += common.MutedText.Render(fmt.Sprintf(" (%d/%d)", m.history.Len(), MaxPublishHistory))
+		content.WriteString(historyHeader)
+		content.WriteString("\n")
+		content.WriteString(m.historyView(width, m.fileList.Height()))
 	} else {
-		content.WriteString(m.fileList.View())
+		// Files section header
+		filesHeader := common.MutedText.Render("Files")
+		if m.focusArea == FocusFileList && m.focused {
+			filesHeader = common.FilterPromptStyle.Render("Files")
+		}
+		if len(m.allFiles) > 0 {
+			filesHeader += common.MutedText.Render(fmt.Sprintf(" (%d)", len(m.allFiles)))
+		}
+		if m.filesDir != "" {
+			filesHeader += common.MutedText.Render(" " + common.TruncateString(m.filesDir, width-lipgloss.Width(filesHeader)-1))
+		}
+		content.WriteString(filesHeader)
+		content.WriteString("\n")
+
+		// File list
+		if len(m.allFiles) == 0 {
+			content.WriteString(common.MutedText.Render("No JSON files"))
+		} else {
+			content.WriteString(m.fileList.View())
+		}
 	}
 
 	// Variables section
@@ -121,11 +162,26 @@ func (m Model) buildLeftPanel(width, height int) string {
 func (m Model) buildRightPanel(width, height int) string {
 	var content strings.Builder
 
+	// Editor replaces the preview while editing
+	if m.focusArea == FocusEditor {
+		editorHeader := common.FilterPromptStyle.Render("Edit")
+		if m.selectedFile != nil {
+			editorHeader += common.MutedText.Render(fmt.Sprintf(" - %s", m.selectedFile.Name))
+		}
+		content.WriteString(editorHeader)
+		content.WriteString("\n")
+		content.WriteString(m.editor.View())
+		return padBlock(content.String(), width, height)
+	}
+
 	// Preview header
 	previewHeader := common.MutedText.Render("Preview")
 	if m.selectedFile != nil {
 		previewHeader += common.MutedText.Render(fmt.Sprintf(" - %s", m.selectedFile.Name))
 	}
+	if m.hasEdits {
+		previewHeader += common.LogWarningStyle.Render(" (edited)")
+	}
 	content.WriteString(previewHeader)
 	content.WriteString("\n")
 
@@ -138,8 +194,11 @@ func (m Model) buildRightPanel(width, height int) string {
 		content.WriteString(common.MutedText.Render("Select a file"))
 	}
 
-	result := content.String()
+	return padBlock(content.String(), width, height)
+}
 
+// padBlock pads content lines to width and the block to height
+func padBlock(result string, width, height int) string {
 	// Pad to width
 	lines := strings.Split(result, "\n")
 	var paddedLines []string
@@ -159,10 +218,33 @@ func (m Model) buildRightPanel(width, height int) string {
 	return strings.Join(paddedLines[:height], "\n")
 }
 
+// FullHelp returns every key binding of the panel for the help overlay
+func (m Model) FullHelp() []key.Binding {
+	return common.KeyBindings(keys)
+}
+
 // ShortHelp returns key bindings for the help display
 func (m Model) ShortHelp() []string {
-	if m.focusArea == FocusVariables {
+	switch m.focusArea {
+	case FocusVariables:
 		return []string{"esc: back", "tab: files"}
+	case FocusEditor:
+		return []string{"ctrl+s: apply", "esc: discard"}
+	case FocusSaveName:
+		return []string{"enter: save", "esc: cancel"}
+	case FocusSchedule:
+		return []string{"enter: schedule", "esc: cancel"}
+	case FocusBatch:
+		return []string{"enter: publish", "esc: cancel"}
+	case FocusConfirmReload:
+		return []string{"y: reload from disk", "n: keep edits"}
+	}
+	if m.showHistory {
+		return []string{"enter: load payload", "j/k: navigate", "h/esc: close"}
+	}
+	help := []string{"enter: publish", "v: variables", "E: edit", "S: save", "P: quick publish", "L: later", "B: batch", "h: history", "o: sort files", "j/k: navigate"}
+	if m.publishing {
+		help = append([]string{"esc: cancel publish"}, help...)
 	}
-	return []string{"enter: publish", "v: variables", "j/k: navigate"}
+	return help
 }
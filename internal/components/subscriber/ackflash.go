@@ -0,0 +1,24 @@
+package subscriber
+
+import (
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+)
+
+// flashAck shows text such as "Acked abc123 ✓" in the detail header until
+// the status duration has passed, confirming an ack where the message is
+// read
+func (m *Model) flashAck(text string) {
+	m.ackFlash = text
+	m.ackFlashExpiry = time.Now().Add(common.StatusSuccessDuration)
+}
+
+// ExpireStatus clears the ack confirmation once it has been shown long
+// enough
+func (m *Model) ExpireStatus(now time.Time) {
+	if common.StatusExpired(m.ackFlashExpiry, now) {
+		m.ackFlash = ""
+		m.ackFlashExpiry = time.Time{}
+	}
+}
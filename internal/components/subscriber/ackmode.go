@@ -0,0 +1,223 @@
+package subscriber
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AckDelayEnvVar sets how long the delayed ack mode waits before
+// acknowledging a message, as a Go duration such as "5s"
+const AckDelayEnvVar = "PUBSUB_TUI_ACK_DELAY"
+
+// DefaultAckDelay is the delayed ack mode's delay when AckDelayEnvVar is unset
+const DefaultAckDelay = 5 * time.Second
+
+// ackKind is the kind of an AckMode
+type ackKind int
+
+const (
+	ackManual ackKind = iota
+	ackImmediate
+	ackDelayed
+)
+
+// AckMode is how received messages are acknowledged: manually, as soon as
+// they arrive, or after a delay that leaves time to inspect them
+type AckMode struct {
+	kind  ackKind
+	delay time.Duration
+}
+
+var (
+	// AckManual leaves acknowledging to the user
+	AckManual = AckMode{kind: ackManual}
+
+	// AckImmediate acknowledges messages as soon as they are received
+	AckImmediate = AckMode{kind: ackImmediate}
+)
+
+// AckDelayed acknowledges each message d after it is received
+func AckDelayed(d time.Duration) AckMode {
+	return AckMode{kind: ackDelayed, delay: d}
+}
+
+// IsDelayed returns whether messages are acknowledged after a delay
+func (a AckMode) IsDelayed() bool {
+	return a.kind == ackDelayed
+}
+
+// Delay returns how long a delayed mode waits; zero for other modes
+func (a AckMode) Delay() time.Duration {
+	return a.delay
+}
+
+// String describes the mode for the header, e.g. "after 5s"
+func (a AckMode) String() string {
+	switch a.kind {
+	case ackImmediate:
+		return "on receive"
+	case ackDelayed:
+		return "after " + a.delay.String()
+	}
+	return "manual"
+}
+
+// AckDelayFromEnv returns the delay configured by PUBSUB_TUI_ACK_DELAY, or
+// DefaultAckDelay when unset
+func AckDelayFromEnv() (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv(AckDelayEnvVar))
+	if v == "" {
+		return DefaultAckDelay, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s: want a positive duration such as 5s, got %q", AckDelayEnvVar, v)
+	}
+	return d, nil
+}
+
+// AutoAckDialogID identifies the dialog confirming auto-ack on a real
+// subscription
+const AutoAckDialogID = "confirm-auto-ack"
+
+// AutoAckConfirmRequestMsg asks the app to confirm switching to an
+// automatic ack mode, which permanently consumes messages on real GCP
+type AutoAckConfirmRequestMsg struct {
+	Subscription string
+	Mode         AckMode
+}
+
+// DelayedAckMsg acknowledges a message once the delayed mode's delay has
+// passed since it was received
+type DelayedAckMsg struct {
+	Message *pubsub.ReceivedMessage
+}
+
+// SetAckDelay sets the delay used by the delayed ack mode. Values that are
+// not positive are ignored.
+func (m *Model) SetAckDelay(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	m.ackDelay = d
+	if m.ackMode.IsDelayed() {
+		m.ackMode = AckDelayed(d)
+	}
+}
+
+// SetAckMode sets how received messages are acknowledged. Messages already
+// received are left as they are.
+func (m *Model) SetAckMode(mode AckMode) {
+	m.ackMode = mode
+}
+
+// AckMode returns how received messages are acknowledged
+func (m Model) AckMode() AckMode {
+	return m.ackMode
+}
+
+// nextAckMode returns the mode after the current one: manual, on receive,
+// then after the configured delay
+func (m Model) nextAckMode() AckMode {
+	switch m.ackMode.kind {
+	case ackManual:
+		return AckImmediate
+	case ackImmediate:
+		return AckDelayed(m.ackDelay)
+	}
+	return AckManual
+}
+
+// CycleAckMode switches to the next ack mode
+func (m *Model) CycleAckMode() {
+	m.ackMode = m.nextAckMode()
+}
+
+// SetEmulatorMode sets whether the panel is connected to the emulator.
+// Against real GCP, the first switch to each automatic ack mode is confirmed.
+func (m *Model) SetEmulatorMode(emulator bool) {
+	m.emulator = emulator
+}
+
+// needsAutoAckConfirm returns whether switching to mode must be confirmed
+// first: automatic acks on a real subscription cannot be undone. Each
+// automatic mode is confirmed on its own, since on receive and after a
+// delay consume messages differently.
+func (m Model) needsAutoAckConfirm(mode AckMode) bool {
+	return mode != AckManual && !m.emulator && !m.autoAckConfirmed[mode.kind]
+}
+
+// ConfirmAutoAck switches to an automatic ack mode the user has confirmed.
+// Later switches to that mode in this session are not confirmed again.
+func (m *Model) ConfirmAutoAck(mode AckMode) {
+	if m.autoAckConfirmed == nil {
+		m.autoAckConfirmed = make(map[ackKind]bool)
+	}
+	m.autoAckConfirmed[mode.kind] = true
+	m.ackMode = mode
+}
+
+// cycleAckMode switches to the next ack mode, or asks for confirmation when
+// it would start acknowledging real messages automatically
+func (m Model) cycleAckMode() (Model, tea.Cmd) {
+	next := m.nextAckMode()
+	if m.needsAutoAckConfirm(next) {
+		sub := m.subscriptionName
+		return m, func() tea.Msg {
+			return AutoAckConfirmRequestMsg{Subscription: sub, Mode: next}
+		}
+	}
+	m.ackMode = next
+	return m, func() tea.Msg {
+		return common.Info("Ack mode: " + next.String())
+	}
+}
+
+// scheduleAck returns a command that acknowledges msg once the delay has
+// passed, or nil unless the delayed mode is active
+func (m Model) scheduleAck(msg *pubsub.ReceivedMessage) tea.Cmd {
+	if !m.ackMode.IsDelayed() || msg.IsAcked() {
+		return nil
+	}
+	return tea.Tick(m.ackMode.delay, func(time.Time) tea.Msg {
+		return DelayedAckMsg{Message: msg}
+	})
+}
+
+// handleDelayedAck acknowledges a message whose delay has passed. It is
+// skipped if the mode changed meanwhile or the message is no longer buffered.
+func (m Model) handleDelayedAck(msg DelayedAckMsg) (Model, tea.Cmd) {
+	if !m.ackMode.IsDelayed() || msg.Message.IsAcked() || !m.isBuffered(msg.Message) {
+		return m, nil
+	}
+	msg.Message.Ack()
+	if !msg.Message.IsAcked() {
+		return m, nil
+	}
+
+	m.applyFilter()
+	if msg.Message == m.SelectedMessage() {
+		// Refresh the status without losing the reader's scroll position
+		offset := m.detailView.YOffset
+		m.updateDetailView()
+		m.detailView.SetYOffset(offset)
+	}
+	return m, m.ackedCmd(msg.Message.ID)
+}
+
+// isBuffered returns whether msg is among the current subscription's messages
+func (m Model) isBuffered(msg *pubsub.ReceivedMessage) bool {
+	for _, buffered := range m.messages {
+		if buffered == msg {
+			return true
+		}
+	}
+	return false
+}
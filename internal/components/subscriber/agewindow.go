@@ -0,0 +1,92 @@
+package subscriber
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SetAgeWindow shows only messages published within the window before now.
+// Zero shows messages of any age.
+func (m *Model) SetAgeWindow(window time.Duration) {
+	m.ageWindow = window
+	m.applyFilter()
+	m.reselect()
+}
+
+// AgeWindow returns the age window of the displayed messages, or zero when
+// messages of any age are shown
+func (m Model) AgeWindow() time.Duration {
+	return m.ageWindow
+}
+
+// IsEditingAgeWindow returns whether the age window prompt is open
+func (m Model) IsEditingAgeWindow() bool {
+	return m.editingAge
+}
+
+// StartAgeWindowEdit opens the age window prompt with the current value
+func (m *Model) StartAgeWindowEdit() {
+	m.ageError = ""
+	m.ageInput.SetValue("")
+	if m.ageWindow > 0 {
+		m.ageInput.SetValue(m.ageWindow.String())
+	}
+	m.ageInput.CursorEnd()
+	m.ageInput.Focus()
+	m.editingAge = true
+}
+
+// CancelAgeWindowEdit closes the age window prompt
+func (m *Model) CancelAgeWindowEdit() {
+	m.ageInput.Blur()
+	m.editingAge = false
+	m.ageError = ""
+}
+
+// parseAgeWindow parses an age window such as 30s or 5m. An empty value or
+// zero clears the window.
+func parseAgeWindow(input string) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	if input == "" || input == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(input)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("must be a duration like 30s or 5m")
+	}
+	return d, nil
+}
+
+// withinAgeWindow reports whether a message published at publish is shown
+// at now. A message exactly window old is still shown.
+func withinAgeWindow(publish, now time.Time, window time.Duration) bool {
+	return window <= 0 || now.Sub(publish) <= window
+}
+
+// handleAgeWindowInput handles keyboard input in the age window prompt
+func (m Model) handleAgeWindowInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.CancelAgeWindowEdit()
+		return m, nil
+
+	case tea.KeyEnter:
+		d, err := parseAgeWindow(m.ageInput.Value())
+		if err != nil {
+			m.ageError = err.Error()
+			return m, nil
+		}
+
+		m.CancelAgeWindowEdit()
+		m.SetAgeWindow(d)
+		return m, m.startAgeTick()
+
+	default:
+		var cmd tea.Cmd
+		m.ageInput, cmd = m.ageInput.Update(msg)
+		return m, cmd
+	}
+}
@@ -0,0 +1,129 @@
+package subscriber
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ListAttributeEnvVar names a message attribute to show in each list row
+const ListAttributeEnvVar = "PUBSUB_TUI_LIST_ATTRIBUTE"
+
+// ListAttributeFromEnv returns the attribute configured by
+// PUBSUB_TUI_LIST_ATTRIBUTE, or "" when unset
+func ListAttributeFromEnv() string {
+	return strings.TrimSpace(os.Getenv(ListAttributeEnvVar))
+}
+
+// SetListAttribute sets the attribute shown in each list row as
+// [name=value]. An empty name shows no attribute.
+func (m *Model) SetListAttribute(name string) {
+	m.listAttribute = strings.TrimSpace(name)
+	m.applyFilter()
+}
+
+// ListAttribute returns the attribute shown in each list row
+func (m Model) ListAttribute() string {
+	return m.listAttribute
+}
+
+// IsEditingAttribute returns whether the list attribute prompt is open
+func (m Model) IsEditingAttribute() bool {
+	return m.editingAttribute
+}
+
+// StartAttributeEdit opens the list attribute prompt with the current value
+func (m *Model) StartAttributeEdit() {
+	m.attributeInput.SetValue(m.listAttribute)
+	m.attributeInput.CursorEnd()
+	m.attributeInput.Focus()
+	m.editingAttribute = true
+}
+
+// CancelAttributeEdit closes the list attribute prompt
+func (m *Model) CancelAttributeEdit() {
+	m.attributeInput.Blur()
+	m.editingAttribute = false
+}
+
+// handleAttributeInput handles keyboard input in the list attribute prompt
+func (m Model) handleAttributeInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.CancelAttributeEdit()
+		return m, nil
+
+	case tea.KeyEnter:
+		m.CancelAttributeEdit()
+		m.SetListAttribute(m.attributeInput.Value())
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.attributeInput, cmd = m.attributeInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// attributeTag returns " [name=value]" for the list attribute, or "" when
+// no attribute is configured or the message lacks it
+func (m MessageItem) attributeTag() string {
+	if m.attribute == "" {
+		return ""
+	}
+	value, ok := m.message.Attributes[m.attribute]
+	if !ok {
+		return ""
+	}
+	return " [" + m.attribute + "=" + value + "]"
+}
+
+// attributeBadge returns " {n}" for a message with n attributes, so messages
+// carrying metadata stand out in the list, or "" without attributes
+func attributeBadge(msg *pubsub.ReceivedMessage) string {
+	if len(msg.Attributes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" {%d}", len(msg.Attributes))
+}
+
+// fitTitle shortens a title to width terminal columns, truncating the ID
+// (which starts with a space) before the tag, and dropping the attribute
+// badge last. The ack mark and time are always kept. Widths are measured in
+// columns, so wide characters such as CJK in attribute values count twice.
+func fitTitle(head, id, tail, badge, tag string, width int) string {
+	title := head + id + tail + badge + tag
+	over := lipgloss.Width(title) - width
+	if width <= 0 || over <= 0 {
+		return title
+	}
+
+	// Drop ID characters first; message IDs are ASCII, one column each
+	if over < len(id)-1 {
+		return head + id[:len(id)-over] + tail + badge + tag
+	}
+	title = head + tail + badge + tag
+	if lipgloss.Width(title) <= width {
+		return title
+	}
+
+	// Then shorten the tag, keeping its closing bracket
+	room := width - lipgloss.Width(head+tail+badge+"…]")
+	runes := []rune(tag)
+	for len(runes) > 0 && lipgloss.Width(string(runes)) > room {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) < 3 {
+		// Not even " [x" fits
+		if lipgloss.Width(head+tail+badge) <= width {
+			return head + tail + badge
+		}
+		return head + tail
+	}
+	return head + tail + badge + string(runes) + "…]"
+}
@@ -0,0 +1,45 @@
+package subscriber
+
+import (
+	"fmt"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bufferWarnBytes is the buffered message data above which a warning is
+// logged, once until the buffer drops back below it
+const bufferWarnBytes = 50 * 1024 * 1024
+
+// dataBytes returns the total data size of messages
+func dataBytes(messages []*pubsub.ReceivedMessage) int64 {
+	var total int64
+	for _, msg := range messages {
+		total += int64(len(msg.Data))
+	}
+	return total
+}
+
+// BufferedBytes returns the data size of the buffered messages
+func (m Model) BufferedBytes() int64 {
+	return m.bufferedBytes
+}
+
+// checkBufferSize returns a warning the first time the buffered data grows
+// beyond bufferWarnBytes, and rearms it once the buffer shrinks below
+func (m *Model) checkBufferSize() tea.Cmd {
+	if m.bufferedBytes <= bufferWarnBytes {
+		m.bufferWarned = false
+		return nil
+	}
+	if m.bufferWarned {
+		return nil
+	}
+	m.bufferWarned = true
+	size := common.FormatSize(m.bufferedBytes)
+	return func() tea.Msg {
+		return common.Warning(fmt.Sprintf("Message buffer holds %s of data (set PUBSUB_TUI_MAX_MESSAGE_BYTES to cap each message)", size))
+	}
+}
@@ -0,0 +1,48 @@
+package subscriber
+
+import (
+	"io"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// messageDelegate renders message rows like its DefaultDelegate, dimming
+// acked messages so pending ones stand out, and coloring the titles of
+// pending rows by attribute value. The selected row keeps its selection
+// style either way.
+type messageDelegate struct {
+	list.DefaultDelegate
+}
+
+// Render renders a message row with its title and description styles
+func (d messageDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	d.Styles.NormalTitle = d.titleStyle(item)
+	d.Styles.NormalDesc = d.descStyle(item)
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// titleStyle returns the style for an unselected row's title
+func (d messageDelegate) titleStyle(item list.Item) lipgloss.Style {
+	msg, ok := item.(MessageItem)
+	if !ok {
+		return d.Styles.NormalTitle
+	}
+	if msg.message.IsAcked() {
+		return d.Styles.NormalTitle.Copy().Foreground(common.ColorTextMuted)
+	}
+	if msg.color != "" {
+		return d.Styles.NormalTitle.Copy().Foreground(msg.color)
+	}
+	return d.Styles.NormalTitle
+}
+
+// descStyle returns the style for an unselected row's description
+func (d messageDelegate) descStyle(item list.Item) lipgloss.Style {
+	if msg, ok := item.(MessageItem); ok && msg.message.IsAcked() {
+		return d.Styles.NormalDesc.Copy().Faint(true)
+	}
+	return d.Styles.NormalDesc
+}
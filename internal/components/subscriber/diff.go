@@ -0,0 +1,107 @@
+package subscriber
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/utils"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DiffMark returns the message marked for diffing, if any
+func (m Model) DiffMark() *pubsub.ReceivedMessage {
+	return m.diffMark
+}
+
+// markForDiff remembers the selected message to diff against later.
+// Marking the marked message again clears the mark.
+func (m *Model) markForDiff() tea.Cmd {
+	selected := m.SelectedMessage()
+	if selected == nil {
+		return nil
+	}
+	if m.diffMark == selected {
+		m.diffMark = nil
+		return func() tea.Msg {
+			return common.Info("Cleared diff mark")
+		}
+	}
+	m.diffMark = selected
+	label := messageLabel(selected)
+	return func() tea.Msg {
+		return common.Info(fmt.Sprintf("Marked message %s for diff (d on another message compares)", label))
+	}
+}
+
+// showDiff shows the diff of the marked message's data against the selected
+// message's in the detail view, then clears the mark
+func (m *Model) showDiff() tea.Cmd {
+	selected := m.SelectedMessage()
+	if selected == nil {
+		return nil
+	}
+	if m.diffMark == nil {
+		return func() tea.Msg {
+			return common.Warning("Mark a message with x before diffing")
+		}
+	}
+
+	marked := m.diffMark
+	m.diffMark = nil
+	m.detailView.SetContent(m.renderDiff(marked, selected))
+	m.detailView.GotoTop()
+	return nil
+}
+
+// renderDiff renders a colored line diff of two messages' data
+func (m Model) renderDiff(from, to *pubsub.ReceivedMessage) string {
+	var content strings.Builder
+	content.WriteString(common.FilterPromptStyle.Render(fmt.Sprintf("Diff %s → %s:", messageLabel(from), messageLabel(to))))
+	content.WriteString("\n")
+
+	diff := utils.DiffLines(m.diffText(from), m.diffText(to))
+	changed := false
+	for _, line := range diff {
+		switch line.Op {
+		case utils.DiffRemoved:
+			changed = true
+			content.WriteString(common.LogErrorStyle.Render("- " + line.Text))
+		case utils.DiffAdded:
+			changed = true
+			content.WriteString(common.LogSuccessStyle.Render("+ " + line.Text))
+		default:
+			content.WriteString(common.MutedText.Render("  " + line.Text))
+		}
+		content.WriteString("\n")
+	}
+	if !changed {
+		content.WriteString(common.MutedText.Render("(identical data)"))
+	}
+	return content.String()
+}
+
+// diffText returns message data formatted as in the detail view, so
+// equivalent JSON with different spacing compares equal
+func (m Model) diffText(msg *pubsub.ReceivedMessage) string {
+	data := msg.Data
+	if !m.showRaw {
+		data, _ = utils.TryDecode(msg.Data)
+	}
+	if !utf8.Valid(data) {
+		return fmt.Sprintf("%q", data)
+	}
+	formatted, _ := utils.FormatJSONIndent(data, m.jsonIndent)
+	return formatted
+}
+
+// messageLabel names a message by arrival number, or ID before numbering
+func messageLabel(msg *pubsub.ReceivedMessage) string {
+	if msg.Sequence > 0 {
+		return fmt.Sprintf("#%d", msg.Sequence)
+	}
+	return msg.ID
+}
@@ -0,0 +1,107 @@
+package subscriber
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/internal/utils"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MessageDumpedMsg is sent when a message has been written to a file
+type MessageDumpedMsg struct {
+	MessageID string
+	Path      string
+	Err       error
+}
+
+// dumpedMessage is the JSON written for a dumped message. Data holds the
+// payload as JSON when it is JSON, otherwise as a string. Subscription and
+// Topic record where the message was received from.
+type dumpedMessage struct {
+	ID           string            `json:"id"`
+	Subscription string            `json:"subscription,omitempty"`
+	Topic        string            `json:"topic,omitempty"`
+	PublishTime  time.Time         `json:"publishTime"`
+	OrderingKey  string            `json:"orderingKey,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	DataEncoding string            `json:"dataEncoding,omitempty"` // "base64" for binary data
+	Data         json.RawMessage   `json:"data"`
+	DataSize     int               `json:"dataSize,omitempty"` // Full data size when Data was truncated on receive
+}
+
+// unsafeFileChars matches characters not kept in dump file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// dumpFileName returns the file name for a message dumped at t, e.g.
+// message-1234-20240102-150405.json
+func dumpFileName(id string, t time.Time) string {
+	id = unsafeFileChars.ReplaceAllString(id, "_")
+	if id == "" {
+		id = "unknown"
+	}
+	return "message-" + id + "-" + t.Format("20060102-150405") + ".json"
+}
+
+// dumpContent renders a message received from subscription (attached to
+// topic) and its attributes as indented JSON. The data is decoded from
+// gzip/base64 the same way the detail view does.
+func dumpContent(msg *pubsub.ReceivedMessage, subscription, topic, indent string) ([]byte, error) {
+	data, _ := utils.TryDecode(msg.Data)
+	out := dumpedMessage{
+		ID:           msg.ID,
+		Subscription: subscription,
+		Topic:        topic,
+		PublishTime:  msg.PublishTime,
+		OrderingKey:  msg.OrderingKey,
+		Attributes:   msg.Attributes,
+	}
+	if msg.Truncated() {
+		out.DataSize = msg.DataSize
+	}
+	switch {
+	case utils.IsValidJSON(data):
+		out.Data = data
+	case utf8.Valid(data):
+		out.Data, _ = json.Marshal(string(data))
+	default:
+		out.DataEncoding = "base64"
+		out.Data, _ = json.Marshal(base64.StdEncoding.EncodeToString(data))
+	}
+
+	compact, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := utils.FormatJSONIndent(compact, indent)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(formatted + "\n"), nil
+}
+
+// dumpSelected returns a command that writes the selected message to a new
+// file in the working directory
+func (m Model) dumpSelected() tea.Cmd {
+	msg := m.SelectedMessage()
+	if msg == nil {
+		return func() tea.Msg {
+			return common.Warning("No message selected to write")
+		}
+	}
+	indent, subscription, topic := m.jsonIndent, m.subscriptionName, m.topicName
+	return func() tea.Msg {
+		content, err := dumpContent(msg, subscription, topic, indent)
+		if err != nil {
+			return MessageDumpedMsg{MessageID: msg.ID, Err: err}
+		}
+		path, err := utils.WriteNewFile("", dumpFileName(msg.ID, time.Now()), content)
+		return MessageDumpedMsg{MessageID: msg.ID, Path: path, Err: err}
+	}
+}
@@ -0,0 +1,84 @@
+package subscriber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SetMaxOutstandingMsg asks the app to restart the subscription with a new
+// MaxOutstandingMessages limit
+type SetMaxOutstandingMsg struct {
+	Messages int
+}
+
+// SetMaxOutstanding sets the outstanding-message limit shown in the header
+func (m *Model) SetMaxOutstanding(n int) {
+	m.maxOutstanding = n
+}
+
+// MaxOutstanding returns the outstanding-message limit shown in the header
+func (m Model) MaxOutstanding() int {
+	return m.maxOutstanding
+}
+
+// IsEditingLimit returns whether the outstanding-message prompt is open
+func (m Model) IsEditingLimit() bool {
+	return m.editingLimit
+}
+
+// StartLimitEdit opens the outstanding-message prompt with the current value
+func (m *Model) StartLimitEdit() {
+	m.limitError = ""
+	m.limitInput.SetValue(strconv.Itoa(m.maxOutstanding))
+	m.limitInput.CursorEnd()
+	m.limitInput.Focus()
+	m.editingLimit = true
+}
+
+// CancelLimitEdit closes the outstanding-message prompt
+func (m *Model) CancelLimitEdit() {
+	m.limitInput.Blur()
+	m.editingLimit = false
+	m.limitError = ""
+}
+
+// parseMaxOutstanding parses a MaxOutstandingMessages value
+func parseMaxOutstanding(input string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("must be a positive number")
+	}
+	return n, nil
+}
+
+// handleLimitInput handles keyboard input in the outstanding-message prompt
+func (m Model) handleLimitInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.CancelLimitEdit()
+		return m, nil
+
+	case tea.KeyEnter:
+		n, err := parseMaxOutstanding(m.limitInput.Value())
+		if err != nil {
+			m.limitError = err.Error()
+			return m, nil
+		}
+
+		m.CancelLimitEdit()
+		if n == m.maxOutstanding {
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return SetMaxOutstandingMsg{Messages: n}
+		}
+
+	default:
+		var cmd tea.Cmd
+		m.limitInput, cmd = m.limitInput.Update(msg)
+		return m, cmd
+	}
+}
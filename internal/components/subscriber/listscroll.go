@@ -0,0 +1,98 @@
+package subscriber
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ScrollList moves the rows shown in the message list by delta messages
+// without changing the selection, so the detail view and what a/./p act on
+// stay put while reading other rows. The first row shown is pinned to its
+// message as new messages arrive. Moving the selection shows its page again.
+func (m *Model) ScrollList(delta int) {
+	items := m.messageList.Items()
+	if len(items) == 0 {
+		return
+	}
+	top := m.listTopIndex()
+	if top < 0 {
+		// Start from the page of the selection
+		top = m.messageList.Paginator.Page * m.messageList.Paginator.PerPage
+	}
+	top += delta
+
+	// Like the last page, the rows can run out before the end of the list
+	if top > len(items)-1 {
+		top = len(items) - 1
+	}
+	if top < 0 {
+		top = 0
+	}
+	m.listTop = items[top].(MessageItem).message
+}
+
+// IsListScrolled returns whether the message list was scrolled away from the
+// page of the selection
+func (m Model) IsListScrolled() bool {
+	return m.listTop != nil
+}
+
+// resetListScroll shows the page of the selection again
+func (m *Model) resetListScroll() {
+	m.listTop = nil
+}
+
+// listTopIndex returns the index of the first row shown in the scrolled
+// list, or -1 when the list is not scrolled or that message is gone
+func (m Model) listTopIndex() int {
+	if m.listTop == nil {
+		return -1
+	}
+	for i, item := range m.messageList.Items() {
+		if item.(MessageItem).message == m.listTop {
+			return i
+		}
+	}
+	return -1
+}
+
+// scrolledListView renders the rows of the scrolled list with the list's
+// delegate, so the selected row keeps its style if it is shown. The status
+// line takes the place of the list's own, and the rest is padded to the
+// list's height.
+func (m Model) scrolledListView() string {
+	items := m.messageList.Items()
+	top := m.listTopIndex()
+	if top < 0 {
+		// The top message was dropped or filtered out
+		top = 0
+	}
+	end := top + m.messageList.Paginator.PerPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	var b strings.Builder
+	b.WriteString(common.MutedText.Render(fmt.Sprintf("%d-%d of %d %s", top+1, end, len(items), scrolledHint(m.messageList.Index(), top, end))))
+	for i := top; i < end; i++ {
+		b.WriteString("\n")
+		m.delegate.Render(&b, m.messageList, i, items[i])
+	}
+	return lipgloss.NewStyle().Height(m.messageList.Height()).Render(b.String())
+}
+
+// scrolledHint tells where the selection is relative to the rows shown
+func scrolledHint(selected, top, end int) string {
+	switch {
+	case selected < top:
+		return "· selection above"
+	case selected >= end:
+		return "· selection below"
+	default:
+		return "· scrolled"
+	}
+}
@@ -3,34 +3,59 @@ package subscriber
 import (
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
-	"github.com/anmaso/pubsub-tui/internal/pubsub"
 	"github.com/anmaso/pubsub-tui/internal/utils"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // MessageItem implements list.Item for displaying messages
 type MessageItem struct {
-	message *pubsub.ReceivedMessage
+	message   *pubsub.ReceivedMessage
+	utc       bool           // Show times in UTC instead of local time
+	relative  bool           // Show message age instead of the publish time
+	attribute string         // Attribute to show in the title, if any
+	width     int            // Title width before truncation (0 for unlimited)
+	color     lipgloss.Color // Title color from the row colors, if any
 }
 
 func (m MessageItem) Title() string {
 	ackMark := "○"
-	if m.message.IsAcked() {
+	switch {
+	case m.message.IsAcked():
 		ackMark = "✓"
+	case m.message.IsNacked():
+		ackMark = "↺"
 	}
 	// Show first 8 chars of ID
 	shortID := m.message.ID
 	if len(shortID) > 8 {
 		shortID = shortID[:8]
 	}
-	timeStr := m.message.PublishTime.Format("15:04:05")
-	return fmt.Sprintf("[%s] %s %s", ackMark, shortID, timeStr)
+	timeStr := displayTime(m.message.PublishTime, m.utc).Format("15:04:05")
+	if m.relative {
+		timeStr = common.FormatAge(time.Since(m.message.PublishTime))
+	}
+	head := "[" + ackMark + "]"
+	if m.message.Sequence > 0 {
+		head += fmt.Sprintf(" #%d", m.message.Sequence)
+	}
+	return fitTitle(head, " "+shortID, " "+timeStr, attributeBadge(m.message), m.attributeTag(), m.width)
+}
+
+// displayTime converts t to the display timezone
+func displayTime(t time.Time, utc bool) time.Time {
+	if utc {
+		return t.UTC()
+	}
+	return t.Local()
 }
 
 func (m MessageItem) Description() string {
@@ -48,10 +73,14 @@ func (m MessageItem) FilterValue() string {
 
 // Model represents the state of the subscriber panel
 type Model struct {
-	messageList list.Model
-	filterInput textinput.Model
-	detailView  viewport.Model
-	spinner     spinner.Model
+	messageList    list.Model
+	delegate       messageDelegate // Renders rows of the scrolled list
+	filterInput    textinput.Model
+	limitInput     textinput.Model
+	attributeInput textinput.Model
+	ageInput       textinput.Model
+	detailView     viewport.Model
+	spinner        spinner.Model
 
 	messages        []*pubsub.ReceivedMessage
 	selectedMessage *pubsub.ReceivedMessage
@@ -63,11 +92,62 @@ type Model struct {
 	filtering   bool
 	filterText  string
 	filterError error
-	autoAck     bool
+	utcTime     bool            // Display timestamps in UTC instead of local time
+	relative    bool            // Display message age instead of publish time
+	ageTicking  bool            // Whether an age refresh tick is pending
+	follow      bool            // Auto-select the newest message as messages arrive
+	pendingG    common.PendingG // First g of gg was pressed
+	showRaw     bool            // Show message data as received instead of decoded
+	jsonIndent  string
+
+	ackMode  AckMode       // How received messages are acknowledged
+	ackDelay time.Duration // Delay used when cycling to the delayed ack mode
+
+	emulator         bool             // Connected to the emulator, where acks are harmless
+	autoAckConfirmed map[ackKind]bool // Automatic modes confirmed on real GCP this session
+
+	filterHistory common.FilterHistory // Recently applied filter patterns
+	regexHelp     common.RegexHelp     // Regex examples opened from the filter
+	filterCache   utils.FilterCache    // Compiled filterText
+	debounce      common.Debouncer     // Delays filtering while typing
+
+	maxOutstanding int    // MaxOutstandingMessages of the receive settings
+	editingLimit   bool   // Whether the outstanding-message prompt is open
+	limitError     string // Validation error for the prompt
+
+	listAttribute    string    // Attribute shown in list rows, if any
+	editingAttribute bool      // Whether the list attribute prompt is open
+	rowColors        RowColors // Row colors by attribute value
+
+	ageWindow  time.Duration // Show only messages published this recently, if set
+	editingAge bool          // Whether the age window prompt is open
+	ageError   string        // Validation error for the prompt
 
 	subscriptionName string
 	topicName        string
 	connected        bool
+	dropped          int64              // Messages dropped because the receive buffer was full
+	schema           *pubsub.SchemaInfo // Schema of the subscribed topic, if any
+	streamError      error              // Error that stopped the subscription stream
+
+	// Buffer of the last stopped subscription, restored if it is selected
+	// again so an accidental stop does not lose captured messages
+	stoppedName     string
+	stoppedMessages []*pubsub.ReceivedMessage
+	stoppedDropped  int64
+	stoppedSequence int64
+
+	sequence int64 // Sequence number of the last message received
+
+	bufferedBytes int64 // Data size of the messages in the buffer
+	bufferWarned  bool  // Whether the buffered data warning was logged
+
+	diffMark *pubsub.ReceivedMessage // Message marked to diff against, if any
+
+	listTop *pubsub.ReceivedMessage // First row of the scrolled list, nil to show the selection's page
+
+	ackFlash       string    // Ack confirmation shown in the detail header
+	ackFlashExpiry time.Time // When the confirmation clears itself
 }
 
 // New creates a new subscriber panel model
@@ -81,7 +161,8 @@ func New() Model {
 	delegate.Styles.NormalDesc = common.MutedText
 	delegate.Styles.SelectedDesc = common.MutedText
 
-	ml := list.New([]list.Item{}, delegate, 0, 0)
+	md := messageDelegate{delegate}
+	ml := list.New([]list.Item{}, md, 0, 0)
 	ml.Title = "Messages"
 	ml.SetShowTitle(false)
 	ml.SetShowStatusBar(true) // Show pagination info
@@ -95,11 +176,35 @@ func New() Model {
 
 	// Create filter input
 	fi := textinput.New()
-	fi.Placeholder = "regex filter..."
+	fi.Placeholder = "regex filter (? for examples)..."
 	fi.Prompt = "/ "
 	fi.PromptStyle = common.FilterPromptStyle
 	fi.TextStyle = common.FilterInputStyle
 
+	// Create outstanding-message limit input
+	li := textinput.New()
+	li.Placeholder = "messages"
+	li.Prompt = "Max outstanding: "
+	li.PromptStyle = common.FilterPromptStyle
+	li.TextStyle = common.FilterInputStyle
+	li.CharLimit = 7
+
+	// Create list attribute input
+	ai := textinput.New()
+	ai.Placeholder = "attribute name (empty to hide)"
+	ai.Prompt = "Show attribute: "
+	ai.PromptStyle = common.FilterPromptStyle
+	ai.TextStyle = common.FilterInputStyle
+	ai.CharLimit = 256
+
+	// Create age window input
+	wi := textinput.New()
+	wi.Placeholder = "duration, e.g. 30s or 5m (empty for all)"
+	wi.Prompt = "Age window: "
+	wi.PromptStyle = common.FilterPromptStyle
+	wi.TextStyle = common.FilterInputStyle
+	wi.CharLimit = 16
+
 	// Create detail viewport
 	dv := viewport.New(0, 0)
 
@@ -109,14 +214,29 @@ func New() Model {
 	sp.Style = common.LogNetworkStyle // Blue color for network activity
 
 	return Model{
-		messageList: ml,
-		filterInput: fi,
-		detailView:  dv,
-		spinner:     sp,
-		messages:    make([]*pubsub.ReceivedMessage, 0, 100),
+		messageList:    ml,
+		delegate:       md,
+		filterInput:    fi,
+		debounce:       common.NewDebouncer("subscriber", common.FilterDebounceDelay),
+		limitInput:     li,
+		attributeInput: ai,
+		ageInput:       wi,
+		detailView:     dv,
+		spinner:        sp,
+		messages:       make([]*pubsub.ReceivedMessage, 0, 100),
+		follow:         true,
+		jsonIndent:     utils.DefaultJSONIndent,
+		ackMode:        AckManual,
+		ackDelay:       DefaultAckDelay,
 	}
 }
 
+// SetJSONIndent sets the indentation used to format message data
+func (m *Model) SetJSONIndent(indent string) {
+	m.jsonIndent = indent
+	m.updateDetailView()
+}
+
 // SetFocused sets whether the panel is focused
 func (m *Model) SetFocused(focused bool) {
 	m.focused = focused
@@ -149,50 +269,107 @@ func (m *Model) SetSize(width, height int) {
 		rightWidth = 15
 	}
 
+	widthChanged := leftWidth != m.messageList.Width()
 	m.messageList.SetSize(leftWidth, contentHeight)
 	m.detailView.Width = rightWidth
 	m.detailView.Height = contentHeight
+
+	// Titles are fitted to the list width
+	if widthChanged && len(m.messages) > 0 {
+		m.applyFilter()
+	}
 }
 
 // SetSubscription sets the active subscription
+// Reconnecting to the same subscription keeps the captured messages; they
+// are only cleared when the subscription changes.
 func (m *Model) SetSubscription(name, topic string) {
+	if name != m.subscriptionName {
+		m.messages = make([]*pubsub.ReceivedMessage, 0, 100)
+		m.selectedMessage = nil
+		m.diffMark = nil
+		m.listTop = nil
+		m.dropped = 0
+		m.sequence = 0
+		if name == m.stoppedName {
+			m.messages = m.stoppedMessages
+			m.dropped = m.stoppedDropped
+			m.sequence = m.stoppedSequence
+		}
+		m.bufferedBytes = dataBytes(m.messages)
+	}
+	m.stoppedName = ""
+	m.stoppedMessages = nil
+	m.stoppedDropped = 0
+	m.stoppedSequence = 0
+
 	m.subscriptionName = name
 	m.topicName = topic
 	m.connected = true
-	m.messages = make([]*pubsub.ReceivedMessage, 0, 100)
-	m.selectedMessage = nil
+	m.schema = nil
+	m.streamError = nil
 	m.applyFilter()
 	m.updateDetailView()
 }
 
-// ClearSubscription clears the active subscription
+// ClearSubscription clears the active subscription. Its messages are kept
+// aside in case the same subscription is selected again.
 func (m *Model) ClearSubscription() {
+	if m.subscriptionName != "" {
+		m.stoppedName = m.subscriptionName
+		m.stoppedMessages = m.messages
+		m.stoppedDropped = m.dropped
+		m.stoppedSequence = m.sequence
+	}
 	m.subscriptionName = ""
 	m.topicName = ""
+	m.diffMark = nil
+	m.listTop = nil
 	m.connected = false
 	m.messages = make([]*pubsub.ReceivedMessage, 0, 100)
+	m.bufferedBytes = 0
 	m.selectedMessage = nil
+	m.dropped = 0
+	m.schema = nil
+	m.streamError = nil
 	m.messageList.SetItems([]list.Item{})
 	m.updateDetailView()
 }
 
 // AddMessage adds a new message to the list
 func (m *Model) AddMessage(msg *pubsub.ReceivedMessage) {
-	// Auto-ack if enabled
-	if m.autoAck {
+	// Acknowledge on receive if enabled; the delayed mode is scheduled by Update
+	if m.ackMode == AckImmediate {
 		msg.Ack()
 	}
 
+	if m.replaceMessage(msg) {
+		return
+	}
+
+	// Number messages in arrival order, which can differ from publish order
+	m.sequence++
+	msg.Sequence = m.sequence
+
 	// Append to list (newest last)
 	m.messages = append(m.messages, msg)
+	m.bufferedBytes += int64(len(msg.Data))
 
 	// Cap at 100 messages
 	if len(m.messages) > 100 {
+		m.bufferedBytes -= int64(len(m.messages[0].Data))
 		m.messages = m.messages[1:]
 	}
 
 	m.applyFilter()
 
+	if !m.follow || !m.isNewestDisplayed(msg) {
+		// Keep the user's selection; its index may shift if the oldest was
+		// dropped. A message hidden by the filter is never selected.
+		m.reselect()
+		return
+	}
+
 	// Auto-select newest message
 	m.selectedMessage = msg
 	m.updateDetailView()
@@ -201,6 +378,113 @@ func (m *Model) AddMessage(msg *pubsub.ReceivedMessage) {
 	m.messageList.Select(len(m.messageList.Items()) - 1)
 }
 
+// replaceMessage swaps in msg for a listed message with the same ID, which
+// Pub/Sub redelivers after the subscription is restarted or reconnected.
+// The row keeps its place, but takes the new message so acks use a handle
+// that still works. Returns false if no message has the ID.
+func (m *Model) replaceMessage(msg *pubsub.ReceivedMessage) bool {
+	if msg.ID == "" {
+		return false
+	}
+	for i, old := range m.messages {
+		if old.ID != msg.ID {
+			continue
+		}
+		msg.Sequence = old.Sequence
+		m.messages[i] = msg
+		m.bufferedBytes += int64(len(msg.Data) - len(old.Data))
+		if m.selectedMessage == old {
+			m.selectedMessage = msg
+		}
+		if m.diffMark == old {
+			m.diffMark = msg
+		}
+		if m.listTop == old {
+			m.listTop = msg
+		}
+		m.applyFilter()
+		m.reselect()
+		m.updateDetailView()
+		return true
+	}
+	return false
+}
+
+// isNewestDisplayed reports whether msg is the last row of the list, i.e.
+// it passed the filters
+func (m Model) isNewestDisplayed(msg *pubsub.ReceivedMessage) bool {
+	items := m.messageList.Items()
+	if len(items) == 0 {
+		return false
+	}
+	item, ok := items[len(items)-1].(MessageItem)
+	return ok && item.message == msg
+}
+
+// reselect moves the list cursor back to the selected message after the
+// items changed. The cursor is left alone if the message is no longer listed.
+func (m *Model) reselect() {
+	if m.selectedMessage == nil {
+		return
+	}
+	for i, item := range m.messageList.Items() {
+		if mi, ok := item.(MessageItem); ok && mi.message == m.selectedMessage {
+			m.messageList.Select(i)
+			return
+		}
+	}
+}
+
+// JumpToFirst selects the oldest message and stops following new messages
+func (m *Model) JumpToFirst() {
+	m.follow = false
+	common.JumpList(&m.messageList, false)
+	m.UpdateSelection()
+}
+
+// JumpToLast selects the newest message and resumes following new messages
+func (m *Model) JumpToLast() {
+	m.follow = true
+	common.JumpList(&m.messageList, true)
+	m.UpdateSelection()
+}
+
+// ToggleFollow switches following new messages on or off. Turning it on
+// jumps to the newest message.
+func (m *Model) ToggleFollow() {
+	if m.follow {
+		m.follow = false
+		return
+	}
+	m.JumpToLast()
+}
+
+// IsFollowing returns whether the newest message is auto-selected on arrival
+func (m Model) IsFollowing() bool {
+	return m.follow
+}
+
+// SetError records an error that stopped the subscription stream.
+// It is shown until the subscription is restarted or cleared.
+func (m *Model) SetError(err error) {
+	m.streamError = err
+}
+
+// Error returns the error that stopped the subscription stream, if any
+func (m Model) Error() error {
+	return m.streamError
+}
+
+// SetDropped updates the count of messages dropped by the subscription
+func (m *Model) SetDropped(n int64) {
+	m.dropped = n
+}
+
+// Dropped returns the count of messages dropped by the subscription
+func (m Model) Dropped() int64 {
+	return m.dropped
+}
+
 // SelectedMessage returns the currently selected message
 func (m Model) SelectedMessage() *pubsub.ReceivedMessage {
 	if m.messageList.SelectedItem() == nil {
@@ -215,14 +499,46 @@ func (m Model) SelectedMessage() *pubsub.ReceivedMessage {
 	return item.message
 }
 
-// ToggleAutoAck toggles auto-acknowledgment
-func (m *Model) ToggleAutoAck() {
-	m.autoAck = !m.autoAck
+// ToggleTimezone switches timestamps between local time and UTC
+func (m *Model) ToggleTimezone() {
+	m.utcTime = !m.utcTime
+	m.applyFilter()
+	m.updateDetailView()
 }
 
-// IsAutoAck returns whether auto-ack is enabled
-func (m Model) IsAutoAck() bool {
-	return m.autoAck
+// IsUTC returns whether timestamps are displayed in UTC
+func (m Model) IsUTC() bool {
+	return m.utcTime
+}
+
+// TimezoneName returns the name of the display timezone
+func (m Model) TimezoneName() string {
+	if m.utcTime {
+		return "UTC"
+	}
+	return "Local"
+}
+
+// ToggleRelativeTime switches the list between publish times and message ages
+func (m *Model) ToggleRelativeTime() {
+	m.relative = !m.relative
+	m.applyFilter()
+}
+
+// IsRelativeTime returns whether the list shows message ages
+func (m Model) IsRelativeTime() bool {
+	return m.relative
+}
+
+// ToggleRaw switches the detail view between decoded and raw message data
+func (m *Model) ToggleRaw() {
+	m.showRaw = !m.showRaw
+	m.updateDetailView()
+}
+
+// IsRaw returns whether the detail view shows raw message data
+func (m Model) IsRaw() bool {
+	return m.showRaw
 }
 
 // IsConnected returns whether connected to a subscription
@@ -245,6 +561,12 @@ func (m Model) IsFiltering() bool {
 	return m.filtering
 }
 
+// Messages returns all buffered messages, oldest first.
+// The returned slice must not be modified.
+func (m Model) Messages() []*pubsub.ReceivedMessage {
+	return m.messages
+}
+
 // MessageCount returns the total message count
 func (m Model) MessageCount() int {
 	return len(m.messages)
@@ -257,29 +579,47 @@ func (m Model) DisplayedCount() int {
 
 // applyFilter filters messages based on current filter text
 func (m *Model) applyFilter() {
-	var items []list.Item
+	items := make([]list.Item, 0, len(m.messages))
+	filter := m.filterCache.Get(m.filterText)
+	now := time.Now()
 
 	for _, msg := range m.messages {
+		if !withinAgeWindow(msg.PublishTime, now, m.ageWindow) {
+			continue
+		}
 		if m.filterText == "" {
-			items = append(items, MessageItem{message: msg})
+			items = append(items, m.newItem(msg))
 			continue
 		}
 
 		// Search in ID and data
 		searchText := msg.ID + string(msg.Data)
-		result := utils.MatchesFilter(searchText, m.filterText)
+		result := filter.Match(searchText)
 		if result.Error != nil {
 			m.filterError = result.Error
-			items = append(items, MessageItem{message: msg})
+			items = append(items, m.newItem(msg))
 		} else if result.Matches {
 			m.filterError = nil
-			items = append(items, MessageItem{message: msg})
+			items = append(items, m.newItem(msg))
 		}
 	}
 
 	m.messageList.SetItems(items)
 }
 
+// newItem builds a list item for a message
+func (m Model) newItem(msg *pubsub.ReceivedMessage) MessageItem {
+	color, _ := m.rowColors.Color(msg)
+	return MessageItem{
+		message:   msg,
+		utc:       m.utcTime,
+		relative:  m.relative,
+		attribute: m.listAttribute,
+		width:     m.messageList.Width(),
+		color:     color,
+	}
+}
+
 // updateDetailView updates the detail view content
 func (m *Model) updateDetailView() {
 	msg := m.SelectedMessage()
@@ -290,19 +630,40 @@ func (m *Model) updateDetailView() {
 
 	var content string
 
+	// Where the message came from, read when rendering so it always names
+	// the current subscription
+	if m.subscriptionName != "" {
+		content += common.FilterPromptStyle.Render("Subscription: ") + m.subscriptionName + "\n"
+	}
+	if m.topicName != "" {
+		content += common.FilterPromptStyle.Render("Topic: ") + m.topicName + "\n"
+	}
+
 	// Message ID
 	content += common.FilterPromptStyle.Render("ID: ") + msg.ID + "\n"
-	content += common.FilterPromptStyle.Render("Time: ") + msg.PublishTime.Format(time.RFC3339) + "\n"
+	if msg.Sequence > 0 {
+		content += common.FilterPromptStyle.Render("Received: ") + fmt.Sprintf("#%d", msg.Sequence) + "\n"
+	}
+	content += common.FilterPromptStyle.Render("Time: ") + displayTime(msg.PublishTime, m.utcTime).Format(time.RFC3339) + "\n"
 
 	// Ack status
 	status := "Pending"
 	statusStyle := common.LogWarningStyle
-	if msg.IsAcked() {
+	switch {
+	case msg.IsAcked():
 		status = "Acknowledged"
 		statusStyle = common.LogSuccessStyle
+	case msg.IsNacked():
+		status = "Nacked (redelivery requested)"
+		statusStyle = common.MutedText
 	}
 	content += common.FilterPromptStyle.Render("Status: ") + statusStyle.Render(status) + "\n"
 
+	schema := schemaFor(msg, m.schema)
+	if schema != nil {
+		content += common.FilterPromptStyle.Render("Schema: ") + schema.String() + "\n"
+	}
+
 	// Attributes
 	if len(msg.Attributes) > 0 {
 		content += "\n" + common.FilterPromptStyle.Render("Attributes:") + "\n"
@@ -311,10 +672,33 @@ func (m *Model) updateDetailView() {
 		}
 	}
 
-	// Data
-	content += "\n" + common.FilterPromptStyle.Render("Data:") + "\n"
-	formatted, _ := utils.FormatJSON(msg.Data)
-	content += formatted
+	// Data, decoded from gzip/base64 unless raw display is selected.
+	// Schema-encoded data is decoded with the schema instead: JSON
+	// encodings are already readable and binary ones are converted to JSON.
+	data, transform := msg.Data, ""
+	if !m.showRaw && schema == nil {
+		data, transform = utils.TryDecode(msg.Data)
+	}
+	label := "Data:"
+	switch {
+	case schema != nil:
+		data, label = schema.decodeData(msg.Data, m.showRaw)
+	case transform != "":
+		label = fmt.Sprintf("Data (%s-decoded, r: raw):", transform)
+	case m.showRaw:
+		label = "Data (raw, r: decoded):"
+	}
+	content += "\n" + common.FilterPromptStyle.Render(label) + "\n"
+	if utf8.Valid(data) {
+		formatted, _ := utils.FormatJSONIndent(data, m.jsonIndent)
+		content += formatted
+	} else {
+		// Binary data would corrupt the terminal, so show it escaped
+		content += fmt.Sprintf("%q", data)
+	}
+	if msg.Truncated() {
+		content += "\n" + common.MutedText.Render(fmt.Sprintf("(truncated, %d bytes total)", msg.DataSize))
+	}
 
 	m.detailView.SetContent(content)
 	m.detailView.GotoTop()
@@ -323,7 +707,7 @@ func (m *Model) updateDetailView() {
 // AckSelected acknowledges the selected message
 func (m *Model) AckSelected() bool {
 	msg := m.SelectedMessage()
-	if msg != nil && !msg.IsAcked() {
+	if msg != nil && !msg.IsAcked() && !msg.IsNacked() {
 		msg.Ack()
 		m.applyFilter() // Refresh display
 		m.updateDetailView()
@@ -332,13 +716,48 @@ func (m *Model) AckSelected() bool {
 	return false
 }
 
+// NackSelected nacks the selected message so Pub/Sub redelivers it. It
+// reports false if nothing is selected or the message was already settled.
+func (m *Model) NackSelected() bool {
+	msg := m.SelectedMessage()
+	if msg == nil || msg.IsAcked() || msg.IsNacked() {
+		return false
+	}
+	msg.Nack()
+	m.applyFilter() // Refresh display
+	m.updateDetailView()
+	return true
+}
+
+// AckDisplayed acknowledges every unacked message the filter displays,
+// leaving hidden messages untouched, and returns the acked messages
+func (m *Model) AckDisplayed() []*pubsub.ReceivedMessage {
+	var acked []*pubsub.ReceivedMessage
+	for _, item := range m.messageList.Items() {
+		msg := item.(MessageItem).message
+		if msg.IsAcked() {
+			continue
+		}
+		msg.Ack()
+		if msg.IsAcked() {
+			acked = append(acked, msg)
+		}
+	}
+	if len(acked) > 0 {
+		m.applyFilter() // Refresh display
+		m.updateDetailView()
+	}
+	return acked
+}
+
 // UpdateSelection updates the detail view when selection changes
 func (m *Model) UpdateSelection() {
+	m.resetListScroll()
 	m.selectedMessage = m.SelectedMessage()
 	m.updateDetailView()
 }
 
 // IsInputActive returns whether an input field is active
 func (m Model) IsInputActive() bool {
-	return m.filtering
+	return m.filtering || m.editingLimit || m.editingAttribute || m.editingAge
 }
@@ -1,10 +1,20 @@
 package subscriber
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/anmaso/pubsub-tui/internal/pubsub"
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestNew(t *testing.T) {
@@ -16,8 +26,8 @@ func TestNew(t *testing.T) {
 	if m.connected {
 		t.Error("new model should not be connected")
 	}
-	if m.autoAck {
-		t.Error("new model should have auto-ack disabled")
+	if m.ackMode != AckManual {
+		t.Error("new model should ack manually")
 	}
 	if m.filtering {
 		t.Error("new model should not be filtering")
@@ -79,21 +89,77 @@ func TestModel_ClearSubscription(t *testing.T) {
 	}
 }
 
-func TestModel_ToggleAutoAck(t *testing.T) {
+func TestModel_SetSubscription_Reconnect(t *testing.T) {
 	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-1", PublishTime: time.Now()})
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-2", PublishTime: time.Now()})
+
+	// Selecting the same subscription again keeps the buffer
+	m.SetSubscription("test-sub", "test-topic")
+	if m.MessageCount() != 2 {
+		t.Errorf("MessageCount() = %d after same-name reconnect, want 2", m.MessageCount())
+	}
+
+	// So does stopping and reconnecting
+	m.ClearSubscription()
+	if m.MessageCount() != 0 {
+		t.Errorf("MessageCount() = %d while stopped, want 0", m.MessageCount())
+	}
+	m.SetSubscription("test-sub", "test-topic")
+	if m.MessageCount() != 2 || m.DisplayedCount() != 2 {
+		t.Errorf("MessageCount() = %d after stop and reconnect, want 2", m.MessageCount())
+	}
+
+	// A different subscription starts empty
+	m.SetSubscription("other-sub", "test-topic")
+	if m.MessageCount() != 0 {
+		t.Errorf("MessageCount() = %d after switching subscription, want 0", m.MessageCount())
+	}
+	m.ClearSubscription()
+	m.SetSubscription("test-sub", "test-topic")
+	if m.MessageCount() != 0 {
+		t.Errorf("MessageCount() = %d, want the old buffer discarded after switching", m.MessageCount())
+	}
+}
 
-	if m.IsAutoAck() {
-		t.Error("auto-ack should be disabled initially")
+func TestModel_SetSubscription_Switch(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	for i := 0; i < 50; i++ {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), PublishTime: time.Now()})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if m.DiffMark() == nil || !m.IsListScrolled() {
+		t.Fatal("x should mark a message and ctrl+k scroll the list")
 	}
 
-	m.ToggleAutoAck()
-	if !m.IsAutoAck() {
-		t.Error("auto-ack should be enabled after toggle")
+	// Switching straight to another subscription drops both
+	m.SetSubscription("other-sub", "other-topic")
+	if m.DiffMark() != nil {
+		t.Error("the diff mark should not carry over to another subscription")
+	}
+	if m.IsListScrolled() {
+		t.Error("the list scroll should not carry over to another subscription")
 	}
+}
+
+func TestModel_CycleAckMode(t *testing.T) {
+	m := New()
+	m.SetAckDelay(3 * time.Second)
 
-	m.ToggleAutoAck()
-	if m.IsAutoAck() {
-		t.Error("auto-ack should be disabled after second toggle")
+	want := []AckMode{AckImmediate, AckDelayed(3 * time.Second), AckManual}
+	for _, mode := range want {
+		m.CycleAckMode()
+		if m.AckMode() != mode {
+			t.Errorf("AckMode() = %v, want %v", m.AckMode(), mode)
+		}
+	}
+	if got := AckDelayed(3 * time.Second).String(); got != "after 3s" {
+		t.Errorf("String() = %q, want after 3s", got)
 	}
 }
 
@@ -118,39 +184,167 @@ func TestModel_AddMessage(t *testing.T) {
 	}
 }
 
-func TestModel_AddMessage_AutoAck(t *testing.T) {
+func TestModel_AddMessage_Sequence(t *testing.T) {
 	m := New()
 	m.SetSize(100, 50)
 	m.SetSubscription("test-sub", "test-topic")
-	m.ToggleAutoAck() // Enable auto-ack
 
-	ackCalled := false
-	msg := &pubsub.ReceivedMessage{
-		ID:          "msg-1",
-		Data:        []byte(`{"test": "data"}`),
-		PublishTime: time.Now(),
+	// Publish times are out of order; sequence follows arrival
+	now := time.Now()
+	var msgs []*pubsub.ReceivedMessage
+	for i, offset := range []time.Duration{0, -time.Minute, time.Second} {
+		msg := &pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), PublishTime: now.Add(offset)}
+		m.AddMessage(msg)
+		msgs = append(msgs, msg)
 	}
-	// Set up ack function via reflection or by setting the unexported field
-	// Since ackFunc is unexported, we'll test via the IsAcked() method
-	msg.SetAcked(false)
-
-	// Create a message with ackFunc
-	msgWithAck := &pubsub.ReceivedMessage{
-		ID:          "msg-2",
-		Data:        []byte(`{"test": "data2"}`),
-		PublishTime: time.Now(),
+	for i, msg := range msgs {
+		if msg.Sequence != int64(i+1) {
+			t.Errorf("message %d Sequence = %d, want %d", i, msg.Sequence, i+1)
+		}
+	}
+	if title := m.newItem(msgs[1]).Title(); !strings.Contains(title, "#2 ") {
+		t.Errorf("Title() = %q, want it to show #2", title)
 	}
-	// We can't set ackFunc directly, but we can verify the behavior through SetAcked
 
+	// A new subscription starts again from 1
+	m.SetSubscription("other-sub", "test-topic")
+	msg := &pubsub.ReceivedMessage{ID: "msg-a", PublishTime: now}
 	m.AddMessage(msg)
+	if msg.Sequence != 1 {
+		t.Errorf("Sequence = %d on a new subscription, want 1", msg.Sequence)
+	}
+}
+
+func TestModel_AutoAckConfirm(t *testing.T) {
+	pressA := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}}
+
+	// On real GCP the first switch to auto-ack asks first
+	m := New()
+	m.SetSubscription("orders-sub", "orders")
+	m, cmd := m.Update(pressA)
+	req, ok := cmd().(AutoAckConfirmRequestMsg)
+	if !ok || req.Mode != AckImmediate || req.Subscription != "orders-sub" {
+		t.Fatalf("A returned %v, want a confirm request for on-receive acks", req)
+	}
+	if m.AckMode() != AckManual {
+		t.Errorf("AckMode() = %v before confirming, want manual", m.AckMode())
+	}
+
+	// The delayed mode is confirmed on its own
+	m.ConfirmAutoAck(req.Mode)
+	m, cmd = m.Update(pressA)
+	req, ok = cmd().(AutoAckConfirmRequestMsg)
+	if !ok || req.Mode != AckDelayed(DefaultAckDelay) {
+		t.Fatalf("A returned %v, want a confirm request for delayed acks", req)
+	}
+	if m.AckMode() != AckImmediate {
+		t.Errorf("AckMode() = %v before confirming, want on receive", m.AckMode())
+	}
+
+	// Once both are confirmed, later switches are not gated
+	m.ConfirmAutoAck(req.Mode)
+	for _, want := range []AckMode{AckManual, AckImmediate, AckDelayed(DefaultAckDelay)} {
+		m, cmd = m.Update(pressA)
+		if _, ok := cmd().(AutoAckConfirmRequestMsg); ok || m.AckMode() != want {
+			t.Errorf("AckMode() = %v (confirm requested %v), want %v without asking", m.AckMode(), ok, want)
+		}
+	}
+
+	// The emulator never asks
+	m = New()
+	m.SetEmulatorMode(true)
+	m, cmd = m.Update(pressA)
+	if _, ok := cmd().(AutoAckConfirmRequestMsg); ok || m.AckMode() != AckImmediate {
+		t.Errorf("emulator AckMode() = %v, want on receive without asking", m.AckMode())
+	}
+}
+
+func TestModel_AckModes(t *testing.T) {
+	newMessage := func(id string, acks *int) *pubsub.ReceivedMessage {
+		return pubsub.NewReceivedMessage(id, []byte(`{}`), func() { *acks++ })
+	}
+
+	t.Run("manual", func(t *testing.T) {
+		m := New()
+		m.SetSubscription("test-sub", "test-topic")
+		acks := 0
+		m, cmd := m.Update(MessageReceivedMsg{Message: newMessage("msg-1", &acks)})
+		if acks != 0 || cmd != nil {
+			t.Errorf("acks = %d, cmd = %v; want no ack and nothing scheduled", acks, cmd)
+		}
+	})
+
+	t.Run("immediate", func(t *testing.T) {
+		m := New()
+		m.SetSubscription("test-sub", "test-topic")
+		m.SetAckMode(AckImmediate)
+		acks := 0
+		_, cmd := m.Update(MessageReceivedMsg{Message: newMessage("msg-1", &acks)})
+		if acks != 1 {
+			t.Errorf("acks = %d on receive, want 1", acks)
+		}
+		if _, ok := cmd().(MessageAckedMsg); !ok {
+			t.Error("an immediate ack should be reported")
+		}
+	})
+
+	t.Run("delayed", func(t *testing.T) {
+		m := New()
+		m.SetSize(100, 50)
+		m.SetSubscription("test-sub", "test-topic")
+		m.SetAckMode(AckDelayed(20 * time.Millisecond))
+		acks := 0
+		msg := newMessage("msg-1", &acks)
+
+		start := time.Now()
+		m, cmd := m.Update(MessageReceivedMsg{Message: msg})
+		if acks != 0 || cmd == nil {
+			t.Fatalf("acks = %d on receive, want 0 with an ack scheduled", acks)
+		}
+		due, ok := cmd().(DelayedAckMsg)
+		if !ok || due.Message != msg {
+			t.Fatalf("scheduled command returned %v, want DelayedAckMsg for the message", due)
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("ack fired after %v, want at least 20ms", elapsed)
+		}
+
+		m, cmd = m.Update(due)
+		if acks != 1 || !msg.IsAcked() {
+			t.Errorf("acks = %d after the delay, want 1", acks)
+		}
+		if _, ok := cmd().(MessageAckedMsg); !ok {
+			t.Error("a delayed ack should be reported")
+		}
+
+		// A message acked before its delay, or after switching to manual, is left alone
+		other := newMessage("msg-2", &acks)
+		m, _ = m.Update(MessageReceivedMsg{Message: other})
+		m.SetAckMode(AckManual)
+		m.Update(DelayedAckMsg{Message: other})
+		if acks != 1 {
+			t.Errorf("acks = %d, want no ack once the mode is manual", acks)
+		}
+	})
+}
 
-	// The AddMessage should have called Ack() but since ackFunc is nil, IsAcked() returns false
-	// unless the Ack() method sets acked=true even with nil ackFunc
-	// Looking at the code, Ack() only sets acked=true if ackFunc is not nil
-	// So we need to test with a proper mock
+func TestAckDelayFromEnv(t *testing.T) {
+	t.Setenv(AckDelayEnvVar, "")
+	if d, err := AckDelayFromEnv(); err != nil || d != DefaultAckDelay {
+		t.Errorf("AckDelayFromEnv() unset = %v, %v; want the default", d, err)
+	}
+
+	t.Setenv(AckDelayEnvVar, "1m30s")
+	if d, err := AckDelayFromEnv(); err != nil || d != 90*time.Second {
+		t.Errorf("AckDelayFromEnv() = %v, %v; want 1m30s", d, err)
+	}
 
-	_ = ackCalled
-	_ = msgWithAck
+	for _, v := range []string{"5", "-2s", "0s"} {
+		t.Setenv(AckDelayEnvVar, v)
+		if _, err := AckDelayFromEnv(); err == nil {
+			t.Errorf("AckDelayFromEnv() with %q should fail", v)
+		}
+	}
 }
 
 func TestModel_AddMessage_Caps(t *testing.T) {
@@ -161,7 +355,7 @@ func TestModel_AddMessage_Caps(t *testing.T) {
 	// Add 101 messages
 	for i := 0; i < 101; i++ {
 		msg := &pubsub.ReceivedMessage{
-			ID:          string(rune('0' + i%10)),
+			ID:          fmt.Sprint(i),
 			Data:        []byte(`{}`),
 			PublishTime: time.Now(),
 		}
@@ -174,6 +368,166 @@ func TestModel_AddMessage_Caps(t *testing.T) {
 	}
 }
 
+func TestModel_AddMessage_Redelivered(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	first := &pubsub.ReceivedMessage{ID: "msg-1", Data: []byte(`{"n":1}`), PublishTime: time.Now()}
+	m.AddMessage(first)
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-2", Data: []byte(`{"n":2}`), PublishTime: time.Now()})
+	m.messageList.Select(0)
+	m.UpdateSelection()
+
+	// Restarting the subscription redelivers unacked messages with new handles
+	m.ClearSubscription()
+	m.SetSubscription("test-sub", "test-topic")
+	redelivered := &pubsub.ReceivedMessage{ID: "msg-1", Data: []byte(`{"n":1}`), PublishTime: time.Now()}
+	m.AddMessage(redelivered)
+
+	if m.MessageCount() != 2 {
+		t.Fatalf("MessageCount() = %d, want 2: a redelivered message replaces its row", m.MessageCount())
+	}
+	if got := m.Messages()[0]; got != redelivered || got.Sequence != first.Sequence {
+		t.Errorf("first row = %+v, want the redelivered message in the original place", got)
+	}
+	if m.SelectedMessage() != redelivered {
+		t.Error("the selection should move to the redelivered message")
+	}
+}
+
+func TestModel_AddMessage_Follow(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+
+	if !m.IsFollowing() {
+		t.Fatal("new model should follow new messages")
+	}
+
+	for _, id := range []string{"msg-1", "msg-2", "msg-3"} {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: id, Data: []byte(`{}`), PublishTime: time.Now()})
+	}
+
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-3" {
+		t.Errorf("SelectedMessage() = %v, want newest msg-3 while following", got)
+	}
+}
+
+func TestModel_JumpFirstLast(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	for i := 0; i < 10; i++ {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), Data: []byte(`{}`), PublishTime: time.Now()})
+	}
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+	G := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}}
+
+	// gg jumps to the oldest message and stops following
+	m, _ = m.Update(g)
+	m, _ = m.Update(g)
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-0" {
+		t.Fatalf("SelectedMessage() after gg = %v, want msg-0", got)
+	}
+	if m.selectedMessage == nil || m.selectedMessage.ID != "msg-0" {
+		t.Errorf("detail view shows %v, want msg-0", m.selectedMessage)
+	}
+	if m.IsFollowing() {
+		t.Error("gg should stop following")
+	}
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-10", Data: []byte(`{}`), PublishTime: time.Now()})
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-0" {
+		t.Errorf("SelectedMessage() = %v, want msg-0 kept while not following", got)
+	}
+
+	// G jumps to the newest message and follows new ones
+	m, _ = m.Update(G)
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-10" {
+		t.Fatalf("SelectedMessage() after G = %v, want msg-10", got)
+	}
+	if !m.IsFollowing() {
+		t.Error("G should follow new messages")
+	}
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-11", Data: []byte(`{}`), PublishTime: time.Now()})
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-11" {
+		t.Errorf("SelectedMessage() = %v, want msg-11 while following", got)
+	}
+
+	// Home and End do the same
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-0" || m.IsFollowing() {
+		t.Errorf("Home selects %v (following %v), want msg-0 without following", got, m.IsFollowing())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-11" || !m.IsFollowing() {
+		t.Errorf("End selects %v (following %v), want msg-11 and following", got, m.IsFollowing())
+	}
+}
+
+func TestModel_AddMessage_Filtered(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	m.filterText = "order-"
+
+	for _, id := range []string{"order-1", "order-2", "refund-1"} {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: id, Data: []byte(`{}`), PublishTime: time.Now()})
+	}
+
+	// The newest message is hidden by the filter, so following keeps the
+	// newest displayed message selected
+	if got := m.SelectedMessage(); got == nil || got.ID != "order-2" {
+		t.Errorf("SelectedMessage() = %v, want order-2", got)
+	}
+	if m.selectedMessage == nil || m.selectedMessage.ID != "order-2" {
+		t.Errorf("detail view shows %v, want order-2", m.selectedMessage)
+	}
+	if got := m.messageList.Index(); got != 1 {
+		t.Errorf("cursor = %d, want the last displayed row", got)
+	}
+
+	// A matching message is followed again
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "order-3", Data: []byte(`{}`), PublishTime: time.Now()})
+	if got := m.SelectedMessage(); got == nil || got.ID != "order-3" {
+		t.Errorf("SelectedMessage() = %v, want order-3", got)
+	}
+}
+
+func TestModel_AddMessage_Paused(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+
+	for i := 0; i < 100; i++ {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), Data: []byte(`{}`), PublishTime: time.Now()})
+	}
+
+	// Moving up pauses following
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if m.IsFollowing() {
+		t.Fatal("navigating up should disable follow")
+	}
+	want := m.SelectedMessage()
+	if want == nil || want.ID != "msg-98" {
+		t.Fatalf("SelectedMessage() = %v, want msg-98", want)
+	}
+
+	// New arrivals (which also evict the oldest) keep the selection
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-new", Data: []byte(`{}`), PublishTime: time.Now()})
+	if got := m.SelectedMessage(); got != want {
+		t.Errorf("SelectedMessage() = %v, want %v to stay selected while paused", got, want)
+	}
+
+	// Toggling follow back on jumps to the newest message
+	m.ToggleFollow()
+	if !m.IsFollowing() {
+		t.Error("ToggleFollow() should re-enable follow")
+	}
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-new" {
+		t.Errorf("SelectedMessage() = %v, want msg-new after resuming follow", got)
+	}
+}
+
 func TestModel_AckSelected(t *testing.T) {
 	m := New()
 	m.SetSize(100, 50)
@@ -198,6 +552,174 @@ func TestModel_AckSelected(t *testing.T) {
 	// AckSelected again would return true again (keeps attempting)
 }
 
+func TestModel_AckKeys_Cursor(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	for i := 0; i < 3; i++ {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), Data: []byte(`{}`), PublishTime: time.Now()})
+	}
+	m.JumpToFirst()
+
+	// "a" acks and advances to the next message
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd == nil {
+		t.Error("acking should return a command")
+	}
+	if got := m.messageList.Index(); got != 1 {
+		t.Errorf("cursor after a = %d, want 1", got)
+	}
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-1" {
+		t.Errorf("SelectedMessage() after a = %v, want msg-1", got)
+	}
+
+	// "." acks and stays on the same message
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	if cmd == nil {
+		t.Error("acking should return a command")
+	}
+	if got := m.messageList.Index(); got != 1 {
+		t.Errorf("cursor after . = %d, want 1", got)
+	}
+	if got := m.SelectedMessage(); got == nil || got.ID != "msg-1" {
+		t.Errorf("SelectedMessage() after . = %v, want msg-1", got)
+	}
+}
+
+func TestModel_AckFlash(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	m.AddMessage(pubsub.NewReceivedMessage("msg-1", []byte(`{}`), func() {}))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	if !strings.Contains(m.View(), "Acked msg-1 ✓") {
+		t.Error("the detail header should confirm the ack")
+	}
+
+	// The confirmation clears itself
+	m.ExpireStatus(time.Now().Add(time.Second))
+	if m.ackFlash == "" {
+		t.Error("the confirmation should stay for a while")
+	}
+	m.ExpireStatus(time.Now().Add(common.StatusSuccessDuration))
+	if strings.Contains(m.View(), "Acked") {
+		t.Error("the confirmation should clear after the status duration")
+	}
+
+	// A nack is confirmed and logged the same way, and marks the row
+	m.AddMessage(pubsub.NewReceivedMessage("msg-2", []byte(`{}`), func() {}))
+	m.JumpToLast()
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if !strings.Contains(m.View(), "Nacked msg-2 ✓") {
+		t.Error("the detail header should confirm the nack")
+	}
+	if log, ok := cmd().(common.LogMsg); !ok || !strings.Contains(log.Message, "Nacked message: msg-2") {
+		t.Errorf("nack sent %#v, want it logged", cmd())
+	}
+	if selected := m.SelectedMessage(); !selected.IsNacked() || !strings.Contains(m.messageList.SelectedItem().(MessageItem).Title(), "[↺]") {
+		t.Error("the nacked row should be marked")
+	}
+
+	// A nacked message cannot be acked or nacked again
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}}); cmd != nil {
+		t.Error("a nacked message should not be acked")
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}); cmd != nil {
+		t.Error("a nacked message should not be nacked again")
+	}
+}
+
+func TestModel_FilterHistoryRecall(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+
+	applyFilter := func(pattern string) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+		m.filterInput.SetValue("")
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(pattern)})
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	applyFilter("orders")
+	applyFilter("events")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.filterInput.Value(); got != "events" {
+		t.Errorf("first Up recalled %q, want events", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.filterInput.Value(); got != "orders" {
+		t.Errorf("second Up recalled %q, want orders", got)
+	}
+	if m.filterText != "orders" {
+		t.Errorf("filterText = %q, recalled pattern should be applied", m.filterText)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.filterInput.Value(); got != "events" {
+		t.Errorf("Down recalled %q, want events", got)
+	}
+}
+
+func TestModel_FilterInput_EditingKeys(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("type order created")})
+
+	steps := []struct {
+		name string
+		msg  tea.KeyMsg
+		want string
+	}{
+		{"ctrl+w deletes the previous word", tea.KeyMsg{Type: tea.KeyCtrlW}, "type order "},
+		{"ctrl+a moves to the start", tea.KeyMsg{Type: tea.KeyCtrlA}, "type order "},
+		{"typing at the start", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("^")}, "^type order "},
+		{"ctrl+e moves to the end", tea.KeyMsg{Type: tea.KeyCtrlE}, "^type order "},
+		{"typing at the end", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("paid")}, "^type order paid"},
+		{"left arrow", tea.KeyMsg{Type: tea.KeyLeft}, "^type order paid"},
+		{"ctrl+u clears to the start", tea.KeyMsg{Type: tea.KeyCtrlU}, "d"},
+	}
+	for _, step := range steps {
+		m, _ = m.Update(step.msg)
+		if got := m.filterInput.Value(); got != step.want {
+			t.Errorf("%s: value = %q, want %q", step.name, got, step.want)
+		}
+		if m.filterText != step.want {
+			t.Errorf("%s: filterText = %q, want the edited pattern %q", step.name, m.filterText, step.want)
+		}
+	}
+}
+
+func TestModel_FilterInput_RegexHelp(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if !m.regexHelp.Visible() || m.filterInput.Value() != "" {
+		t.Fatalf("? should open the regex examples, value %q", m.filterInput.Value())
+	}
+	if !strings.Contains(m.View(), "Go regular expressions") {
+		t.Error("the examples should be shown in the panel")
+	}
+
+	// Enter closes the examples without submitting the filter
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.regexHelp.Visible() || !m.IsFiltering() {
+		t.Error("enter should close the examples and keep filtering")
+	}
+
+	// ? after text is part of the pattern
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("colou?r")})
+	if m.regexHelp.Visible() || m.filterInput.Value() != "colou?r" {
+		t.Errorf("value = %q, want colou?r typed", m.filterInput.Value())
+	}
+}
+
 func TestModel_AckSelected_AlreadyAcked(t *testing.T) {
 	m := New()
 	m.SetSize(100, 50)
@@ -292,51 +814,239 @@ func TestMessageItem_Title_Acked(t *testing.T) {
 	}
 }
 
-func TestMessageItem_Description(t *testing.T) {
-	tests := []struct {
-		name     string
-		data     string
-		wantFull bool
-	}{
-		{
-			name:     "short data",
-			data:     `{"key": "value"}`,
-			wantFull: true,
-		},
-		{
-			name:     "long data gets truncated",
-			data:     `{"very_long_key": "this is a very long value that should be truncated at 40 chars"}`,
-			wantFull: false,
-		},
+func TestMessageItem_Title_Timezone(t *testing.T) {
+	// Pin local time to a non-UTC zone so the two modes must differ
+	origLocal := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	defer func() { time.Local = origLocal }()
+
+	msg := &pubsub.ReceivedMessage{
+		ID:          "12345678abcd",
+		PublishTime: time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			msg := &pubsub.ReceivedMessage{
-				ID:   "test",
-				Data: []byte(tt.data),
-			}
-			item := MessageItem{message: msg}
-			desc := item.Description()
+	local := MessageItem{message: msg}.Title()
+	utc := MessageItem{message: msg, utc: true}.Title()
 
-			if tt.wantFull {
-				if desc != tt.data {
-					t.Errorf("Description() = %q, want %q", desc, tt.data)
-				}
-			} else {
-				if len(desc) > 43 { // 40 chars + "..."
-					t.Errorf("Description() length = %d, should be truncated", len(desc))
-				}
-			}
-		})
+	if !strings.Contains(local, "15:30:45") {
+		t.Errorf("local Title() = %q, want time 15:30:45", local)
+	}
+	if !strings.Contains(utc, "10:30:45") {
+		t.Errorf("UTC Title() = %q, want time 10:30:45", utc)
+	}
+	if local == utc {
+		t.Error("Title() should differ between local and UTC modes")
 	}
 }
 
-func TestMessageItem_FilterValue(t *testing.T) {
-	msg := &pubsub.ReceivedMessage{
-		ID:   "msg-123",
-		Data: []byte(`{"test": "data"}`),
-	}
+func TestModel_ToggleTimezone(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	m.AddMessage(&pubsub.ReceivedMessage{
+		ID:          "msg-1",
+		PublishTime: time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+	})
+
+	if m.IsUTC() || m.TimezoneName() != "Local" {
+		t.Error("new model should display local time")
+	}
+
+	m.ToggleTimezone()
+	if !m.IsUTC() || m.TimezoneName() != "UTC" {
+		t.Error("ToggleTimezone() should switch to UTC")
+	}
+
+	// Existing list items pick up the new zone
+	item, ok := m.messageList.Items()[0].(MessageItem)
+	if !ok || !item.utc {
+		t.Error("list items should be rebuilt in UTC mode")
+	}
+}
+
+func TestMessageItem_Title_Relative(t *testing.T) {
+	msg := &pubsub.ReceivedMessage{
+		ID:          "12345678abcd",
+		PublishTime: time.Now().Add(-90 * time.Second),
+	}
+
+	title := MessageItem{message: msg, relative: true}.Title()
+	if !strings.HasSuffix(title, " 1m") {
+		t.Errorf("relative Title() = %q, want age 1m", title)
+	}
+}
+
+func TestMessageItem_Title_Attribute(t *testing.T) {
+	msg := &pubsub.ReceivedMessage{
+		ID:          "12345678abcd",
+		PublishTime: time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+		Attributes:  map[string]string{"eventType": "order"},
+	}
+
+	tests := []struct {
+		name      string
+		attribute string
+		width     int
+		want      string
+	}{
+		{"no attribute", "", 0, "[○] 12345678 10:30:45 {1}"},
+		{"attribute shown", "eventType", 0, "[○] 12345678 10:30:45 {1} [eventType=order]"},
+		{"attribute absent", "region", 0, "[○] 12345678 10:30:45 {1}"},
+		{"fits", "eventType", 43, "[○] 12345678 10:30:45 {1} [eventType=order]"},
+		{"ID truncated first", "eventType", 39, "[○] 1234 10:30:45 {1} [eventType=order]"},
+		{"ID dropped", "eventType", 34, "[○] 10:30:45 {1} [eventType=order]"},
+		{"tag shortened", "eventType", 31, "[○] 10:30:45 {1} [eventType=o…]"},
+		{"too narrow for the tag", "eventType", 18, "[○] 10:30:45 {1}"},
+		{"badge dropped last", "eventType", 14, "[○] 10:30:45"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := MessageItem{message: msg, utc: true, attribute: tt.attribute, width: tt.width}
+			if got := item.Title(); got != tt.want {
+				t.Errorf("Title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageItem_Title_WideAttribute(t *testing.T) {
+	msg := &pubsub.ReceivedMessage{
+		ID:          "12345678abcd",
+		PublishTime: time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+		Attributes:  map[string]string{"region": "東京都"},
+	}
+
+	// Each CJK character takes two columns
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"fits", 41, "[○] 12345678 10:30:45 {1} [region=東京都]"},
+		{"ID truncated by columns", 38, "[○] 12345 10:30:45 {1} [region=東京都]"},
+		{"tag shortened by columns", 30, "[○] 10:30:45 {1} [region=東…]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := MessageItem{message: msg, utc: true, attribute: "region", width: tt.width}
+			got := item.Title()
+			if got != tt.want {
+				t.Errorf("Title() = %q, want %q", got, tt.want)
+			}
+			if w := lipgloss.Width(got); w > tt.width {
+				t.Errorf("Title() is %d columns wide, want at most %d", w, tt.width)
+			}
+		})
+	}
+}
+
+func TestMessageItem_Title_AttributeBadge(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       string
+	}{
+		{"no attributes", nil, "[○] 12345678 10:30:45"},
+		{"empty attributes", map[string]string{}, "[○] 12345678 10:30:45"},
+		{"three attributes", map[string]string{"a": "1", "b": "2", "c": "3"}, "[○] 12345678 10:30:45 {3}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &pubsub.ReceivedMessage{
+				ID:          "12345678abcd",
+				PublishTime: time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+				Attributes:  tt.attributes,
+			}
+			if got := (MessageItem{message: msg, utc: true}).Title(); got != tt.want {
+				t.Errorf("Title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_ListAttributePrompt(t *testing.T) {
+	m := New()
+	m.SetSize(200, 40)
+	m.AddMessage(&pubsub.ReceivedMessage{
+		ID:          "msg-1",
+		Data:        []byte(`{}`),
+		PublishTime: time.Now(),
+		Attributes:  map[string]string{"eventType": "order"},
+	})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	if !m.IsEditingAttribute() || !m.IsInputActive() {
+		t.Fatal("@ should open the list attribute prompt")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("eventType")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.ListAttribute() != "eventType" {
+		t.Errorf("ListAttribute() = %q, want eventType", m.ListAttribute())
+	}
+	item := m.messageList.Items()[0].(MessageItem)
+	if !strings.HasSuffix(item.Title(), " [eventType=order]") {
+		t.Errorf("Title() = %q, want the attribute tag", item.Title())
+	}
+
+	// Clearing the prompt hides the attribute
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	m.attributeInput.SetValue("")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	item = m.messageList.Items()[0].(MessageItem)
+	if strings.Contains(item.Title(), "eventType") {
+		t.Errorf("Title() = %q, want no attribute after clearing", item.Title())
+	}
+}
+
+func TestMessageItem_Description(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantFull bool
+	}{
+		{
+			name:     "short data",
+			data:     `{"key": "value"}`,
+			wantFull: true,
+		},
+		{
+			name:     "long data gets truncated",
+			data:     `{"very_long_key": "this is a very long value that should be truncated at 40 chars"}`,
+			wantFull: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &pubsub.ReceivedMessage{
+				ID:   "test",
+				Data: []byte(tt.data),
+			}
+			item := MessageItem{message: msg}
+			desc := item.Description()
+
+			if tt.wantFull {
+				if desc != tt.data {
+					t.Errorf("Description() = %q, want %q", desc, tt.data)
+				}
+			} else {
+				if len(desc) > 43 { // 40 chars + "..."
+					t.Errorf("Description() length = %d, should be truncated", len(desc))
+				}
+			}
+		})
+	}
+}
+
+func TestMessageItem_FilterValue(t *testing.T) {
+	msg := &pubsub.ReceivedMessage{
+		ID:   "msg-123",
+		Data: []byte(`{"test": "data"}`),
+	}
 	item := MessageItem{message: msg}
 
 	filterValue := item.FilterValue()
@@ -347,4 +1057,830 @@ func TestMessageItem_FilterValue(t *testing.T) {
 	}
 }
 
+func TestSchemaFor(t *testing.T) {
+	topicSchema := &pubsub.SchemaInfo{Name: "orders", Type: pubsub.SchemaTypeAvro, Encoding: pubsub.SchemaEncodingJSON}
+
+	tests := []struct {
+		name      string
+		attrs     map[string]string
+		topic     *pubsub.SchemaInfo
+		want      string
+		wantLabel string
+	}{
+		{
+			name:  "no schema",
+			attrs: map[string]string{"type": "order"},
+		},
+		{
+			name:      "topic schema",
+			topic:     topicSchema,
+			want:      "orders (Avro, JSON)",
+			wantLabel: "Data (Avro JSON):",
+		},
+		{
+			name: "message attributes override topic settings",
+			attrs: map[string]string{
+				pubsub.SchemaNameAttribute:     "projects/p/schemas/orders-v2",
+				pubsub.SchemaEncodingAttribute: "BINARY",
+			},
+			topic:     topicSchema,
+			want:      "orders-v2 (Avro, BINARY)",
+			wantLabel: "Data (binary Avro, schema definition unavailable - showing raw):",
+		},
+		{
+			name: "attributes only",
+			attrs: map[string]string{
+				pubsub.SchemaNameAttribute:     "projects/p/schemas/events",
+				pubsub.SchemaEncodingAttribute: "JSON",
+			},
+			want:      "events (schema, JSON)",
+			wantLabel: "Data (schema JSON):",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schemaFor(&pubsub.ReceivedMessage{Attributes: tt.attrs}, tt.topic)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("schemaFor() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("schemaFor() = nil, want schema")
+			}
+			if got.String() != tt.want {
+				t.Errorf("schemaFor().String() = %q, want %q", got.String(), tt.want)
+			}
+			if _, label := got.decodeData(nil, false); label != tt.wantLabel {
+				t.Errorf("decodeData() label = %q, want %q", label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestMessageSchema_DecodeData(t *testing.T) {
+	avroSchema := &pubsub.SchemaInfo{
+		Name:       "orders",
+		Type:       pubsub.SchemaTypeAvro,
+		Encoding:   pubsub.SchemaEncodingBinary,
+		Definition: `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"},{"name":"item","type":"string"}]}`,
+	}
+	protoSchema := &pubsub.SchemaInfo{
+		Name:       "orders",
+		Type:       pubsub.SchemaTypeProtobuf,
+		Encoding:   pubsub.SchemaEncodingBinary,
+		Definition: `syntax = "proto3"; message Order { int64 id = 1; string item = 2; }`,
+	}
+	avroData := []byte{0x54, 0x06, 'p', 'e', 'n'}              // id 42, item "pen"
+	protoData := []byte{0x08, 0x2a, 0x12, 0x03, 'p', 'e', 'n'} // id 42, item "pen"
+
+	tests := []struct {
+		name      string
+		topic     *pubsub.SchemaInfo
+		attrs     map[string]string
+		data      []byte
+		raw       bool
+		want      string
+		wantLabel string
+	}{
+		{
+			name:      "avro",
+			topic:     avroSchema,
+			data:      avroData,
+			want:      `{"id":42,"item":"pen"}`,
+			wantLabel: "Data (binary Avro decoded, r: raw):",
+		},
+		{
+			name:      "proto",
+			topic:     protoSchema,
+			data:      protoData,
+			want:      `{"id":42,"item":"pen"}`,
+			wantLabel: "Data (binary Proto decoded, r: raw):",
+		},
+		{
+			name:      "raw display",
+			topic:     avroSchema,
+			data:      avroData,
+			raw:       true,
+			want:      string(avroData),
+			wantLabel: "Data (binary Avro, raw, r: decoded):",
+		},
+		{
+			name:      "decoding fails",
+			topic:     avroSchema,
+			data:      avroData[:2],
+			want:      string(avroData[:2]),
+			wantLabel: "Data (binary Avro, decoding failed: item: data ends unexpectedly - showing raw):",
+		},
+		{
+			name:      "published against another schema",
+			topic:     avroSchema,
+			attrs:     map[string]string{pubsub.SchemaNameAttribute: "projects/p/schemas/payments"},
+			data:      avroData,
+			want:      string(avroData),
+			wantLabel: "Data (binary Avro, schema definition unavailable - showing raw):",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := schemaFor(&pubsub.ReceivedMessage{Attributes: tt.attrs}, tt.topic)
+			got, label := s.decodeData(tt.data, tt.raw)
+			if string(got) != tt.want {
+				t.Errorf("decodeData() = %q, want %q", got, tt.want)
+			}
+			if label != tt.wantLabel {
+				t.Errorf("decodeData() label = %q, want %q", label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestModel_SetSchema(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscription("test-sub", "orders")
+
+	m.SetSchema("other-topic", &pubsub.SchemaInfo{Name: "stale"})
+	if m.Schema() != nil {
+		t.Error("schema for a different topic should be ignored")
+	}
+
+	m.SetSchema("orders", &pubsub.SchemaInfo{Name: "orders"})
+	if m.Schema() == nil || m.Schema().Name != "orders" {
+		t.Errorf("Schema() = %v, want orders", m.Schema())
+	}
+
+	m.SetSubscription("test-sub", "orders")
+	if m.Schema() != nil {
+		t.Error("changing subscription should clear the schema")
+	}
+}
+
+func TestModel_MaxOutstandingPrompt(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetMaxOutstanding(100)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if !m.IsEditingLimit() || !m.IsInputActive() {
+		t.Fatal("m should open the max outstanding prompt")
+	}
+	if got := m.limitInput.Value(); got != "100" {
+		t.Errorf("prompt value = %q, want the current limit", got)
+	}
+
+	// Invalid values keep the prompt open
+	m.limitInput.SetValue("0")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || !m.IsEditingLimit() || m.limitError == "" {
+		t.Error("a non-positive limit should be rejected")
+	}
+
+	m.limitInput.SetValue("250")
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsEditingLimit() {
+		t.Error("Enter should close the prompt")
+	}
+	if cmd == nil {
+		t.Fatal("a new limit should return a command")
+	}
+	if got, ok := cmd().(SetMaxOutstandingMsg); !ok || got.Messages != 250 {
+		t.Errorf("command returned %#v, want SetMaxOutstandingMsg{Messages: 250}", cmd())
+	}
+
+	// Esc cancels without a change
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil || m.IsEditingLimit() {
+		t.Error("Esc should close the prompt without a command")
+	}
+}
+
+func TestModel_Diff(t *testing.T) {
+	m := New()
+	m.SetSize(120, 50)
+	m.SetSubscription("test-sub", "test-topic")
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-1", Data: []byte(`{"id":1,"type":"order"}`), PublishTime: time.Now()})
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-2", Data: []byte(`{"id":2,"type":"order"}`), PublishTime: time.Now()})
+
+	x := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}
+	d := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}
+
+	// d without a mark only warns
+	if _, cmd := m.Update(d); cmd == nil {
+		t.Error("d without a mark should warn")
+	}
+
+	m.JumpToFirst()
+	m, _ = m.Update(x)
+	if m.DiffMark() == nil || m.DiffMark().ID != "msg-1" {
+		t.Fatal("x should mark the selected message")
+	}
+
+	m.JumpToLast()
+	m, _ = m.Update(d)
+	if m.DiffMark() != nil {
+		t.Error("diffing should clear the mark")
+	}
+	view := m.detailView.View()
+	for _, want := range []string{"Diff #1 → #2", `-   "id": 1,`, `+   "id": 2,`, `"type": "order"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Disconnecting clears the mark
+	m, _ = m.Update(x)
+	m.ClearSubscription()
+	if m.DiffMark() != nil {
+		t.Error("disconnecting should clear the mark")
+	}
+}
+
+func TestModel_DetailProvenance(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("orders-sub", "orders")
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-1", Data: []byte(`{"id":1}`), PublishTime: time.Now()})
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-2", Data: []byte(`{"id":2}`), PublishTime: time.Now()})
+
+	assertProvenance := func(step, sub, topic string) {
+		t.Helper()
+		view := m.detailView.View()
+		for _, want := range []string{"Subscription: " + sub, "Topic: " + topic} {
+			if !strings.Contains(view, want) {
+				t.Errorf("%s: detail view missing %q:\n%s", step, want, view)
+			}
+		}
+	}
+	assertProvenance("selected", "orders-sub", "orders")
+
+	// Re-filtering the buffer keeps the header
+	m.filterText = "msg-2"
+	m.applyFilter()
+	assertProvenance("filtered", "orders-sub", "orders")
+
+	// Switching subscriptions shows the new source
+	m.filterText = ""
+	m.SetSubscription("billing-sub", "billing")
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-3", Data: []byte(`{"id":3}`), PublishTime: time.Now()})
+	m.JumpToLast()
+	assertProvenance("switched", "billing-sub", "billing")
+}
+
+func TestModel_AckDisplayed(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("orders-sub", "orders")
+	acks := map[string]int{}
+	for _, id := range []string{"order-1", "order-2", "refund-1"} {
+		id := id
+		m.AddMessage(pubsub.NewReceivedMessage(id, []byte(`{}`), func() { acks[id]++ }))
+	}
+	m.filterText = "order-"
+	m.applyFilter()
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	if acks["order-1"] != 1 || acks["order-2"] != 1 {
+		t.Errorf("acks = %v, want each displayed message acked once", acks)
+	}
+	if acks["refund-1"] != 0 {
+		t.Error("a message hidden by the filter should not be acked")
+	}
+	if cmd == nil {
+		t.Fatal("ack should report the acked messages")
+	}
+	reported := 0
+	var logged string
+	for _, c := range cmd().(tea.BatchMsg) {
+		switch msg := c().(type) {
+		case MessageAckedMsg:
+			reported++
+		case common.LogMsg:
+			logged = msg.Message
+		}
+	}
+	if reported != 2 || logged != "Acked 2 filtered messages" {
+		t.Errorf("reported %d acks, logged %q; want 2 and the count", reported, logged)
+	}
+
+	// Already acked messages are not acked again
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}}); cmd != nil || acks["order-1"] != 1 {
+		t.Error("acking again should do nothing")
+	}
+}
+
+func TestModel_BufferedBytes(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	receive := func(id string, size int) tea.Cmd {
+		var cmd tea.Cmd
+		m, cmd = m.Update(MessageReceivedMsg{Message: &pubsub.ReceivedMessage{ID: id, Data: make([]byte, size), PublishTime: time.Now()}})
+		return cmd
+	}
+
+	// Adds grow the total
+	for i := 0; i < 100; i++ {
+		receive(fmt.Sprintf("msg-%d", i), 10)
+	}
+	if got := m.BufferedBytes(); got != 1000 {
+		t.Fatalf("BufferedBytes() = %d after 100 adds, want 1000", got)
+	}
+	if !strings.Contains(m.View(), "1000 B buffered") {
+		t.Error("header should show the buffered bytes")
+	}
+
+	// Trimming the oldest message subtracts its data
+	receive("msg-100", 50)
+	if got := m.BufferedBytes(); got != 1040 {
+		t.Errorf("BufferedBytes() = %d after a trim, want 1040", got)
+	}
+
+	// Crossing the threshold warns once
+	cmd := receive("big-1", bufferWarnBytes)
+	if log, ok := cmd().(tea.BatchMsg)[0]().(common.LogMsg); !ok || log.Level != common.LogWarning {
+		t.Error("crossing the threshold should log a warning")
+	}
+	if cmd := receive("big-2", 10); cmd != nil {
+		t.Error("the warning should not repeat while above the threshold")
+	}
+
+	// Switching subscriptions counts the new buffer; restoring counts the old
+	m.ClearSubscription()
+	if got := m.BufferedBytes(); got != 0 {
+		t.Errorf("BufferedBytes() = %d after clearing, want 0", got)
+	}
+	m.SetSubscription("test-sub", "test-topic")
+	if got := m.BufferedBytes(); got != dataBytes(m.messages) || got <= bufferWarnBytes {
+		t.Errorf("BufferedBytes() = %d after restoring the buffer, want its data size", got)
+	}
+}
+
+func TestModel_ListNavigation(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	for i := 0; i < 50; i++ {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), PublishTime: time.Now()})
+	}
+
+	perPage := m.messageList.Paginator.PerPage
+	if perPage < 2 || perPage >= 25 {
+		t.Fatalf("PerPage = %d, want a page well inside 50 messages", perPage)
+	}
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+	selected := func() string {
+		if sel := m.SelectedMessage(); sel != nil {
+			return sel.ID
+		}
+		return ""
+	}
+
+	m, _ = m.Update(g)
+	m, _ = m.Update(g)
+	if got := selected(); got != "msg-0" || m.IsFollowing() {
+		t.Errorf("gg: selected %q (following %v), want msg-0 without following", got, m.IsFollowing())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if got, want := selected(), fmt.Sprintf("msg-%d", perPage); got != want {
+		t.Errorf("ctrl+f: selected %q, want %q", got, want)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if got := selected(); got != "msg-0" {
+		t.Errorf("ctrl+b: selected %q, want msg-0", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if got := selected(); got != "msg-49" || !m.IsFollowing() {
+		t.Errorf("G: selected %q (following %v), want msg-49 and following", got, m.IsFollowing())
+	}
+
+	// Paging up stops following; a lone g is dropped by the next key
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if got, want := selected(), fmt.Sprintf("msg-%d", 49-perPage); got != want || m.IsFollowing() {
+		t.Errorf("ctrl+b: selected %q (following %v), want %q without following", got, m.IsFollowing(), want)
+	}
+	m, _ = m.Update(g)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if got, want := selected(), fmt.Sprintf("msg-%d", 50-perPage); got != want {
+		t.Errorf("g, j: selected %q, want %q", got, want)
+	}
+}
+
+func TestModel_ListScroll(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	for i := 0; i < 50; i++ {
+		m.AddMessage(&pubsub.ReceivedMessage{ID: fmt.Sprintf("msg-%d", i), PublishTime: time.Now()})
+	}
+	perPage := m.messageList.Paginator.PerPage
+	ctrlJ := tea.KeyMsg{Type: tea.KeyCtrlJ}
+	ctrlK := tea.KeyMsg{Type: tea.KeyCtrlK}
+
+	// Scrolling up from the newest page keeps the newest message selected
+	for i := 0; i < 3; i++ {
+		m, _ = m.Update(ctrlK)
+	}
+	if !m.IsListScrolled() {
+		t.Fatal("ctrl+k should scroll the list")
+	}
+	if got := m.SelectedMessage().ID; got != "msg-49" {
+		t.Errorf("selected %q after scrolling, want msg-49", got)
+	}
+	pageStart := (49 / perPage) * perPage
+	if got, want := m.listTopIndex(), pageStart-3; got != want {
+		t.Errorf("top row = %d, want %d", got, want)
+	}
+	if view := m.scrolledListView(); !strings.Contains(view, "msg-"+fmt.Sprint(pageStart-3)) {
+		t.Errorf("scrolled view should start at the top row:\n%s", view)
+	}
+
+	// New messages keep the rows shown in place
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-50", PublishTime: time.Now()})
+	if got, want := m.messageList.Items()[m.listTopIndex()].(MessageItem).message.ID, fmt.Sprintf("msg-%d", pageStart-3); got != want {
+		t.Errorf("top row is %q after a new message, want %q", got, want)
+	}
+
+	// Scrolling stops at the ends of the list
+	for i := 0; i < 100; i++ {
+		m, _ = m.Update(ctrlK)
+	}
+	if got := m.listTopIndex(); got != 0 {
+		t.Errorf("top row = %d, want 0", got)
+	}
+	for i := 0; i < 100; i++ {
+		m, _ = m.Update(ctrlJ)
+	}
+	if got, want := m.listTopIndex(), len(m.messageList.Items())-1; got != want {
+		t.Errorf("top row = %d, want the last message at %d", got, want)
+	}
+
+	// Moving the selection shows its page again
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if m.IsListScrolled() {
+		t.Error("k should return the list to the selection")
+	}
+}
+
+func TestParseRowColors(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    RowColors
+		wantErr bool
+	}{
+		{spec: "", want: RowColors{}},
+		{spec: "off", want: RowColors{}},
+		{spec: DefaultRowColors, want: RowColors{Attribute: "severity", Colors: map[string]lipgloss.Color{
+			"error": common.ColorError,
+			"warn":  common.ColorWarning,
+		}}},
+		{spec: " level : DEBUG = #808080 , info=33 ", want: RowColors{Attribute: "level", Colors: map[string]lipgloss.Color{
+			"debug": "#808080",
+			"info":  "33",
+		}}},
+		{spec: "severity", wantErr: true},
+		{spec: ":error=red", wantErr: true},
+		{spec: "severity:error", wantErr: true},
+		{spec: "severity:error=crimson", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseRowColors(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRowColors(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRowColors(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRowColors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    RowColors
+		wantErr bool
+	}{
+		{name: "no path", path: "", want: RowColors{Attribute: "severity", Colors: map[string]lipgloss.Color{
+			"error": common.ColorError,
+			"warn":  common.ColorWarning,
+		}}},
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), want: RowColors{Attribute: "severity", Colors: map[string]lipgloss.Color{
+			"error": common.ColorError,
+			"warn":  common.ColorWarning,
+		}}},
+		{name: "file", path: write("level.json", `{"attribute": "level", "colors": {"DEBUG": "#808080", "info": "33"}}`), want: RowColors{Attribute: "level", Colors: map[string]lipgloss.Color{
+			"debug": "#808080",
+			"info":  "33",
+		}}},
+		{name: "empty file object", path: write("off.json", `{}`), want: RowColors{}},
+		{name: "no attribute", path: write("noattr.json", `{"colors": {"error": "red"}}`), wantErr: true},
+		{name: "unknown color", path: write("crimson.json", `{"attribute": "severity", "colors": {"error": "crimson"}}`), wantErr: true},
+		{name: "invalid JSON", path: write("bad.json", `{`), wantErr: true},
+	}
+
+	// t.Setenv restores the variable after the test
+	t.Setenv(RowColorsEnvVar, "")
+	os.Unsetenv(RowColorsEnvVar)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadRowColors(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadRowColors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadRowColors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// The environment overrides the file
+	t.Setenv(RowColorsEnvVar, "")
+	if got, err := LoadRowColors(filepath.Join(dir, "level.json")); err != nil || got.Attribute != "" {
+		t.Errorf("LoadRowColors() with an empty %s = %+v, %v, want no coloring", RowColorsEnvVar, got, err)
+	}
+}
+
+func TestMessageDelegate_TitleStyle(t *testing.T) {
+	colors, err := ParseRowColors("severity:error=red,warn=yellow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New()
+	m.SetRowColors(colors)
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.NormalTitle = common.NormalText
+	d := messageDelegate{delegate}
+
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       lipgloss.TerminalColor
+	}{
+		{"error", map[string]string{"severity": "error"}, common.ColorError},
+		{"value case is ignored", map[string]string{"severity": "WARN"}, common.ColorWarning},
+		{"unmapped value", map[string]string{"severity": "info"}, common.ColorText},
+		{"missing attribute", map[string]string{"type": "order"}, common.ColorText},
+		{"no attributes", nil, common.ColorText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := m.newItem(&pubsub.ReceivedMessage{ID: "msg-1", Attributes: tt.attributes, PublishTime: time.Now()})
+			if got := d.titleStyle(item).GetForeground(); got != tt.want {
+				t.Errorf("titleStyle() foreground = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageDelegate_AckedStyle(t *testing.T) {
+	colors, err := ParseRowColors("severity:error=red")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New()
+	m.SetRowColors(colors)
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.NormalTitle = common.NormalText
+	delegate.Styles.NormalDesc = common.MutedText
+	d := messageDelegate{delegate}
+
+	pending := m.newItem(&pubsub.ReceivedMessage{ID: "msg-1", PublishTime: time.Now()})
+	acked := &pubsub.ReceivedMessage{ID: "msg-2", Attributes: map[string]string{"severity": "error"}, PublishTime: time.Now()}
+	acked.SetAcked(true)
+	ackedItem := m.newItem(acked)
+
+	if got := d.titleStyle(pending).GetForeground(); got != common.ColorText {
+		t.Errorf("pending title foreground = %v, want %v", got, common.ColorText)
+	}
+	// Acked rows are dimmed even when their attribute has a row color
+	if got := d.titleStyle(ackedItem).GetForeground(); got != common.ColorTextMuted {
+		t.Errorf("acked title foreground = %v, want %v", got, common.ColorTextMuted)
+	}
+	if d.descStyle(pending).GetFaint() || !d.descStyle(ackedItem).GetFaint() {
+		t.Error("only acked descriptions should be faint")
+	}
+}
+
+func TestDumpFileName(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"1234567890", "message-1234567890-20240102-150405.json"},
+		{"a/b\\c d", "message-a_b_c_d-20240102-150405.json"},
+		{"", "message-unknown-20240102-150405.json"},
+	}
+
+	for _, tt := range tests {
+		if got := dumpFileName(tt.id, at); got != tt.want {
+			t.Errorf("dumpFileName(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestDumpContent(t *testing.T) {
+	msg := &pubsub.ReceivedMessage{
+		ID:          "42",
+		Data:        []byte(`{"order":1}`),
+		Attributes:  map[string]string{"type": "order"},
+		PublishTime: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+
+	got, err := dumpContent(msg, "orders-sub", "orders", "  ")
+	if err != nil {
+		t.Fatalf("dumpContent() error = %v", err)
+	}
+	want := `{
+  "id": "42",
+  "subscription": "orders-sub",
+  "topic": "orders",
+  "publishTime": "2024-01-02T15:04:05Z",
+  "attributes": {
+    "type": "order"
+  },
+  "data": {
+    "order": 1
+  }
+}
+`
+	if string(got) != want {
+		t.Errorf("dumpContent() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Non-JSON data is kept as a string
+	msg.Data = []byte("plain text")
+	got, _ = dumpContent(msg, "orders-sub", "orders", "  ")
+	if !strings.Contains(string(got), `"data": "plain text"`) {
+		t.Errorf("dumpContent() with text data =\n%s", got)
+	}
+
+	// Data truncated on receive records the full size
+	msg.DataSize = 1000
+	got, _ = dumpContent(msg, "orders-sub", "orders", "  ")
+	if !strings.Contains(string(got), `"dataSize": 1000`) {
+		t.Errorf("dumpContent() with truncated data =\n%s", got)
+	}
+}
+
+func TestModel_DetailTruncatedData(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-1", Data: []byte(`{"id":`), DataSize: 5000, PublishTime: time.Now()})
+	m.JumpToLast()
+	if view := m.detailView.View(); !strings.Contains(view, "(truncated, 5000 bytes total)") {
+		t.Errorf("detail view should note the truncation:\n%s", view)
+	}
+
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-2", Data: []byte(`{"id":2}`), DataSize: 8, PublishTime: time.Now()})
+	m.JumpToLast()
+	if view := m.detailView.View(); strings.Contains(view, "truncated") {
+		t.Errorf("complete data should not be noted as truncated:\n%s", view)
+	}
+}
+
+func TestModel_RepublishTruncated(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("test-sub", "test-topic")
+	p := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}
+
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-1", Data: []byte(`{"id":`), DataSize: 5000, PublishTime: time.Now()})
+	m.JumpToLast()
+	_, cmd := m.Update(p)
+	log, ok := cmd().(common.LogMsg)
+	if !ok || log.Level != common.LogError || !strings.Contains(log.Message, "truncated to 6 of 5000 bytes") {
+		t.Errorf("republishing a truncated message sent %#v, want it refused", cmd())
+	}
+
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "msg-2", Data: []byte(`{"id":2}`), DataSize: 8, PublishTime: time.Now()})
+	m.JumpToLast()
+	_, cmd = m.Update(p)
+	if req, ok := cmd().(RepublishRequestMsg); !ok || req.Message.ID != "msg-2" {
+		t.Errorf("republishing a complete message sent %#v, want RepublishRequestMsg", cmd())
+	}
+}
+
+func TestModel_DumpNoSelection(t *testing.T) {
+	m := New()
+	cmd := m.dumpSelected()
+	if log, ok := cmd().(common.LogMsg); !ok || log.Level != common.LogWarning {
+		t.Errorf("dumpSelected() with no selection = %#v, want a warning", cmd())
+	}
+}
+
+func TestWithinAgeWindow(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		publish time.Time
+		window  time.Duration
+		want    bool
+	}{
+		{"no window", now.Add(-time.Hour), 0, true},
+		{"inside", now.Add(-10 * time.Second), 30 * time.Second, true},
+		{"exactly at the window", now.Add(-30 * time.Second), 30 * time.Second, true},
+		{"just past the window", now.Add(-30*time.Second - time.Nanosecond), 30 * time.Second, false},
+		{"published in the future", now.Add(time.Second), 30 * time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinAgeWindow(tt.publish, now, tt.window); got != tt.want {
+				t.Errorf("withinAgeWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAgeWindow(t *testing.T) {
+	for input, want := range map[string]time.Duration{"30s": 30 * time.Second, " 5m ": 5 * time.Minute, "": 0, "0": 0} {
+		if got, err := parseAgeWindow(input); err != nil || got != want {
+			t.Errorf("parseAgeWindow(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"30", "soon", "-5s"} {
+		if _, err := parseAgeWindow(input); err == nil {
+			t.Errorf("parseAgeWindow(%q) should fail", input)
+		}
+	}
+}
+
+func TestModel_AgeWindow(t *testing.T) {
+	m := New()
+	m.SetSize(120, 40)
+	m.SetSubscription("orders-sub", "orders")
+	now := time.Now()
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "order-old", PublishTime: now.Add(-time.Hour)})
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "order-new", PublishTime: now})
+	m.AddMessage(&pubsub.ReceivedMessage{ID: "refund-new", PublishTime: now})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	if !m.IsEditingAgeWindow() || !m.IsInputActive() {
+		t.Fatal("W should open the age window prompt")
+	}
+
+	// Invalid values keep the prompt open
+	m.ageInput.SetValue("soon")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.IsEditingAgeWindow() || m.ageError == "" {
+		t.Error("an invalid duration should be rejected")
+	}
+
+	m.ageInput.SetValue("1m")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsEditingAgeWindow() || m.AgeWindow() != time.Minute {
+		t.Fatalf("age window = %v, want 1m", m.AgeWindow())
+	}
+	if cmd == nil {
+		t.Error("an age window should start the refresh tick")
+	}
+	if got := m.DisplayedCount(); got != 2 {
+		t.Errorf("DisplayedCount() = %d, want the 2 recent messages", got)
+	}
 
+	// The window composes with the regex filter
+	m.filterText = "order-"
+	m.applyFilter()
+	if got := m.DisplayedCount(); got != 1 {
+		t.Errorf("DisplayedCount() = %d, want only the recent order", got)
+	}
+
+	// Messages drop out on the refresh tick once they age out
+	m.filterText = ""
+	m.applyFilter()
+	m.messages[2].PublishTime = now.Add(-2 * time.Minute)
+	m, _ = m.Update(AgeTickMsg{})
+	if got := m.DisplayedCount(); got != 1 {
+		t.Errorf("DisplayedCount() = %d after the tick, want 1", got)
+	}
+
+	// An empty window shows every message again
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	m.ageInput.SetValue("")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.DisplayedCount(); got != 3 {
+		t.Errorf("DisplayedCount() = %d after clearing, want 3", got)
+	}
+}
@@ -0,0 +1,167 @@
+package subscriber
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RowColorsEnvVar overrides the row colors of the config file, as
+// "attribute:value=color,value=color". Set it to "off" to disable.
+const RowColorsEnvVar = "PUBSUB_TUI_ROW_COLORS"
+
+// DefaultRowColors is the row coloring used when neither the config file
+// nor RowColorsEnvVar sets one
+const DefaultRowColors = "severity:error=red,warn=yellow"
+
+// rowColorNames maps color names to the palette; other colors are given as
+// "#rrggbb" or an ANSI number
+var rowColorNames = map[string]lipgloss.Color{
+	"red":    common.ColorError,
+	"yellow": common.ColorWarning,
+	"green":  common.ColorSuccess,
+	"blue":   common.ColorInfo,
+	"cyan":   common.ColorNetwork,
+	"purple": common.ColorSecondary,
+	"gray":   common.ColorTextMuted,
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+var ansiColor = regexp.MustCompile(`^[0-9]{1,3}$`)
+
+// RowColors colors subscriber rows by the value of one message attribute.
+// The zero value colors nothing.
+type RowColors struct {
+	Attribute string
+	Colors    map[string]lipgloss.Color // Lower-cased attribute value -> color
+}
+
+// ParseRowColors parses "attribute:value=color,value=color". Values match
+// case-insensitively. An empty spec or "off" colors nothing.
+func ParseRowColors(spec string) (RowColors, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "off" {
+		return RowColors{}, nil
+	}
+
+	attribute, mapping, ok := strings.Cut(spec, ":")
+	attribute = strings.TrimSpace(attribute)
+	if !ok || attribute == "" {
+		return RowColors{}, fmt.Errorf("%s: want attribute:value=color,..., got %q", RowColorsEnvVar, spec)
+	}
+
+	colors := make(map[string]lipgloss.Color)
+	for _, pair := range strings.Split(mapping, ",") {
+		value, color, ok := strings.Cut(pair, "=")
+		value = strings.ToLower(strings.TrimSpace(value))
+		color = strings.TrimSpace(color)
+		if !ok || value == "" {
+			return RowColors{}, fmt.Errorf("%s: want value=color, got %q", RowColorsEnvVar, pair)
+		}
+		c, err := parseRowColor(color)
+		if err != nil {
+			return RowColors{}, fmt.Errorf("%s: %w", RowColorsEnvVar, err)
+		}
+		colors[value] = c
+	}
+	return RowColors{Attribute: attribute, Colors: colors}, nil
+}
+
+// parseRowColor parses a color name, "#rrggbb" or an ANSI color number
+func parseRowColor(color string) (lipgloss.Color, error) {
+	if c, ok := rowColorNames[strings.ToLower(color)]; ok {
+		return c, nil
+	}
+	if hexColor.MatchString(color) || ansiColor.MatchString(color) {
+		return lipgloss.Color(color), nil
+	}
+	return "", fmt.Errorf("unknown color %q (use a name, #rrggbb or an ANSI number)", color)
+}
+
+// rowColorsFile is the config file format: the attribute and a map of its
+// values to colors, e.g. {"attribute": "severity", "colors": {"error": "red"}}
+type rowColorsFile struct {
+	Attribute string            `json:"attribute"`
+	Colors    map[string]string `json:"colors"`
+}
+
+// RowColorsPath returns the row color config path, pubsub-tui/colors.json in
+// the user config directory next to keys.json, or "" when no config
+// directory exists
+func RowColorsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pubsub-tui", "colors.json")
+}
+
+// LoadRowColors returns the row coloring configured by PUBSUB_TUI_ROW_COLORS,
+// or else by the config file at path. A missing file or empty path uses
+// DefaultRowColors; a file without an attribute colors nothing.
+func LoadRowColors(path string) (RowColors, error) {
+	if spec, ok := os.LookupEnv(RowColorsEnvVar); ok {
+		return ParseRowColors(spec)
+	}
+	if path == "" {
+		return ParseRowColors(DefaultRowColors)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ParseRowColors(DefaultRowColors)
+	}
+	if err != nil {
+		return RowColors{}, fmt.Errorf("failed to read row colors: %w", err)
+	}
+
+	var file rowColorsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return RowColors{}, fmt.Errorf("failed to parse row colors %s: %w", path, err)
+	}
+	attribute := strings.TrimSpace(file.Attribute)
+	if attribute == "" {
+		if len(file.Colors) > 0 {
+			return RowColors{}, fmt.Errorf("%s: colors need an attribute", path)
+		}
+		return RowColors{}, nil
+	}
+
+	colors := make(map[string]lipgloss.Color, len(file.Colors))
+	for value, color := range file.Colors {
+		c, err := parseRowColor(strings.TrimSpace(color))
+		if err != nil {
+			return RowColors{}, fmt.Errorf("%s: %w", path, err)
+		}
+		colors[strings.ToLower(strings.TrimSpace(value))] = c
+	}
+	return RowColors{Attribute: attribute, Colors: colors}, nil
+}
+
+// Color returns the color for a message's row, and false when the message
+// lacks the attribute or its value has no color
+func (c RowColors) Color(msg *pubsub.ReceivedMessage) (lipgloss.Color, bool) {
+	if c.Attribute == "" || msg == nil {
+		return "", false
+	}
+	value, ok := msg.Attributes[c.Attribute]
+	if !ok {
+		return "", false
+	}
+	color, ok := c.Colors[strings.ToLower(value)]
+	return color, ok
+}
+
+// SetRowColors sets how rows are colored by attribute value
+func (m *Model) SetRowColors(colors RowColors) {
+	m.rowColors = colors
+	m.applyFilter()
+}
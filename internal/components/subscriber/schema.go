@@ -0,0 +1,107 @@
+package subscriber
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/utils"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// TopicSchemaMsg carries the schema attached to the subscribed topic
+type TopicSchemaMsg struct {
+	TopicName string
+	Schema    *pubsub.SchemaInfo // nil if the topic has no schema
+	Err       error
+}
+
+// SetSchema sets the schema of the subscribed topic. Schemas for a topic
+// other than the current one are ignored, as the user may have switched.
+func (m *Model) SetSchema(topicName string, schema *pubsub.SchemaInfo) {
+	if topicName != m.topicName {
+		return
+	}
+	m.schema = schema
+	m.updateDetailView()
+}
+
+// Schema returns the schema of the subscribed topic, or nil
+func (m Model) Schema() *pubsub.SchemaInfo {
+	return m.schema
+}
+
+// messageSchema describes how a message was encoded against a schema
+type messageSchema struct {
+	name       string
+	kind       string // "Avro", "Proto" or "schema" if the type is unknown
+	encoding   string
+	definition string // Schema source used to decode binary data, if known
+}
+
+// schemaFor returns the schema encoding of msg, preferring the attributes
+// Pub/Sub adds to each message over the topic's current settings.
+// Returns nil if the message was not published against a schema.
+func schemaFor(msg *pubsub.ReceivedMessage, topic *pubsub.SchemaInfo) *messageSchema {
+	s := &messageSchema{kind: "schema"}
+	if topic != nil {
+		s.name = topic.Name
+		s.encoding = topic.Encoding
+		s.definition = topic.Definition
+		switch topic.Type {
+		case pubsub.SchemaTypeAvro:
+			s.kind = "Avro"
+		case pubsub.SchemaTypeProtobuf:
+			s.kind = "Proto"
+		}
+	}
+	if name := msg.Attributes[pubsub.SchemaNameAttribute]; name != "" {
+		name = name[strings.LastIndex(name, "/")+1:]
+		if name != s.name {
+			// Published against another schema, whose definition is unknown
+			s.definition = ""
+		}
+		s.name = name
+	}
+	if enc := msg.Attributes[pubsub.SchemaEncodingAttribute]; enc != "" {
+		s.encoding = strings.ToUpper(enc)
+	}
+
+	if s.encoding == "" {
+		return nil
+	}
+	return s
+}
+
+// String describes the schema for the detail view, e.g. "orders (Avro, JSON)"
+func (s *messageSchema) String() string {
+	return fmt.Sprintf("%s (%s, %s)", s.name, s.kind, s.encoding)
+}
+
+// decodeData returns the message data to display and its label. Binary
+// data is decoded to JSON with the schema definition unless raw is set;
+// when that is not possible it is shown raw and the label says why.
+func (s *messageSchema) decodeData(data []byte, raw bool) ([]byte, string) {
+	if s.encoding != pubsub.SchemaEncodingBinary {
+		return data, fmt.Sprintf("Data (%s JSON):", s.kind)
+	}
+	if raw {
+		return data, fmt.Sprintf("Data (binary %s, raw, r: decoded):", s.kind)
+	}
+
+	var decoded []byte
+	var err error
+	switch {
+	case s.definition == "":
+		return data, fmt.Sprintf("Data (binary %s, schema definition unavailable - showing raw):", s.kind)
+	case s.kind == "Avro":
+		decoded, err = utils.DecodeAvro(s.definition, data)
+	case s.kind == "Proto":
+		decoded, err = utils.DecodeProto(s.definition, data)
+	default:
+		return data, fmt.Sprintf("Data (binary %s, unknown schema type - showing raw):", s.kind)
+	}
+	if err != nil {
+		return data, fmt.Sprintf("Data (binary %s, decoding failed: %v - showing raw):", s.kind, err)
+	}
+	return decoded, fmt.Sprintf("Data (binary %s decoded, r: raw):", s.kind)
+}
@@ -1,8 +1,11 @@
 package subscriber
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/anmaso/pubsub-tui/internal/components/common"
-	"github.com/anmaso/pubsub-tui/internal/pubsub"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -27,6 +30,50 @@ type StartSubscriptionMsg struct {
 // StopSubscriptionMsg requests to stop the current subscription
 type StopSubscriptionMsg struct{}
 
+// RepublishRequestMsg requests that a received message be published again
+// to the currently selected topic
+type RepublishRequestMsg struct {
+	Message *pubsub.ReceivedMessage
+}
+
+// MessageAckedMsg is sent when a message has been acknowledged, either
+// manually or by auto-ack
+type MessageAckedMsg struct {
+	SubscriptionName string
+	MessageID        string
+}
+
+// ackedCmd reports an acknowledged message on the current subscription
+func (m Model) ackedCmd(id string) tea.Cmd {
+	sub := m.subscriptionName
+	return func() tea.Msg {
+		return MessageAckedMsg{SubscriptionName: sub, MessageID: id}
+	}
+}
+
+// AgeTickMsg refreshes message ages while relative time or an age window is
+// shown
+type AgeTickMsg struct{}
+
+// ageTickInterval is how often message ages are refreshed
+const ageTickInterval = time.Second
+
+// ageTick returns a command that requests the next age refresh
+func ageTick() tea.Cmd {
+	return tea.Tick(ageTickInterval, func(time.Time) tea.Msg {
+		return AgeTickMsg{}
+	})
+}
+
+// startAgeTick starts refreshing message ages if needed and not already running
+func (m *Model) startAgeTick() tea.Cmd {
+	if (!m.relative && m.ageWindow == 0) || !m.connected || m.ageTicking {
+		return nil
+	}
+	m.ageTicking = true
+	return ageTick()
+}
+
 // Update handles messages for the subscriber panel
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -36,25 +83,84 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if m.filtering {
 			return m.handleFilterInput(msg)
 		}
+		if m.editingLimit {
+			return m.handleLimitInput(msg)
+		}
+		if m.editingAttribute {
+			return m.handleAttributeInput(msg)
+		}
+		if m.editingAge {
+			return m.handleAgeWindowInput(msg)
+		}
 		return m.handleNavigation(msg)
 
+	case common.DebounceMsg:
+		if m.debounce.Due(msg) {
+			m.applyFilter()
+		}
+		return m, nil
+
 	case MessageReceivedMsg:
 		m.AddMessage(msg.Message)
-		return m, nil
+		cmd := m.scheduleAck(msg.Message)
+		if msg.Message.IsAcked() {
+			cmd = m.ackedCmd(msg.Message.ID)
+		}
+		if warn := m.checkBufferSize(); warn != nil {
+			return m, tea.Batch(warn, cmd)
+		}
+		return m, cmd
+
+	case DelayedAckMsg:
+		return m.handleDelayedAck(msg)
 
 	case SubscriptionErrorMsg:
 		return m, func() tea.Msg {
-			return common.Error("Subscription error: " + msg.Error.Error())
+			return common.ErrorLog("Subscription error", msg.Error)
+		}
+
+	case AgeTickMsg:
+		// Ages are computed at render time; keep ticking while they are shown
+		m.ageTicking = false
+		if m.ageWindow > 0 {
+			// Drop messages that have aged out of the window
+			m.applyFilter()
+			m.reselect()
 		}
+		return m, m.startAgeTick()
 
 	case common.SubscriptionSelectedMsg:
 		m.SetSubscription(msg.SubscriptionName, msg.TopicName)
 		// Start the spinner
-		return m, m.spinner.Tick
+		return m, tea.Batch(m.spinner.Tick, m.startAgeTick())
+
+	case TopicSchemaMsg:
+		m.SetSchema(msg.TopicName, msg.Schema)
+		if msg.Err != nil {
+			return m, func() tea.Msg {
+				return common.Warning(fmt.Sprintf("Could not fetch schema for topic %s: %v", msg.TopicName, msg.Err))
+			}
+		}
+		if msg.Schema != nil && msg.TopicName == m.topicName {
+			return m, func() tea.Msg {
+				return common.Info(fmt.Sprintf("Topic %s uses schema %s", msg.TopicName, msg.Schema.Name))
+			}
+		}
+		return m, nil
 
 	case common.SubscriptionStoppedMsg:
 		m.ClearSubscription()
 		return m, nil
+
+	case MessageDumpedMsg:
+		if msg.Err != nil {
+			return m, func() tea.Msg {
+				return common.Error("Write failed: " + msg.Err.Error())
+			}
+		}
+		return m, func() tea.Msg {
+			return common.Success(fmt.Sprintf("Wrote message %s to %s", truncateID(msg.MessageID), msg.Path))
+		}
 	}
 
 	// Pass other messages to sub-components
@@ -83,30 +189,71 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // handleFilterInput handles keyboard input in filter mode
 func (m Model) handleFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.regexHelp.HandleKey(msg, m.filterInput.Value()) {
+		return m, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.filtering = false
 		m.filterText = ""
 		m.filterInput.SetValue("")
 		m.filterError = nil
+		m.filterHistory.Reset()
 		m.applyFilter()
 		return m, nil
 
 	case tea.KeyEnter:
+		// Apply now in case a debounced pass is still pending
 		m.filtering = false
+		m.filterHistory.Add(m.filterText)
+		m.debounce.Cancel()
+		m.applyFilter()
+		return m, nil
+
+	case tea.KeyUp:
+		// Recall an older filter pattern
+		if pattern, ok := m.filterHistory.Prev(m.filterInput.Value()); ok {
+			m.recallFilter(pattern)
+		}
+		return m, nil
+
+	case tea.KeyDown:
+		// Recall a newer filter pattern, or the pattern being typed
+		if pattern, ok := m.filterHistory.Next(); ok {
+			m.recallFilter(pattern)
+		}
 		return m, nil
 
 	default:
 		var cmd tea.Cmd
 		m.filterInput, cmd = m.filterInput.Update(msg)
-		m.filterText = m.filterInput.Value()
-		m.applyFilter()
+		// Cursor movement doesn't change the pattern; skip re-filtering.
+		// Otherwise wait for typing to pause before filtering.
+		if value := m.filterInput.Value(); value != m.filterText {
+			m.filterText = value
+			return m, tea.Batch(cmd, m.debounce.Schedule())
+		}
 		return m, cmd
 	}
 }
 
+// recallFilter replaces the filter input with a pattern from history
+func (m *Model) recallFilter(pattern string) {
+	m.filterInput.SetValue(pattern)
+	m.filterInput.CursorEnd()
+	m.filterText = pattern
+	m.applyFilter()
+}
+
 // handleNavigation handles keyboard input in normal mode
 func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// A pending g completes gg or is dropped
+	if m.pendingG.Complete(msg, keys.First) {
+		m.JumpToFirst()
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, keys.Stop):
 		// Stop active subscription
@@ -122,32 +269,105 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.filterInput.Focus()
 		return m, nil
 
+	case key.Matches(msg, keys.MaxOutstanding):
+		m.StartLimitEdit()
+		return m, nil
+
+	case key.Matches(msg, keys.ListAttribute):
+		m.StartAttributeEdit()
+		return m, nil
+
+	case key.Matches(msg, keys.AgeWindow):
+		m.StartAgeWindowEdit()
+		return m, nil
+
+	case key.Matches(msg, keys.MarkDiff):
+		return m, m.markForDiff()
+
+	case key.Matches(msg, keys.Diff):
+		return m, m.showDiff()
+
 	case key.Matches(msg, keys.Ack):
-		if m.AckSelected() {
-			msg := m.SelectedMessage()
-			if msg != nil {
-				// Move to next message after acknowledging
-				m.messageList.CursorDown()
-				m.UpdateSelection()
-				msgID := msg.ID
-				return m, func() tea.Msg {
-					return common.Info("Acknowledged message: " + truncateID(msgID))
-				}
+		return m.ackSelected(true)
+
+	case key.Matches(msg, keys.AckStay):
+		return m.ackSelected(false)
+
+	case key.Matches(msg, keys.AckDisplayed):
+		return m.ackDisplayed()
+
+	case key.Matches(msg, keys.Nack):
+		return m.nackSelected()
+
+	case key.Matches(msg, keys.Republish):
+		selected := m.SelectedMessage()
+		if selected == nil {
+			return m, nil
+		}
+		if selected.Truncated() {
+			// Republishing the kept prefix would publish a corrupt message
+			return m, func() tea.Msg {
+				return common.Error(fmt.Sprintf("Republish refused: payload truncated to %d of %d bytes; raise PUBSUB_TUI_MAX_MESSAGE_BYTES to republish",
+					len(selected.Data), selected.DataSize))
 			}
 		}
-		return m, nil
+		return m, func() tea.Msg {
+			return RepublishRequestMsg{Message: selected}
+		}
 
-	case key.Matches(msg, keys.AutoAck):
-		m.ToggleAutoAck()
-		status := "disabled"
-		if m.autoAck {
-			status = "enabled"
+	case key.Matches(msg, keys.Dump):
+		return m, m.dumpSelected()
+
+	case key.Matches(msg, keys.AckMode):
+		return m.cycleAckMode()
+
+	case key.Matches(msg, keys.Timezone):
+		m.ToggleTimezone()
+		zone := m.TimezoneName()
+		return m, func() tea.Msg {
+			return common.Info("Showing times in " + zone)
+		}
+
+	case key.Matches(msg, keys.Raw):
+		m.ToggleRaw()
+		mode := "decoded"
+		if m.showRaw {
+			mode = "raw"
 		}
 		return m, func() tea.Msg {
-			return common.Info("Auto-ack " + status)
+			return common.Info("Showing " + mode + " message data")
+		}
+
+	case key.Matches(msg, keys.RelativeTime):
+		m.ToggleRelativeTime()
+		mode := "publish times"
+		if m.relative {
+			mode = "message ages"
+		}
+		return m, tea.Batch(m.startAgeTick(), func() tea.Msg {
+			return common.Info("Showing " + mode)
+		})
+
+	case key.Matches(msg, keys.First):
+		if msg.String() == "g" {
+			// The first g waits for a second
+			m.pendingG.Press()
+			return m, nil
 		}
+		m.JumpToFirst()
+		return m, nil
+
+	case key.Matches(msg, keys.Last):
+		m.JumpToLast()
+		return m, nil
+
+	case key.Matches(msg, keys.Follow):
+		m.ToggleFollow()
+		return m, nil
 
 	case key.Matches(msg, keys.Up):
+		// Reading older messages; don't jump away on new arrivals
+		m.follow = false
 		m.messageList.CursorUp()
 		m.UpdateSelection()
 		return m, nil
@@ -157,6 +377,14 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.UpdateSelection()
 		return m, nil
 
+	case key.Matches(msg, keys.ListScrollUp):
+		m.ScrollList(-1)
+		return m, nil
+
+	case key.Matches(msg, keys.ListScrollDown):
+		m.ScrollList(1)
+		return m, nil
+
 	case key.Matches(msg, keys.ScrollUp):
 		m.detailView.LineUp(3)
 		return m, nil
@@ -165,6 +393,17 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.detailView.LineDown(3)
 		return m, nil
 
+	case key.Matches(msg, keys.PageDown):
+		common.PageList(&m.messageList, 1)
+		m.UpdateSelection()
+		return m, nil
+
+	case key.Matches(msg, keys.PageUp):
+		m.follow = false
+		common.PageList(&m.messageList, -1)
+		m.UpdateSelection()
+		return m, nil
+
 	default:
 		var cmd tea.Cmd
 		m.messageList, cmd = m.messageList.Update(msg)
@@ -175,49 +414,232 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 // Key bindings
 type keyMap struct {
-	Stop       key.Binding
-	Filter     key.Binding
-	Ack        key.Binding
-	AutoAck    key.Binding
-	Up         key.Binding
-	Down       key.Binding
-	ScrollUp   key.Binding
-	ScrollDown key.Binding
-}
-
-var keys = keyMap{
-	Stop: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "stop"),
-	),
-	Filter: key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "filter"),
-	),
-	Ack: key.NewBinding(
-		key.WithKeys("a"),
-		key.WithHelp("a", "ack"),
-	),
-	AutoAck: key.NewBinding(
-		key.WithKeys("A"),
-		key.WithHelp("A", "toggle auto-ack"),
-	),
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
-	ScrollUp: key.NewBinding(
-		key.WithKeys("ctrl+u"),
-		key.WithHelp("ctrl+u", "scroll detail up"),
-	),
-	ScrollDown: key.NewBinding(
-		key.WithKeys("ctrl+d"),
-		key.WithHelp("ctrl+d", "scroll detail down"),
-	),
+	Stop           key.Binding
+	Filter         key.Binding
+	Ack            key.Binding
+	AckStay        key.Binding
+	AckDisplayed   key.Binding
+	Nack           key.Binding
+	AckMode        key.Binding
+	Republish      key.Binding
+	Dump           key.Binding
+	Timezone       key.Binding
+	RelativeTime   key.Binding
+	First          key.Binding
+	Last           key.Binding
+	Follow         key.Binding
+	Raw            key.Binding
+	MaxOutstanding key.Binding
+	ListAttribute  key.Binding
+	AgeWindow      key.Binding
+	MarkDiff       key.Binding
+	Diff           key.Binding
+	Up             key.Binding
+	Down           key.Binding
+	ListScrollUp   key.Binding
+	ListScrollDown key.Binding
+	ScrollUp       key.Binding
+	ScrollDown     key.Binding
+	PageDown       key.Binding
+	PageUp         key.Binding
+}
+
+// defaultKeys returns the built-in key bindings
+func defaultKeys() keyMap {
+	return keyMap{
+		Stop: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "Stop the subscription"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "Filter messages by regex"),
+		),
+		Ack: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "Acknowledge selected message (moves to next)"),
+		),
+		AckStay: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "Acknowledge selected message (stays on it)"),
+		),
+		AckDisplayed: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "Acknowledge all displayed (filtered) messages"),
+		),
+		Nack: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "Nack selected message (Pub/Sub redelivers it)"),
+		),
+		AckMode: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "Cycle ack mode: manual, on receive, after delay"),
+		),
+		Republish: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "Republish selected message to the selected topic"),
+		),
+		Dump: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "Write selected message to message-<id>-<ts>.json"),
+		),
+		Timezone: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "Toggle timestamps between local time and UTC"),
+		),
+		RelativeTime: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "Toggle publish time / relative age (e.g. 45s, 2m)"),
+		),
+		First: key.NewBinding(
+			key.WithKeys("g", "home"),
+			key.WithHelp("gg/home", "Jump to oldest (pauses following)"),
+		),
+		Last: key.NewBinding(
+			key.WithKeys("G", "end"),
+			key.WithHelp("G/end", "Jump to newest (follows new messages)"),
+		),
+		Follow: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "Toggle follow (moving up pauses following)"),
+		),
+		Raw: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "Toggle raw / decoded (gzip, base64) message data"),
+		),
+		MaxOutstanding: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "Set max outstanding messages (restarts the stream)"),
+		),
+		ListAttribute: key.NewBinding(
+			key.WithKeys("@"),
+			key.WithHelp("@", "Show an attribute in list rows, e.g. eventType"),
+		),
+		AgeWindow: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "Show only messages from the last N (e.g. 30s)"),
+		),
+		MarkDiff: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "Mark selected message for diff (x again unmarks)"),
+		),
+		Diff: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "Diff the marked message against the selected one"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "Move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "Move down"),
+		),
+		ListScrollUp: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "Scroll message list up (keeps the selection)"),
+		),
+		ListScrollDown: key.NewBinding(
+			key.WithKeys("ctrl+j"),
+			key.WithHelp("ctrl+j", "Scroll message list down (keeps the selection)"),
+		),
+		ScrollUp: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("ctrl+u", "Scroll message detail up"),
+		),
+		ScrollDown: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "Scroll message detail down"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "Page down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("ctrl+b"),
+			key.WithHelp("ctrl+b", "Page up (pauses following)"),
+		),
+	}
+}
+
+// keys are the active key bindings: the defaults with any overrides from the
+// key config file
+var keys = defaultKeys()
+
+// SetKeyOverrides replaces the panel's key bindings with the defaults plus
+// overrides, keyed by action name (see OverrideKeys). It returns the actions
+// that match no binding; on error the bindings are unchanged.
+func SetKeyOverrides(overrides map[string][]string) ([]string, error) {
+	km := defaultKeys()
+	unknown, err := common.OverrideKeys(&km, overrides)
+	if err != nil {
+		return nil, err
+	}
+	keys = km
+	return unknown, nil
+}
+
+// Binding returns the panel's key binding for an action name, as used in
+// the key config file
+func Binding(name string) (key.Binding, bool) {
+	return common.FindKey(keys, name)
+}
+
+// ackSelected acknowledges the selected message, then moves to the next
+// message if advance is set
+func (m Model) ackSelected(advance bool) (Model, tea.Cmd) {
+	selected := m.SelectedMessage()
+	if selected == nil || !m.AckSelected() {
+		return m, nil
+	}
+
+	if advance {
+		m.messageList.CursorDown()
+		m.UpdateSelection()
+	}
+	msgID := selected.ID
+	m.flashAck("Acked " + truncateID(msgID) + " ✓")
+	return m, tea.Batch(
+		m.ackedCmd(msgID),
+		func() tea.Msg {
+			return common.Info("Acknowledged message: " + truncateID(msgID))
+		},
+	)
+}
+
+// ackDisplayed acknowledges the messages matching the filter and reports
+// each one, like single acks
+func (m Model) ackDisplayed() (Model, tea.Cmd) {
+	acked := m.AckDisplayed()
+	if len(acked) == 0 {
+		return m, nil
+	}
+
+	cmds := make([]tea.Cmd, 0, len(acked)+1)
+	for _, msg := range acked {
+		cmds = append(cmds, m.ackedCmd(msg.ID))
+	}
+	count := len(acked)
+	m.flashAck(fmt.Sprintf("Acked %d ✓", count))
+	cmds = append(cmds, func() tea.Msg {
+		return common.Info(fmt.Sprintf("Acked %d filtered messages", count))
+	})
+	return m, tea.Batch(cmds...)
+}
+
+// nackSelected hands the selected message back to Pub/Sub for redelivery.
+// It stays listed, marked as nacked, and can no longer be acked.
+func (m Model) nackSelected() (Model, tea.Cmd) {
+	selected := m.SelectedMessage()
+	if selected == nil || !m.NackSelected() {
+		return m, nil
+	}
+
+	msgID := selected.ID
+	m.flashAck("Nacked " + truncateID(msgID) + " ✓")
+	return m, func() tea.Msg {
+		return common.Info("Nacked message: " + truncateID(msgID) + " (it will be redelivered)")
+	}
 }
 
 // truncateID safely truncates a message ID for display
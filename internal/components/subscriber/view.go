@@ -6,6 +6,7 @@ import (
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -38,22 +39,60 @@ func (m Model) View() string {
 		rightWidth = 15
 	}
 
-	// Build header line with auto-ack status and spinner
+	// Build header line with the ack mode and spinner
 	var header strings.Builder
-	autoAckStatus := "[ ] auto-ack"
-	if m.autoAck {
-		autoAckStatus = "[✓] auto-ack"
+	header.WriteString(common.MutedText.Render("ack: " + m.ackMode.String() + " (A)"))
+	header.WriteString(common.MutedText.Render("  " + m.TimezoneName() + " time (z)"))
+
+	// Follow mode: whether new messages move the selection
+	header.WriteString("  ")
+	if m.follow {
+		header.WriteString(common.LogSuccessStyle.Render("FOLLOW"))
+	} else {
+		header.WriteString(common.LogWarningStyle.Render("PAUSED"))
+	}
+	header.WriteString(common.MutedText.Render(" (F)"))
+
+	if m.maxOutstanding > 0 {
+		header.WriteString(common.MutedText.Render(fmt.Sprintf("  max %d (m)", m.maxOutstanding)))
+	}
+
+	if m.ageWindow > 0 {
+		header.WriteString(common.LogWarningStyle.Render("  last " + m.ageWindow.String()))
+		header.WriteString(common.MutedText.Render(" (W)"))
+	}
+
+	// Memory held by the message buffer
+	if m.bufferedBytes > 0 {
+		style := common.MutedText
+		if m.bufferedBytes > bufferWarnBytes {
+			style = common.LogWarningStyle
+		}
+		header.WriteString(style.Render("  " + common.FormatSize(m.bufferedBytes) + " buffered"))
 	}
-	header.WriteString(common.MutedText.Render(autoAckStatus + " (A)"))
 
 	// Add spinner when connected
-	if m.connected {
+	if m.connected && m.streamError != nil {
+		header.WriteString("  ")
+		header.WriteString(common.LogErrorStyle.Render("✗ disconnected"))
+	} else if m.connected {
 		header.WriteString("  ")
 		header.WriteString(m.spinner.View())
 		header.WriteString(" ")
 		header.WriteString(common.LogNetworkStyle.Render("listening"))
 	}
 
+	if m.diffMark != nil {
+		header.WriteString("  ")
+		header.WriteString(common.LogWarningStyle.Render("diff " + messageLabel(m.diffMark) + " marked"))
+	}
+
+	// Warn when messages were dropped due to a full receive buffer
+	if m.dropped > 0 {
+		header.WriteString("  ")
+		header.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("%d dropped", m.dropped)))
+	}
+
 	// Build left panel (message list)
 	leftContent := m.buildLeftPanel(leftWidth, contentHeight)
 
@@ -80,6 +119,20 @@ func (m Model) View() string {
 		if m.filterError != nil {
 			footer += " " + common.FilterErrorStyle.Render("(invalid regex)")
 		}
+	} else if m.editingLimit {
+		footer = m.limitInput.View()
+		if m.limitError != "" {
+			footer += " " + common.FilterErrorStyle.Render("("+m.limitError+")")
+		}
+	} else if m.editingAttribute {
+		footer = m.attributeInput.View()
+	} else if m.editingAge {
+		footer = m.ageInput.View()
+		if m.ageError != "" {
+			footer += " " + common.FilterErrorStyle.Render("("+m.ageError+")")
+		}
+	} else if m.streamError != nil {
+		footer = common.LogErrorStyle.Render("Subscription error: " + m.streamError.Error())
 	} else if m.filterText != "" {
 		footer = common.FilterPromptStyle.Render("/ ") + common.FilterInputStyle.Render(m.filterText)
 	} else if !m.connected {
@@ -115,6 +168,8 @@ func (m Model) buildLeftPanel(width, height int) string {
 		for i := 0; i < height-2; i++ {
 			content.WriteString("\n")
 		}
+	} else if m.IsListScrolled() {
+		content.WriteString(m.scrolledListView())
 	} else {
 		// Render the list (includes status bar with pagination)
 		content.WriteString(m.messageList.View())
@@ -142,11 +197,18 @@ func (m Model) buildRightPanel(width, height int) string {
 
 	// Detail header
 	detailHeader := common.MutedText.Render("Detail")
+	if m.ackFlash != "" {
+		detailHeader += "  " + common.LogSuccessStyle.Render(m.ackFlash)
+	}
 	content.WriteString(detailHeader)
 	content.WriteString("\n")
 
-	// Detail content
-	content.WriteString(m.detailView.View())
+	// Detail content, or the regex examples opened from the filter
+	if m.regexHelp.Visible() {
+		content.WriteString(common.RenderRegexHelp(width))
+	} else {
+		content.WriteString(m.detailView.View())
+	}
 
 	result := content.String()
 
@@ -169,10 +231,21 @@ func (m Model) buildRightPanel(width, height int) string {
 	return strings.Join(paddedLines[:height], "\n")
 }
 
+// FullHelp returns every key binding of the panel for the help overlay
+func (m Model) FullHelp() []key.Binding {
+	return common.KeyBindings(keys)
+}
+
 // ShortHelp returns key bindings for the help display
 func (m Model) ShortHelp() []string {
 	if m.filtering {
 		return []string{"esc: clear", "enter: apply"}
 	}
-	return []string{"/: filter", "a: ack", "A: auto-ack", "j/k: navigate"}
+	if m.editingLimit {
+		return []string{"enter: restart", "esc: cancel"}
+	}
+	if m.editingAttribute || m.editingAge {
+		return []string{"enter: apply", "esc: cancel"}
+	}
+	return []string{"/: filter", "a: ack", ".: ack (stay)", "*: ack displayed", "n: nack", "A: ack mode", "p: republish", "w: write to file", "z: local/UTC", "t: time/age", "r: raw/decoded", "m: max outstanding", "@: list attribute", "W: age window", "x: mark diff", "d: diff", "gg/G: oldest/newest", "ctrl+f/b: page", "F: follow", "j/k: navigate", "ctrl+j/k: scroll list"}
 }
@@ -0,0 +1,77 @@
+package subscriptions
+
+import (
+	"strings"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// formatExpirationPolicy describes when the subscription is deleted for
+// inactivity. The emulator reports no policy, so "never" would be a guess.
+func formatExpirationPolicy(sub common.SubscriptionData, emulator bool) string {
+	switch {
+	case !sub.ConfigLoaded:
+		return "unknown"
+	case sub.ExpirationTTL > 0:
+		return "expires after " + common.FormatAge(sub.ExpirationTTL) + " of inactivity"
+	case emulator:
+		return "not reported by the emulator"
+	default:
+		return "never"
+	}
+}
+
+// formatDelivery describes how the subscription delivers messages
+func formatDelivery(sub common.SubscriptionData) string {
+	switch {
+	case !sub.ConfigLoaded:
+		return "unknown"
+	case sub.IsPush:
+		return "push to " + sub.PushEndpoint
+	default:
+		return "pull"
+	}
+}
+
+// ToggleInfo shows or hides the details of the selected subscription in
+// place of the list
+func (m *Model) ToggleInfo() {
+	m.showInfo = !m.showInfo
+}
+
+// renderInfo renders the details of the selected subscription, cutting
+// lines longer than width
+func (m Model) renderInfo(width int) string {
+	sub := m.SelectedSubscription()
+	if sub == nil {
+		return common.MutedText.Render("No subscription selected")
+	}
+
+	filter := sub.Filter
+	if filter == "" {
+		filter = "none"
+	}
+	topic := sub.TopicName
+	if sub.Orphaned {
+		topic = orphanedLabel
+	}
+	rows := [][2]string{
+		{"Name", sub.Name},
+		{"Topic", topic},
+		{"Delivery", formatDelivery(*sub)},
+		{"Filter", filter},
+		{"Expiration", formatExpirationPolicy(*sub, m.emulator)},
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(common.FilterPromptStyle.Render(row[0] + ": "))
+		b.WriteString(common.BrightText.Render(row[1]))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(common.MutedText.Render("i/esc: back to the list"))
+	return lipgloss.NewStyle().MaxWidth(width).Render(b.String())
+}
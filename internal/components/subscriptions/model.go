@@ -1,6 +1,10 @@
 package subscriptions
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 	"github.com/anmaso/pubsub-tui/internal/utils"
 
@@ -8,6 +12,7 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Mode represents the current mode of the subscriptions panel
@@ -17,17 +22,31 @@ const (
 	ModeNormal Mode = iota
 	ModeFilter
 	ModeCreate
+	ModeCreateFilter
 	ModeConfirmDelete
+	ModeConfirmBulkDelete
+	ModeCreateSnapshot
+	ModeSnapshots
+	ModeConfirmDeleteSnapshot
+	ModeConfirmSeek
+	ModeSearch
 )
 
 // SubscriptionItem implements list.Item for displaying subscriptions
 type SubscriptionItem struct {
-	name      string
-	fullName  string
-	topicName string
-	topicFull string
-	width     int  // For column formatting
-	active    bool // Whether this is the active subscription
+	name         string
+	fullName     string
+	topicName    string
+	topicFull    string
+	filter       string
+	orphaned     bool          // Whether the topic was deleted
+	configLoaded bool          // Whether delivery type is known
+	isPush       bool          // Whether this is a push subscription
+	pushEndpoint string        // Push endpoint URL
+	expiration   time.Duration // Inactivity before deletion; 0 never expires
+	width        int           // List width for column formatting (0 for unlimited)
+	active       bool          // Whether this is the active subscription
+	match        bool          // Whether this subscription matches the search
 }
 
 func (s SubscriptionItem) Title() string {
@@ -47,28 +66,70 @@ func (s SubscriptionItem) Title() string {
 		prefix = "● "
 	}
 
-	name := s.name
-	maxNameLen := nameWidth - len(prefix) - 2
-	if len(name) > maxNameLen {
-		name = name[:maxNameLen-3] + "..."
-	}
+	// Add delivery type marker (push, pull, or unknown)
+	prefix += s.deliveryMarker() + " "
+
+	name := truncateText(s.name, nameWidth-lipgloss.Width(prefix)-2)
 
 	// Pad name to fixed width
 	fullName := prefix + name
-	for len(fullName) < nameWidth {
-		fullName += " "
+	if w := lipgloss.Width(fullName); w < nameWidth {
+		fullName += strings.Repeat(" ", nameWidth-w)
+	}
+
+	topic := s.topicName
+	if s.orphaned {
+		topic = orphanedLabel
+	}
+	title := fullName + "→ " + topic
+	if s.width > 0 {
+		// The topic takes the remaining width; on very narrow lists the
+		// name column is cut too
+		title = truncateText(title, s.width)
+	}
+	return title
+}
+
+// truncateText shortens text to at most width cells, ending with "…" when
+// anything was cut
+func truncateText(text string, width int) string {
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
 	}
+	return string(runes) + "…"
+}
 
-	return fullName + "→ " + s.topicName
+// deliveryMarker returns a one-character marker for the delivery type:
+// "⇪" for push, blank for pull, and "?" when the config could not be fetched
+func (s SubscriptionItem) deliveryMarker() string {
+	switch {
+	case !s.configLoaded:
+		return "?"
+	case s.isPush:
+		return "⇪"
+	default:
+		return " "
+	}
 }
 func (s SubscriptionItem) Description() string { return "" }
 func (s SubscriptionItem) FilterValue() string { return s.name }
+func (s SubscriptionItem) SearchMatch() bool   { return s.match }
 
 // Model represents the state of the subscriptions panel
 type Model struct {
 	list               list.Model
 	filterInput        textinput.Model
 	createInput        textinput.Model
+	confirmInput       textinput.Model
+	createFilterInput  textinput.Model
+	snapshotInput      textinput.Model
 	spinner            spinner.Model
 	allSubscriptions   []common.SubscriptionData // All subscriptions from GCP
 	width              int
@@ -77,12 +138,39 @@ type Model struct {
 	mode               Mode
 	filterText         string // Current regex filter
 	filterError        error
-	selectedTopic      string // Topic filter (from topic selection)
+	filterHistory      common.FilterHistory // Recently applied regex filters
+	prefixHistory      common.FilterHistory // Recently applied name prefixes
+	regexHelp          common.RegexHelp     // Regex examples opened from the filter
+	filterCache        utils.FilterCache    // Compiled filterText
+	debounce           common.Debouncer     // Delays filtering while typing
+	filterPrefix       bool                 // Whether filterText is a literal name prefix
+	selectedTopic      string               // Topic filter (from topic selection)
 	loading            bool
 	loadError          error
 	statusMsg          string
 	statusError        bool
-	activeSubscription string // Currently connected subscription
+	statusExpiry       time.Time       // When the status clears itself; zero keeps it
+	activeSubscription string          // Currently connected subscription
+	pendingCreate      string          // Subscription name awaiting a filter expression
+	pendingG           common.PendingG // First g of gg was pressed
+
+	showInfo bool // Details of the selection are shown in place of the list
+
+	bulkOrphans bool // Whether the bulk delete confirmation is for orphaned subscriptions
+
+	emulator        bool // Connected to the emulator: deletes confirm with y/n
+	confirmMismatch bool // Typed confirmation did not match the subscription name
+
+	// Snapshots
+	snapshotSource   string                   // Subscription being snapshotted
+	seekTarget       *common.SubscriptionData // Subscription the browser seeks, if any
+	snapshots        []common.SnapshotData    // Snapshots shown in the browser
+	snapshotCursor   int
+	snapshotsLoading bool
+	snapshotsErr     error
+
+	// Search highlights matches without hiding the other subscriptions
+	search common.Search
 }
 
 // New creates a new subscriptions panel model
@@ -94,7 +182,7 @@ func New() Model {
 	delegate.Styles.SelectedTitle = common.SelectedItem
 	delegate.Styles.NormalTitle = common.NormalText
 
-	l := list.New([]list.Item{}, delegate, 0, 0)
+	l := list.New([]list.Item{}, subscriptionDelegate{common.SearchDelegate{DefaultDelegate: delegate}}, 0, 0)
 	l.Title = "Subscriptions"
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
@@ -104,8 +192,8 @@ func New() Model {
 
 	// Create filter input
 	fi := textinput.New()
-	fi.Placeholder = "regex filter..."
-	fi.Prompt = "/ "
+	fi.Placeholder = "regex filter (? for examples)..."
+	fi.Prompt = "filter: "
 	fi.PromptStyle = common.FilterPromptStyle
 	fi.TextStyle = common.FilterInputStyle
 
@@ -117,6 +205,27 @@ func New() Model {
 	ci.TextStyle = common.FilterInputStyle
 	ci.CharLimit = 255
 
+	// Create subscription filter expression input
+	cfi := textinput.New()
+	cfi.Placeholder = `attributes.type = "order" (optional)`
+	cfi.Prompt = "Filter: "
+	cfi.PromptStyle = common.FilterPromptStyle
+	cfi.TextStyle = common.FilterInputStyle
+	cfi.CharLimit = 256
+
+	// Create delete confirmation input (real GCP only)
+	dci := textinput.New()
+	dci.PromptStyle = common.FilterPromptStyle
+	dci.TextStyle = common.FilterInputStyle
+	dci.CharLimit = 255
+
+	// Create snapshot name input
+	si := textinput.New()
+	si.Prompt = "Snapshot: "
+	si.PromptStyle = common.FilterPromptStyle
+	si.TextStyle = common.FilterInputStyle
+	si.CharLimit = 255
+
 	// Create spinner
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
@@ -125,8 +234,13 @@ func New() Model {
 	return Model{
 		list:        l,
 		filterInput: fi,
-		createInput: ci,
-		spinner:     sp,
+		search:            common.NewSearch(),
+		debounce:          common.NewDebouncer("subscriptions", common.FilterDebounceDelay),
+		createInput:       ci,
+		createFilterInput: cfi,
+		confirmInput:      dci,
+		snapshotInput:     si,
+		spinner:           sp,
 		loading:     true,
 		mode:        ModeNormal,
 	}
@@ -139,8 +253,53 @@ func (m *Model) SetFocused(focused bool) {
 		// Reset to normal mode when losing focus
 		m.mode = ModeNormal
 		m.filterInput.Blur()
+		m.search.Input.Blur()
 		m.createInput.Blur()
+		m.createFilterInput.Blur()
+		m.confirmInput.Blur()
+		m.snapshotInput.Blur()
+	}
+}
+
+// SetEmulatorMode sets whether the panel is connected to the emulator.
+// Against real GCP, deleting a subscription or snapshot requires typing its
+// name, and a bulk delete typing "delete N".
+func (m *Model) SetEmulatorMode(emulator bool) {
+	m.emulator = emulator
+}
+
+// startConfirmDelete enters delete confirmation mode for the selection
+func (m *Model) startConfirmDelete() {
+	m.mode = ModeConfirmDelete
+	m.startTypedConfirm("Type the name to confirm: ")
+}
+
+// startConfirmBulkDelete enters confirmation mode for deleting every
+// displayed subscription, or with orphans every orphaned one
+func (m *Model) startConfirmBulkDelete(orphans bool) {
+	m.mode = ModeConfirmBulkDelete
+	m.bulkOrphans = orphans
+	m.startTypedConfirm(fmt.Sprintf("Type '%s' to confirm: ", common.BulkDeletePhrase(len(m.bulkDeleteNames()))))
+}
+
+// startTypedConfirm clears and focuses the confirmation input. With the
+// emulator, deletes confirm with y/n instead.
+func (m *Model) startTypedConfirm(prompt string) {
+	m.confirmMismatch = false
+	if !m.emulator {
+		m.confirmInput.Prompt = prompt
+		m.confirmInput.SetValue("")
+		m.confirmInput.Focus()
+	}
+}
+
+// bulkDeleteNames returns the subscriptions the bulk delete being confirmed
+// would delete
+func (m Model) bulkDeleteNames() []string {
+	if m.bulkOrphans {
+		return m.OrphanedNames()
 	}
+	return m.DisplayedNames()
 }
 
 // IsFocused returns whether the panel is focused
@@ -215,10 +374,16 @@ func (m Model) SelectedSubscription() *common.SubscriptionData {
 	}
 
 	return &common.SubscriptionData{
-		Name:      item.name,
-		FullName:  item.fullName,
-		TopicName: item.topicName,
-		TopicFull: item.topicFull,
+		Name:         item.name,
+		FullName:     item.fullName,
+		TopicName:    item.topicName,
+		TopicFull:    item.topicFull,
+		Filter:       item.filter,
+		ConfigLoaded: item.configLoaded,
+		IsPush:       item.isPush,
+		PushEndpoint: item.pushEndpoint,
+
+		ExpirationTTL: item.expiration,
 	}
 }
 
@@ -236,12 +401,29 @@ func (m Model) GetMode() Mode {
 func (m *Model) SetStatus(msg string, isError bool) {
 	m.statusMsg = msg
 	m.statusError = isError
+	m.statusExpiry = common.StatusExpiry(isError, time.Now())
+}
+
+// SetProgress sets a status message for work in progress. It does not
+// expire, since the result replaces it.
+func (m *Model) SetProgress(msg string) {
+	m.statusMsg = msg
+	m.statusError = false
+	m.statusExpiry = time.Time{}
 }
 
 // ClearStatus clears the status message
 func (m *Model) ClearStatus() {
 	m.statusMsg = ""
 	m.statusError = false
+	m.statusExpiry = time.Time{}
+}
+
+// ExpireStatus clears the status message once it has been shown long enough
+func (m *Model) ExpireStatus(now time.Time) {
+	if common.StatusExpired(m.statusExpiry, now) {
+		m.ClearStatus()
+	}
 }
 
 // SetActiveSubscription sets the currently active subscription
@@ -262,7 +444,8 @@ func (m Model) IsActiveSubscription(name string) bool {
 
 // IsInputActive returns whether an input field is active
 func (m Model) IsInputActive() bool {
-	return m.mode == ModeFilter || m.mode == ModeCreate
+	return m.mode == ModeFilter || m.mode == ModeSearch || m.mode == ModeCreate || m.mode == ModeCreateFilter ||
+		m.mode == ModeCreateSnapshot || ((m.mode == ModeConfirmDelete || m.mode == ModeConfirmBulkDelete || m.mode == ModeConfirmDeleteSnapshot) && !m.emulator)
 }
 
 // SpinnerTickCmd returns the spinner tick command
@@ -272,7 +455,8 @@ func (m Model) SpinnerTickCmd() tea.Cmd {
 
 // applyFilter filters the subscriptions based on current filters
 func (m *Model) applyFilter() {
-	var items []list.Item
+	items := make([]list.Item, 0, len(m.allSubscriptions))
+	filter := m.filterCache.Get(m.filterPattern())
 
 	for _, sub := range m.allSubscriptions {
 		// Apply topic filter first
@@ -282,45 +466,43 @@ func (m *Model) applyFilter() {
 
 		// Apply regex filter
 		if m.filterText == "" {
-			items = append(items, SubscriptionItem{
-				name:      sub.Name,
-				fullName:  sub.FullName,
-				topicName: sub.TopicName,
-				topicFull: sub.TopicFull,
-				width:     m.width,
-				active:    m.activeSubscription == sub.Name,
-			})
+			items = append(items, m.newItem(sub))
 			continue
 		}
 
-		result := utils.MatchesFilter(sub.Name, m.filterText)
+		result := filter.Match(sub.Name)
 		if result.Error != nil {
 			m.filterError = result.Error
 			// On error, include item
-			items = append(items, SubscriptionItem{
-				name:      sub.Name,
-				fullName:  sub.FullName,
-				topicName: sub.TopicName,
-				topicFull: sub.TopicFull,
-				width:     m.width,
-				active:    m.activeSubscription == sub.Name,
-			})
+			items = append(items, m.newItem(sub))
 		} else if result.Matches {
 			m.filterError = nil
-			items = append(items, SubscriptionItem{
-				name:      sub.Name,
-				fullName:  sub.FullName,
-				topicName: sub.TopicName,
-				topicFull: sub.TopicFull,
-				width:     m.width,
-				active:    m.activeSubscription == sub.Name,
-			})
+			items = append(items, m.newItem(sub))
 		}
 	}
 
 	m.list.SetItems(items)
 }
 
+// newItem builds a list item for a subscription
+func (m *Model) newItem(sub common.SubscriptionData) SubscriptionItem {
+	return SubscriptionItem{
+		name:         sub.Name,
+		fullName:     sub.FullName,
+		topicName:    sub.TopicName,
+		topicFull:    sub.TopicFull,
+		filter:       sub.Filter,
+		orphaned:     sub.Orphaned,
+		configLoaded: sub.ConfigLoaded,
+		isPush:       sub.IsPush,
+		pushEndpoint: sub.PushEndpoint,
+		expiration:   sub.ExpirationTTL,
+		width:        m.list.Width(),
+		active:       m.activeSubscription == sub.Name,
+		match:        m.search.Match(sub.Name),
+	}
+}
+
 // TotalCount returns total subscription count
 func (m Model) TotalCount() int {
 	return len(m.allSubscriptions)
@@ -341,7 +523,69 @@ func (m Model) FilteredCount() int {
 	return count
 }
 
+// DisplayedNames returns the names of the currently displayed subscriptions
+func (m Model) DisplayedNames() []string {
+	var names []string
+	for _, item := range m.list.Items() {
+		if sub, ok := item.(SubscriptionItem); ok {
+			names = append(names, sub.name)
+		}
+	}
+	return names
+}
+
+// NamesForTopic returns the names of all subscriptions attached to a topic,
+// regardless of the current filters
+func (m Model) NamesForTopic(topicName string) []string {
+	var names []string
+	for _, sub := range m.allSubscriptions {
+		if sub.TopicName == topicName {
+			names = append(names, sub.Name)
+		}
+	}
+	return names
+}
+
 // DisplayCount returns count of currently displayed items
 func (m Model) DisplayCount() int {
 	return len(m.list.Items())
 }
+
+// startFilter enters filter mode, treating input as a literal name prefix or
+// as a regex. Switching between the two clears the previous filter.
+func (m *Model) startFilter(prefix bool) {
+	if prefix != m.filterPrefix && m.filterText != "" {
+		m.filterText = ""
+		m.filterInput.SetValue("")
+		m.filterError = nil
+		m.applyFilter()
+	}
+
+	m.filterPrefix = prefix
+	if prefix {
+		m.filterInput.Prompt = "prefix: "
+		m.filterInput.Placeholder = "name prefix..."
+	} else {
+		m.filterInput.Prompt = "/ "
+		m.filterInput.Placeholder = "regex filter (? for examples)..."
+	}
+	m.mode = ModeFilter
+	m.filterInput.Focus()
+}
+
+// filterPattern returns the regex for the current filter
+func (m Model) filterPattern() string {
+	if m.filterPrefix {
+		return utils.PrefixPattern(m.filterText)
+	}
+	return m.filterText
+}
+
+// history returns the filter history of the current filter mode, so prefixes
+// and regexes are recalled separately
+func (m *Model) history() *common.FilterHistory {
+	if m.filterPrefix {
+		return &m.prefixHistory
+	}
+	return &m.filterHistory
+}
@@ -0,0 +1,824 @@
+package subscriptions
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// testSubscriptions returns n subscriptions spread over 10 topics
+func testSubscriptions(n int) []common.SubscriptionData {
+	subs := make([]common.SubscriptionData, n)
+	for i := range subs {
+		subs[i] = common.SubscriptionData{
+			Name:         fmt.Sprintf("service-%d-events", i),
+			TopicName:    fmt.Sprintf("topic-%d", i%10),
+			ConfigLoaded: true,
+		}
+	}
+	return subs
+}
+
+func TestSubscriptionItem_Title_Width(t *testing.T) {
+	items := []SubscriptionItem{
+		{name: "orders-sub", topicName: "orders", configLoaded: true},
+		{name: "a-very-long-subscription-name-for-billing", topicName: "billing-events-from-the-legacy-system", configLoaded: true, active: true},
+		{name: "push-sub", topicName: "notifications-for-mobile-devices", configLoaded: true, isPush: true},
+		{name: "unknown", topicName: "(unknown)"},
+	}
+
+	for _, width := range []int{8, 20, 30, 40, 60, 120} {
+		arrow := -1
+		for _, item := range items {
+			item.width = width
+			title := item.Title()
+			if w := lipgloss.Width(title); w > width {
+				t.Errorf("width %d: Title() = %q is %d cells wide", width, title, w)
+			}
+
+			// The topic column starts at the same place on every row
+			if width < 20 {
+				continue
+			}
+			col := strings.Index(title, "→")
+			if col < 0 {
+				t.Errorf("width %d: Title() = %q has no topic column", width, title)
+				continue
+			}
+			col = lipgloss.Width(title[:col])
+			if arrow >= 0 && col != arrow {
+				t.Errorf("width %d: topic column at %d in %q, want %d", width, col, title, arrow)
+			}
+			arrow = col
+		}
+	}
+}
+
+func TestSubscriptionItem_Title_Truncation(t *testing.T) {
+	item := SubscriptionItem{name: "orders-sub", topicName: "orders-from-the-web-shop", configLoaded: true, width: 30}
+	// A 13-cell name column keeps 7 cells for the name, then "→ " and the
+	// topic cut to the remaining cells
+	want := "    orders…  → orders-from-th…"
+	if got := item.Title(); got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+
+	item.width = 0
+	if got := item.Title(); !strings.HasSuffix(got, "→ orders-from-the-web-shop") {
+		t.Errorf("Title() without a width = %q, want the full topic", got)
+	}
+}
+
+func TestSubscriptionItem_DeliveryMarker(t *testing.T) {
+	m := New()
+	m.SetSubscriptions([]common.SubscriptionData{
+		{Name: "pull-sub", TopicName: "orders", ConfigLoaded: true},
+		{Name: "push-sub", TopicName: "orders", ConfigLoaded: true, IsPush: true, PushEndpoint: "https://example.com/push"},
+		{Name: "unknown-sub", TopicName: pubsub.UnknownTopic},
+	})
+
+	// An unfetched config is unknown rather than pull
+	want := map[string]string{"pull-sub": " ", "push-sub": "⇪", "unknown-sub": "?"}
+	for _, listItem := range m.list.Items() {
+		item := listItem.(SubscriptionItem)
+		if got := item.deliveryMarker(); got != want[item.name] {
+			t.Errorf("%s marker = %q, want %q", item.name, got, want[item.name])
+		}
+		if marker := want[item.name]; marker != " " && !strings.Contains(item.Title(), marker) {
+			t.Errorf("%s Title() = %q should show %q", item.name, item.Title(), marker)
+		}
+	}
+}
+
+func TestModel_ApplyFilter(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions(testSubscriptions(100))
+
+	m.filterText = `^service-\d*7-`
+	m.applyFilter()
+	if got := len(m.DisplayedNames()); got != 10 {
+		t.Errorf("displayed %d subscriptions, want 10", got)
+	}
+
+	m.SetTopicFilter("topic-7")
+	if got := len(m.DisplayedNames()); got != 10 {
+		t.Errorf("displayed %d subscriptions with topic filter, want 10", got)
+	}
+
+	m.filterText = "[invalid"
+	m.applyFilter()
+	if m.filterError == nil {
+		t.Error("invalid pattern should set filterError")
+	}
+	if got := len(m.DisplayedNames()); got != 10 {
+		t.Errorf("invalid pattern should show all topic subscriptions, got %d", got)
+	}
+}
+
+func TestModel_FilterInput_CursorMovementKeepsFilter(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions(testSubscriptions(20))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	m, _ = m.Update(m.debounce.Msg())
+	want := m.DisplayedNames()
+	pending := m.debounce.Msg()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.filterText != "1" {
+		t.Errorf("filterText = %q after cursor movement, want 1", m.filterText)
+	}
+	if got := m.DisplayedNames(); len(got) != len(want) {
+		t.Errorf("displayed %d subscriptions after cursor movement, want %d", len(got), len(want))
+	}
+	if m.debounce.Msg() != pending {
+		t.Error("cursor movement should not schedule a filter pass")
+	}
+}
+
+func TestModel_CreateInput_EditingKeys(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetTopicFilter("orders")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("orders audit")})
+
+	steps := []struct {
+		name string
+		msg  tea.KeyMsg
+		want string
+	}{
+		{"ctrl+w deletes the previous word", tea.KeyMsg{Type: tea.KeyCtrlW}, "orders "},
+		{"backspace", tea.KeyMsg{Type: tea.KeyBackspace}, "orders"},
+		{"ctrl+a moves to the start", tea.KeyMsg{Type: tea.KeyCtrlA}, "orders"},
+		{"typing at the start", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("all-")}, "all-orders"},
+		{"ctrl+e moves to the end", tea.KeyMsg{Type: tea.KeyCtrlE}, "all-orders"},
+		{"typing at the end", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-sub")}, "all-orders-sub"},
+		{"ctrl+u clears to the start", tea.KeyMsg{Type: tea.KeyCtrlU}, ""},
+	}
+	for _, step := range steps {
+		m, _ = m.Update(step.msg)
+		if got := m.createInput.Value(); got != step.want {
+			t.Errorf("%s: value = %q, want %q", step.name, got, step.want)
+		}
+	}
+	if m.mode != ModeCreate {
+		t.Errorf("mode = %v, editing keys should stay in create mode", m.mode)
+	}
+}
+
+func TestModel_FilterInput_Debounce(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions(testSubscriptions(100))
+	all := len(m.DisplayedNames())
+
+	// Rapid keystrokes each schedule a pass but none applies immediately
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	var cmd tea.Cmd
+	for _, r := range "-42-" {
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if cmd == nil {
+			t.Fatalf("keystroke %q should schedule a debounced filter pass", r)
+		}
+	}
+	if got := len(m.DisplayedNames()); got != all {
+		t.Fatalf("displayed %d subscriptions before the debounce fired, want %d", got, all)
+	}
+
+	// Stale ticks from earlier keystrokes are ignored
+	latest := m.debounce.Msg()
+	for id := 1; id < latest.ID; id++ {
+		m, _ = m.Update(common.DebounceMsg{Source: latest.Source, ID: id})
+		if got := len(m.DisplayedNames()); got != all {
+			t.Fatalf("stale debounce %d applied the filter", id)
+		}
+	}
+
+	// Only the latest tick applies the full pattern, once
+	m, _ = m.Update(latest)
+	if got := m.DisplayedNames(); len(got) != 1 || got[0] != "service-42-events" {
+		t.Errorf("DisplayedNames() = %v, want [service-42-events]", got)
+	}
+}
+
+func TestModel_FilterInput_EnterAppliesPendingFilter(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions(testSubscriptions(100))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-42-")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := len(m.DisplayedNames()); got != 1 {
+		t.Errorf("displayed %d subscriptions after Enter, want 1", got)
+	}
+}
+
+func TestModel_PrefixFilter(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions([]common.SubscriptionData{
+		{Name: "orders.v1", TopicName: "orders"},
+		{Name: "ordersxv1", TopicName: "orders"},
+		{Name: "legacy-orders.v1", TopicName: "orders"},
+	})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if m.filterInput.Prompt != "prefix: " {
+		t.Errorf("prompt = %q, want %q", m.filterInput.Prompt, "prefix: ")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("orders.")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// The dot is literal and the match is anchored at the start
+	if got := m.DisplayedNames(); len(got) != 1 || got[0] != "orders.v1" {
+		t.Errorf("DisplayedNames() = %v, want [orders.v1]", got)
+	}
+
+	// Switching to the regex filter clears the prefix
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if m.filterText != "" || m.filterInput.Prompt != "/ " {
+		t.Errorf("regex filter should start empty with the / prompt, got %q %q", m.filterText, m.filterInput.Prompt)
+	}
+	if got := len(m.DisplayedNames()); got != 3 {
+		t.Errorf("displayed %d subscriptions after switching, want 3", got)
+	}
+}
+
+func TestModel_FilterHistoryPerMode(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions(testSubscriptions(10))
+
+	apply := func(key rune, text string) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	apply('F', `-\d-`)
+	apply('f', "service-1")
+
+	// Each mode recalls only its own entries
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.filterInput.Value(); got != `-\d-` {
+		t.Errorf("regex Up recalled %q, want the regex", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.filterInput.Value(); got != `-\d-` {
+		t.Errorf("second regex Up recalled %q, want no prefix entry", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.filterInput.Value(); got != "service-1" {
+		t.Errorf("prefix Up recalled %q, want service-1", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.filterInput.Value(); got != "service-1" {
+		t.Errorf("second prefix Up recalled %q, want no regex entry", got)
+	}
+}
+
+func TestModel_DeleteConfirm(t *testing.T) {
+	subs := []common.SubscriptionData{{Name: "orders-sub", TopicName: "orders"}}
+	deleteRequested := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+		msg, ok := cmd().(DeleteSubscriptionMsg)
+		return ok && msg.SubscriptionName == "orders-sub"
+	}
+
+	t.Run("real GCP requires typing the name", func(t *testing.T) {
+		m := New()
+		m.SetSize(100, 50)
+		m.SetSubscriptions(subs)
+
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+		if !m.IsInputActive() {
+			t.Fatal("typed confirmation should capture input")
+		}
+
+		// "y" is just text here, and a wrong name is rejected
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if deleteRequested(cmd) || m.mode != ModeConfirmDelete || !m.confirmMismatch {
+			t.Fatal("a mismatched name should not delete")
+		}
+
+		m.confirmInput.SetValue("orders-sub")
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if !deleteRequested(cmd) {
+			t.Error("typing the name should request deletion")
+		}
+		if m.mode != ModeNormal {
+			t.Error("confirmation should close after deleting")
+		}
+	})
+
+	t.Run("emulator confirms with y", func(t *testing.T) {
+		m := New()
+		m.SetSize(100, 50)
+		m.SetEmulatorMode(true)
+		m.SetSubscriptions(subs)
+
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+		if m.IsInputActive() {
+			t.Error("y/n confirmation should not capture input")
+		}
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		if !deleteRequested(cmd) {
+			t.Error("y should request deletion in emulator mode")
+		}
+	})
+}
+
+func TestModel_BulkDeleteConfirm(t *testing.T) {
+	bulkDeleteRequested := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+		msg, ok := cmd().(BulkDeleteSubscriptionsMsg)
+		return ok && len(msg.SubscriptionNames) == 3
+	}
+
+	t.Run("real GCP requires typing the count", func(t *testing.T) {
+		m := New()
+		m.SetSize(100, 50)
+		m.SetSubscriptions(testSubscriptions(3))
+
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+		if !m.IsInputActive() || !strings.Contains(m.View(), "Type 'delete 3' to confirm") {
+			t.Fatalf("D should ask to type delete 3:\n%s", m.View())
+		}
+
+		// "y" is just text here, and a wrong count is rejected
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if bulkDeleteRequested(cmd) || m.mode != ModeConfirmBulkDelete || !m.confirmMismatch {
+			t.Fatal("y should not confirm a bulk delete from GCP")
+		}
+		m.confirmInput.SetValue("delete 2")
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if bulkDeleteRequested(cmd) {
+			t.Fatal("a wrong count should not delete")
+		}
+
+		m.confirmInput.SetValue("delete 3")
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if !bulkDeleteRequested(cmd) {
+			t.Error("typing the count should start the bulk delete")
+		}
+		if m.mode != ModeNormal {
+			t.Error("confirmation should close after deleting")
+		}
+	})
+
+	t.Run("emulator confirms with y", func(t *testing.T) {
+		m := New()
+		m.SetSize(100, 50)
+		m.SetEmulatorMode(true)
+		m.SetSubscriptions(testSubscriptions(3))
+
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+		if m.IsInputActive() {
+			t.Error("y/n confirmation should not capture input")
+		}
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		if !bulkDeleteRequested(cmd) {
+			t.Error("y should start the bulk delete in emulator mode")
+		}
+	})
+}
+
+func TestModel_DeleteOrphans(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions([]common.SubscriptionData{
+		{Name: "orders-sub", TopicName: "orders", ConfigLoaded: true},
+		{Name: "old-sub", TopicName: "_deleted-topic_", Orphaned: true, ConfigLoaded: true},
+		{Name: "legacy-sub", TopicName: "_deleted-topic_", Orphaned: true, ConfigLoaded: true},
+	})
+	m.SetTopicFilter("orders")
+
+	// Orphans are labelled instead of showing the deleted-topic marker
+	item := m.newItem(m.allSubscriptions[1])
+	if title := item.Title(); !strings.Contains(title, orphanedLabel) || strings.Contains(title, "_deleted-topic_") {
+		t.Errorf("Title() = %q, want the orphaned label", title)
+	}
+	if view := m.View(); !strings.Contains(view, "2 orphaned") {
+		t.Errorf("View() should count the orphaned subscriptions:\n%s", view)
+	}
+
+	// O deletes every orphan, whatever the filters
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	if m.mode != ModeConfirmBulkDelete || !strings.Contains(m.View(), "Delete all 2 orphaned subscriptions from GCP?") {
+		t.Fatal("O should confirm deleting the orphaned subscriptions")
+	}
+	m.confirmInput.SetValue("delete 2")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(BulkDeleteSubscriptionsMsg); !ok || strings.Join(msg.SubscriptionNames, ",") != "old-sub,legacy-sub" {
+		t.Errorf("O requested %+v, want old-sub and legacy-sub", msg)
+	}
+
+	// Without orphans there is nothing to confirm
+	m = New()
+	m.SetSubscriptions(testSubscriptions(3))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	if m.mode != ModeNormal || m.statusMsg != "No orphaned subscriptions" {
+		t.Errorf("mode = %v, status = %q; want no confirmation", m.mode, m.statusMsg)
+	}
+}
+
+func BenchmarkApplyFilter(b *testing.B) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions(testSubscriptions(5000))
+	m.filterText = `-events$`
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.applyFilter()
+	}
+}
+
+func TestModel_Snapshots(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions([]common.SubscriptionData{{Name: "orders-sub", TopicName: "orders"}})
+
+	// s prompts for a name defaulting to the subscription name
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.mode != ModeCreateSnapshot || !m.IsInputActive() {
+		t.Fatal("s should open the snapshot name prompt")
+	}
+	if !strings.HasPrefix(m.snapshotInput.Value(), "orders-sub-") {
+		t.Errorf("default snapshot name = %q, want orders-sub- prefix", m.snapshotInput.Value())
+	}
+	m.snapshotInput.SetValue("orders-snap")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should request a snapshot")
+	}
+	if got, ok := cmd().(CreateSnapshotMsg); !ok || got.SnapshotName != "orders-snap" || got.SubscriptionName != "orders-sub" {
+		t.Errorf("create request = %+v, want orders-snap of orders-sub", got)
+	}
+
+	// S opens the browser and loads snapshots
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	if !m.IsBrowsingSnapshots() {
+		t.Fatal("S should open the snapshot browser")
+	}
+	if cmd == nil {
+		t.Fatal("S should request the snapshot list")
+	}
+	if _, ok := cmd().(LoadSnapshotsMsg); !ok {
+		t.Error("S should request the snapshot list")
+	}
+	m, _ = m.Update(common.SnapshotsLoadedMsg{Snapshots: []common.SnapshotData{
+		{Name: "orders-snap", TopicName: "orders"},
+		{Name: "orders-snap-2", TopicName: "orders"},
+	}})
+
+	// Against real GCP, d asks for the name of the snapshot under the cursor
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if !m.IsInputActive() {
+		t.Fatal("d should open the typed confirmation")
+	}
+	m.confirmInput.SetValue("orders-snap")
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || !m.confirmMismatch {
+		t.Fatal("a wrong name should not delete the snapshot")
+	}
+	m.confirmInput.SetValue("orders-snap-2")
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("the matching name should request deletion")
+	}
+	if got, ok := cmd().(DeleteSnapshotMsg); !ok || got.SnapshotName != "orders-snap-2" {
+		t.Errorf("delete request = %+v, want orders-snap-2", got)
+	}
+
+	// With the emulator, y confirms
+	m.SetEmulatorMode(true)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd == nil {
+		t.Fatal("y should request deletion with the emulator")
+	}
+	if got, ok := cmd().(DeleteSnapshotMsg); !ok || got.SnapshotName != "orders-snap-2" {
+		t.Errorf("delete request = %+v, want orders-snap-2", got)
+	}
+
+	// A successful delete reloads the list
+	m, cmd = m.Update(common.SnapshotDeletedMsg{SnapshotName: "orders-snap-2"})
+	if cmd == nil || !m.snapshotsLoading {
+		t.Error("deleting a snapshot should reload the browser")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.IsBrowsingSnapshots() {
+		t.Error("esc should close the snapshot browser")
+	}
+}
+
+func TestDefaultSnapshotName(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	long := "a" + strings.Repeat("1", 299)
+
+	tests := []struct {
+		subName string
+		want    string
+	}{
+		{"orders-sub", "orders-sub-20240301-123000"},
+		{long, long[:239] + "-20240301-123000"},
+		{"", "snapshot-20240301-123000"},
+	}
+	for _, tt := range tests {
+		got := defaultSnapshotName(tt.subName, now)
+		if got != tt.want {
+			t.Errorf("defaultSnapshotName(%.20q) = %q, want %q", tt.subName, got, tt.want)
+		}
+		if err := pubsub.ValidateResourceID(got); err != nil {
+			t.Errorf("defaultSnapshotName(%.20q) = %q: %v", tt.subName, got, err)
+		}
+	}
+}
+
+func TestModel_SnapshotSeek(t *testing.T) {
+	m := New()
+	m.SetSize(100, 50)
+	m.SetSubscriptions([]common.SubscriptionData{{Name: "orders-sub", TopicName: "orders"}})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	m, _ = m.Update(common.SnapshotsLoadedMsg{Snapshots: []common.SnapshotData{
+		{Name: "events-snap", TopicName: "events"},
+		{Name: "orders-snap", TopicName: "orders"},
+	}})
+
+	// Snapshots of another topic are rejected before reaching the server
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.mode != ModeSnapshots || !m.statusError {
+		t.Fatal("seeking to a snapshot of another topic should be refused")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != ModeConfirmSeek {
+		t.Fatal("enter should ask to confirm the seek")
+	}
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd == nil {
+		t.Fatal("y should request the seek")
+	}
+	if got, ok := cmd().(SeekSnapshotMsg); !ok || got.SubscriptionName != "orders-sub" || got.SnapshotName != "orders-snap" {
+		t.Errorf("seek request = %+v, want orders-sub to orders-snap", got)
+	}
+}
+
+func TestFormatExpiration(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expiration time.Time
+		want       string
+	}{
+		{time.Time{}, ""},
+		{now.Add(-time.Minute), "expired"},
+		{now.Add(30 * time.Minute), "expires in 30m"},
+		{now.Add(5 * time.Hour), "expires in 5h"},
+		{now.Add(6*24*time.Hour + time.Hour), "expires in 6d"},
+	}
+	for _, tt := range tests {
+		if got := formatExpiration(tt.expiration, now); got != tt.want {
+			t.Errorf("formatExpiration(%v) = %q, want %q", tt.expiration, got, tt.want)
+		}
+	}
+}
+
+func TestFormatExpirationPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		sub      common.SubscriptionData
+		emulator bool
+		want     string
+	}{
+		{"expires", common.SubscriptionData{ConfigLoaded: true, ExpirationTTL: 31 * 24 * time.Hour}, false, "expires after 31d of inactivity"},
+		{"never expires", common.SubscriptionData{ConfigLoaded: true}, false, "never"},
+		{"emulator", common.SubscriptionData{ConfigLoaded: true}, true, "not reported by the emulator"},
+		{"config not loaded", common.SubscriptionData{}, false, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := formatExpirationPolicy(tt.sub, tt.emulator); got != tt.want {
+			t.Errorf("%s: formatExpirationPolicy() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestModel_Info(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	m.SetSubscriptions([]common.SubscriptionData{
+		{Name: "orders-sub", TopicName: "orders", ConfigLoaded: true, ExpirationTTL: 24 * time.Hour},
+		{Name: "audit-sub", TopicName: "audit", ConfigLoaded: true, IsPush: true, PushEndpoint: "https://example.com/push"},
+	})
+	m.SetActiveSubscription("orders-sub")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	if view := m.View(); !strings.Contains(view, "expires after 1d of inactivity") || !strings.Contains(view, "Delivery: pull") {
+		t.Errorf("details of orders-sub missing from view:\n%s", view)
+	}
+
+	// The details follow the selection
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if view := m.View(); !strings.Contains(view, "Expiration: never") || !strings.Contains(view, "push to https://example.com/push") {
+		t.Errorf("details of audit-sub missing from view:\n%s", view)
+	}
+
+	// Esc closes the details without stopping the subscription
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil || m.showInfo {
+		t.Error("esc should only close the details")
+	}
+}
+
+func TestModel_ExpireStatus(t *testing.T) {
+	m := New()
+	start := time.Now()
+
+	m.SetStatus("Created subscription: orders-sub", false)
+	m.ExpireStatus(start)
+	if m.statusMsg == "" {
+		t.Fatal("status should not clear before it expires")
+	}
+	m.ExpireStatus(start.Add(common.StatusSuccessDuration + time.Second))
+	if m.statusMsg != "" {
+		t.Errorf("success status = %q after expiry, want cleared", m.statusMsg)
+	}
+
+	// Errors outlive successes
+	m.SetStatus("Delete failed: not found", true)
+	m.ExpireStatus(start.Add(common.StatusSuccessDuration + time.Second))
+	if m.statusMsg == "" {
+		t.Error("error status should outlast the success duration")
+	}
+	m.ExpireStatus(start.Add(common.StatusErrorDuration + time.Second))
+	if m.statusMsg != "" || m.statusError {
+		t.Errorf("error status = %q after expiry, want cleared", m.statusMsg)
+	}
+
+	// Progress stays until the result replaces it
+	m.SetProgress("Deleting 1/3...")
+	m.ExpireStatus(start.Add(time.Hour))
+	if m.statusMsg == "" {
+		t.Error("progress status should not expire")
+	}
+}
+
+func TestModel_ListNavigation(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	m.SetSubscriptions(testSubscriptions(50))
+
+	perPage := m.list.Paginator.PerPage
+	if perPage < 2 || perPage >= 25 {
+		t.Fatalf("PerPage = %d, want a page well inside 50 items", perPage)
+	}
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		m, _ = m.Update(msg)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if got := m.list.Index(); got != perPage {
+		t.Errorf("ctrl+f: Index() = %d, want %d", got, perPage)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlF})
+	press(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if got := m.list.Index(); got != perPage {
+		t.Errorf("ctrl+f, ctrl+b: Index() = %d, want %d", got, perPage)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if got := m.list.Index(); got != 49 {
+		t.Errorf("G: Index() = %d, want 49", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if got := m.list.Index(); got != 49 {
+		t.Errorf("ctrl+f on the last item: Index() = %d, want 49", got)
+	}
+
+	// A single g waits; another key drops it
+	press(g)
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if got := m.list.Index(); got != 48 {
+		t.Errorf("g, k: Index() = %d, want 48", got)
+	}
+	press(g)
+	if got := m.list.Index(); got != 48 {
+		t.Errorf("g: Index() = %d, want 48 until the second g", got)
+	}
+	press(g)
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("gg: Index() = %d, want 0", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("ctrl+b on the first item: Index() = %d, want 0", got)
+	}
+}
+
+func TestModel_Search(t *testing.T) {
+	m := New()
+	m.SetSize(100, 30)
+	m.SetSubscriptions(testSubscriptions(20))
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		m, _ = m.Update(msg)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	selected := func() string {
+		if sub := m.SelectedSubscription(); sub != nil {
+			return sub.Name
+		}
+		return ""
+	}
+
+	// Matches service-3, service-13 and service-17; the selection follows
+	// the first match as the pattern is typed
+	m.list.Select(5)
+	press(runes("/"))
+	for _, r := range "-(1?3|17)-" {
+		press(runes(string(r)))
+	}
+	if got := selected(); got != "service-13-events" {
+		t.Errorf("typing: selected %q, want service-13-events", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != ModeNormal || !m.IsSearching() {
+		t.Fatal("Enter should keep the search")
+	}
+	if got := m.DisplayCount(); got != 20 {
+		t.Errorf("DisplayCount() = %d, want all 20 subscriptions kept", got)
+	}
+
+	// n wraps from the last match to the first
+	press(runes("n"))
+	if got := selected(); got != "service-17-events" {
+		t.Errorf("n: selected %q, want service-17-events", got)
+	}
+	press(runes("n"))
+	if got := selected(); got != "service-3-events" || !strings.Contains(m.statusMsg, "wrapped") {
+		t.Errorf("n from the last match: selected %q (status %q), want service-3-events wrapped", got, m.statusMsg)
+	}
+
+	// N wraps from the first match to the last
+	press(runes("N"))
+	if got := selected(); got != "service-17-events" || !strings.Contains(m.statusMsg, "wrapped") {
+		t.Errorf("N from the first match: selected %q (status %q), want service-17-events wrapped", got, m.statusMsg)
+	}
+	press(runes("N"))
+	if got := selected(); got != "service-13-events" {
+		t.Errorf("N: selected %q, want service-13-events", got)
+	}
+
+	// Esc in the prompt restores the previous search and selection
+	press(runes("/"))
+	press(runes("x"))
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.search.Text() != "-(1?3|17)-" || selected() != "service-13-events" {
+		t.Errorf("Esc: search %q, selected %q, want the previous search and selection", m.search.Text(), selected())
+	}
+
+	// An empty search clears it, and n creates again
+	press(runes("/"))
+	m.search.Input.SetValue("")
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsSearching() {
+		t.Fatal("an empty search should clear it")
+	}
+	m.SetTopicFilter("topic-3")
+	press(runes("n"))
+	if m.mode != ModeCreate {
+		t.Errorf("n without a search: mode = %v, want ModeCreate", m.mode)
+	}
+}
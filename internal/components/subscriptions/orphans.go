@@ -0,0 +1,47 @@
+package subscriptions
+
+import (
+	"io"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// orphanedLabel replaces the topic of a subscription whose topic was deleted
+const orphanedLabel = "⚠ orphaned (topic deleted)"
+
+// subscriptionDelegate renders subscription rows, showing orphaned
+// subscriptions in the warning style
+type subscriptionDelegate struct {
+	common.SearchDelegate
+}
+
+// Render renders an item, using LogWarningStyle for unselected orphans
+func (d subscriptionDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if sub, ok := item.(SubscriptionItem); ok && sub.orphaned {
+		d.Styles.NormalTitle = common.LogWarningStyle
+	}
+	d.SearchDelegate.Render(w, m, index, item)
+}
+
+// OrphanedNames returns the names of subscriptions whose topic was deleted,
+// regardless of the current filters
+func (m Model) OrphanedNames() []string {
+	var names []string
+	for _, sub := range m.allSubscriptions {
+		if sub.Orphaned {
+			names = append(names, sub.Name)
+		}
+	}
+	return names
+}
+
+// startDeleteOrphans confirms deleting every orphaned subscription
+func (m *Model) startDeleteOrphans() {
+	if len(m.OrphanedNames()) == 0 {
+		m.SetStatus("No orphaned subscriptions", false)
+		return
+	}
+	m.startConfirmBulkDelete(true)
+}
@@ -0,0 +1,355 @@
+package subscriptions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CreateSnapshotMsg requests a snapshot of a subscription
+type CreateSnapshotMsg struct {
+	SnapshotName     string
+	SubscriptionName string
+}
+
+// DeleteSnapshotMsg requests snapshot deletion
+type DeleteSnapshotMsg struct {
+	SnapshotName string
+}
+
+// SeekSnapshotMsg requests seeking a subscription to a snapshot
+type SeekSnapshotMsg struct {
+	SubscriptionName string
+	SnapshotName     string
+}
+
+// LoadSnapshotsMsg requests the list of snapshots
+type LoadSnapshotsMsg struct{}
+
+// loadSnapshots returns a command that requests the snapshot list
+func loadSnapshots() tea.Msg {
+	return LoadSnapshotsMsg{}
+}
+
+// defaultSnapshotName suggests a snapshot name for a subscription. Long
+// subscription names are cut from the end so the timestamp is kept; a name
+// that is still not a valid resource ID falls back to "snapshot".
+func defaultSnapshotName(subName string, now time.Time) string {
+	suffix := "-" + now.Format("20060102-150405")
+	if max := 255 - len(suffix); len(subName) > max {
+		subName = subName[:max]
+	}
+	name := subName + suffix
+	if pubsub.ValidateResourceID(name) != nil {
+		return "snapshot" + suffix
+	}
+	return name
+}
+
+// formatExpiration describes how long until a snapshot expires
+func formatExpiration(expiration, now time.Time) string {
+	if expiration.IsZero() {
+		return ""
+	}
+	left := expiration.Sub(now)
+	switch {
+	case left <= 0:
+		return "expired"
+	case left < time.Hour:
+		return fmt.Sprintf("expires in %dm", int(left.Minutes()))
+	case left < 24*time.Hour:
+		return fmt.Sprintf("expires in %dh", int(left.Hours()))
+	default:
+		return fmt.Sprintf("expires in %dd", int(left.Hours()/24))
+	}
+}
+
+// startCreateSnapshot opens the snapshot name prompt for the selection
+func (m *Model) startCreateSnapshot() {
+	sub := m.SelectedSubscription()
+	if sub == nil {
+		return
+	}
+	m.snapshotSource = sub.Name
+	m.snapshotInput.SetValue(defaultSnapshotName(sub.Name, time.Now()))
+	m.snapshotInput.CursorEnd()
+	m.snapshotInput.Focus()
+	m.mode = ModeCreateSnapshot
+}
+
+// openSnapshots opens the snapshot browser for the selected subscription
+func (m *Model) openSnapshots() tea.Cmd {
+	m.seekTarget = m.SelectedSubscription()
+	return m.reloadSnapshots()
+}
+
+// reloadSnapshots shows the snapshot browser and requests the snapshot list
+func (m *Model) reloadSnapshots() tea.Cmd {
+	m.mode = ModeSnapshots
+	m.snapshotsLoading = true
+	m.snapshotsErr = nil
+	return loadSnapshots
+}
+
+// SetSnapshots updates the snapshot browser with loaded snapshots
+func (m *Model) SetSnapshots(snapshots []common.SnapshotData, err error) {
+	m.snapshotsLoading = false
+	m.snapshotsErr = err
+	if err != nil {
+		return
+	}
+	m.snapshots = snapshots
+	if m.snapshotCursor >= len(snapshots) {
+		m.snapshotCursor = len(snapshots) - 1
+	}
+	if m.snapshotCursor < 0 {
+		m.snapshotCursor = 0
+	}
+}
+
+// IsBrowsingSnapshots returns whether the snapshot browser is open
+func (m Model) IsBrowsingSnapshots() bool {
+	return m.mode == ModeSnapshots || m.mode == ModeConfirmDeleteSnapshot || m.mode == ModeConfirmSeek
+}
+
+// canSeek reports whether the seek target can seek to a snapshot. The server
+// only accepts snapshots of the subscription's own topic.
+func (m Model) canSeek(snap common.SnapshotData) bool {
+	return m.seekTarget != nil && m.seekTarget.TopicName == snap.TopicName
+}
+
+// SelectedSnapshot returns the snapshot under the browser cursor, if any
+func (m Model) SelectedSnapshot() *common.SnapshotData {
+	if m.snapshotCursor < 0 || m.snapshotCursor >= len(m.snapshots) {
+		return nil
+	}
+	snap := m.snapshots[m.snapshotCursor]
+	return &snap
+}
+
+// handleCreateSnapshotInput handles keyboard input in the snapshot name prompt
+func (m Model) handleCreateSnapshotInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		m.snapshotInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.snapshotInput.Value())
+		if name == "" {
+			return m, nil
+		}
+
+		subName := m.snapshotSource
+		m.mode = ModeNormal
+		m.snapshotInput.Blur()
+		return m, func() tea.Msg {
+			return CreateSnapshotMsg{SnapshotName: name, SubscriptionName: subName}
+		}
+
+	default:
+		var cmd tea.Cmd
+		m.snapshotInput, cmd = m.snapshotInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleSnapshotsNavigation handles keyboard input in the snapshot browser
+func (m Model) handleSnapshotsNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
+	m.ClearStatus()
+
+	switch {
+	case msg.Type == tea.KeyEsc:
+		m.mode = ModeNormal
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.snapshotCursor > 0 {
+			m.snapshotCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Down):
+		if m.snapshotCursor < len(m.snapshots)-1 {
+			m.snapshotCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Delete):
+		if m.SelectedSnapshot() != nil {
+			m.mode = ModeConfirmDeleteSnapshot
+			m.startTypedConfirm("Type the name to confirm: ")
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Select):
+		snap := m.SelectedSnapshot()
+		switch {
+		case snap == nil:
+		case m.seekTarget == nil:
+			m.SetStatus("Select a subscription before opening snapshots to seek", true)
+		case !m.canSeek(*snap):
+			m.SetStatus(fmt.Sprintf("Snapshot is for topic %s, not %s", snap.TopicName, m.seekTarget.TopicName), true)
+		default:
+			m.mode = ModeConfirmSeek
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Snapshots):
+		// Reload
+		return m, m.reloadSnapshots()
+	}
+
+	return m, nil
+}
+
+// handleConfirmDeleteSnapshot handles keyboard input when confirming
+// deletion of the snapshot under the browser cursor
+func (m Model) handleConfirmDeleteSnapshot(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if !m.emulator {
+		return m.handleTypedConfirmDeleteSnapshot(msg)
+	}
+
+	switch msg.String() {
+	case "y", "Y":
+		m.mode = ModeSnapshots
+		snap := m.SelectedSnapshot()
+		if snap == nil {
+			return m, nil
+		}
+		name := snap.Name
+		return m, func() tea.Msg {
+			return DeleteSnapshotMsg{SnapshotName: name}
+		}
+
+	case "n", "N", "esc":
+		m.mode = ModeSnapshots
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleTypedConfirmDeleteSnapshot handles keyboard input when deleting a
+// snapshot from real GCP must be confirmed by typing its name
+func (m Model) handleTypedConfirmDeleteSnapshot(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeSnapshots
+		m.confirmInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		snap := m.SelectedSnapshot()
+		if snap == nil {
+			m.mode = ModeSnapshots
+			m.confirmInput.Blur()
+			return m, nil
+		}
+		if m.confirmInput.Value() != snap.Name {
+			m.confirmMismatch = true
+			return m, nil
+		}
+
+		name := snap.Name
+		m.mode = ModeSnapshots
+		m.confirmInput.Blur()
+		return m, func() tea.Msg {
+			return DeleteSnapshotMsg{SnapshotName: name}
+		}
+
+	default:
+		m.confirmMismatch = false
+		var cmd tea.Cmd
+		m.confirmInput, cmd = m.confirmInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleConfirmSeek handles keyboard input when confirming a seek of the
+// target subscription to the snapshot under the browser cursor
+func (m Model) handleConfirmSeek(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.mode = ModeSnapshots
+		snap := m.SelectedSnapshot()
+		if snap == nil || m.seekTarget == nil {
+			return m, nil
+		}
+		req := SeekSnapshotMsg{SubscriptionName: m.seekTarget.Name, SnapshotName: snap.Name}
+		m.SetProgress(fmt.Sprintf("Seeking %s to %s...", req.SubscriptionName, req.SnapshotName))
+		return m, func() tea.Msg {
+			return req
+		}
+
+	case "n", "N", "esc":
+		m.mode = ModeSnapshots
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleSnapshotResult updates the panel after a snapshot is created or
+// deleted, reloading the browser if it is open
+func (m Model) handleSnapshotResult(status string, err error) (Model, tea.Cmd) {
+	if err != nil {
+		m.SetStatus(status+" failed: "+err.Error(), true)
+		return m, nil
+	}
+	m.SetStatus(status, false)
+	if m.IsBrowsingSnapshots() {
+		return m, m.reloadSnapshots()
+	}
+	return m, nil
+}
+
+// snapshotsView renders the snapshot browser in place of the list
+func (m Model) snapshotsView(height int) string {
+	if m.snapshotsLoading {
+		return common.LogNetworkStyle.Render("Loading snapshots...")
+	}
+	if m.snapshotsErr != nil {
+		return common.LogErrorStyle.Render(fmt.Sprintf("Error: %v", m.snapshotsErr))
+	}
+	if len(m.snapshots) == 0 {
+		return common.MutedText.Render("No snapshots (s on a subscription creates one)")
+	}
+
+	// Keep the cursor in view
+	start := 0
+	if m.snapshotCursor >= height {
+		start = m.snapshotCursor - height + 1
+	}
+	end := start + height
+	if end > len(m.snapshots) {
+		end = len(m.snapshots)
+	}
+
+	now := time.Now()
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		snap := m.snapshots[i]
+		line := snap.Name + " → " + snap.TopicName
+		if exp := formatExpiration(snap.Expiration, now); exp != "" {
+			line += " (" + exp + ")"
+		}
+		switch {
+		case i == m.snapshotCursor:
+			lines = append(lines, common.SelectedItem.Render("> "+line))
+		case m.seekTarget != nil && !m.canSeek(snap):
+			// Other topics cannot be seeked to
+			lines = append(lines, common.MutedText.Render("  "+line))
+		default:
+			lines = append(lines, common.NormalText.Render("  "+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,6 +1,9 @@
 package subscriptions
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,6 +14,7 @@ import (
 type CreateSubscriptionMsg struct {
 	SubscriptionName string
 	TopicName        string
+	Filter           string // Optional server-side filter expression
 }
 
 // DeleteSubscriptionMsg requests subscription deletion
@@ -18,6 +22,12 @@ type DeleteSubscriptionMsg struct {
 	SubscriptionName string
 }
 
+// BulkDeleteSubscriptionsMsg requests deletion of several subscriptions, one
+// at a time, stopping at the first failure
+type BulkDeleteSubscriptionsMsg struct {
+	SubscriptionNames []string
+}
+
 // Update handles messages for the subscriptions panel
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -27,14 +37,34 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		switch m.mode {
 		case ModeFilter:
 			return m.handleFilterInput(msg)
+		case ModeSearch:
+			return m.handleSearchInput(msg)
 		case ModeCreate:
 			return m.handleCreateInput(msg)
+		case ModeCreateFilter:
+			return m.handleCreateFilterInput(msg)
 		case ModeConfirmDelete:
 			return m.handleConfirmDelete(msg)
+		case ModeConfirmBulkDelete:
+			return m.handleConfirmBulkDelete(msg)
+		case ModeCreateSnapshot:
+			return m.handleCreateSnapshotInput(msg)
+		case ModeSnapshots:
+			return m.handleSnapshotsNavigation(msg)
+		case ModeConfirmDeleteSnapshot:
+			return m.handleConfirmDeleteSnapshot(msg)
+		case ModeConfirmSeek:
+			return m.handleConfirmSeek(msg)
 		default:
 			return m.handleNavigation(msg)
 		}
 
+	case common.DebounceMsg:
+		if m.debounce.Due(msg) {
+			m.applyFilter()
+		}
+		return m, nil
+
 	case common.SubscriptionsLoadedMsg:
 		if msg.Err != nil {
 			m.SetError(msg.Err)
@@ -75,6 +105,24 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case common.SubscriptionStoppedMsg:
 		m.activeSubscription = ""
 		return m, nil
+
+	case common.SnapshotsLoadedMsg:
+		m.SetSnapshots(msg.Snapshots, msg.Err)
+		return m, nil
+
+	case common.SnapshotCreatedMsg:
+		return m.handleSnapshotResult("Created snapshot: "+msg.SnapshotName, msg.Err)
+
+	case common.SnapshotDeletedMsg:
+		return m.handleSnapshotResult("Deleted snapshot: "+msg.SnapshotName, msg.Err)
+
+	case common.SnapshotSeekedMsg:
+		if msg.Err != nil {
+			m.SetStatus("Seek failed: "+msg.Err.Error(), true)
+		} else {
+			m.SetStatus(fmt.Sprintf("Seeked %s to %s", msg.SubscriptionName, msg.SnapshotName), false)
+		}
+		return m, nil
 	}
 
 	// Update spinner if loading
@@ -98,6 +146,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // handleFilterInput handles keyboard input in filter mode
 func (m Model) handleFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Prefix filters are literal, so ? is just typed
+	if !m.filterPrefix && m.regexHelp.HandleKey(msg, m.filterInput.Value()) {
+		return m, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
 		// Exit filter mode and clear filter
@@ -106,25 +159,56 @@ func (m Model) handleFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.filterInput.SetValue("")
 		m.filterError = nil
 		m.filterInput.Blur()
+		m.history().Reset()
 		m.applyFilter()
 		return m, nil
 
 	case tea.KeyEnter:
-		// Exit filter mode but keep filter
+		// Exit filter mode but keep filter, applying it now if a
+		// debounced pass is still pending
 		m.mode = ModeNormal
 		m.filterInput.Blur()
+		m.history().Add(m.filterText)
+		m.debounce.Cancel()
+		m.applyFilter()
+		return m, nil
+
+	case tea.KeyUp:
+		// Recall an older filter pattern
+		if pattern, ok := m.history().Prev(m.filterInput.Value()); ok {
+			m.recallFilter(pattern)
+		}
+		return m, nil
+
+	case tea.KeyDown:
+		// Recall a newer filter pattern, or the pattern being typed
+		if pattern, ok := m.history().Next(); ok {
+			m.recallFilter(pattern)
+		}
 		return m, nil
 
 	default:
 		// Update filter input
 		var cmd tea.Cmd
 		m.filterInput, cmd = m.filterInput.Update(msg)
-		m.filterText = m.filterInput.Value()
-		m.applyFilter()
+		// Cursor movement doesn't change the pattern; skip re-filtering.
+		// Otherwise wait for typing to pause before filtering.
+		if value := m.filterInput.Value(); value != m.filterText {
+			m.filterText = value
+			return m, tea.Batch(cmd, m.debounce.Schedule())
+		}
 		return m, cmd
 	}
 }
 
+// recallFilter replaces the filter input with a pattern from history
+func (m *Model) recallFilter(pattern string) {
+	m.filterInput.SetValue(pattern)
+	m.filterInput.CursorEnd()
+	m.filterText = pattern
+	m.applyFilter()
+}
+
 // handleCreateInput handles keyboard input in create mode
 func (m Model) handleCreateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.Type {
@@ -150,28 +234,68 @@ func (m Model) handleCreateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
-		topicName := m.selectedTopic
-		m.mode = ModeNormal
+		// Ask for an optional filter expression next
+		m.pendingCreate = subName
+		m.mode = ModeCreateFilter
 		m.createInput.SetValue("")
 		m.createInput.Blur()
+		m.createFilterInput.SetValue("")
+		m.createFilterInput.Focus()
+		return m, nil
+
+	default:
+		// Update create input
+		var cmd tea.Cmd
+		m.createInput, cmd = m.createInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleCreateFilterInput handles keyboard input for the optional filter
+// expression of a subscription being created
+func (m Model) handleCreateFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Cancel creation
+		m.mode = ModeNormal
+		m.pendingCreate = ""
+		m.createFilterInput.SetValue("")
+		m.createFilterInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		// Submit creation; an empty filter receives all messages
+		subName := m.pendingCreate
+		topicName := m.selectedTopic
+		filter := strings.TrimSpace(m.createFilterInput.Value())
+
+		m.mode = ModeNormal
+		m.pendingCreate = ""
+		m.createFilterInput.SetValue("")
+		m.createFilterInput.Blur()
 
 		return m, func() tea.Msg {
 			return CreateSubscriptionMsg{
 				SubscriptionName: subName,
 				TopicName:        topicName,
+				Filter:           filter,
 			}
 		}
 
 	default:
-		// Update create input
+		// Update filter expression input
 		var cmd tea.Cmd
-		m.createInput, cmd = m.createInput.Update(msg)
+		m.createFilterInput, cmd = m.createFilterInput.Update(msg)
 		return m, cmd
 	}
 }
 
 // handleConfirmDelete handles keyboard input in delete confirmation mode
 func (m Model) handleConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if !m.emulator {
+		return m.handleTypedConfirmDelete(msg)
+	}
+
 	switch msg.String() {
 	case "y", "Y":
 		// Confirm deletion
@@ -197,12 +321,124 @@ func (m Model) handleConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleTypedConfirmDelete handles keyboard input when deletion against real
+// GCP must be confirmed by typing the subscription name
+func (m Model) handleTypedConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		m.confirmInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		sub := m.SelectedSubscription()
+		if sub == nil {
+			m.mode = ModeNormal
+			m.confirmInput.Blur()
+			return m, nil
+		}
+		if m.confirmInput.Value() != sub.Name {
+			m.confirmMismatch = true
+			return m, nil
+		}
+
+		name := sub.Name
+		m.mode = ModeNormal
+		m.confirmInput.Blur()
+		return m, func() tea.Msg {
+			return DeleteSubscriptionMsg{SubscriptionName: name}
+		}
+
+	default:
+		m.confirmMismatch = false
+		var cmd tea.Cmd
+		m.confirmInput, cmd = m.confirmInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleConfirmBulkDelete handles keyboard input when confirming deletion
+// of all displayed (or orphaned) subscriptions
+func (m Model) handleConfirmBulkDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if !m.emulator {
+		return m.handleTypedConfirmBulkDelete(msg)
+	}
+
+	switch msg.String() {
+	case "y", "Y":
+		return m.confirmBulkDelete()
+
+	case "n", "N", "esc":
+		m.mode = ModeNormal
+		m.bulkOrphans = false
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleTypedConfirmBulkDelete handles keyboard input when a bulk delete
+// from real GCP must be confirmed by typing "delete N"
+func (m Model) handleTypedConfirmBulkDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		m.bulkOrphans = false
+		m.confirmInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		if m.confirmInput.Value() != common.BulkDeletePhrase(len(m.bulkDeleteNames())) {
+			m.confirmMismatch = true
+			return m, nil
+		}
+		m.confirmInput.Blur()
+		return m.confirmBulkDelete()
+
+	default:
+		m.confirmMismatch = false
+		var cmd tea.Cmd
+		m.confirmInput, cmd = m.confirmInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// confirmBulkDelete requests deleting the subscriptions of the confirmed
+// bulk delete
+func (m Model) confirmBulkDelete() (Model, tea.Cmd) {
+	m.mode = ModeNormal
+	names := m.bulkDeleteNames()
+	m.bulkOrphans = false
+	if len(names) == 0 {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return BulkDeleteSubscriptionsMsg{SubscriptionNames: names}
+	}
+}
+
 // handleNavigation handles keyboard input in normal navigation mode
 func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Clear status on any key
 	m.ClearStatus()
 
+	// A pending g completes gg or is dropped
+	if m.pendingG.Complete(msg, keys.Top) {
+		common.JumpList(&m.list, false)
+		return m, nil
+	}
+
+	// Esc closes the details before it stops the subscription
+	if m.showInfo && msg.Type == tea.KeyEsc {
+		m.showInfo = false
+		return m, nil
+	}
+
 	switch {
+	case key.Matches(msg, keys.Info):
+		m.ToggleInfo()
+		return m, nil
+
 	case key.Matches(msg, keys.Stop):
 		// Stop active subscription
 		if m.activeSubscription != "" {
@@ -213,9 +449,25 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, keys.Filter):
-		// Enter filter mode
-		m.mode = ModeFilter
-		m.filterInput.Focus()
+		// Enter regex filter mode
+		m.startFilter(false)
+		return m, nil
+
+	case key.Matches(msg, keys.PrefixFilter):
+		// Enter literal prefix filter mode
+		m.startFilter(true)
+		return m, nil
+
+	case key.Matches(msg, keys.Search):
+		m.startSearch()
+		return m, nil
+
+	case m.IsSearching() && key.Matches(msg, keys.SearchNext):
+		m.nextMatch(false)
+		return m, nil
+
+	case m.IsSearching() && key.Matches(msg, keys.SearchPrev):
+		m.nextMatch(true)
 		return m, nil
 
 	case key.Matches(msg, keys.Create):
@@ -231,10 +483,31 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case key.Matches(msg, keys.Delete):
 		// Enter delete confirmation mode
 		if m.SelectedSubscription() != nil {
-			m.mode = ModeConfirmDelete
+			m.startConfirmDelete()
 		}
 		return m, nil
 
+	case key.Matches(msg, keys.DeleteAll):
+		// Confirm deletion of every displayed (filtered) subscription
+		if m.DisplayCount() > 0 {
+			m.startConfirmBulkDelete(false)
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.DeleteOrphans):
+		// Confirm deletion of every subscription whose topic was deleted
+		m.startDeleteOrphans()
+		return m, nil
+
+	case key.Matches(msg, keys.Snapshot):
+		// Snapshot the selected subscription
+		m.startCreateSnapshot()
+		return m, nil
+
+	case key.Matches(msg, keys.Snapshots):
+		// Browse snapshots
+		return m, m.openSnapshots()
+
 	case key.Matches(msg, keys.ClearFilter):
 		// Clear topic filter
 		m.ClearTopicFilter()
@@ -268,6 +541,23 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.list.CursorDown()
 		return m, nil
 
+	case key.Matches(msg, keys.Top):
+		// The first g waits for a second
+		m.pendingG.Press()
+		return m, nil
+
+	case key.Matches(msg, keys.Bottom):
+		common.JumpList(&m.list, true)
+		return m, nil
+
+	case key.Matches(msg, keys.PageDown):
+		common.PageList(&m.list, 1)
+		return m, nil
+
+	case key.Matches(msg, keys.PageUp):
+		common.PageList(&m.list, -1)
+		return m, nil
+
 	default:
 		// Pass to list for handling
 		var cmd tea.Cmd
@@ -278,47 +568,138 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 // Key bindings
 type keyMap struct {
-	Stop        key.Binding
-	Filter      key.Binding
-	ClearFilter key.Binding
-	Create      key.Binding
-	Delete      key.Binding
-	Select      key.Binding
-	Up          key.Binding
-	Down        key.Binding
+	Stop          key.Binding
+	Filter        key.Binding
+	PrefixFilter  key.Binding
+	Search        key.Binding
+	SearchNext    key.Binding
+	SearchPrev    key.Binding
+	ClearFilter   key.Binding
+	Create        key.Binding
+	Delete        key.Binding
+	DeleteAll     key.Binding
+	DeleteOrphans key.Binding
+	Snapshot      key.Binding
+	Snapshots     key.Binding
+	Info          key.Binding
+	Select        key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	Top           key.Binding
+	Bottom        key.Binding
+	PageDown      key.Binding
+	PageUp        key.Binding
+}
+
+// defaultKeys returns the built-in key bindings
+func defaultKeys() keyMap {
+	return keyMap{
+		Stop: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "Stop the active subscription"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "Filter subscriptions by regex"),
+		),
+		PrefixFilter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "Filter subscriptions by literal name prefix"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "Search: highlight matches, keep all listed"),
+		),
+		SearchNext: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "Next search match (while searching)"),
+		),
+		SearchPrev: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "Previous search match"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "Clear the topic filter"),
+		),
+		Create: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "Create new subscription (optional message filter)"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "Delete subscription (GCP: type its name to confirm)"),
+		),
+		DeleteAll: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "Delete all displayed (filtered) subscriptions"),
+		),
+		DeleteOrphans: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "Delete all orphaned subscriptions (topic deleted)"),
+		),
+		Snapshot: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "Snapshot selected subscription"),
+		),
+		Snapshots: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "Browse snapshots (enter: seek selected sub, d: del)"),
+		),
+		Info: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "Show details: delivery, filter, expiration policy"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "Start/stop subscription in subscriber panel"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "Move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "Move down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("gg", "Jump to the first item"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "Jump to the last item"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "Page down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("ctrl+b"),
+			key.WithHelp("ctrl+b", "Page up"),
+		),
+	}
+}
+
+// keys are the active key bindings: the defaults with any overrides from the
+// key config file
+var keys = defaultKeys()
+
+// SetKeyOverrides replaces the panel's key bindings with the defaults plus
+// overrides, keyed by action name (see OverrideKeys). It returns the actions
+// that match no binding; on error the bindings are unchanged.
+func SetKeyOverrides(overrides map[string][]string) ([]string, error) {
+	km := defaultKeys()
+	unknown, err := common.OverrideKeys(&km, overrides)
+	if err != nil {
+		return nil, err
+	}
+	keys = km
+	return unknown, nil
 }
 
-var keys = keyMap{
-	Stop: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "stop"),
-	),
-	Filter: key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "filter"),
-	),
-	ClearFilter: key.NewBinding(
-		key.WithKeys("c"),
-		key.WithHelp("c", "clear topic filter"),
-	),
-	Create: key.NewBinding(
-		key.WithKeys("n"),
-		key.WithHelp("n", "new"),
-	),
-	Delete: key.NewBinding(
-		key.WithKeys("d"),
-		key.WithHelp("d", "delete"),
-	),
-	Select: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
+// Binding returns the panel's key binding for an action name, as used in
+// the key config file
+func Binding(name string) (key.Binding, bool) {
+	return common.FindKey(keys, name)
 }
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/key"
 )
 
 // View renders the subscriptions panel
@@ -22,6 +24,14 @@ func (m Model) View() string {
 		} else {
 			title = fmt.Sprintf("2 Subscriptions (%d)", total)
 		}
+		if orphans := len(m.OrphanedNames()); orphans > 0 {
+			title += fmt.Sprintf(" ⚠ %d orphaned", orphans)
+		}
+	}
+
+	// Snapshot browser replaces the list
+	if m.IsBrowsingSnapshots() {
+		return m.snapshotsPanel()
 	}
 
 	// Topic filter indicator
@@ -37,7 +47,11 @@ func (m Model) View() string {
 	}
 
 	// Main content area
-	if m.loading {
+	if m.regexHelp.Visible() {
+		content.WriteString(common.RenderRegexHelp(m.width - 4))
+	} else if m.showInfo && !m.loading && m.loadError == nil {
+		content.WriteString(m.renderInfo(m.width - 4))
+	} else if m.loading {
 		content.WriteString(m.spinner.View())
 		content.WriteString(" ")
 		content.WriteString(common.LogNetworkStyle.Render("Loading subscriptions..."))
@@ -62,15 +76,60 @@ func (m Model) View() string {
 			content.WriteString(common.FilterErrorStyle.Render("(invalid regex)"))
 		}
 
+	case ModeSearch:
+		content.WriteString(m.search.Input.View())
+		if m.search.Err() != nil {
+			content.WriteString(" ")
+			content.WriteString(common.FilterErrorStyle.Render("(invalid regex)"))
+		}
+
 	case ModeCreate:
 		content.WriteString(m.createInput.View())
 		content.WriteString("\n")
 		content.WriteString(common.MutedText.Render(fmt.Sprintf("Creating for topic: %s", m.selectedTopic)))
 
+	case ModeCreateFilter:
+		content.WriteString(m.createFilterInput.View())
+		content.WriteString("\n")
+		content.WriteString(common.MutedText.Render(fmt.Sprintf("Creating '%s' (Enter to skip filter)", m.pendingCreate)))
+
 	case ModeConfirmDelete:
 		if sub := m.SelectedSubscription(); sub != nil {
-			content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete '%s'? (y/n)", sub.Name)))
+			if m.emulator {
+				content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete '%s'? (y/n)", sub.Name)))
+				break
+			}
+			content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("Delete '%s' from GCP?", sub.Name)))
+			content.WriteString("\n")
+			content.WriteString(m.confirmInput.View())
+			if m.confirmMismatch {
+				content.WriteString(" ")
+				content.WriteString(common.FilterErrorStyle.Render("(name does not match)"))
+			}
+		}
+
+	case ModeConfirmBulkDelete:
+		which := "displayed"
+		if m.bulkOrphans {
+			which = "orphaned"
+		}
+		count := len(m.bulkDeleteNames())
+		if m.emulator {
+			content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete all %d %s subscriptions? (y/n)", count, which)))
+			break
 		}
+		content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("Delete all %d %s subscriptions from GCP?", count, which)))
+		content.WriteString("\n")
+		content.WriteString(m.confirmInput.View())
+		if m.confirmMismatch {
+			content.WriteString(" ")
+			content.WriteString(common.FilterErrorStyle.Render("(does not match)"))
+		}
+
+	case ModeCreateSnapshot:
+		content.WriteString(m.snapshotInput.View())
+		content.WriteString("\n")
+		content.WriteString(common.MutedText.Render(fmt.Sprintf("Snapshot of: %s", m.snapshotSource)))
 
 	default:
 		// Show status or active filter
@@ -80,30 +139,112 @@ func (m Model) View() string {
 				style = common.LogErrorStyle
 			}
 			content.WriteString(style.Render(m.statusMsg))
-		} else if m.filterText != "" {
-			filterDisplay := common.FilterPromptStyle.Render("/ ") +
-				common.FilterInputStyle.Render(m.filterText)
-			content.WriteString(filterDisplay)
+		} else {
+			var active []string
+			if m.filterText != "" {
+				active = append(active, common.FilterPromptStyle.Render(m.filterInput.Prompt)+
+					common.FilterInputStyle.Render(m.filterText))
+			}
+			if m.search.Text() != "" {
+				active = append(active, common.FilterPromptStyle.Render(m.search.Input.Prompt)+
+					common.SearchMatchText.Render(m.search.Text()))
+			}
+			content.WriteString(strings.Join(active, "  "))
 		}
 	}
 
 	return common.BorderedPanel(title, content.String(), m.focused, m.width, m.height)
 }
 
+// snapshotsPanel renders the panel while the snapshot browser is open
+func (m Model) snapshotsPanel() string {
+	var content strings.Builder
+
+	title := "2 Snapshots"
+	if !m.snapshotsLoading && m.snapshotsErr == nil {
+		title = fmt.Sprintf("2 Snapshots (%d)", len(m.snapshots))
+	}
+
+	if m.seekTarget != nil {
+		content.WriteString(common.FilterPromptStyle.Render("Seek: ") +
+			common.BrightText.Render(m.seekTarget.Name) +
+			common.MutedText.Render(" (enter on a snapshot of "+m.seekTarget.TopicName+")"))
+	} else {
+		content.WriteString(common.MutedText.Render("All snapshots (esc to close)"))
+	}
+	content.WriteString("\n")
+
+	// Same space as the subscription list
+	height := m.height - 5
+	if height < 1 {
+		height = 1
+	}
+	content.WriteString(m.snapshotsView(height))
+	content.WriteString("\n")
+
+	snap := m.SelectedSnapshot()
+	if m.mode == ModeConfirmDeleteSnapshot && snap != nil && m.emulator {
+		content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete snapshot '%s'? (y/n)", snap.Name)))
+	} else if m.mode == ModeConfirmDeleteSnapshot && snap != nil {
+		content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("Delete snapshot '%s' from GCP?", snap.Name)))
+		content.WriteString("\n")
+		content.WriteString(m.confirmInput.View())
+		if m.confirmMismatch {
+			content.WriteString(" ")
+			content.WriteString(common.FilterErrorStyle.Render("(name does not match)"))
+		}
+	} else if m.mode == ModeConfirmSeek && snap != nil && m.seekTarget != nil {
+		content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Seek '%s' to '%s'? Later acks are undone (y/n)", m.seekTarget.Name, snap.Name)))
+	} else if m.statusMsg != "" {
+		style := common.LogSuccessStyle
+		if m.statusError {
+			style = common.LogErrorStyle
+		}
+		content.WriteString(style.Render(m.statusMsg))
+	}
+
+	return common.BorderedPanel(title, content.String(), m.focused, m.width, m.height)
+}
+
+// FullHelp returns every key binding of the panel for the help overlay
+func (m Model) FullHelp() []key.Binding {
+	return common.KeyBindings(keys)
+}
+
 // ShortHelp returns key bindings for the help display
 func (m Model) ShortHelp() []string {
 	switch m.mode {
 	case ModeFilter:
 		return []string{"esc: clear", "enter: apply"}
+	case ModeSearch:
+		return []string{"esc: cancel", "enter: keep highlights"}
 	case ModeCreate:
+		return []string{"enter: next", "esc: cancel"}
+	case ModeCreateFilter:
 		return []string{"enter: create", "esc: cancel"}
-	case ModeConfirmDelete:
+	case ModeConfirmDelete, ModeConfirmBulkDelete, ModeConfirmDeleteSnapshot:
+		if !m.emulator {
+			return []string{"enter: delete", "esc: cancel"}
+		}
+		return []string{"y: yes", "n: no"}
+	case ModeConfirmSeek:
 		return []string{"y: yes", "n: no"}
+	case ModeCreateSnapshot:
+		return []string{"enter: create", "esc: cancel"}
+	case ModeSnapshots:
+		return []string{"enter: seek", "d: delete", "S: reload", "esc: close"}
 	default:
-		help := []string{"/: filter", "n: new", "d: delete", "enter: select"}
+		help := []string{"/: search", "F: filter", "n: new", "d: delete", "D: delete all", "s: snapshot", "S: snapshots", "i: details", "enter: select"}
+		if m.IsSearching() {
+			// n moves between matches until the search is cleared
+			help[2] = "n/N: next/prev match"
+		}
 		if m.selectedTopic != "" {
 			help = append(help, "c: clear topic")
 		}
+		if len(m.OrphanedNames()) > 0 {
+			help = append(help, "O: delete orphaned")
+		}
 		return help
 	}
 }
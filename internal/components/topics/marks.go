@@ -0,0 +1,68 @@
+package topics
+
+import (
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MarkedTopics returns the topics marked for multi-topic publish, in list
+// order
+func (m Model) MarkedTopics() []string {
+	var names []string
+	for _, topic := range m.allTopics {
+		if m.marked[topic.Name] {
+			names = append(names, topic.Name)
+		}
+	}
+	return names
+}
+
+// toggleMark marks or unmarks the selected topic for multi-topic publish
+func (m *Model) toggleMark() tea.Cmd {
+	topic := m.SelectedTopic()
+	if topic == nil {
+		return nil
+	}
+	if m.marked[topic.Name] {
+		delete(m.marked, topic.Name)
+	} else {
+		if m.marked == nil {
+			m.marked = make(map[string]bool)
+		}
+		m.marked[topic.Name] = true
+	}
+	m.applyFilter()
+	return m.marksChanged()
+}
+
+// clearMarks unmarks every topic
+func (m *Model) clearMarks() tea.Cmd {
+	if len(m.marked) == 0 {
+		return nil
+	}
+	m.marked = nil
+	m.applyFilter()
+	return m.marksChanged()
+}
+
+// pruneMarks drops the marks of topics that no longer exist
+func (m *Model) pruneMarks() {
+	exists := make(map[string]bool, len(m.allTopics))
+	for _, topic := range m.allTopics {
+		exists[topic.Name] = true
+	}
+	for name := range m.marked {
+		if !exists[name] {
+			delete(m.marked, name)
+		}
+	}
+}
+
+// marksChanged returns a command reporting the marked topics
+func (m Model) marksChanged() tea.Cmd {
+	names := m.MarkedTopics()
+	return func() tea.Msg {
+		return common.TopicsMarkedMsg{Topics: names}
+	}
+}
@@ -1,6 +1,9 @@
 package topics
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/anmaso/pubsub-tui/internal/components/common"
 	"github.com/anmaso/pubsub-tui/internal/utils"
 
@@ -18,6 +21,8 @@ const (
 	ModeFilter
 	ModeCreate
 	ModeConfirmDelete
+	ModeConfirmBulkDelete
+	ModeSearch
 )
 
 // TopicItem implements list.Item for displaying topics
@@ -25,23 +30,46 @@ type TopicItem struct {
 	name     string
 	fullName string
 	selected bool // Whether this topic is currently selected
+	marked   bool // Whether this topic is marked for multi-topic publish
+	match    bool // Whether this topic matches the search
+
+	tree     bool // Shown in the tree view
+	children int  // Subscriptions nested under the topic in the tree view
+	expanded bool // Whether the nested subscriptions are shown
 }
 
 func (t TopicItem) Title() string {
 	prefix := "  "
-	if t.selected {
+	switch {
+	case t.selected && t.marked:
+		prefix = "⊕ "
+	case t.selected:
 		prefix = "● "
+	case t.marked:
+		prefix = "+ "
+	}
+	if t.tree {
+		switch {
+		case t.children == 0:
+			prefix += "  "
+		case t.expanded:
+			prefix += "▾ "
+		default:
+			prefix += "▸ "
+		}
 	}
 	return prefix + t.name
 }
 func (t TopicItem) Description() string { return "" }
 func (t TopicItem) FilterValue() string { return t.name }
+func (t TopicItem) SearchMatch() bool   { return t.match }
 
 // Model represents the state of the topics panel
 type Model struct {
 	list          list.Model
 	filterInput   textinput.Model
 	createInput   textinput.Model
+	confirmInput  textinput.Model
 	spinner       spinner.Model
 	allTopics     []common.TopicData // All topics from GCP
 	width         int
@@ -50,11 +78,35 @@ type Model struct {
 	mode          Mode
 	filterText    string
 	filterError   error
+	filterHistory common.FilterHistory // Recently applied regex filters
+	prefixHistory common.FilterHistory // Recently applied name prefixes
+	regexHelp     common.RegexHelp     // Regex examples opened from the filter
+	filterCache   utils.FilterCache    // Compiled filterText
+	debounce      common.Debouncer     // Delays filtering while typing
+	filterPrefix  bool                 // Whether filterText is a literal name prefix
 	loading       bool
 	loadError     error
 	statusMsg     string
 	statusError   bool
-	selectedTopic string // Currently selected topic
+	statusExpiry  time.Time       // When the status clears itself; zero keeps it
+	selectedTopic string          // Currently selected topic
+	pendingG      common.PendingG // First g of gg was pressed
+
+	// Search highlights matches without hiding the other topics
+	search common.Search
+
+	subscriptionCounts map[string]int  // Attached subscriptions per topic name
+	marked             map[string]bool // Topics marked for multi-topic publish
+
+	// Tree view nests each topic's subscriptions under it
+	tree               bool
+	subscriptions      map[string][]common.SubscriptionData // By topic name
+	collapsed          map[string]bool                      // Topics whose subscriptions are hidden
+	activeSubscription string                               // Streaming subscription
+
+	emulator        bool // Connected to the emulator: deletes confirm with y/n
+	confirmMismatch bool // Typed confirmation did not match the topic name
+	bulkWithSubs    bool // Typed bulk delete also deletes attached subscriptions
 }
 
 // New creates a new topics panel model
@@ -66,7 +118,7 @@ func New() Model {
 	delegate.Styles.SelectedTitle = common.SelectedItem
 	delegate.Styles.NormalTitle = common.NormalText
 
-	l := list.New([]list.Item{}, delegate, 0, 0)
+	l := list.New([]list.Item{}, common.SearchDelegate{DefaultDelegate: delegate}, 0, 0)
 	l.Title = "Topics"
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
@@ -76,8 +128,8 @@ func New() Model {
 
 	// Create filter input
 	fi := textinput.New()
-	fi.Placeholder = "regex filter..."
-	fi.Prompt = "/ "
+	fi.Placeholder = "regex filter (? for examples)..."
+	fi.Prompt = "filter: "
 	fi.PromptStyle = common.FilterPromptStyle
 	fi.TextStyle = common.FilterInputStyle
 
@@ -89,18 +141,27 @@ func New() Model {
 	ci.TextStyle = common.FilterInputStyle
 	ci.CharLimit = 255
 
+	// Create delete confirmation input (real GCP only)
+	dci := textinput.New()
+	dci.PromptStyle = common.FilterPromptStyle
+	dci.TextStyle = common.FilterInputStyle
+	dci.CharLimit = 255
+
 	// Create spinner
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = common.LogNetworkStyle // Blue color for network activity
 
 	return Model{
-		list:        l,
-		filterInput: fi,
-		createInput: ci,
-		spinner:     sp,
-		loading:     true,
-		mode:        ModeNormal,
+		list:         l,
+		filterInput:  fi,
+		search:       common.NewSearch(),
+		debounce:     common.NewDebouncer("topics", common.FilterDebounceDelay),
+		createInput:  ci,
+		confirmInput: dci,
+		spinner:      sp,
+		loading:      true,
+		mode:         ModeNormal,
 	}
 }
 
@@ -111,7 +172,41 @@ func (m *Model) SetFocused(focused bool) {
 		// Reset to normal mode when losing focus
 		m.mode = ModeNormal
 		m.filterInput.Blur()
+		m.search.Input.Blur()
 		m.createInput.Blur()
+		m.confirmInput.Blur()
+	}
+}
+
+// SetEmulatorMode sets whether the panel is connected to the emulator.
+// Against real GCP, deleting a topic requires typing its name, and deleting
+// all displayed topics typing "delete N".
+func (m *Model) SetEmulatorMode(emulator bool) {
+	m.emulator = emulator
+}
+
+// startConfirmDelete enters delete confirmation mode for the selection
+func (m *Model) startConfirmDelete() {
+	m.mode = ModeConfirmDelete
+	m.startTypedConfirm("Type the name to confirm: ")
+}
+
+// startConfirmBulkDelete enters confirmation mode for deleting every
+// displayed topic
+func (m *Model) startConfirmBulkDelete() {
+	m.mode = ModeConfirmBulkDelete
+	m.bulkWithSubs = false
+	m.startTypedConfirm(fmt.Sprintf("Type '%s' to confirm: ", common.BulkDeletePhrase(len(m.DisplayedNames()))))
+}
+
+// startTypedConfirm clears and focuses the confirmation input. With the
+// emulator, deletes confirm with y/n instead.
+func (m *Model) startTypedConfirm(prompt string) {
+	m.confirmMismatch = false
+	if !m.emulator {
+		m.confirmInput.Prompt = prompt
+		m.confirmInput.SetValue("")
+		m.confirmInput.Focus()
 	}
 }
 
@@ -139,6 +234,7 @@ func (m *Model) SetTopics(topics []common.TopicData) {
 	m.allTopics = topics
 	m.loading = false
 	m.loadError = nil
+	m.pruneMarks()
 	m.applyFilter()
 }
 
@@ -184,12 +280,29 @@ func (m Model) GetMode() Mode {
 func (m *Model) SetStatus(msg string, isError bool) {
 	m.statusMsg = msg
 	m.statusError = isError
+	m.statusExpiry = common.StatusExpiry(isError, time.Now())
+}
+
+// SetProgress sets a status message for work in progress. It does not
+// expire, since the result replaces it.
+func (m *Model) SetProgress(msg string) {
+	m.statusMsg = msg
+	m.statusError = false
+	m.statusExpiry = time.Time{}
 }
 
 // ClearStatus clears the status message
 func (m *Model) ClearStatus() {
 	m.statusMsg = ""
 	m.statusError = false
+	m.statusExpiry = time.Time{}
+}
+
+// ExpireStatus clears the status message once it has been shown long enough
+func (m *Model) ExpireStatus(now time.Time) {
+	if common.StatusExpired(m.statusExpiry, now) {
+		m.ClearStatus()
+	}
 }
 
 // SetSelectedTopic sets the currently selected topic
@@ -203,9 +316,30 @@ func (m Model) GetSelectedTopic() string {
 	return m.selectedTopic
 }
 
+// DisplayedNames returns the names of the currently displayed topics
+func (m Model) DisplayedNames() []string {
+	var names []string
+	for _, item := range m.list.Items() {
+		if topic, ok := item.(TopicItem); ok {
+			names = append(names, topic.name)
+		}
+	}
+	return names
+}
+
+// attachedSubscriptions returns how many subscriptions are attached to the given topics
+func (m Model) attachedSubscriptions(topicNames []string) int {
+	count := 0
+	for _, name := range topicNames {
+		count += m.subscriptionCounts[name]
+	}
+	return count
+}
+
 // IsInputActive returns whether an input field is active
 func (m Model) IsInputActive() bool {
-	return m.mode == ModeFilter || m.mode == ModeCreate
+	return m.mode == ModeFilter || m.mode == ModeSearch || m.mode == ModeCreate ||
+		((m.mode == ModeConfirmDelete || m.mode == ModeConfirmBulkDelete) && !m.emulator)
 }
 
 // SpinnerTickCmd returns the spinner tick command
@@ -215,55 +349,80 @@ func (m Model) SpinnerTickCmd() tea.Cmd {
 
 // applyFilter filters the topics based on current filter text
 func (m *Model) applyFilter() {
-	var items []list.Item
+	items := make([]list.Item, 0, len(m.allTopics))
+	filter := m.filterCache.Get(m.filterPattern())
 
 	for _, topic := range m.allTopics {
 		// If no filter, include all
 		if m.filterText == "" {
-			items = append(items, TopicItem{
-				name:     topic.Name,
-				fullName: topic.FullName,
-				selected: m.selectedTopic == topic.Name,
-			})
+			items = append(items, m.treeItems(topic)...)
 			continue
 		}
 
 		// Apply regex filter
-		result := matchFilter(topic.Name, m.filterText)
-		if result.err != nil {
-			m.filterError = result.err
+		result := filter.Match(topic.Name)
+		if result.Error != nil {
+			m.filterError = result.Error
 			// On error, show all topics
-			items = append(items, TopicItem{
-				name:     topic.Name,
-				fullName: topic.FullName,
-				selected: m.selectedTopic == topic.Name,
-			})
-		} else if result.matches {
+			items = append(items, m.treeItems(topic)...)
+		} else if result.Matches {
 			m.filterError = nil
-			items = append(items, TopicItem{
-				name:     topic.Name,
-				fullName: topic.FullName,
-				selected: m.selectedTopic == topic.Name,
-			})
+			items = append(items, m.treeItems(topic)...)
 		}
 	}
 
 	m.list.SetItems(items)
 }
 
-// filterResult holds the result of a filter operation
-type filterResult struct {
-	matches bool
-	err     error
+// newItem builds a list item for a topic
+func (m *Model) newItem(topic common.TopicData) TopicItem {
+	return TopicItem{
+		name:     topic.Name,
+		fullName: topic.FullName,
+		selected: m.selectedTopic == topic.Name,
+		marked:   m.marked[topic.Name],
+		match:    m.search.Match(topic.Name),
+		tree:     m.tree,
+		children: len(m.subscriptions[topic.Name]),
+		expanded: !m.collapsed[topic.Name],
+	}
+}
+
+// startFilter enters filter mode, treating input as a literal name prefix or
+// as a regex. Switching between the two clears the previous filter.
+func (m *Model) startFilter(prefix bool) {
+	if prefix != m.filterPrefix && m.filterText != "" {
+		m.filterText = ""
+		m.filterInput.SetValue("")
+		m.filterError = nil
+		m.applyFilter()
+	}
+
+	m.filterPrefix = prefix
+	if prefix {
+		m.filterInput.Prompt = "prefix: "
+		m.filterInput.Placeholder = "name prefix..."
+	} else {
+		m.filterInput.Prompt = "/ "
+		m.filterInput.Placeholder = "regex filter (? for examples)..."
+	}
+	m.mode = ModeFilter
+	m.filterInput.Focus()
 }
 
-// matchFilter checks if text matches the regex pattern
-func matchFilter(text, pattern string) filterResult {
-	if pattern == "" {
-		return filterResult{matches: true}
+// filterPattern returns the regex for the current filter
+func (m Model) filterPattern() string {
+	if m.filterPrefix {
+		return utils.PrefixPattern(m.filterText)
 	}
+	return m.filterText
+}
 
-	// Use our utils package
-	result := utils.MatchesFilter(text, pattern)
-	return filterResult{matches: result.Matches, err: result.Error}
+// history returns the filter history of the current filter mode, so prefixes
+// and regexes are recalled separately
+func (m *Model) history() *common.FilterHistory {
+	if m.filterPrefix {
+		return &m.prefixHistory
+	}
+	return &m.filterHistory
 }
@@ -0,0 +1,34 @@
+package topics
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// startSearch opens the search prompt. Unlike the filter, a search keeps
+// every topic listed and highlights the matches.
+func (m *Model) startSearch() {
+	m.search.Start(m.list.Index())
+	m.mode = ModeSearch
+}
+
+// IsSearching returns whether a search is highlighting matches
+func (m Model) IsSearching() bool {
+	return m.search.Active()
+}
+
+// nextMatch moves the selection to the next or previous match
+func (m *Model) nextMatch(backward bool) {
+	if status, isError := m.search.Next(&m.list, backward, "topics"); status != "" {
+		m.SetStatus(status, isError)
+	}
+}
+
+// handleSearchInput handles keyboard input in search mode
+func (m Model) handleSearchInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	closed, noMatch, cmd := m.search.Update(msg, &m.list, m.applyFilter)
+	if closed {
+		m.mode = ModeNormal
+	}
+	if noMatch {
+		m.SetStatus("No topics match: "+m.search.Text(), true)
+	}
+	return m, cmd
+}
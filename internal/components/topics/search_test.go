@@ -0,0 +1,47 @@
+package topics
+
+import (
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_SearchNextAndCreate(t *testing.T) {
+	m := New()
+	m.SetSize(60, 20)
+	m.SetTopics([]common.TopicData{{Name: "billing"}, {Name: "orders"}, {Name: "orders-dlq"}})
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	m, _ = m.Update(runes("/"))
+	m, _ = m.Update(runes("orders"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.IsSearching() {
+		t.Fatal("Enter should keep the search")
+	}
+
+	// n moves between matches while the search is kept
+	m, _ = m.Update(runes("n"))
+	if topic := m.SelectedTopic(); topic == nil || topic.Name != "orders-dlq" {
+		t.Errorf("n: selected %+v, want orders-dlq", topic)
+	}
+	if m.mode != ModeNormal {
+		t.Errorf("n while searching: mode = %v, want ModeNormal", m.mode)
+	}
+
+	// Once the search is cleared, n creates a topic again
+	m, _ = m.Update(runes("/"))
+	m.search.Input.SetValue("")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsSearching() {
+		t.Fatal("an empty search should clear it")
+	}
+	m, _ = m.Update(runes("n"))
+	if m.mode != ModeCreate {
+		t.Errorf("n without a search: mode = %v, want ModeCreate", m.mode)
+	}
+}
@@ -0,0 +1,155 @@
+package topics
+
+import (
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SubscriptionNode implements list.Item for a subscription nested under its
+// topic in the tree view
+type SubscriptionNode struct {
+	sub    common.SubscriptionData
+	last   bool // Whether this is the topic's last subscription
+	active bool // Whether this subscription is streaming
+	match  bool // Whether this subscription matches the search
+}
+
+func (s SubscriptionNode) Title() string {
+	branch := "├─ "
+	if s.last {
+		branch = "└─ "
+	}
+	if s.active {
+		branch += "● "
+	}
+	return "    " + branch + s.sub.Name
+}
+func (s SubscriptionNode) Description() string { return "" }
+func (s SubscriptionNode) FilterValue() string { return s.sub.Name }
+func (s SubscriptionNode) SearchMatch() bool   { return s.match }
+
+// SetSubscriptions sets the subscriptions nested under each topic in the tree
+// view and counted for bulk deletes
+func (m *Model) SetSubscriptions(subs []common.SubscriptionData) {
+	m.subscriptions = make(map[string][]common.SubscriptionData)
+	m.subscriptionCounts = make(map[string]int)
+	for _, sub := range subs {
+		if sub.TopicName == "" {
+			continue
+		}
+		m.subscriptions[sub.TopicName] = append(m.subscriptions[sub.TopicName], sub)
+		m.subscriptionCounts[sub.TopicName]++
+	}
+	if m.tree {
+		m.applyFilter()
+	}
+}
+
+// SetActiveSubscription sets the streaming subscription, marked in the tree
+// view
+func (m *Model) SetActiveSubscription(name string) {
+	m.activeSubscription = name
+	if m.tree {
+		m.applyFilter()
+	}
+}
+
+// IsTreeView returns whether subscriptions are nested under their topics
+func (m Model) IsTreeView() bool {
+	return m.tree
+}
+
+// toggleTree switches between the topic list and the tree view, keeping the
+// selected topic
+func (m *Model) toggleTree() {
+	topic := m.selectedTopicName()
+	m.tree = !m.tree
+	m.applyFilter()
+	m.selectTopic(topic)
+}
+
+// setExpanded shows or hides a topic's subscriptions in the tree view. On a
+// subscription, collapsing hides its siblings and selects the topic.
+func (m *Model) setExpanded(expanded bool) {
+	topic := m.selectedTopicName()
+	if topic == "" || (expanded && len(m.subscriptions[topic]) == 0) {
+		return
+	}
+	if expanded {
+		delete(m.collapsed, topic)
+	} else {
+		if m.collapsed == nil {
+			m.collapsed = make(map[string]bool)
+		}
+		m.collapsed[topic] = true
+	}
+	m.applyFilter()
+	if !expanded {
+		m.selectTopic(topic)
+	}
+}
+
+// treeItems returns the list items for a topic: the topic itself, followed by
+// its subscriptions in the tree view unless the topic is collapsed
+func (m *Model) treeItems(topic common.TopicData) []list.Item {
+	items := []list.Item{m.newItem(topic)}
+	if !m.tree || m.collapsed[topic.Name] {
+		return items
+	}
+	subs := m.subscriptions[topic.Name]
+	for i, sub := range subs {
+		items = append(items, SubscriptionNode{
+			sub:    sub,
+			last:   i == len(subs)-1,
+			active: sub.Name == m.activeSubscription,
+			match:  m.search.Match(sub.Name),
+		})
+	}
+	return items
+}
+
+// selectedTopicName returns the selected topic, or in the tree view the topic
+// of the selected subscription
+func (m Model) selectedTopicName() string {
+	switch item := m.list.SelectedItem().(type) {
+	case TopicItem:
+		return item.name
+	case SubscriptionNode:
+		return item.sub.TopicName
+	}
+	return ""
+}
+
+// selectTopic moves the selection to the named topic, if it is listed
+func (m *Model) selectTopic(name string) {
+	for i, item := range m.list.Items() {
+		if topic, ok := item.(TopicItem); ok && topic.name == name {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// selectedSubscription returns the subscription selected in the tree view,
+// if any
+func (m Model) selectedSubscription() *common.SubscriptionData {
+	node, ok := m.list.SelectedItem().(SubscriptionNode)
+	if !ok {
+		return nil
+	}
+	return &node.sub
+}
+
+// selectSubscription returns a command that starts streaming the
+// subscription, as selecting it in the subscriptions panel does
+func selectSubscription(sub common.SubscriptionData) tea.Cmd {
+	return func() tea.Msg {
+		return common.SubscriptionSelectedMsg{
+			SubscriptionName: sub.Name,
+			SubscriptionFull: sub.FullName,
+			TopicName:        sub.TopicName,
+		}
+	}
+}
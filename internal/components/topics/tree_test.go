@@ -0,0 +1,105 @@
+package topics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_Tree(t *testing.T) {
+	m := New()
+	m.SetSize(60, 20)
+	m.SetTopics([]common.TopicData{{Name: "billing"}, {Name: "orders", FullName: "projects/p/topics/orders"}})
+	m.SetSubscriptions([]common.SubscriptionData{
+		{Name: "orders-audit", FullName: "projects/p/subscriptions/orders-audit", TopicName: "orders"},
+		{Name: "orders-sub", TopicName: "orders"},
+		{Name: "unknown-topic"},
+	})
+	key := func(s string) tea.KeyMsg {
+		switch s {
+		case "enter":
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		case "left":
+			return tea.KeyMsg{Type: tea.KeyLeft}
+		case "right":
+			return tea.KeyMsg{Type: tea.KeyRight}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	titles := func() string {
+		var lines []string
+		for _, item := range m.list.Items() {
+			lines = append(lines, strings.TrimSpace(item.(interface{ Title() string }).Title()))
+		}
+		return strings.Join(lines, "|")
+	}
+
+	// t nests subscriptions under their topics, keeping the selection
+	m, _ = m.Update(key("j"))
+	m, _ = m.Update(key("t"))
+	if !m.IsTreeView() {
+		t.Fatal("t should switch to the tree view")
+	}
+	if got, want := titles(), "billing|▾ orders|├─ orders-audit|└─ orders-sub"; got != want {
+		t.Fatalf("tree = %q, want %q", got, want)
+	}
+	if topic := m.SelectedTopic(); topic == nil || topic.Name != "orders" {
+		t.Fatalf("SelectedTopic() = %+v, want orders", topic)
+	}
+
+	// Enter on a topic sets the publish target
+	_, cmd := m.Update(key("enter"))
+	if msg, ok := cmd().(common.TopicSelectedMsg); !ok || msg.TopicName != "orders" {
+		t.Errorf("enter on a topic sent %#v, want TopicSelectedMsg", msg)
+	}
+
+	// Enter on a subscription starts streaming it
+	m, _ = m.Update(key("j"))
+	_, cmd = m.Update(key("enter"))
+	msg, ok := cmd().(common.SubscriptionSelectedMsg)
+	if !ok || msg.SubscriptionName != "orders-audit" || msg.SubscriptionFull != "projects/p/subscriptions/orders-audit" || msg.TopicName != "orders" {
+		t.Errorf("enter on a subscription sent %#v, want SubscriptionSelectedMsg", msg)
+	}
+	m.SetActiveSubscription("orders-audit")
+	if got := titles(); !strings.Contains(got, "├─ ● orders-audit") {
+		t.Errorf("tree = %q, want the streaming subscription marked", got)
+	}
+
+	// Collapsing from a subscription selects its topic
+	m, _ = m.Update(key("left"))
+	if got, want := titles(), "billing|▸ orders"; got != want {
+		t.Errorf("collapsed tree = %q, want %q", got, want)
+	}
+	if topic := m.SelectedTopic(); topic == nil || topic.Name != "orders" {
+		t.Errorf("SelectedTopic() = %+v, want orders", topic)
+	}
+	m, _ = m.Update(key("l"))
+	if got := titles(); !strings.Contains(got, "└─ orders-sub") {
+		t.Errorf("expanded tree = %q, want the subscriptions back", got)
+	}
+
+	// A topic without subscriptions has nothing to expand
+	m, _ = m.Update(key("k"))
+	m, _ = m.Update(key("right"))
+	if got := titles(); !strings.HasPrefix(got, "billing|") {
+		t.Errorf("tree = %q, want billing without children", got)
+	}
+
+	// A filtered title counts topics, not the subscriptions under them
+	m.filterText = "orders"
+	m.applyFilter()
+	if got := m.View(); !strings.Contains(got, "1 Topics (1/2)") {
+		t.Errorf("filtered tree title should count topics only, got:\n%s", got)
+	}
+	m.filterText = ""
+	m.applyFilter()
+
+	// Leaving the tree view lists only topics
+	m, _ = m.Update(key("t"))
+	if got, want := titles(), "billing|orders"; got != want {
+		t.Errorf("list = %q, want %q", got, want)
+	}
+}
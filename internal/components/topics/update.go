@@ -17,6 +17,13 @@ type DeleteTopicMsg struct {
 	TopicName string
 }
 
+// BulkDeleteTopicsMsg requests deletion of several topics, optionally
+// deleting their attached subscriptions first
+type BulkDeleteTopicsMsg struct {
+	TopicNames        []string
+	WithSubscriptions bool
+}
+
 // Update handles messages for the topics panel
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -26,14 +33,24 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		switch m.mode {
 		case ModeFilter:
 			return m.handleFilterInput(msg)
+		case ModeSearch:
+			return m.handleSearchInput(msg)
 		case ModeCreate:
 			return m.handleCreateInput(msg)
 		case ModeConfirmDelete:
 			return m.handleConfirmDelete(msg)
+		case ModeConfirmBulkDelete:
+			return m.handleConfirmBulkDelete(msg)
 		default:
 			return m.handleNavigation(msg)
 		}
 
+	case common.DebounceMsg:
+		if m.debounce.Due(msg) {
+			m.applyFilter()
+		}
+		return m, nil
+
 	case common.TopicsLoadedMsg:
 		if msg.Err != nil {
 			m.SetError(msg.Err)
@@ -88,6 +105,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // handleFilterInput handles keyboard input in filter mode
 func (m Model) handleFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Prefix filters are literal, so ? is just typed
+	if !m.filterPrefix && m.regexHelp.HandleKey(msg, m.filterInput.Value()) {
+		return m, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
 		// Exit filter mode and clear filter
@@ -96,25 +118,56 @@ func (m Model) handleFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.filterInput.SetValue("")
 		m.filterError = nil
 		m.filterInput.Blur()
+		m.history().Reset()
 		m.applyFilter()
 		return m, nil
 
 	case tea.KeyEnter:
-		// Exit filter mode but keep filter
+		// Exit filter mode but keep filter, applying it now if a
+		// debounced pass is still pending
 		m.mode = ModeNormal
 		m.filterInput.Blur()
+		m.history().Add(m.filterText)
+		m.debounce.Cancel()
+		m.applyFilter()
+		return m, nil
+
+	case tea.KeyUp:
+		// Recall an older filter pattern
+		if pattern, ok := m.history().Prev(m.filterInput.Value()); ok {
+			m.recallFilter(pattern)
+		}
+		return m, nil
+
+	case tea.KeyDown:
+		// Recall a newer filter pattern, or the pattern being typed
+		if pattern, ok := m.history().Next(); ok {
+			m.recallFilter(pattern)
+		}
 		return m, nil
 
 	default:
 		// Update filter input
 		var cmd tea.Cmd
 		m.filterInput, cmd = m.filterInput.Update(msg)
-		m.filterText = m.filterInput.Value()
-		m.applyFilter()
+		// Cursor movement doesn't change the pattern; skip re-filtering.
+		// Otherwise wait for typing to pause before filtering.
+		if value := m.filterInput.Value(); value != m.filterText {
+			m.filterText = value
+			return m, tea.Batch(cmd, m.debounce.Schedule())
+		}
 		return m, cmd
 	}
 }
 
+// recallFilter replaces the filter input with a pattern from history
+func (m *Model) recallFilter(pattern string) {
+	m.filterInput.SetValue(pattern)
+	m.filterInput.CursorEnd()
+	m.filterText = pattern
+	m.applyFilter()
+}
+
 // handleCreateInput handles keyboard input in create mode
 func (m Model) handleCreateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.Type {
@@ -150,6 +203,10 @@ func (m Model) handleCreateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 // handleConfirmDelete handles keyboard input in delete confirmation mode
 func (m Model) handleConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if !m.emulator {
+		return m.handleTypedConfirmDelete(msg)
+	}
+
 	switch msg.String() {
 	case "y", "Y":
 		// Confirm deletion
@@ -175,16 +232,139 @@ func (m Model) handleConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleTypedConfirmDelete handles keyboard input when deletion against real
+// GCP must be confirmed by typing the topic name
+func (m Model) handleTypedConfirmDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		m.confirmInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		topic := m.SelectedTopic()
+		if topic == nil {
+			m.mode = ModeNormal
+			m.confirmInput.Blur()
+			return m, nil
+		}
+		if m.confirmInput.Value() != topic.Name {
+			m.confirmMismatch = true
+			return m, nil
+		}
+
+		name := topic.Name
+		m.mode = ModeNormal
+		m.confirmInput.Blur()
+		return m, func() tea.Msg {
+			return DeleteTopicMsg{TopicName: name}
+		}
+
+	default:
+		m.confirmMismatch = false
+		var cmd tea.Cmd
+		m.confirmInput, cmd = m.confirmInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleConfirmBulkDelete handles keyboard input when confirming deletion
+// of all displayed topics. "s" also deletes attached subscriptions.
+func (m Model) handleConfirmBulkDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if !m.emulator {
+		return m.handleTypedConfirmBulkDelete(msg)
+	}
+
+	switch msg.String() {
+	case "y", "Y", "s", "S":
+		return m.confirmBulkDelete(msg.String() == "s" || msg.String() == "S")
+
+	case "n", "N", "esc":
+		m.mode = ModeNormal
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleTypedConfirmBulkDelete handles keyboard input when deleting all
+// displayed topics from real GCP must be confirmed by typing "delete N".
+// Tab toggles also deleting attached subscriptions.
+func (m Model) handleTypedConfirmBulkDelete(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		m.confirmInput.Blur()
+		return m, nil
+
+	case tea.KeyTab:
+		if m.attachedSubscriptions(m.DisplayedNames()) > 0 {
+			m.bulkWithSubs = !m.bulkWithSubs
+		}
+		return m, nil
+
+	case tea.KeyEnter:
+		if m.confirmInput.Value() != common.BulkDeletePhrase(len(m.DisplayedNames())) {
+			m.confirmMismatch = true
+			return m, nil
+		}
+		m.confirmInput.Blur()
+		return m.confirmBulkDelete(m.bulkWithSubs)
+
+	default:
+		m.confirmMismatch = false
+		var cmd tea.Cmd
+		m.confirmInput, cmd = m.confirmInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// confirmBulkDelete requests deleting every displayed topic, and with
+// withSubs their attached subscriptions
+func (m Model) confirmBulkDelete(withSubs bool) (Model, tea.Cmd) {
+	m.mode = ModeNormal
+	names := m.DisplayedNames()
+	if len(names) == 0 {
+		return m, nil
+	}
+
+	return m, func() tea.Msg {
+		return BulkDeleteTopicsMsg{TopicNames: names, WithSubscriptions: withSubs}
+	}
+}
+
 // handleNavigation handles keyboard input in normal navigation mode
 func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Clear status on any key
 	m.ClearStatus()
 
+	// A pending g completes gg or is dropped
+	if m.pendingG.Complete(msg, keys.Top) {
+		common.JumpList(&m.list, false)
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, keys.Filter):
-		// Enter filter mode
-		m.mode = ModeFilter
-		m.filterInput.Focus()
+		// Enter regex filter mode
+		m.startFilter(false)
+		return m, nil
+
+	case key.Matches(msg, keys.PrefixFilter):
+		// Enter literal prefix filter mode
+		m.startFilter(true)
+		return m, nil
+
+	case key.Matches(msg, keys.Search):
+		m.startSearch()
+		return m, nil
+
+	case m.IsSearching() && key.Matches(msg, keys.SearchNext):
+		m.nextMatch(false)
+		return m, nil
+
+	case m.IsSearching() && key.Matches(msg, keys.SearchPrev):
+		m.nextMatch(true)
 		return m, nil
 
 	case key.Matches(msg, keys.Create):
@@ -196,11 +376,22 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case key.Matches(msg, keys.Delete):
 		// Enter delete confirmation mode
 		if m.SelectedTopic() != nil {
-			m.mode = ModeConfirmDelete
+			m.startConfirmDelete()
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.DeleteAll):
+		// Confirm deletion of every displayed (filtered) topic
+		if len(m.list.Items()) > 0 {
+			m.startConfirmBulkDelete()
 		}
 		return m, nil
 
 	case key.Matches(msg, keys.Select):
+		// In the tree view, a subscription starts streaming
+		if sub := m.selectedSubscription(); sub != nil {
+			return m, selectSubscription(*sub)
+		}
 		// Select current topic
 		if topic := m.SelectedTopic(); topic != nil {
 			return m, func() tea.Msg {
@@ -212,6 +403,24 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, keys.Tree):
+		m.toggleTree()
+		return m, nil
+
+	case m.tree && key.Matches(msg, keys.Expand):
+		m.setExpanded(true)
+		return m, nil
+
+	case m.tree && key.Matches(msg, keys.Collapse):
+		m.setExpanded(false)
+		return m, nil
+
+	case key.Matches(msg, keys.Mark):
+		return m, m.toggleMark()
+
+	case key.Matches(msg, keys.ClearMarks):
+		return m, m.clearMarks()
+
 	case key.Matches(msg, keys.Up):
 		m.list.CursorUp()
 		return m, nil
@@ -220,6 +429,23 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.list.CursorDown()
 		return m, nil
 
+	case key.Matches(msg, keys.Top):
+		// The first g waits for a second
+		m.pendingG.Press()
+		return m, nil
+
+	case key.Matches(msg, keys.Bottom):
+		common.JumpList(&m.list, true)
+		return m, nil
+
+	case key.Matches(msg, keys.PageDown):
+		common.PageList(&m.list, 1)
+		return m, nil
+
+	case key.Matches(msg, keys.PageUp):
+		common.PageList(&m.list, -1)
+		return m, nil
+
 	default:
 		// Pass to list for handling
 		var cmd tea.Cmd
@@ -230,37 +456,133 @@ func (m Model) handleNavigation(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 // Key bindings
 type keyMap struct {
-	Filter key.Binding
-	Create key.Binding
-	Delete key.Binding
-	Select key.Binding
-	Up     key.Binding
-	Down   key.Binding
+	Filter       key.Binding
+	PrefixFilter key.Binding
+	Search       key.Binding
+	SearchNext   key.Binding
+	SearchPrev   key.Binding
+	Create       key.Binding
+	Delete       key.Binding
+	DeleteAll    key.Binding
+	Select       key.Binding
+	Tree         key.Binding
+	Expand       key.Binding
+	Collapse     key.Binding
+	Mark         key.Binding
+	ClearMarks   key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	PageDown     key.Binding
+	PageUp       key.Binding
+}
+
+// defaultKeys returns the built-in key bindings
+func defaultKeys() keyMap {
+	return keyMap{
+		Filter: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "Filter topics by regex"),
+		),
+		PrefixFilter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "Filter topics by literal name prefix"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "Search: highlight matches, keep all topics"),
+		),
+		SearchNext: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "Next search match (while searching)"),
+		),
+		SearchPrev: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "Previous search match"),
+		),
+		Create: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "Create new topic"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "Delete selected topic (GCP: type its name to confirm)"),
+		),
+		DeleteAll: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "Delete all displayed topics (s: with subscriptions)"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "Select topic for publisher (tree: stream subscription)"),
+		),
+		Tree: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "Toggle tree view: subscriptions under topics"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "Expand topic (tree view)"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "Collapse topic (tree view)"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "Mark topic to publish to several at once"),
+		),
+		ClearMarks: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "Clear all publish marks"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "Move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "Move down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("gg", "Jump to the first item"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "Jump to the last item"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "Page down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("ctrl+b"),
+			key.WithHelp("ctrl+b", "Page up"),
+		),
+	}
+}
+
+// keys are the active key bindings: the defaults with any overrides from the
+// key config file
+var keys = defaultKeys()
+
+// SetKeyOverrides replaces the panel's key bindings with the defaults plus
+// overrides, keyed by action name (see OverrideKeys). It returns the actions
+// that match no binding; on error the bindings are unchanged.
+func SetKeyOverrides(overrides map[string][]string) ([]string, error) {
+	km := defaultKeys()
+	unknown, err := common.OverrideKeys(&km, overrides)
+	if err != nil {
+		return nil, err
+	}
+	keys = km
+	return unknown, nil
 }
 
-var keys = keyMap{
-	Filter: key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "filter"),
-	),
-	Create: key.NewBinding(
-		key.WithKeys("n"),
-		key.WithHelp("n", "new"),
-	),
-	Delete: key.NewBinding(
-		key.WithKeys("d"),
-		key.WithHelp("d", "delete"),
-	),
-	Select: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
+// Binding returns the panel's key binding for an action name, as used in
+// the key config file
+func Binding(name string) (key.Binding, bool) {
+	return common.FindKey(keys, name)
 }
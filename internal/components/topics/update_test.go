@@ -0,0 +1,132 @@
+package topics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_BulkDeleteConfirm(t *testing.T) {
+	setup := func(emulator bool) Model {
+		m := New()
+		m.SetSize(80, 20)
+		m.SetEmulatorMode(emulator)
+		m.SetTopics([]common.TopicData{{Name: "billing"}, {Name: "orders"}})
+		m.SetSubscriptions([]common.SubscriptionData{{Name: "orders-sub", TopicName: "orders"}})
+		return m
+	}
+	bulkDelete := func(cmd tea.Cmd) *BulkDeleteTopicsMsg {
+		if cmd == nil {
+			return nil
+		}
+		msg, ok := cmd().(BulkDeleteTopicsMsg)
+		if !ok {
+			return nil
+		}
+		return &msg
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	t.Run("real GCP requires typing the count", func(t *testing.T) {
+		m := setup(false)
+		m, _ = m.Update(runes("D"))
+		if !m.IsInputActive() || !strings.Contains(m.View(), "Type 'delete 2' to confirm") {
+			t.Fatalf("D should ask to type delete 2:\n%s", m.View())
+		}
+
+		// "y" is just text here
+		m, _ = m.Update(runes("y"))
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if bulkDelete(cmd) != nil || m.mode != ModeConfirmBulkDelete || !m.confirmMismatch {
+			t.Fatal("y should not confirm a bulk delete from GCP")
+		}
+
+		// Tab also deletes the attached subscriptions
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		if !strings.Contains(m.View(), "Also deletes 1 subscriptions") {
+			t.Errorf("tab should include the subscriptions:\n%s", m.View())
+		}
+		m.confirmInput.SetValue("delete 2")
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msg := bulkDelete(cmd)
+		if msg == nil || strings.Join(msg.TopicNames, ",") != "billing,orders" || !msg.WithSubscriptions {
+			t.Errorf("typed confirmation sent %+v, want both topics with subscriptions", msg)
+		}
+		if m.mode != ModeNormal {
+			t.Error("confirmation should close after deleting")
+		}
+	})
+
+	t.Run("emulator confirms with y or s", func(t *testing.T) {
+		m := setup(true)
+		m, _ = m.Update(runes("D"))
+		if m.IsInputActive() {
+			t.Error("y/n confirmation should not capture input")
+		}
+		_, cmd := m.Update(runes("s"))
+		if msg := bulkDelete(cmd); msg == nil || !msg.WithSubscriptions {
+			t.Errorf("s sent %+v, want a bulk delete with subscriptions", msg)
+		}
+	})
+}
+
+func TestModel_ListNavigation(t *testing.T) {
+	m := New()
+	m.SetSize(100, 20)
+
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+	G := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}}
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		m, _ = m.Update(msg)
+	}
+
+	// Jumps on an empty list leave it alone
+	press(G)
+	press(g)
+	press(g)
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("G, gg on an empty list: Index() = %d, want 0", got)
+	}
+
+	topics := make([]common.TopicData, 50)
+	for i := range topics {
+		topics[i] = common.TopicData{Name: fmt.Sprintf("topic-%02d", i)}
+	}
+	m.SetTopics(topics)
+	perPage := m.list.Paginator.PerPage
+	if perPage < 2 || perPage >= 25 {
+		t.Fatalf("PerPage = %d, want a page well inside 50 items", perPage)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if got := m.list.Index(); got != perPage {
+		t.Errorf("ctrl+f: Index() = %d, want %d", got, perPage)
+	}
+	press(G)
+	if got := m.list.Index(); got != 49 {
+		t.Errorf("G: Index() = %d, want 49", got)
+	}
+
+	// A single g waits; another key drops it
+	press(g)
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if got := m.list.Index(); got != 48 {
+		t.Errorf("g, k: Index() = %d, want 48", got)
+	}
+	press(g)
+	press(g)
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("gg: Index() = %d, want 0", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("ctrl+b on the first item: Index() = %d, want 0", got)
+	}
+}
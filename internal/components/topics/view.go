@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/anmaso/pubsub-tui/internal/components/common"
+
+	"github.com/charmbracelet/bubbles/key"
 )
 
 // View renders the topics panel
@@ -15,14 +17,23 @@ func (m Model) View() string {
 	title := "1 Topics"
 	if len(m.allTopics) > 0 {
 		if m.filterText != "" {
-			title = fmt.Sprintf("1 Topics (%d/%d)", len(m.list.Items()), len(m.allTopics))
+			// Only topics count; the tree view also lists subscriptions
+			title = fmt.Sprintf("1 Topics (%d/%d)", len(m.DisplayedNames()), len(m.allTopics))
 		} else {
 			title = fmt.Sprintf("1 Topics (%d)", len(m.allTopics))
 		}
 	}
+	if len(m.marked) > 0 {
+		title += fmt.Sprintf(" +%d marked", len(m.marked))
+	}
+	if m.tree {
+		title += " · tree"
+	}
 
 	// Main content area
-	if m.loading {
+	if m.regexHelp.Visible() {
+		content.WriteString(common.RenderRegexHelp(m.width - 4))
+	} else if m.loading {
 		content.WriteString(m.spinner.View())
 		content.WriteString(" ")
 		content.WriteString(common.LogNetworkStyle.Render("Loading topics..."))
@@ -45,6 +56,13 @@ func (m Model) View() string {
 			content.WriteString(common.FilterErrorStyle.Render("(invalid regex)"))
 		}
 
+	case ModeSearch:
+		content.WriteString(m.search.Input.View())
+		if m.search.Err() != nil {
+			content.WriteString(" ")
+			content.WriteString(common.FilterErrorStyle.Render("(invalid regex)"))
+		}
+
 	case ModeCreate:
 		content.WriteString(m.createInput.View())
 		content.WriteString("\n")
@@ -52,7 +70,45 @@ func (m Model) View() string {
 
 	case ModeConfirmDelete:
 		if topic := m.SelectedTopic(); topic != nil {
-			content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete '%s'? (y/n)", topic.Name)))
+			if m.emulator {
+				content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete '%s'? (y/n)", topic.Name)))
+				break
+			}
+			content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("Delete '%s' from GCP?", topic.Name)))
+			content.WriteString("\n")
+			content.WriteString(m.confirmInput.View())
+			if m.confirmMismatch {
+				content.WriteString(" ")
+				content.WriteString(common.FilterErrorStyle.Render("(name does not match)"))
+			}
+		}
+
+	case ModeConfirmBulkDelete:
+		names := m.DisplayedNames()
+		attached := m.attachedSubscriptions(names)
+		if m.emulator {
+			content.WriteString(common.LogWarningStyle.Render(fmt.Sprintf("Delete all %d displayed topics? (y/n)", len(names))))
+			if attached > 0 {
+				content.WriteString("\n")
+				content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("⚠ Orphans %d subscriptions (s: delete them too)", attached)))
+			}
+			break
+		}
+		// The prompt names the count, leaving the line above for the
+		// attached subscriptions when there are any
+		switch {
+		case attached > 0 && m.bulkWithSubs:
+			content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("⚠ Also deletes %d subscriptions (Tab: keep them)", attached)))
+		case attached > 0:
+			content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("⚠ Orphans %d subscriptions (Tab: delete them too)", attached)))
+		default:
+			content.WriteString(common.LogErrorStyle.Render(fmt.Sprintf("Delete all %d displayed topics from GCP?", len(names))))
+		}
+		content.WriteString("\n")
+		content.WriteString(m.confirmInput.View())
+		if m.confirmMismatch {
+			content.WriteString(" ")
+			content.WriteString(common.FilterErrorStyle.Render("(does not match)"))
 		}
 
 	default:
@@ -63,26 +119,53 @@ func (m Model) View() string {
 				style = common.LogErrorStyle
 			}
 			content.WriteString(style.Render(m.statusMsg))
-		} else if m.filterText != "" {
-			filterDisplay := common.FilterPromptStyle.Render("/ ") +
-				common.FilterInputStyle.Render(m.filterText)
-			content.WriteString(filterDisplay)
+		} else {
+			var active []string
+			if m.filterText != "" {
+				active = append(active, common.FilterPromptStyle.Render(m.filterInput.Prompt)+
+					common.FilterInputStyle.Render(m.filterText))
+			}
+			if m.search.Text() != "" {
+				active = append(active, common.FilterPromptStyle.Render(m.search.Input.Prompt)+
+					common.SearchMatchText.Render(m.search.Text()))
+			}
+			content.WriteString(strings.Join(active, "  "))
 		}
 	}
 
 	return common.BorderedPanel(title, content.String(), m.focused, m.width, m.height)
 }
 
+// FullHelp returns every key binding of the panel for the help overlay
+func (m Model) FullHelp() []key.Binding {
+	return common.KeyBindings(keys)
+}
+
 // ShortHelp returns key bindings for the help display
 func (m Model) ShortHelp() []string {
 	switch m.mode {
 	case ModeFilter:
 		return []string{"esc: clear", "enter: apply"}
+	case ModeSearch:
+		return []string{"esc: cancel", "enter: keep highlights"}
 	case ModeCreate:
 		return []string{"enter: create", "esc: cancel"}
 	case ModeConfirmDelete:
+		if !m.emulator {
+			return []string{"enter: delete", "esc: cancel"}
+		}
 		return []string{"y: yes", "n: no"}
+	case ModeConfirmBulkDelete:
+		if !m.emulator {
+			return []string{"enter: delete", "tab: with subscriptions", "esc: cancel"}
+		}
+		return []string{"y: yes", "s: with subscriptions", "n: no"}
 	default:
-		return []string{"/: filter", "n: new", "d: delete", "enter: select"}
+		help := []string{"/: search", "F: filter", "n: new", "d: delete", "D: delete all", "enter: select", "space: mark", "t: tree"}
+		if m.IsSearching() {
+			// n moves between matches until the search is cleared
+			help[2] = "n/N: next/prev match"
+		}
+		return help
 	}
 }
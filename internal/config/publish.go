@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// Environment variables that override the publish batching settings
+const (
+	PublishCountThresholdEnvVar = "PUBSUB_TUI_PUBLISH_COUNT_THRESHOLD"
+	PublishDelayThresholdEnvVar = "PUBSUB_TUI_PUBLISH_DELAY_THRESHOLD"
+	PublishByteThresholdEnvVar  = "PUBSUB_TUI_PUBLISH_BYTE_THRESHOLD"
+)
+
+// PublishSettingsFromEnv returns the batching settings, overriding the
+// defaults with PUBSUB_TUI_PUBLISH_COUNT_THRESHOLD,
+// PUBSUB_TUI_PUBLISH_DELAY_THRESHOLD and PUBSUB_TUI_PUBLISH_BYTE_THRESHOLD
+// when set.
+func PublishSettingsFromEnv() (pubsub.PublishSettings, error) {
+	settings := pubsub.DefaultPublishSettings()
+
+	if v := os.Getenv(PublishCountThresholdEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return settings, fmt.Errorf("%s must be a positive integer, got %q", PublishCountThresholdEnvVar, v)
+		}
+		settings.CountThreshold = n
+	}
+
+	if v := os.Getenv(PublishDelayThresholdEnvVar); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return settings, fmt.Errorf("%s must be a positive duration such as 10ms, got %q", PublishDelayThresholdEnvVar, v)
+		}
+		settings.DelayThreshold = d
+	}
+
+	if v := os.Getenv(PublishByteThresholdEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return settings, fmt.Errorf("%s must be a positive integer, got %q", PublishByteThresholdEnvVar, v)
+		}
+		settings.ByteThreshold = n
+	}
+
+	return settings, nil
+}
+
+// PublishAttemptsEnvVar sets how many times a publish is attempted when it
+// fails with a transient error. 1 disables retries.
+const PublishAttemptsEnvVar = "PUBSUB_TUI_PUBLISH_ATTEMPTS"
+
+// DefaultPublishAttempts is the number of attempts when
+// PublishAttemptsEnvVar is unset
+const DefaultPublishAttempts = 3
+
+// PublishAttemptsFromEnv returns the attempts per publish from
+// PUBSUB_TUI_PUBLISH_ATTEMPTS, defaulting to DefaultPublishAttempts
+func PublishAttemptsFromEnv() (int, error) {
+	v := strings.TrimSpace(os.Getenv(PublishAttemptsEnvVar))
+	if v == "" {
+		return DefaultPublishAttempts, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", PublishAttemptsEnvVar, v)
+	}
+	return n, nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+func TestPublishSettingsFromEnv(t *testing.T) {
+	defaults := pubsub.DefaultPublishSettings()
+
+	tests := []struct {
+		name    string
+		count   string
+		delay   string
+		bytes   string
+		want    pubsub.PublishSettings
+		wantErr bool
+	}{
+		{
+			name: "defaults when unset",
+			want: defaults,
+		},
+		{
+			name:  "overrides all",
+			count: "500",
+			delay: "50ms",
+			bytes: "2000000",
+			want:  pubsub.PublishSettings{CountThreshold: 500, DelayThreshold: 50 * time.Millisecond, ByteThreshold: 2000000},
+		},
+		{
+			name:  "overrides delay only",
+			delay: "1s",
+			want:  pubsub.PublishSettings{CountThreshold: defaults.CountThreshold, DelayThreshold: time.Second, ByteThreshold: defaults.ByteThreshold},
+		},
+		{
+			name:    "invalid count",
+			count:   "many",
+			wantErr: true,
+		},
+		{
+			name:    "delay without unit",
+			delay:   "10",
+			wantErr: true,
+		},
+		{
+			name:    "non-positive bytes",
+			bytes:   "0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PublishCountThresholdEnvVar, tt.count)
+			t.Setenv(PublishDelayThresholdEnvVar, tt.delay)
+			t.Setenv(PublishByteThresholdEnvVar, tt.bytes)
+
+			got, err := PublishSettingsFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublishSettingsFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("PublishSettingsFromEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishAttemptsFromEnv(t *testing.T) {
+	t.Setenv(PublishAttemptsEnvVar, "")
+	if n, err := PublishAttemptsFromEnv(); err != nil || n != DefaultPublishAttempts {
+		t.Errorf("PublishAttemptsFromEnv() = %d, %v; want the default", n, err)
+	}
+	t.Setenv(PublishAttemptsEnvVar, " 5 ")
+	if n, err := PublishAttemptsFromEnv(); err != nil || n != 5 {
+		t.Errorf("PublishAttemptsFromEnv() = %d, %v; want 5", n, err)
+	}
+	for _, v := range []string{"0", "-1", "many"} {
+		t.Setenv(PublishAttemptsEnvVar, v)
+		if _, err := PublishAttemptsFromEnv(); err == nil {
+			t.Errorf("PublishAttemptsFromEnv() with %q should fail", v)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package config reads the pubsub-tui settings given as PUBSUB_TUI_*
+// environment variables into the settings types of pkg/pubsub, which itself
+// reads no environment of its own.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// Environment variables that override the receive flow control settings
+const (
+	MaxOutstandingMessagesEnvVar = "PUBSUB_TUI_MAX_OUTSTANDING_MESSAGES"
+	MaxOutstandingBytesEnvVar    = "PUBSUB_TUI_MAX_OUTSTANDING_BYTES"
+	MaxMessageBytesEnvVar        = "PUBSUB_TUI_MAX_MESSAGE_BYTES"
+)
+
+// ReceiveConfigFromEnv returns the receive settings, overriding the defaults
+// with PUBSUB_TUI_MAX_OUTSTANDING_MESSAGES, PUBSUB_TUI_MAX_OUTSTANDING_BYTES
+// and PUBSUB_TUI_MAX_MESSAGE_BYTES when set.
+func ReceiveConfigFromEnv() (pubsub.ReceiveConfig, error) {
+	cfg := pubsub.DefaultReceiveConfig()
+
+	if v := os.Getenv(MaxOutstandingMessagesEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, fmt.Errorf("%s must be a positive integer, got %q", MaxOutstandingMessagesEnvVar, v)
+		}
+		cfg.MaxOutstandingMessages = n
+	}
+
+	if v := os.Getenv(MaxOutstandingBytesEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, fmt.Errorf("%s must be a positive integer, got %q", MaxOutstandingBytesEnvVar, v)
+		}
+		cfg.MaxOutstandingBytes = n
+	}
+
+	if v := os.Getenv(MaxMessageBytesEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, fmt.Errorf("%s must be a positive integer, got %q", MaxMessageBytesEnvVar, v)
+		}
+		cfg.MaxMessageBytes = n
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+func TestReceiveConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages string
+		bytes    string
+		perMsg   string
+		want     pubsub.ReceiveConfig
+		wantErr  bool
+	}{
+		{
+			name: "defaults when unset",
+			want: pubsub.DefaultReceiveConfig(),
+		},
+		{
+			name:     "overrides both",
+			messages: "500",
+			bytes:    "1048576",
+			want:     pubsub.ReceiveConfig{MaxOutstandingMessages: 500, MaxOutstandingBytes: 1048576},
+		},
+		{
+			name:     "overrides messages only",
+			messages: "10",
+			want:     pubsub.ReceiveConfig{MaxOutstandingMessages: 10, MaxOutstandingBytes: pubsub.DefaultMaxOutstandingBytes},
+		},
+		{
+			name:   "caps message data",
+			perMsg: "4096",
+			want:   pubsub.ReceiveConfig{MaxOutstandingMessages: pubsub.DefaultMaxOutstandingMessages, MaxOutstandingBytes: pubsub.DefaultMaxOutstandingBytes, MaxMessageBytes: 4096},
+		},
+		{
+			name:    "invalid message data cap",
+			perMsg:  "-1",
+			wantErr: true,
+		},
+		{
+			name:     "invalid messages",
+			messages: "lots",
+			wantErr:  true,
+		},
+		{
+			name:    "non-positive bytes",
+			bytes:   "0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(MaxOutstandingMessagesEnvVar, tt.messages)
+			t.Setenv(MaxOutstandingBytesEnvVar, tt.bytes)
+			t.Setenv(MaxMessageBytesEnvVar, tt.perMsg)
+
+			got, err := ReceiveConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReceiveConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ReceiveConfigFromEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
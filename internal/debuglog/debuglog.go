@@ -0,0 +1,71 @@
+// Package debuglog writes a log of the TUI's activity and server requests to
+// a file, for diagnosing problems such as intermittent connectivity that the
+// UI itself cannot show. It is off unless PUBSUB_TUI_DEBUG is set, and never
+// writes to the terminal, which the TUI is drawing on.
+package debuglog
+
+import (
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EnvVar enables the debug log when set to 1 or true
+const EnvVar = "PUBSUB_TUI_DEBUG"
+
+// FileName is the debug log written in the working directory. Entries are
+// appended, so a log survives restarts.
+const FileName = "pubsub-tui-debug.log"
+
+// logger writes entries once the log is opened. It discards them until then,
+// so nothing reaches the terminal.
+var logger = log.New(io.Discard, "", log.LstdFlags|log.Lmicroseconds)
+
+// enabled skips formatting entries while the log is not open
+var enabled atomic.Bool
+
+// EnabledFromEnv returns whether PUBSUB_TUI_DEBUG enables the debug log
+func EnabledFromEnv() bool {
+	on, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(EnvVar)))
+	return err == nil && on
+}
+
+// Open starts writing entries to the file at path. The caller closes the
+// returned file on exit.
+func Open(path string) (*os.File, error) {
+	f, err := tea.LogToFileWith(path, "pubsub-tui", logger)
+	if err != nil {
+		return nil, err
+	}
+	enabled.Store(true)
+	return f, nil
+}
+
+// Enabled returns whether entries are being written
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Printf writes an entry when the log is open
+func Printf(format string, args ...any) {
+	if enabled.Load() {
+		logger.Printf(format, args...)
+	}
+}
+
+// Call writes an entry for a request to the server. Its signature matches
+// pubsub.CallObserver.
+func Call(op string, elapsed time.Duration, err error) {
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		Printf("call %s failed after %s: %v", op, elapsed, err)
+		return
+	}
+	Printf("call %s took %s", op, elapsed)
+}
@@ -0,0 +1,61 @@
+package debuglog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnabledFromEnv(t *testing.T) {
+	tests := map[string]bool{"": false, "1": true, "true": true, " TRUE ": true, "0": false, "yes": false}
+	for value, want := range tests {
+		t.Setenv(EnvVar, value)
+		if got := EnabledFromEnv(); got != want {
+			t.Errorf("EnabledFromEnv() with %q = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Cleanup(func() { enabled.Store(false) })
+
+	// Nothing is written before the log is opened
+	Printf("dropped")
+
+	path := filepath.Join(t.TempDir(), FileName)
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !Enabled() {
+		t.Error("Open() should enable the log")
+	}
+	Printf("subscription %s started", "orders-sub")
+	Call("ListTopics", 1234*time.Microsecond, nil)
+	Call("Publish", 2*time.Second, errors.New("deadline exceeded"))
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"pubsub-tui ",
+		"subscription orders-sub started",
+		"call ListTopics took 1ms",
+		"call Publish failed after 2s: deadline exceeded",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "dropped") {
+		t.Error("entries before Open() should be dropped")
+	}
+}
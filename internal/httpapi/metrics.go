@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metrics counts message activity for the Prometheus endpoint. The TUI
+// increments the counters and HTTP handlers read them, so all access is
+// guarded by a mutex. A nil *Metrics ignores all updates.
+type Metrics struct {
+	mu        sync.Mutex
+	project   string
+	published uint64
+	errors    uint64
+	received  map[string]uint64 // By subscription
+	acked     map[string]uint64 // By subscription
+}
+
+// NewMetrics creates zeroed counters labeled with the given project
+func NewMetrics(project string) *Metrics {
+	return &Metrics{
+		project:  project,
+		received: make(map[string]uint64),
+		acked:    make(map[string]uint64),
+	}
+}
+
+// IncPublished counts a successfully published message
+func (m *Metrics) IncPublished() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.published++
+}
+
+// IncReceived counts a message received on subscription
+func (m *Metrics) IncReceived(subscription string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.received[subscription]++
+}
+
+// IncAcked counts a message acknowledged on subscription
+func (m *Metrics) IncAcked(subscription string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acked[subscription]++
+}
+
+// IncErrors counts a failed operation
+func (m *Metrics) IncErrors() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors++
+}
+
+// WritePrometheus writes all counters in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	project := `project="` + escapeLabel(m.project) + `"`
+
+	writeHeader(&b, "pubsub_tui_messages_published_total", "Messages published successfully.")
+	fmt.Fprintf(&b, "pubsub_tui_messages_published_total{%s} %d\n", project, m.published)
+
+	writeHeader(&b, "pubsub_tui_messages_received_total", "Messages received from subscriptions.")
+	writeBySubscription(&b, "pubsub_tui_messages_received_total", project, m.received)
+
+	writeHeader(&b, "pubsub_tui_messages_acked_total", "Messages acknowledged.")
+	writeBySubscription(&b, "pubsub_tui_messages_acked_total", project, m.acked)
+
+	writeHeader(&b, "pubsub_tui_errors_total", "Failed operations.")
+	fmt.Fprintf(&b, "pubsub_tui_errors_total{%s} %d\n", project, m.errors)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHeader writes the HELP and TYPE lines for a counter
+func writeHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", name)
+}
+
+// writeBySubscription writes one sample per subscription, sorted by name
+func writeBySubscription(b *strings.Builder, name, project string, counts map[string]uint64) {
+	subs := make([]string, 0, len(counts))
+	for sub := range counts {
+		subs = append(subs, sub)
+	}
+	sort.Strings(subs)
+
+	for _, sub := range subs {
+		fmt.Fprintf(b, "%s{%s,subscription=\"%s\"} %d\n", name, project, escapeLabel(sub), counts[sub])
+	}
+}
+
+// escapeLabel escapes a label value as required by the text format
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
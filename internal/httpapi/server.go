@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// AddrEnvVar enables the HTTP server when set to a listen address (e.g. ":8089")
+const AddrEnvVar = "PUBSUB_TUI_HTTP_ADDR"
+
+// AddrFromEnv returns the configured listen address, or "" when disabled
+func AddrFromEnv() string {
+	return os.Getenv(AddrEnvVar)
+}
+
+// Server serves the captured messages and metrics read-only over HTTP
+type Server struct {
+	store   *Store
+	metrics *Metrics
+	server  *http.Server
+}
+
+// New creates a server for the given address, store and metrics
+func New(addr string, store *Store, metrics *Metrics) *Server {
+	s := &Server{store: store, metrics: metrics}
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Handler returns the HTTP handler with all endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/messages", s.handleMessages)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+// Start binds the listen address and serves in the background.
+// Bind errors are returned immediately.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	// Serve returns http.ErrServerClosed after Shutdown; the TUI owns the
+	// terminal, so there is nowhere useful to report other serve errors
+	go s.server.Serve(ln)
+	return nil
+}
+
+// Shutdown stops the server, waiting for in-flight requests until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// handleMessages serves the message buffer as JSON
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, s.store.Snapshot())
+}
+
+// handleMetrics serves the counters in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.WritePrometheus(w)
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,164 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+func TestServer_Messages(t *testing.T) {
+	store := NewStore()
+	acked := &pubsub.ReceivedMessage{
+		ID:          "msg-1",
+		Data:        []byte(`{"n":1}`),
+		Attributes:  map[string]string{"type": "order"},
+		PublishTime: time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+	}
+	acked.SetAcked(true)
+	store.Update("orders-sub", []*pubsub.ReceivedMessage{
+		acked,
+		{ID: "msg-2", Data: []byte(`{"n":`), DataSize: 100},
+	}, 3)
+
+	srv := New("", store, NewMetrics("test-project"))
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/messages", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /messages status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if snap.Subscription != "orders-sub" {
+		t.Errorf("Subscription = %q, want orders-sub", snap.Subscription)
+	}
+	if len(snap.Messages) != 2 {
+		t.Fatalf("len(Messages) = %d, want 2", len(snap.Messages))
+	}
+	if got := snap.Messages[0]; got.ID != "msg-1" || got.Data != `{"n":1}` || !got.Acked || got.Attributes["type"] != "order" {
+		t.Errorf("Messages[0] = %+v, want acked msg-1 with data and attributes", got)
+	}
+	if got := snap.Messages[1]; got.DataSize != 100 || !got.Truncated {
+		t.Errorf("Messages[1] = %+v, want truncated from 100 bytes", got)
+	}
+	if snap.Dropped != 3 {
+		t.Errorf("Dropped = %d, want 3", snap.Dropped)
+	}
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	srv := New("", NewStore(), NewMetrics("test-project"))
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/messages", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /messages status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestStore_UpdateCopiesAttributes(t *testing.T) {
+	store := NewStore()
+	msg := &pubsub.ReceivedMessage{ID: "msg-1", Attributes: map[string]string{"k": "v"}}
+	store.Update("sub", []*pubsub.ReceivedMessage{msg}, 0)
+
+	// Later changes to the source message must not leak into the snapshot
+	msg.Attributes["k"] = "changed"
+	if got := store.Snapshot().Messages[0].Attributes["k"]; got != "v" {
+		t.Errorf("snapshot attribute = %q, want %q", got, "v")
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	metrics := NewMetrics("test-project")
+	metrics.IncPublished()
+	metrics.IncPublished()
+	metrics.IncReceived("orders-sub")
+	metrics.IncReceived("orders-sub")
+	metrics.IncReceived("audit-sub")
+	metrics.IncAcked("orders-sub")
+	metrics.IncErrors()
+
+	srv := New("", NewStore(), metrics)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want Prometheus text format", ct)
+	}
+
+	body := rec.Body.String()
+	want := []string{
+		"# TYPE pubsub_tui_messages_published_total counter",
+		`pubsub_tui_messages_published_total{project="test-project"} 2`,
+		`pubsub_tui_messages_received_total{project="test-project",subscription="audit-sub"} 1`,
+		`pubsub_tui_messages_received_total{project="test-project",subscription="orders-sub"} 2`,
+		`pubsub_tui_messages_acked_total{project="test-project",subscription="orders-sub"} 1`,
+		`pubsub_tui_errors_total{project="test-project"} 1`,
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics output missing line %q\n%s", line, body)
+		}
+	}
+
+	// Every line is a comment or a "name{labels} value" sample
+	sample := regexp.MustCompile(`^[a-z_]+\{[a-z]+="[^"]*"(,[a-z]+="[^"]*")*\} [0-9]+$`)
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		if strings.HasPrefix(line, "# HELP ") || strings.HasPrefix(line, "# TYPE ") {
+			continue
+		}
+		if !sample.MatchString(line) {
+			t.Errorf("malformed sample line %q", line)
+		}
+	}
+}
+
+func TestMetrics_ConcurrentAccess(t *testing.T) {
+	metrics := NewMetrics("test-project")
+	srv := New("", NewStore(), metrics)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				metrics.IncReceived("sub")
+				metrics.IncAcked("sub")
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	}
+	wg.Wait()
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `pubsub_tui_messages_received_total{project="test-project",subscription="sub"} 400`) {
+		t.Errorf("expected 400 received messages, got:\n%s", rec.Body.String())
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabel() = %q", got)
+	}
+}
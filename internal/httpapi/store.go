@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// Message is a read-only copy of a received message
+type Message struct {
+	ID          string            `json:"id"`
+	Data        string            `json:"data"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	PublishTime time.Time         `json:"publishTime"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+	Acked       bool              `json:"acked"`
+	DataSize    int               `json:"dataSize"`  // Size of the published data
+	Truncated   bool              `json:"truncated"` // Whether Data holds only the start of it
+}
+
+// Snapshot is a point-in-time copy of the subscriber buffer
+type Snapshot struct {
+	Subscription string    `json:"subscription"`
+	Messages     []Message `json:"messages"`
+	Dropped      int64     `json:"dropped"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Store holds the latest snapshot. The TUI writes it and HTTP handlers
+// read it, so all access is guarded by a mutex.
+type Store struct {
+	mu       sync.RWMutex
+	snapshot Snapshot
+}
+
+// NewStore creates an empty snapshot store
+func NewStore() *Store {
+	return &Store{
+		snapshot: Snapshot{Messages: []Message{}},
+	}
+}
+
+// Update replaces the snapshot with a copy of the given messages
+func (s *Store) Update(subscription string, messages []*pubsub.ReceivedMessage, dropped int64) {
+	copied := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		var attrs map[string]string
+		if len(msg.Attributes) > 0 {
+			attrs = make(map[string]string, len(msg.Attributes))
+			for k, v := range msg.Attributes {
+				attrs[k] = v
+			}
+		}
+		copied = append(copied, Message{
+			ID:          msg.ID,
+			Data:        string(msg.Data),
+			Attributes:  attrs,
+			PublishTime: msg.PublishTime,
+			OrderingKey: msg.OrderingKey,
+			Acked:       msg.IsAcked(),
+			DataSize:    msg.DataSize,
+			Truncated:   msg.Truncated(),
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot = Snapshot{
+		Subscription: subscription,
+		Messages:     copied,
+		Dropped:      dropped,
+		UpdatedAt:    time.Now(),
+	}
+}
+
+// Snapshot returns the latest snapshot. The returned value must not be modified.
+func (s *Store) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snapshot
+}
@@ -0,0 +1,173 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// Creator creates topics and subscriptions; *pubsub.Client implements it
+type Creator interface {
+	Lister
+	CreateTopic(ctx context.Context, topicID string) error
+	CreateSubscriptionWithFilter(ctx context.Context, subscriptionID, topicID, filter string) error
+}
+
+// Status is the outcome of importing one resource
+type Status string
+
+const (
+	StatusCreated Status = "created"
+	StatusSkipped Status = "skipped" // Already exists
+	StatusFailed  Status = "failed"
+)
+
+// Result is the outcome of importing one topic or subscription
+type Result struct {
+	Kind   string // "topic" or "subscription"
+	Name   string
+	Status Status
+	Note   string // Extra detail, e.g. why a resource was skipped
+	Err    error  // Set when Status is StatusFailed
+}
+
+// Report lists the outcome of every resource in an import, topics first
+type Report struct {
+	Results []Result
+}
+
+// Count returns how many resources ended with the given status
+func (r Report) Count(status Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns whether any resource failed to import
+func (r Report) Failed() bool {
+	return r.Count(StatusFailed) > 0
+}
+
+// Read reads an inventory from path, or from stdin when path is "-"
+func Read(path string) (Inventory, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return Inventory{}, fmt.Errorf("failed to read inventory: %w", err)
+	}
+
+	var inv Inventory
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return Inventory{}, fmt.Errorf("failed to parse inventory %s: %w", path, err)
+	}
+	if inv.Version > Version {
+		return Inventory{}, fmt.Errorf("inventory %s has version %d; this build reads up to version %d", path, inv.Version, Version)
+	}
+	return inv, nil
+}
+
+// Import creates the topics, then the subscriptions, of an inventory that
+// do not exist yet. Existing resources are skipped, so importing twice is
+// harmless. Push subscriptions are created as pull subscriptions.
+func Import(ctx context.Context, client Creator, inv Inventory) (Report, error) {
+	topics, err := client.ListTopics(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to list topics: %w", err)
+	}
+	subs, err := client.ListSubscriptions(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	existingTopics := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		existingTopics[t.Name] = true
+	}
+	existingSubs := make(map[string]bool, len(subs))
+	for _, s := range subs {
+		existingSubs[s.Name] = true
+	}
+
+	var report Report
+	failedTopics := make(map[string]bool)
+	for _, t := range inv.Topics {
+		res := Result{Kind: "topic", Name: t.Name}
+		invalid := pubsub.ValidateResourceID(t.Name)
+		switch {
+		case invalid != nil:
+			res.Status, res.Err = StatusFailed, invalid
+		case existingTopics[t.Name]:
+			res.Status, res.Note = StatusSkipped, "already exists"
+		default:
+			if err := client.CreateTopic(ctx, t.Name); err != nil {
+				res.Status, res.Err = StatusFailed, err
+			} else {
+				res.Status = StatusCreated
+				existingTopics[t.Name] = true
+			}
+		}
+		if res.Status == StatusFailed {
+			failedTopics[t.Name] = true
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	for _, s := range inv.Subscriptions {
+		report.Results = append(report.Results, importSubscription(ctx, client, s, existingSubs, existingTopics, failedTopics))
+	}
+	return report, nil
+}
+
+// importSubscription creates one subscription unless it exists or its
+// topic is missing
+func importSubscription(ctx context.Context, client Creator, s Subscription, existingSubs, existingTopics, failedTopics map[string]bool) Result {
+	res := Result{Kind: "subscription", Name: s.Name}
+	if err := pubsub.ValidateResourceID(s.Name); err != nil {
+		res.Status, res.Err = StatusFailed, err
+		return res
+	}
+	if existingSubs[s.Name] {
+		res.Status, res.Note = StatusSkipped, "already exists"
+		return res
+	}
+
+	switch {
+	case s.Topic == "":
+		res.Status, res.Err = StatusFailed, errors.New("topic unknown in the inventory")
+		return res
+	case failedTopics[s.Topic]:
+		res.Status, res.Err = StatusFailed, fmt.Errorf("topic %q was not created", s.Topic)
+		return res
+	case !existingTopics[s.Topic]:
+		res.Status, res.Err = StatusFailed, fmt.Errorf("topic %q does not exist", s.Topic)
+		return res
+	}
+
+	var filter string
+	if s.Config != nil {
+		filter = s.Config.Filter
+		if s.Config.Delivery == "push" {
+			res.Note = "created as pull; push endpoint not set"
+		}
+	}
+	if err := client.CreateSubscriptionWithFilter(ctx, s.Name, s.Topic, filter); err != nil {
+		res.Status, res.Err = StatusFailed, err
+		return res
+	}
+	res.Status = StatusCreated
+	return res
+}
@@ -0,0 +1,107 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// fakeCreator records created resources on top of a fakeLister
+type fakeCreator struct {
+	fakeLister
+	created  []string
+	failures map[string]error // Create errors by resource name
+}
+
+func (f *fakeCreator) CreateTopic(ctx context.Context, topicID string) error {
+	if err := f.failures[topicID]; err != nil {
+		return err
+	}
+	f.created = append(f.created, "topic "+topicID)
+	return nil
+}
+
+func (f *fakeCreator) CreateSubscriptionWithFilter(ctx context.Context, subscriptionID, topicID, filter string) error {
+	if err := f.failures[subscriptionID]; err != nil {
+		return err
+	}
+	f.created = append(f.created, "subscription "+subscriptionID+" -> "+topicID+" "+filter)
+	return nil
+}
+
+func TestImport(t *testing.T) {
+	client := &fakeCreator{
+		fakeLister: fakeLister{
+			topics: []pubsub.TopicInfo{{Name: "orders"}},
+			subs:   []pubsub.SubscriptionInfo{{Name: "orders-sub", TopicName: "orders"}},
+		},
+		failures: map[string]error{"broken": errors.New("quota exceeded")},
+	}
+	inv := Inventory{
+		Topics: []Topic{{Name: "orders"}, {Name: "events"}, {Name: "broken"}, {Name: "x"}},
+		Subscriptions: []Subscription{
+			{Name: "orders-sub", Topic: "orders"},
+			{Name: "events-audit", Topic: "events", Config: &SubscriptionConfig{Delivery: "push", Filter: `attributes.type = "audit"`}},
+			{Name: "broken-sub", Topic: "broken"},
+			{Name: "orphan-sub"},
+		},
+	}
+
+	report, err := Import(context.Background(), client, inv)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	// Topics are created before subscriptions
+	wantCreated := []string{"topic events", `subscription events-audit -> events attributes.type = "audit"`}
+	if !reflect.DeepEqual(client.created, wantCreated) {
+		t.Errorf("created %v, want %v", client.created, wantCreated)
+	}
+
+	var got []Status
+	for _, res := range report.Results {
+		got = append(got, res.Status)
+	}
+	want := []Status{
+		StatusSkipped, StatusCreated, StatusFailed, StatusFailed, // orders, events, broken, x (invalid name)
+		StatusSkipped, StatusCreated, StatusFailed, StatusFailed, // orders-sub, events-audit, broken-sub, orphan-sub
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if report.Results[5].Note == "" {
+		t.Error("a push subscription created as pull should say so")
+	}
+	if !report.Failed() || report.Count(StatusCreated) != 2 {
+		t.Errorf("Failed() = %v, created %d; want failures and 2 created", report.Failed(), report.Count(StatusCreated))
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "inventory.json")
+	inv := build([]pubsub.TopicInfo{{Name: "orders"}}, []pubsub.SubscriptionInfo{{Name: "orders-sub", TopicName: "orders", ConfigLoaded: true}})
+	if err := Write(path, inv); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, inv) {
+		t.Errorf("Read() = %+v, want %+v", got, inv)
+	}
+
+	newer := filepath.Join(dir, "newer.json")
+	if err := os.WriteFile(newer, []byte(`{"version":99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(newer); err == nil {
+		t.Error("Read() should reject a newer inventory version")
+	}
+}
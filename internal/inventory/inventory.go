@@ -0,0 +1,144 @@
+// Package inventory exports a project's topics and subscriptions as JSON,
+// e.g. to document them, and imports such a file to recreate their shape
+// elsewhere.
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// Version is written to inventory files so the format can evolve
+const Version = 1
+
+// Inventory is the JSON document listing a project's topics and
+// subscriptions
+type Inventory struct {
+	Version       int            `json:"version"`
+	Project       string         `json:"project"`
+	Emulator      bool           `json:"emulator,omitempty"`
+	ExportedAt    time.Time      `json:"exportedAt"`
+	Counts        Counts         `json:"counts"`
+	Topics        []Topic        `json:"topics"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Counts summarizes the number of resources in an inventory
+type Counts struct {
+	Topics        int `json:"topics"`
+	Subscriptions int `json:"subscriptions"`
+}
+
+// Topic is a topic and the names of its subscriptions
+type Topic struct {
+	Name          string   `json:"name"`
+	Subscriptions []string `json:"subscriptions"`
+}
+
+// Subscription is a subscription and the topic it is attached to. Topic is
+// empty and Config nil when the subscription's config could not be read.
+type Subscription struct {
+	Name   string              `json:"name"`
+	Topic  string              `json:"topic,omitempty"`
+	Config *SubscriptionConfig `json:"config,omitempty"`
+}
+
+// SubscriptionConfig is the delivery configuration of a subscription
+type SubscriptionConfig struct {
+	Delivery     string `json:"delivery"` // "pull" or "push"
+	PushEndpoint string `json:"pushEndpoint,omitempty"`
+	Filter       string `json:"filter,omitempty"`
+}
+
+// Lister lists a project's topics and subscriptions; *pubsub.Client
+// implements it
+type Lister interface {
+	ListTopics(ctx context.Context) ([]pubsub.TopicInfo, error)
+	ListSubscriptions(ctx context.Context) ([]pubsub.SubscriptionInfo, error)
+}
+
+// Export lists the topics and subscriptions of a project
+func Export(ctx context.Context, client Lister, project string, emulator bool) (Inventory, error) {
+	topics, err := client.ListTopics(ctx)
+	if err != nil {
+		return Inventory{}, fmt.Errorf("failed to list topics: %w", err)
+	}
+	subs, err := client.ListSubscriptions(ctx)
+	if err != nil {
+		return Inventory{}, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	inv := build(topics, subs)
+	inv.Project = project
+	inv.Emulator = emulator
+	inv.ExportedAt = time.Now().UTC()
+	return inv, nil
+}
+
+// build assembles an inventory sorted by name, listing each subscription
+// under its topic
+func build(topics []pubsub.TopicInfo, subs []pubsub.SubscriptionInfo) Inventory {
+	inv := Inventory{
+		Version:       Version,
+		Topics:        make([]Topic, 0, len(topics)),
+		Subscriptions: make([]Subscription, 0, len(subs)),
+	}
+
+	byTopic := make(map[string]int, len(topics))
+	for _, t := range topics {
+		byTopic[t.Name] = len(inv.Topics)
+		inv.Topics = append(inv.Topics, Topic{Name: t.Name, Subscriptions: []string{}})
+	}
+
+	for _, s := range subs {
+		sub := Subscription{Name: s.Name}
+		if s.ConfigLoaded {
+			sub.Topic = s.TopicName
+			sub.Config = &SubscriptionConfig{
+				Delivery:     "pull",
+				PushEndpoint: s.PushEndpoint,
+				Filter:       s.Filter,
+			}
+			if s.IsPush {
+				sub.Config.Delivery = "push"
+			}
+		}
+		if i, ok := byTopic[sub.Topic]; ok {
+			inv.Topics[i].Subscriptions = append(inv.Topics[i].Subscriptions, sub.Name)
+		}
+		inv.Subscriptions = append(inv.Subscriptions, sub)
+	}
+
+	sort.Slice(inv.Topics, func(i, j int) bool { return inv.Topics[i].Name < inv.Topics[j].Name })
+	sort.Slice(inv.Subscriptions, func(i, j int) bool { return inv.Subscriptions[i].Name < inv.Subscriptions[j].Name })
+	for _, t := range inv.Topics {
+		sort.Strings(t.Subscriptions)
+	}
+	inv.Counts = Counts{Topics: len(inv.Topics), Subscriptions: len(inv.Subscriptions)}
+	return inv
+}
+
+// Write writes the inventory as indented JSON to path, or to stdout when
+// path is "-"
+func Write(path string, inv Inventory) error {
+	data, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write inventory: %w", err)
+	}
+	return nil
+}
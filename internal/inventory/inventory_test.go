@@ -0,0 +1,91 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// fakeLister returns fixed topics and subscriptions
+type fakeLister struct {
+	topics []pubsub.TopicInfo
+	subs   []pubsub.SubscriptionInfo
+	err    error
+}
+
+func (f fakeLister) ListTopics(ctx context.Context) ([]pubsub.TopicInfo, error) {
+	return f.topics, f.err
+}
+
+func (f fakeLister) ListSubscriptions(ctx context.Context) ([]pubsub.SubscriptionInfo, error) {
+	return f.subs, nil
+}
+
+func TestExport(t *testing.T) {
+	client := fakeLister{
+		topics: []pubsub.TopicInfo{{Name: "orders"}, {Name: "events"}},
+		subs: []pubsub.SubscriptionInfo{
+			{Name: "orders-push", TopicName: "orders", ConfigLoaded: true, IsPush: true, PushEndpoint: "https://example.com/push"},
+			{Name: "orders-audit", TopicName: "orders", ConfigLoaded: true, Filter: `attributes.type = "audit"`},
+			{Name: "mystery", TopicName: pubsub.UnknownTopic},
+		},
+	}
+
+	inv, err := Export(context.Background(), client, "my-project", true)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if inv.Project != "my-project" || !inv.Emulator || inv.Version != Version {
+		t.Errorf("header = %q emulator %v version %d", inv.Project, inv.Emulator, inv.Version)
+	}
+	if inv.Counts != (Counts{Topics: 2, Subscriptions: 3}) {
+		t.Errorf("Counts = %+v, want 2 topics and 3 subscriptions", inv.Counts)
+	}
+
+	wantTopics := []Topic{
+		{Name: "events", Subscriptions: []string{}},
+		{Name: "orders", Subscriptions: []string{"orders-audit", "orders-push"}},
+	}
+	if !reflect.DeepEqual(inv.Topics, wantTopics) {
+		t.Errorf("Topics = %+v, want %+v", inv.Topics, wantTopics)
+	}
+
+	wantSubs := []Subscription{
+		{Name: "mystery"},
+		{Name: "orders-audit", Topic: "orders", Config: &SubscriptionConfig{Delivery: "pull", Filter: `attributes.type = "audit"`}},
+		{Name: "orders-push", Topic: "orders", Config: &SubscriptionConfig{Delivery: "push", PushEndpoint: "https://example.com/push"}},
+	}
+	if !reflect.DeepEqual(inv.Subscriptions, wantSubs) {
+		t.Errorf("Subscriptions = %+v, want %+v", inv.Subscriptions, wantSubs)
+	}
+
+	if _, err := Export(context.Background(), fakeLister{err: errors.New("denied")}, "p", false); err == nil {
+		t.Error("Export() should fail when listing fails")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	inv := build([]pubsub.TopicInfo{{Name: "orders"}}, nil)
+	if err := Write(path, inv); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Inventory
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("written inventory is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, inv) {
+		t.Errorf("read back %+v, want %+v", got, inv)
+	}
+}
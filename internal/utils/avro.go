@@ -0,0 +1,303 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// errShortData is returned when binary data ends in the middle of a value
+var errShortData = errors.New("data ends unexpectedly")
+
+// jsonField is one field of a jsonObject
+type jsonField struct {
+	name  string
+	value interface{}
+}
+
+// jsonObject is a JSON object that keeps its fields in schema order, where
+// a map would sort them
+type jsonObject []jsonField
+
+// MarshalJSON writes the fields in order
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// bytesValue shows binary data as text when it is valid UTF-8; otherwise
+// it is marshalled as base64
+func bytesValue(b []byte) interface{} {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return b
+}
+
+// DecodeAvro decodes Avro binary data to JSON using the schema definition
+// it was written with. Record fields keep their schema order.
+func DecodeAvro(definition string, data []byte) ([]byte, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(definition), &schema); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+
+	d := &avroDecoder{data: data, named: make(map[string]interface{})}
+	v, err := d.decode(schema, "")
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%d bytes left after the Avro value", len(d.data)-d.pos)
+	}
+	return json.Marshal(v)
+}
+
+// avroDecoder reads Avro binary data. Named types (records, enums and
+// fixed) are remembered by full name as they are defined, so later fields
+// can refer to them.
+type avroDecoder struct {
+	data  []byte
+	pos   int
+	named map[string]interface{}
+}
+
+// decode reads one value of the given schema. ns is the enclosing
+// namespace, used to resolve unqualified type names.
+func (d *avroDecoder) decode(schema interface{}, ns string) (interface{}, error) {
+	switch s := schema.(type) {
+	case string:
+		return d.decodeNamed(s, ns)
+	case []interface{}:
+		// Union: the branch index, then the value
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s) {
+			return nil, fmt.Errorf("union index %d out of range", i)
+		}
+		return d.decode(s[i], ns)
+	case map[string]interface{}:
+		return d.decodeComplex(s, ns)
+	}
+	return nil, fmt.Errorf("invalid Avro schema %v", schema)
+}
+
+// decodeNamed reads a primitive or a previously defined named type
+func (d *avroDecoder) decodeNamed(name, ns string) (interface{}, error) {
+	switch name {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		return d.readLong()
+	case "float":
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case "double":
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes":
+		b, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		return bytesValue(b), nil
+	case "string":
+		b, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+
+	if schema, ok := d.named[fullName(name, ns)]; ok {
+		return d.decode(schema, ns)
+	}
+	if schema, ok := d.named[name]; ok {
+		return d.decode(schema, ns)
+	}
+	return nil, fmt.Errorf("unknown Avro type %q", name)
+}
+
+// decodeComplex reads a value of a schema given as a JSON object
+func (d *avroDecoder) decodeComplex(s map[string]interface{}, ns string) (interface{}, error) {
+	typ, ok := s["type"].(string)
+	if !ok {
+		// A nested schema such as {"type": {"type": "array", ...}}
+		return d.decode(s["type"], ns)
+	}
+
+	switch typ {
+	case "record", "error":
+		ns = d.define(s, ns)
+		fields, _ := s["fields"].([]interface{})
+		obj := make(jsonObject, 0, len(fields))
+		for _, f := range fields {
+			field, _ := f.(map[string]interface{})
+			name, _ := field["name"].(string)
+			v, err := d.decode(field["type"], ns)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			obj = append(obj, jsonField{name: name, value: v})
+		}
+		return obj, nil
+	case "enum":
+		d.define(s, ns)
+		symbols, _ := s["symbols"].([]interface{})
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(symbols) {
+			return nil, fmt.Errorf("enum index %d out of range", i)
+		}
+		return symbols[i], nil
+	case "fixed":
+		d.define(s, ns)
+		size, _ := s["size"].(float64)
+		b, err := d.read(int(size))
+		if err != nil {
+			return nil, err
+		}
+		return bytesValue(b), nil
+	case "array":
+		items := []interface{}{}
+		err := d.readBlocks(func() error {
+			v, err := d.decode(s["items"], ns)
+			items = append(items, v)
+			return err
+		})
+		return items, err
+	case "map":
+		values := jsonObject{}
+		err := d.readBlocks(func() error {
+			k, err := d.readBytes()
+			if err != nil {
+				return err
+			}
+			v, err := d.decode(s["values"], ns)
+			values = append(values, jsonField{name: string(k), value: v})
+			return err
+		})
+		return values, err
+	}
+
+	// A primitive or named type, possibly with a logical type
+	return d.decodeNamed(typ, ns)
+}
+
+// define remembers a named type and returns the namespace its fields use
+func (d *avroDecoder) define(s map[string]interface{}, ns string) string {
+	name, _ := s["name"].(string)
+	if space, ok := s["namespace"].(string); ok {
+		ns = space
+	}
+	full := fullName(name, ns)
+	d.named[full] = s
+	if i := strings.LastIndex(full, "."); i >= 0 {
+		return full[:i]
+	}
+	return ""
+}
+
+// fullName qualifies name with the namespace unless it already has one
+func fullName(name, ns string) string {
+	if ns == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return ns + "." + name
+}
+
+// readBlocks reads the blocks of an array or map, calling item for each
+// entry. A negative block count is followed by the block size in bytes.
+func (d *avroDecoder) readBlocks(item func() error) error {
+	for {
+		n, err := d.readLong()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if n < 0 {
+			n = -n
+			if _, err := d.readLong(); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < n; i++ {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readLong reads a zigzag-encoded variable-length integer
+func (d *avroDecoder) readLong() (int64, error) {
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, errShortData
+	}
+	d.pos += n
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+// readBytes reads a length-prefixed byte string
+func (d *avroDecoder) readBytes() ([]byte, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("negative length %d", n)
+	}
+	return d.read(int(n))
+}
+
+// read reads n raw bytes
+func (d *avroDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errShortData
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
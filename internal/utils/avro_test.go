@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// avroLong zigzag-encodes an Avro int or long
+func avroLong(v int64) []byte {
+	return binary.AppendUvarint(nil, uint64((v<<1)^(v>>63)))
+}
+
+// avroString encodes an Avro string or bytes value
+func avroString(s string) []byte {
+	return append(avroLong(int64(len(s))), s...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+const orderSchema = `{
+	"type": "record", "name": "Order", "namespace": "shop",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "customer", "type": ["null", "string"]},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "PAID"]}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "paid", "type": "boolean"},
+		{"name": "previous", "type": ["null", "Status"]},
+		{"name": "extra", "type": {"type": "map", "values": "int"}}
+	]
+}`
+
+func TestDecodeAvro(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "record in schema order",
+			schema: orderSchema,
+			data: concat(
+				avroLong(-42),
+				avroLong(1), avroString("ada"),
+				avroLong(1),
+				avroLong(2), avroString("a"), avroString("b"), avroLong(0),
+				[]byte{1},
+				avroLong(1), avroLong(0),
+				avroLong(1), avroString("n"), avroLong(7), avroLong(0),
+			),
+			want: `{"id":-42,"customer":"ada","status":"PAID","tags":["a","b"],"paid":true,"previous":"NEW","extra":{"n":7}}`,
+		},
+		{
+			name:   "null union branch and empty collections",
+			schema: orderSchema,
+			data:   concat(avroLong(1), avroLong(0), avroLong(0), avroLong(0), []byte{0}, avroLong(0), avroLong(0)),
+			want:   `{"id":1,"customer":null,"status":"NEW","tags":[],"paid":false,"previous":null,"extra":{}}`,
+		},
+		{
+			name:   "array block with byte size",
+			schema: `{"type": "array", "items": "int"}`,
+			data:   concat(avroLong(-2), avroLong(2), avroLong(3), avroLong(4), avroLong(0)),
+			want:   `[3,4]`,
+		},
+		{
+			name:   "double",
+			schema: `"double"`,
+			data:   binary.LittleEndian.AppendUint64(nil, 0x3ff8000000000000),
+			want:   `1.5`,
+		},
+		{name: "truncated", schema: orderSchema, data: avroLong(1), wantErr: true},
+		{name: "trailing bytes", schema: `"int"`, data: concat(avroLong(1), avroLong(2)), wantErr: true},
+		{name: "union index out of range", schema: `["null", "int"]`, data: avroLong(5), wantErr: true},
+		{name: "invalid schema", schema: `{"type":`, data: nil, wantErr: true},
+		{name: "unknown type", schema: `"Missing"`, data: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeAvro(tt.schema, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeAvro() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("DecodeAvro() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+)
+
+// Transforms reported by TryDecode
+const (
+	TransformGzip       = "gzip"
+	TransformBase64     = "base64"
+	TransformBase64Gzip = "base64+gzip"
+)
+
+// maxDecodedSize bounds decompressed output to guard against gzip bombs
+const maxDecodedSize = 10 << 20
+
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// TryDecode detects gzip-compressed or base64-encoded message data and
+// returns the decoded bytes with the transform applied. Base64 is only
+// decoded when the result is JSON or gzip, since short plain strings are
+// often valid base64 too. Returns data unchanged and "" when nothing applies.
+func TryDecode(data []byte) ([]byte, string) {
+	if decoded, ok := gunzip(data); ok {
+		return decoded, TransformGzip
+	}
+
+	decoded, ok := decodeBase64(bytes.TrimSpace(data))
+	if !ok {
+		return data, ""
+	}
+	if unzipped, ok := gunzip(decoded); ok {
+		return unzipped, TransformBase64Gzip
+	}
+	if IsValidJSON(decoded) {
+		return decoded, TransformBase64
+	}
+	return data, ""
+}
+
+// gunzip decompresses data if it starts with the gzip magic bytes
+func gunzip(data []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return nil, false
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(io.LimitReader(r, maxDecodedSize+1))
+	if err != nil || len(out) > maxDecodedSize {
+		return nil, false
+	}
+	return out, true
+}
+
+// decodeBase64 decodes standard or URL-safe base64, padded or not
+func decodeBase64(data []byte) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	encodings := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.URLEncoding,
+		base64.RawStdEncoding,
+		base64.RawURLEncoding,
+	}
+	for _, enc := range encodings {
+		out := make([]byte, enc.DecodedLen(len(data)))
+		n, err := enc.Decode(out, data)
+		if err == nil {
+			return out[:n], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("gzip write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTryDecode(t *testing.T) {
+	payload := []byte(`{"order":42}`)
+	gzipped := gzipBytes(t, payload)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		want          []byte
+		wantTransform string
+	}{
+		{
+			name:          "plain JSON unchanged",
+			data:          payload,
+			want:          payload,
+			wantTransform: "",
+		},
+		{
+			name:          "gzip",
+			data:          gzipped,
+			want:          payload,
+			wantTransform: TransformGzip,
+		},
+		{
+			name:          "base64 JSON",
+			data:          []byte(base64.StdEncoding.EncodeToString(payload)),
+			want:          payload,
+			wantTransform: TransformBase64,
+		},
+		{
+			name:          "unpadded URL-safe base64 JSON",
+			data:          []byte(base64.RawURLEncoding.EncodeToString(payload)),
+			want:          payload,
+			wantTransform: TransformBase64,
+		},
+		{
+			name:          "base64 with trailing newline",
+			data:          []byte(base64.StdEncoding.EncodeToString(payload) + "\n"),
+			want:          payload,
+			wantTransform: TransformBase64,
+		},
+		{
+			name:          "base64 gzip",
+			data:          []byte(base64.StdEncoding.EncodeToString(gzipped)),
+			want:          payload,
+			wantTransform: TransformBase64Gzip,
+		},
+		{
+			name:          "plain word that is valid base64 stays unchanged",
+			data:          []byte("test"),
+			want:          []byte("test"),
+			wantTransform: "",
+		},
+		{
+			name:          "truncated gzip stays unchanged",
+			data:          gzipped[:len(gzipped)/2],
+			want:          gzipped[:len(gzipped)/2],
+			wantTransform: "",
+		},
+		{
+			name:          "empty",
+			data:          []byte{},
+			want:          []byte{},
+			wantTransform: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, transform := TryDecode(tt.data)
+			if transform != tt.wantTransform {
+				t.Errorf("TryDecode() transform = %q, want %q", transform, tt.wantTransform)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("TryDecode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
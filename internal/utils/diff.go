@@ -0,0 +1,103 @@
+package utils
+
+import "strings"
+
+// DiffOp is how a line differs between two texts
+type DiffOp int
+
+const (
+	DiffEqual   DiffOp = iota // Line is in both texts
+	DiffRemoved               // Line is only in the first text
+	DiffAdded                 // Line is only in the second text
+)
+
+// DiffLine is one line of a diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// maxDiffCells bounds the longest-common-subsequence table. Larger inputs
+// are diffed as a whole replacement instead.
+const maxDiffCells = 4 << 20
+
+// DiffLines returns a line-based diff turning a into b. Lines common to both
+// are kept in order, using a longest common subsequence; removed lines come
+// before added ones where they replace each other.
+func DiffLines(a, b string) []DiffLine {
+	x, y := splitLines(a), splitLines(b)
+
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var diff []DiffLine
+	for _, line := range x[:prefix] {
+		diff = append(diff, DiffLine{DiffEqual, line})
+	}
+	diff = append(diff, diffMiddle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, line := range x[len(x)-suffix:] {
+		diff = append(diff, DiffLine{DiffEqual, line})
+	}
+	return diff
+}
+
+// diffMiddle diffs the lines between the common prefix and suffix
+func diffMiddle(x, y []string) []DiffLine {
+	var diff []DiffLine
+	if (len(x)+1)*(len(y)+1) > maxDiffCells {
+		for _, line := range x {
+			diff = append(diff, DiffLine{DiffRemoved, line})
+		}
+		for _, line := range y {
+			diff = append(diff, DiffLine{DiffAdded, line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			diff = append(diff, DiffLine{DiffEqual, x[i]})
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, DiffLine{DiffRemoved, x[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{DiffAdded, y[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// diffString renders a diff as "+added", "-removed" and " equal" lines
+func diffString(diff []DiffLine) string {
+	var lines []string
+	for _, d := range diff {
+		switch d.Op {
+		case DiffAdded:
+			lines = append(lines, "+"+d.Text)
+		case DiffRemoved:
+			lines = append(lines, "-"+d.Text)
+		default:
+			lines = append(lines, " "+d.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    "{\n  \"id\": 1\n}",
+			b:    "{\n  \"id\": 1\n}",
+			want: " {\n   \"id\": 1\n }",
+		},
+		{
+			name: "changed line",
+			a:    "{\n  \"id\": 1,\n  \"type\": \"order\"\n}",
+			b:    "{\n  \"id\": 2,\n  \"type\": \"order\"\n}",
+			want: " {\n-  \"id\": 1,\n+  \"id\": 2,\n   \"type\": \"order\"\n }",
+		},
+		{
+			name: "added and removed lines",
+			a:    "a\nb\nc\nd",
+			b:    "a\nc\nd\ne",
+			want: " a\n-b\n c\n d\n+e",
+		},
+		{
+			name: "empty to text",
+			a:    "",
+			b:    "a\nb",
+			want: "+a\n+b",
+		},
+		{
+			name: "trailing newline ignored",
+			a:    "a\n",
+			b:    "a",
+			want: " a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffString(DiffLines(tt.a, tt.b)); got != tt.want {
+				t.Errorf("DiffLines() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines_Large(t *testing.T) {
+	// Beyond the table limit the middle is replaced wholesale
+	var a, b []string
+	for i := 0; i < 3000; i++ {
+		a = append(a, "a"+strings.Repeat("x", i%7))
+		b = append(b, "b"+strings.Repeat("x", i%7))
+	}
+	diff := DiffLines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+	var removed, added []string
+	for _, d := range diff {
+		switch d.Op {
+		case DiffRemoved:
+			removed = append(removed, d.Text)
+		case DiffAdded:
+			added = append(added, d.Text)
+		default:
+			t.Fatalf("unexpected equal line %q", d.Text)
+		}
+	}
+	if !reflect.DeepEqual(removed, a) || !reflect.DeepEqual(added, b) {
+		t.Error("large diff should remove every old line and add every new one")
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -9,20 +12,21 @@ import (
 
 // JSONFile represents a JSON file in the working directory
 type JSONFile struct {
-	Name     string // File name without path
+	Name     string // File name without path (relative path when listed recursively)
 	Path     string // Full path to file
 	Size     int64  // File size in bytes
 	Modified int64  // Unix timestamp of last modification
 }
 
-// ListJSONFiles returns all JSON files in the specified directory
+// ListJSONFiles returns all JSON files in the specified directory, or in
+// the working directory when dir is empty. Paths are absolute.
 func ListJSONFiles(dir string) ([]JSONFile, error) {
 	if dir == "" {
-		var err error
-		dir, err = os.Getwd()
-		if err != nil {
-			return nil, err
-		}
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
 	}
 
 	entries, err := os.ReadDir(dir)
@@ -62,6 +66,131 @@ func ListJSONFiles(dir string) ([]JSONFile, error) {
 	return files, nil
 }
 
+// skippedDirs are directories never searched for JSON files
+var skippedDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+}
+
+// skipDir reports whether a recursive search skips the directory name.
+// Hidden directories such as .git are skipped too.
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || skippedDirs[name]
+}
+
+// ListJSONFilesRecursive returns all JSON files in dir and its
+// subdirectories, named by their path relative to dir. Symbolic links are
+// not followed, and hidden and vendor directories are skipped.
+func ListJSONFilesRecursive(dir string) ([]JSONFile, error) {
+	if dir == "" {
+		dir = "."
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []JSONFile
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // Skip unreadable entries
+		}
+		if d.IsDir() {
+			if path != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !strings.HasSuffix(strings.ToLower(d.Name()), ".json") {
+			return nil // Symlinks and other special files
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, JSONFile{
+			Name:     rel,
+			Path:     path,
+			Size:     info.Size(),
+			Modified: info.ModTime().Unix(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort by relative path
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	return files, nil
+}
+
+// ListSubdirs returns dir and every subdirectory a recursive search visits
+func ListSubdirs(dir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
+
+// StatJSONFile describes the file at path, which may be outside the working
+// directory. Files elsewhere keep the path as given for their name so they
+// stand apart from listed files. It fails unless path is a readable file.
+func StatJSONFile(path string) (JSONFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return JSONFile{}, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return JSONFile{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return JSONFile{}, fmt.Errorf("%s is not a regular file", path)
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return JSONFile{}, err
+	}
+	f.Close()
+
+	name := filepath.Base(abs)
+	if cwd, err := os.Getwd(); err != nil || filepath.Dir(abs) != cwd {
+		name = filepath.Clean(path)
+	}
+	return JSONFile{
+		Name:     name,
+		Path:     abs,
+		Size:     info.Size(),
+		Modified: info.ModTime().Unix(),
+	}, nil
+}
+
 // ReadFile reads the entire contents of a file
 func ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
@@ -73,4 +202,53 @@ func FileExists(path string) bool {
 	return err == nil
 }
 
+// ValidateFileName checks that name is a plain file name that can be
+// created in a directory (no path separators or reserved names)
+func ValidateFileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("file name is empty")
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf("invalid file name %q", name)
+	}
+	if strings.ContainsAny(name, "/\\\x00") {
+		return fmt.Errorf("file name %q must not contain path separators", name)
+	}
+	return nil
+}
+
+// WriteNewFile writes data to a new file named name in dir.
+// If dir is empty, the current working directory is used.
+// It fails if the file already exists rather than overwriting it.
+func WriteNewFile(dir, name string, data []byte) (string, error) {
+	if err := ValidateFileName(name); err != nil {
+		return "", err
+	}
+
+	if dir == "" {
+		var err error
+		dir, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("file %q already exists", name)
+		}
+		return "", err
+	}
 
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		wantErr  bool
+	}{
+		{
+			name:     "simple name",
+			fileName: "order-event.json",
+			wantErr:  false,
+		},
+		{
+			name:     "empty name",
+			fileName: "",
+			wantErr:  true,
+		},
+		{
+			name:     "whitespace only",
+			fileName: "   ",
+			wantErr:  true,
+		},
+		{
+			name:     "dot",
+			fileName: ".",
+			wantErr:  true,
+		},
+		{
+			name:     "parent directory",
+			fileName: "..",
+			wantErr:  true,
+		},
+		{
+			name:     "contains slash",
+			fileName: "sub/file.json",
+			wantErr:  true,
+		},
+		{
+			name:     "contains backslash",
+			fileName: `sub\file.json`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFileName(tt.fileName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFileName(%q) error = %v, wantErr %v", tt.fileName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteNewFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := WriteNewFile(dir, "new.json", []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("WriteNewFile() error = %v", err)
+	}
+	if path != filepath.Join(dir, "new.json") {
+		t.Errorf("WriteNewFile() path = %q, want %q", path, filepath.Join(dir, "new.json"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read written file: %v", err)
+	}
+	if string(data) != `{"a":1}` {
+		t.Errorf("file content = %q, want %q", string(data), `{"a":1}`)
+	}
+
+	// Writing the same name again must not overwrite
+	if _, err := WriteNewFile(dir, "new.json", []byte(`{}`)); err == nil {
+		t.Error("WriteNewFile() should fail when the file already exists")
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != `{"a":1}` {
+		t.Error("existing file should not be overwritten")
+	}
+
+	// Invalid names are rejected
+	if _, err := WriteNewFile(dir, "../escape.json", []byte(`{}`)); err == nil {
+		t.Error("WriteNewFile() should reject names with path separators")
+	}
+}
+
+func TestStatJSONFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "order.json")
+	if err := os.WriteFile(path, []byte(`{"id":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := StatJSONFile(path)
+	if err != nil {
+		t.Fatalf("StatJSONFile() error = %v", err)
+	}
+	if file.Path != path || file.Size != 8 {
+		t.Errorf("StatJSONFile() = %+v, want path %s and size 8", file, path)
+	}
+	// Outside the working directory the path is kept as the name
+	if file.Name != path {
+		t.Errorf("StatJSONFile() name = %q, want %q", file.Name, path)
+	}
+
+	if _, err := StatJSONFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("StatJSONFile() should fail for a missing file")
+	}
+	if _, err := StatJSONFile(dir); err == nil {
+		t.Error("StatJSONFile() should fail for a directory")
+	}
+}
+
+func TestListJSONFilesRecursive(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"top.json",
+		"notes.txt",
+		"orders/created.json",
+		"orders/eu/refund.JSON",
+		".git/config.json",
+		"vendor/lib.json",
+		"node_modules/pkg/package.json",
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Symlinks, to files or directories, are not followed
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "linked.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "linkdir")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "linked.json"), filepath.Join(root, "link.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ListJSONFilesRecursive(root)
+	if err != nil {
+		t.Fatalf("ListJSONFilesRecursive() error = %v", err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+		if f.Path != filepath.Join(root, f.Name) {
+			t.Errorf("file %s has path %s, want it under %s", f.Name, f.Path, root)
+		}
+	}
+	want := []string{
+		filepath.Join("orders", "created.json"),
+		filepath.Join("orders", "eu", "refund.JSON"),
+		"top.json",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("ListJSONFilesRecursive() names = %v, want %v", names, want)
+	}
+
+	dirs, err := ListSubdirs(root)
+	if err != nil {
+		t.Fatalf("ListSubdirs() error = %v", err)
+	}
+	wantDirs := []string{root, filepath.Join(root, "orders"), filepath.Join(root, "orders", "eu")}
+	if !reflect.DeepEqual(dirs, wantDirs) {
+		t.Errorf("ListSubdirs() = %v, want %v", dirs, wantDirs)
+	}
+
+	if _, err := ListJSONFilesRecursive(filepath.Join(root, "missing")); err == nil {
+		t.Error("ListJSONFilesRecursive() should fail for a missing directory")
+	}
+}
@@ -3,12 +3,46 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
 )
 
-// FormatJSON formats JSON data with indentation
+// DefaultJSONIndent is the indentation used when none is configured
+const DefaultJSONIndent = "  "
+
+// JSONIndentEnvVar configures the JSON display indentation: a number of
+// spaces (1-8) or "tab"
+const JSONIndentEnvVar = "PUBSUB_TUI_JSON_INDENT"
+
+// JSONIndentFromEnv returns the indentation configured by
+// PUBSUB_TUI_JSON_INDENT, or DefaultJSONIndent when unset
+func JSONIndentFromEnv() (string, error) {
+	v := strings.TrimSpace(os.Getenv(JSONIndentEnvVar))
+	if v == "" {
+		return DefaultJSONIndent, nil
+	}
+	if strings.EqualFold(v, "tab") || v == `\t` {
+		return "\t", nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > 8 {
+		return DefaultJSONIndent, fmt.Errorf("%s must be a number of spaces (1-8) or \"tab\", got %q", JSONIndentEnvVar, v)
+	}
+	return strings.Repeat(" ", n), nil
+}
+
+// FormatJSON formats JSON data with the default indentation
 func FormatJSON(data []byte) (string, error) {
+	return FormatJSONIndent(data, DefaultJSONIndent)
+}
+
+// FormatJSONIndent formats JSON data with the given indentation
+func FormatJSONIndent(data []byte, indent string) (string, error) {
 	var out bytes.Buffer
-	err := json.Indent(&out, data, "", "  ")
+	err := json.Indent(&out, data, "", indent)
 	if err != nil {
 		// If it's not valid JSON, return as-is
 		return string(data), nil
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"os"
 	"testing"
 )
 
@@ -246,4 +247,81 @@ func TestPrettyPrint(t *testing.T) {
 	}
 }
 
+func TestFormatJSONIndent(t *testing.T) {
+	data := []byte(`{"outer":{"inner":[1]}}`)
 
+	tests := []struct {
+		name   string
+		indent string
+		want   string
+	}{
+		{
+			name:   "four spaces",
+			indent: "    ",
+			want:   "{\n    \"outer\": {\n        \"inner\": [\n            1\n        ]\n    }\n}",
+		},
+		{
+			name:   "tab",
+			indent: "\t",
+			want:   "{\n\t\"outer\": {\n\t\t\"inner\": [\n\t\t\t1\n\t\t]\n\t}\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatJSONIndent(data, tt.indent)
+			if err != nil {
+				t.Fatalf("FormatJSONIndent() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatJSONIndent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// FormatJSON keeps the default two-space indentation
+	got, _ := FormatJSON(data)
+	want, _ := FormatJSONIndent(data, DefaultJSONIndent)
+	if got != want {
+		t.Errorf("FormatJSON() = %q, want %q", got, want)
+	}
+}
+
+func TestJSONIndentFromEnv(t *testing.T) {
+	original, set := os.LookupEnv(JSONIndentEnvVar)
+	defer func() {
+		if set {
+			os.Setenv(JSONIndentEnvVar, original)
+		} else {
+			os.Unsetenv(JSONIndentEnvVar)
+		}
+	}()
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: "  "},
+		{value: "4", want: "    "},
+		{value: "tab", want: "\t"},
+		{value: "TAB", want: "\t"},
+		{value: `\t`, want: "\t"},
+		{value: "0", want: DefaultJSONIndent, wantErr: true},
+		{value: "9", want: DefaultJSONIndent, wantErr: true},
+		{value: "wide", want: DefaultJSONIndent, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			os.Setenv(JSONIndentEnvVar, tt.value)
+			got, err := JSONIndentFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSONIndentFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("JSONIndentFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
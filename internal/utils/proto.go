@@ -0,0 +1,633 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoField is a field of a message in a .proto definition
+type protoField struct {
+	name     string
+	number   protowire.Number
+	typ      string // Scalar type or resolved full message/enum name
+	repeated bool
+	key      string // Map key type, empty unless this is a map field
+}
+
+// protoMessage is a message type in a .proto definition
+type protoMessage struct {
+	fields   []*protoField
+	byNumber map[protowire.Number]*protoField
+}
+
+// protoSchema holds the message and enum types of a .proto definition by
+// full name, without the package
+type protoSchema struct {
+	messages map[string]*protoMessage
+	enums    map[string]map[int32]string
+	first    string // First top-level message, the one Pub/Sub validates
+}
+
+// DecodeProto decodes Protocol Buffer binary data to JSON using a .proto
+// definition, as the first message type in it, which is the type Pub/Sub
+// validates messages against. Fields keep their definition order and
+// unknown fields are skipped.
+func DecodeProto(definition string, data []byte) ([]byte, error) {
+	schema, err := parseProto(definition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proto definition: %w", err)
+	}
+	if schema.first == "" {
+		return nil, errors.New("proto definition has no message type")
+	}
+	v, err := schema.decodeMessage(schema.messages[schema.first], data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// decodeMessage decodes the wire data of a message
+func (s *protoSchema) decodeMessage(msg *protoMessage, data []byte) (jsonObject, error) {
+	values := make(map[protowire.Number][]interface{})
+	for len(data) > 0 {
+		num, wt, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		field := msg.byNumber[num]
+		if field == nil {
+			n = protowire.ConsumeFieldValue(num, wt, data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+
+		vs, n, err := s.decodeField(field, wt, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+		data = data[n:]
+		if field.repeated {
+			values[num] = append(values[num], vs...)
+		} else {
+			values[num] = vs // The last value wins
+		}
+	}
+
+	obj := jsonObject{}
+	for _, f := range msg.fields {
+		vs, ok := values[f.number]
+		switch {
+		case !ok:
+		case f.key != "":
+			entries := jsonObject{}
+			for _, v := range vs {
+				entry := v.(jsonObject)
+				var key, value interface{}
+				for _, e := range entry {
+					if e.name == "key" {
+						key = e.value
+					} else {
+						value = e.value
+					}
+				}
+				entries = append(entries, jsonField{name: fmt.Sprint(key), value: value})
+			}
+			obj = append(obj, jsonField{name: f.name, value: entries})
+		case f.repeated:
+			obj = append(obj, jsonField{name: f.name, value: vs})
+		default:
+			obj = append(obj, jsonField{name: f.name, value: vs[0]})
+		}
+	}
+	return obj, nil
+}
+
+// decodeField decodes one field value, or several for a packed repeated
+// field, returning the bytes consumed
+func (s *protoSchema) decodeField(f *protoField, wt protowire.Type, data []byte) ([]interface{}, int, error) {
+	if wt == protowire.BytesType && f.repeated && f.key == "" && s.isPackable(f.typ) {
+		b, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		var vs []interface{}
+		for len(b) > 0 {
+			v, m, err := s.decodeValue(f, packedWireType(f.typ), b)
+			if err != nil {
+				return nil, 0, err
+			}
+			vs = append(vs, v)
+			b = b[m:]
+		}
+		return vs, n, nil
+	}
+
+	v, n, err := s.decodeValue(f, wt, data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return []interface{}{v}, n, nil
+}
+
+// decodeValue decodes a single value of a field
+func (s *protoSchema) decodeValue(f *protoField, wt protowire.Type, data []byte) (interface{}, int, error) {
+	if want := s.wireType(f); wt != want {
+		return nil, 0, fmt.Errorf("wire type %d does not match type %s", wt, f.typ)
+	}
+
+	switch wt {
+	case protowire.VarintType:
+		v, n := protowire.ConsumeVarint(data)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		switch f.typ {
+		case "bool":
+			return v != 0, n, nil
+		case "int32":
+			return int32(v), n, nil
+		case "int64":
+			return int64(v), n, nil
+		case "uint32":
+			return uint32(v), n, nil
+		case "sint32", "sint64":
+			return protowire.DecodeZigZag(v), n, nil
+		}
+		if names, ok := s.enums[f.typ]; ok {
+			if name, ok := names[int32(v)]; ok {
+				return name, n, nil
+			}
+			return int32(v), n, nil
+		}
+		return v, n, nil
+	case protowire.Fixed32Type:
+		v, n := protowire.ConsumeFixed32(data)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		switch f.typ {
+		case "float":
+			return math.Float32frombits(v), n, nil
+		case "sfixed32":
+			return int32(v), n, nil
+		}
+		return v, n, nil
+	case protowire.Fixed64Type:
+		v, n := protowire.ConsumeFixed64(data)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		switch f.typ {
+		case "double":
+			return math.Float64frombits(v), n, nil
+		case "sfixed64":
+			return int64(v), n, nil
+		}
+		return v, n, nil
+	case protowire.BytesType:
+		b, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		switch {
+		case f.key != "":
+			// A map entry is a message with the key and value as fields
+			entry := &protoMessage{byNumber: map[protowire.Number]*protoField{}}
+			entry.add(&protoField{name: "key", number: 1, typ: f.key})
+			entry.add(&protoField{name: "value", number: 2, typ: f.typ})
+			v, err := s.decodeMessage(entry, b)
+			return v, n, err
+		case f.typ == "string":
+			return string(b), n, nil
+		case f.typ == "bytes":
+			return bytesValue(b), n, nil
+		}
+		v, err := s.decodeMessage(s.messages[f.typ], b)
+		return v, n, err
+	}
+	return nil, 0, fmt.Errorf("unsupported wire type %d", wt)
+}
+
+// wireType returns the wire type of a single value of the field
+func (s *protoSchema) wireType(f *protoField) protowire.Type {
+	if f.key != "" || !s.isPackable(f.typ) {
+		return protowire.BytesType
+	}
+	return packedWireType(f.typ)
+}
+
+// isPackable reports whether a repeated field of typ may be packed
+func (s *protoSchema) isPackable(typ string) bool {
+	switch typ {
+	case "string", "bytes":
+		return false
+	}
+	_, isMessage := s.messages[typ]
+	return !isMessage
+}
+
+// packedWireType returns the wire type of each element of a packed field
+func packedWireType(typ string) protowire.Type {
+	switch typ {
+	case "fixed32", "sfixed32", "float":
+		return protowire.Fixed32Type
+	case "fixed64", "sfixed64", "double":
+		return protowire.Fixed64Type
+	}
+	return protowire.VarintType
+}
+
+// add appends a field to the message
+func (m *protoMessage) add(f *protoField) {
+	m.fields = append(m.fields, f)
+	m.byNumber[f.number] = f
+}
+
+// protoScalars are the built-in field types
+var protoScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true,
+	"uint64": true, "sint32": true, "sint64": true, "fixed32": true,
+	"fixed64": true, "sfixed32": true, "sfixed64": true, "bool": true,
+	"string": true, "bytes": true,
+}
+
+// protoParser parses the subset of the .proto language that describes
+// messages and enums. Services, options and extensions are skipped.
+type protoParser struct {
+	tokens []string
+	pos    int
+	schema *protoSchema
+	pkg    string // Package, dropped from qualified type names
+	// Field types as written, resolved once every type is known
+	pending []pendingType
+}
+
+// pendingType is a field type awaiting resolution in its message's scope
+type pendingType struct {
+	field *protoField
+	scope string
+	key   bool // Resolve the map key type rather than the value type
+}
+
+// parseProto parses a .proto definition
+func parseProto(definition string) (*protoSchema, error) {
+	tokens, err := tokenizeProto(definition)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{
+		tokens: tokens,
+		schema: &protoSchema{messages: map[string]*protoMessage{}, enums: map[string]map[int32]string{}},
+	}
+	for p.pos < len(p.tokens) {
+		if err := p.parseStatement(""); err != nil {
+			return nil, err
+		}
+	}
+	for _, pt := range p.pending {
+		typ := &pt.field.typ
+		if pt.key {
+			typ = &pt.field.key
+		}
+		resolved, ok := p.resolve(*typ, pt.scope)
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q", *typ)
+		}
+		*typ = resolved
+	}
+	return p.schema, nil
+}
+
+// parseStatement parses a top-level or message-level statement
+func (p *protoParser) parseStatement(scope string) error {
+	switch tok := p.next(); tok {
+	case ";":
+		return nil
+	case "message":
+		return p.parseMessage(scope)
+	case "enum":
+		return p.parseEnum(scope)
+	case "service", "extend":
+		return p.skipBlock()
+	case "package":
+		p.pkg = p.next()
+		return p.skipStatement()
+	case "syntax", "import", "option", "edition":
+		return p.skipStatement()
+	default:
+		return fmt.Errorf("unexpected %q", tok)
+	}
+}
+
+// parseMessage parses a message and its nested types
+func (p *protoParser) parseMessage(scope string) error {
+	name := qualify(scope, p.next())
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	msg := &protoMessage{byNumber: map[protowire.Number]*protoField{}}
+	p.schema.messages[name] = msg
+	if scope == "" && p.schema.first == "" {
+		p.schema.first = name
+	}
+	return p.parseMessageBody(name, msg)
+}
+
+// parseMessageBody parses message members up to the closing brace. Oneof
+// members are parsed as ordinary fields of the message.
+func (p *protoParser) parseMessageBody(name string, msg *protoMessage) error {
+	for {
+		switch tok := p.peek(); tok {
+		case "":
+			return errors.New("unexpected end of definition")
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "message", "enum":
+			if err := p.parseStatement(name); err != nil {
+				return err
+			}
+		case "oneof":
+			p.next()
+			p.next() // Name
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(name, msg); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "extend":
+			p.next()
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+		default:
+			if err := p.parseField(name, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseField parses a field such as "repeated string tags = 3 [packed=true];"
+func (p *protoParser) parseField(scope string, msg *protoMessage) error {
+	f := &protoField{}
+	typ := p.next()
+	switch typ {
+	case "repeated":
+		f.repeated = true
+		typ = p.next()
+	case "optional", "required":
+		typ = p.next()
+	}
+	if typ == "map" {
+		if err := p.expect("<"); err != nil {
+			return err
+		}
+		f.key = p.next()
+		if err := p.expect(","); err != nil {
+			return err
+		}
+		typ = p.next()
+		if err := p.expect(">"); err != nil {
+			return err
+		}
+		f.repeated = true
+	}
+	f.typ = typ
+	f.name = p.next()
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	num, err := strconv.Atoi(p.next())
+	if err != nil {
+		return fmt.Errorf("field %s: invalid number: %w", f.name, err)
+	}
+	f.number = protowire.Number(num)
+	if err := p.skipStatement(); err != nil {
+		return err
+	}
+
+	msg.add(f)
+	if !protoScalars[f.typ] {
+		p.pending = append(p.pending, pendingType{field: f, scope: scope})
+	}
+	if f.key != "" && !protoScalars[f.key] {
+		p.pending = append(p.pending, pendingType{field: f, scope: scope, key: true})
+	}
+	return nil
+}
+
+// parseEnum parses an enum's values
+func (p *protoParser) parseEnum(scope string) error {
+	name := qualify(scope, p.next())
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	values := map[int32]string{}
+	p.schema.enums[name] = values
+	for {
+		switch tok := p.next(); tok {
+		case "":
+			return errors.New("unexpected end of definition")
+		case "}":
+			return nil
+		case ";":
+		case "option", "reserved":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		default:
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			num, err := strconv.ParseInt(p.next(), 0, 32)
+			if err != nil {
+				return fmt.Errorf("enum %s: invalid value: %w", tok, err)
+			}
+			if _, ok := values[int32(num)]; !ok {
+				values[int32(num)] = tok // The first name of an alias wins
+			}
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolve finds the full name of a type used in scope, searching from the
+// innermost scope outwards as protoc does
+func (p *protoParser) resolve(typ, scope string) (string, bool) {
+	if strings.HasPrefix(typ, ".") {
+		typ = strings.TrimPrefix(typ[1:], p.pkg+".")
+		return typ, p.known(typ)
+	}
+	if p.pkg != "" && strings.HasPrefix(typ, p.pkg+".") {
+		if name := strings.TrimPrefix(typ, p.pkg+"."); p.known(name) {
+			return name, true
+		}
+	}
+	for {
+		if name := qualify(scope, typ); p.known(name) {
+			return name, true
+		}
+		if scope == "" {
+			break
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+	return "", false
+}
+
+// known reports whether a message or enum has the full name
+func (p *protoParser) known(name string) bool {
+	_, isMessage := p.schema.messages[name]
+	_, isEnum := p.schema.enums[name]
+	return isMessage || isEnum
+}
+
+// skipStatement skips to the end of the current statement, including any
+// bracketed options
+func (p *protoParser) skipStatement() error {
+	for {
+		switch p.next() {
+		case "":
+			return errors.New("unexpected end of definition")
+		case ";":
+			return nil
+		case "{":
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// skipBlock skips past the closing brace of the current block, or of the
+// next block if none is open yet
+func (p *protoParser) skipBlock() error {
+	depth := 0
+	for {
+		switch p.next() {
+		case "":
+			return errors.New("unexpected end of definition")
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// expect consumes tok or fails
+func (p *protoParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		return fmt.Errorf("expected %q, got %q", tok, got)
+	}
+	return nil
+}
+
+// next consumes a token, returning "" at the end
+func (p *protoParser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.pos++
+	}
+	return tok
+}
+
+// peek returns the next token without consuming it
+func (p *protoParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// qualify joins a scope and a name
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// tokenizeProto splits a .proto definition into identifiers, numbers,
+// quoted strings and symbols, dropping comments
+func tokenizeProto(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, src[i:j+1])
+			i = j + 1
+		case isProtoWord(c) || c == '.' || c == '-':
+			j := i + 1
+			for j < len(src) && (isProtoWord(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// isProtoWord reports whether c can appear in an identifier or number
+func isProtoWord(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const orderProto = `
+syntax = "proto3";
+package shop.v1;
+
+// The first message is the one Pub/Sub validates
+message Order {
+  int64 id = 1;
+  string customer = 2;
+  Status status = 3 [deprecated = true];
+  repeated int32 quantities = 4;
+  repeated Item items = 5;
+  map<string, int32> extra = 6;
+  oneof payment {
+    string card = 7;
+    sint32 credit = 8;
+  }
+  shop.v1.Order.Note note = 9;
+
+  message Note {
+    string text = 1;
+  }
+}
+
+/* Nested and top-level types are both resolved */
+message Item {
+  string sku = 1;
+  double price = 2;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_PAID = 1;
+}
+`
+
+func TestDecodeProto(t *testing.T) {
+	item := protowire.AppendTag(nil, 1, protowire.BytesType)
+	item = protowire.AppendString(item, "A1")
+	item = protowire.AppendTag(item, 2, protowire.Fixed64Type)
+	item = protowire.AppendFixed64(item, 0x3ff8000000000000) // 1.5
+
+	entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, "n")
+	entry = protowire.AppendTag(entry, 2, protowire.VarintType)
+	entry = protowire.AppendVarint(entry, 7)
+
+	note := protowire.AppendTag(nil, 1, protowire.BytesType)
+	note = protowire.AppendString(note, "hi")
+
+	var packed []byte
+	packed = protowire.AppendVarint(packed, 2)
+	packed = protowire.AppendVarint(packed, 3)
+
+	// Fields out of order, with an unknown field, as encoders may write them
+	var data []byte
+	data = protowire.AppendTag(data, 2, protowire.BytesType)
+	data = protowire.AppendString(data, "ada")
+	data = protowire.AppendTag(data, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, 42)
+	data = protowire.AppendTag(data, 3, protowire.VarintType)
+	data = protowire.AppendVarint(data, 1)
+	data = protowire.AppendTag(data, 4, protowire.BytesType)
+	data = protowire.AppendBytes(data, packed)
+	data = protowire.AppendTag(data, 4, protowire.VarintType)
+	data = protowire.AppendVarint(data, 5)
+	data = protowire.AppendTag(data, 5, protowire.BytesType)
+	data = protowire.AppendBytes(data, item)
+	data = protowire.AppendTag(data, 6, protowire.BytesType)
+	data = protowire.AppendBytes(data, entry)
+	data = protowire.AppendTag(data, 8, protowire.VarintType)
+	data = protowire.AppendVarint(data, protowire.EncodeZigZag(-3))
+	data = protowire.AppendTag(data, 9, protowire.BytesType)
+	data = protowire.AppendBytes(data, note)
+	data = protowire.AppendTag(data, 99, protowire.VarintType)
+	data = protowire.AppendVarint(data, 1)
+
+	got, err := DecodeProto(orderProto, data)
+	if err != nil {
+		t.Fatalf("DecodeProto() error = %v", err)
+	}
+	want := `{"id":42,"customer":"ada","status":"STATUS_PAID","quantities":[2,3,5],"items":[{"sku":"A1","price":1.5}],"extra":{"n":7},"credit":-3,"note":{"text":"hi"}}`
+	if string(got) != want {
+		t.Errorf("DecodeProto() = %s, want %s", got, want)
+	}
+}
+
+func TestDecodeProto_Errors(t *testing.T) {
+	valid := protowire.AppendTag(nil, 1, protowire.VarintType)
+	valid = protowire.AppendVarint(valid, 1)
+
+	tests := []struct {
+		name       string
+		definition string
+		data       []byte
+	}{
+		{"no message", `syntax = "proto3"; enum E { A = 0; }`, valid},
+		{"unknown type", `message M { Missing m = 1; }`, valid},
+		{"unterminated", `message M { int64 id = 1;`, valid},
+		{"truncated data", orderProto, valid[:1]},
+		{"wrong wire type", `message M { string s = 1; }`, valid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := DecodeProto(tt.definition, tt.data); err == nil {
+				t.Errorf("DecodeProto() = %s, want an error", got)
+			}
+		})
+	}
+}
@@ -1,28 +1,96 @@
 package utils
 
 import (
+	"fmt"
 	"regexp"
 )
 
+// MaxFilterPatternLength bounds the size of filter patterns. Go's RE2-based
+// regexp matches in time linear in the input, so pathological patterns
+// cannot backtrack catastrophically; pattern size is the remaining cost.
+const MaxFilterPatternLength = 1024
+
 // FilterResult contains the result of a regex filter operation
 type FilterResult struct {
 	Matches bool
 	Error   error
 }
 
-// MatchesFilter checks if a string matches a regex pattern
-// Returns true if the pattern is empty (no filter applied)
-func MatchesFilter(text, pattern string) FilterResult {
+// Filter is a regex filter compiled once and matched against many items.
+// An empty pattern matches everything.
+type Filter struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// CompileFilter compiles a filter pattern. Invalid or oversized patterns
+// produce a filter whose Match reports the error.
+func CompileFilter(pattern string) Filter {
 	if pattern == "" {
-		return FilterResult{Matches: true, Error: nil}
+		return Filter{}
+	}
+	if len(pattern) > MaxFilterPatternLength {
+		return Filter{err: fmt.Errorf("pattern too long (%d > %d characters)", len(pattern), MaxFilterPatternLength)}
 	}
 
 	re, err := regexp.Compile(pattern)
 	if err != nil {
-		return FilterResult{Matches: false, Error: err}
+		return Filter{err: err}
 	}
+	return Filter{re: re}
+}
+
+// Match checks if text matches the filter
+func (f Filter) Match(text string) FilterResult {
+	if f.err != nil {
+		return FilterResult{Matches: false, Error: f.err}
+	}
+	if f.re == nil {
+		return FilterResult{Matches: true}
+	}
+	return FilterResult{Matches: f.re.MatchString(text)}
+}
 
-	return FilterResult{Matches: re.MatchString(text), Error: nil}
+// Err returns the pattern compilation error, if any
+func (f Filter) Err() error {
+	return f.err
+}
+
+// FilterCache holds the filter compiled for the most recent pattern, so
+// repeated filter passes (e.g. as new items arrive) only compile when the
+// pattern changes. The zero value is ready to use.
+type FilterCache struct {
+	pattern string
+	filter  Filter
+	valid   bool
+}
+
+// Get returns the compiled filter for pattern, compiling it on a cache miss
+func (c *FilterCache) Get(pattern string) Filter {
+	if !c.valid || c.pattern != pattern {
+		c.pattern = pattern
+		c.filter = CompileFilter(pattern)
+		c.valid = true
+	}
+	return c.filter
+}
+
+// PrefixPattern returns a regex matching text that starts with the literal
+// prefix, so names can be filtered without regex syntax. Returns "" (no
+// filter) for an empty prefix.
+func PrefixPattern(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return "^" + regexp.QuoteMeta(prefix)
+}
+
+// MatchesFilter checks if a string matches a regex pattern
+// Returns true if the pattern is empty (no filter applied).
+// Compiles the pattern on every call; use CompileFilter when matching
+// many items against the same pattern.
+func MatchesFilter(text, pattern string) FilterResult {
+	return CompileFilter(pattern).Match(text)
 }
 
 // ValidateRegex checks if a regex pattern is valid
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -266,4 +268,183 @@ func stringSliceEqual(a, b []string) bool {
 	return true
 }
 
+func TestPrefixPattern(t *testing.T) {
+	tests := []struct {
+		prefix string
+		text   string
+		want   bool
+	}{
+		{prefix: "", text: "anything", want: true},
+		{prefix: "prod", text: "prod-orders", want: true},
+		{prefix: "prod", text: "dev-prod-orders", want: false},
+		{prefix: "a.b", text: "a.b-topic", want: true},
+		{prefix: "a.b", text: "axb-topic", want: false},
+		{prefix: "orders[", text: "orders[1]", want: true},
+		{prefix: "(v2)", text: "(v2)-events", want: true},
+	}
+
+	for _, tt := range tests {
+		result := MatchesFilter(tt.text, PrefixPattern(tt.prefix))
+		if result.Error != nil {
+			t.Errorf("PrefixPattern(%q) produced an invalid regex: %v", tt.prefix, result.Error)
+			continue
+		}
+		if result.Matches != tt.want {
+			t.Errorf("PrefixPattern(%q) on %q = %v, want %v", tt.prefix, tt.text, result.Matches, tt.want)
+		}
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		text        string
+		wantMatches bool
+		wantErr     bool
+	}{
+		{
+			name:        "empty pattern matches everything",
+			pattern:     "",
+			text:        "anything",
+			wantMatches: true,
+		},
+		{
+			name:        "valid pattern",
+			pattern:     "^prod-",
+			text:        "prod-orders",
+			wantMatches: true,
+		},
+		{
+			name:        "valid pattern without match",
+			pattern:     "^prod-",
+			text:        "dev-orders",
+			wantMatches: false,
+		},
+		{
+			name:    "invalid pattern",
+			pattern: "[unclosed",
+			text:    "anything",
+			wantErr: true,
+		},
+		{
+			name:    "pattern too long",
+			pattern: strings.Repeat("a", MaxFilterPatternLength+1),
+			text:    "aaa",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := CompileFilter(tt.pattern)
+			if (f.Err() != nil) != tt.wantErr {
+				t.Fatalf("CompileFilter(%q).Err() = %v, wantErr %v", tt.pattern, f.Err(), tt.wantErr)
+			}
+			result := f.Match(tt.text)
+			if (result.Error != nil) != tt.wantErr {
+				t.Errorf("Match() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Matches != tt.wantMatches {
+				t.Errorf("Match(%q) = %v, want %v", tt.text, result.Matches, tt.wantMatches)
+			}
+		})
+	}
+}
+
+func TestFilterCache(t *testing.T) {
+	var c FilterCache
+
+	f1 := c.Get("^prod-")
+	f2 := c.Get("^prod-")
+	if f1.re != f2.re {
+		t.Error("Get() with an unchanged pattern should reuse the compiled regexp")
+	}
+
+	f3 := c.Get("^dev-")
+	if f3.re == f1.re {
+		t.Error("Get() with a new pattern should recompile")
+	}
+	if !f3.Match("dev-orders").Matches {
+		t.Error("recompiled filter should use the new pattern")
+	}
+
+	if !c.Get("").Match("anything").Matches {
+		t.Error("empty pattern should match everything")
+	}
+	if c.Get("[bad").Err() == nil {
+		t.Error("invalid pattern error should be cached too")
+	}
+}
+
+func TestCompileFilter_NestedQuantifiers(t *testing.T) {
+	// Classic catastrophic-backtracking pattern; RE2 matches it in linear time
+	f := CompileFilter("(a+)+$")
+	text := strings.Repeat("a", 10000) + "!"
+	if f.Match(text).Matches {
+		t.Error("pattern should not match text ending in !")
+	}
+}
 
+// filterBenchItems returns n subscription-like names for filter benchmarks
+func filterBenchItems(n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf("projects/demo/subscriptions/service-%d-events", i)
+	}
+	return items
+}
+
+// BenchmarkFilterCache applies an unchanged filter to 10k items repeatedly,
+// as the panels do when items arrive while a filter is active. Unlike
+// BenchmarkFilter, which compares compiling per item with once per pass, it
+// measures what the cache saves across passes.
+func BenchmarkFilterCache(b *testing.B) {
+	items := filterBenchItems(10000)
+	pattern := `service-\d+5-events$`
+
+	b.Run("compile per pass", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			f := CompileFilter(pattern)
+			for _, item := range items {
+				f.Match(item)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		var cache FilterCache
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			f := cache.Get(pattern)
+			for _, item := range items {
+				f.Match(item)
+			}
+		}
+	})
+}
+
+func BenchmarkFilter(b *testing.B) {
+	items := filterBenchItems(1000)
+	pattern := `service-\d+5-events$`
+
+	b.Run("compile per item", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, item := range items {
+				MatchesFilter(item, pattern)
+			}
+		}
+	})
+
+	b.Run("compile once", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			f := CompileFilter(pattern)
+			for _, item := range items {
+				f.Match(item)
+			}
+		}
+	})
+}
@@ -1,17 +1,88 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/anmaso/pubsub-tui/internal/app"
-	"github.com/anmaso/pubsub-tui/internal/pubsub"
+	"github.com/anmaso/pubsub-tui/internal/components/publisher"
+	"github.com/anmaso/pubsub-tui/internal/components/subscriber"
+	"github.com/anmaso/pubsub-tui/internal/config"
+	"github.com/anmaso/pubsub-tui/internal/debuglog"
+	"github.com/anmaso/pubsub-tui/internal/httpapi"
+	"github.com/anmaso/pubsub-tui/internal/inventory"
+	"github.com/anmaso/pubsub-tui/internal/utils"
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run starts the application and returns the process exit code. It is split
+// from main so deferred cleanup runs before the process exits.
+func run() int {
+	startEmulator := flag.Bool("start-emulator", false, "start a local Pub/Sub emulator before connecting (best-effort)")
+	emulatorBin := flag.String("emulator-bin", "gcloud", "gcloud binary used by --start-emulator")
+	templateDir := flag.String("template-dir", publisher.TemplateDirFromEnv(), "directory to list message files from (default: working directory; env "+publisher.TemplateDirEnvVar+")")
+	recursive := flag.Bool("recursive", false, "also list message files in subdirectories (skips hidden and vendor directories)")
+	templatePath := flag.String("template", "", "message file to select in the publisher at startup (may be outside the working directory)")
+	credentialsFile := flag.String("credentials", "", "service account key file to authenticate with (ignored with the emulator)")
+	keysPath := flag.String("keys", app.KeysPathFromEnv(), "JSON file of key binding overrides (env "+app.KeysEnvVar+"; default pubsub-tui/keys.json in the user config directory)")
+	exportPath := flag.String("export-inventory", "", "write the project's topics and subscriptions as JSON to `path` (- for stdout) and exit")
+	importPath := flag.String("import-inventory", "", "create the topics and subscriptions listed in the JSON inventory at `path` that do not exist yet, then exit")
+	noAltScreen := flag.Bool("no-alt-screen", false, "draw in the main terminal buffer instead of the alternate screen, e.g. to capture output in CI")
+	noMouse := flag.Bool("no-mouse", false, "do not capture the mouse, for terminals that mishandle mouse reporting")
+	flag.Parse()
+
+	if *exportPath != "" && *importPath != "" {
+		fmt.Fprintf(os.Stderr, "Configuration error: --export-inventory and --import-inventory cannot be combined\n")
+		return 1
+	}
+
+	// Optionally log activity and server requests to a file; the terminal
+	// belongs to the TUI
+	if debuglog.EnabledFromEnv() {
+		f, err := debuglog.Open(debuglog.FileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Debug log error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		fmt.Fprintf(os.Stderr, "Writing debug log to %s\n", debuglog.FileName)
+	}
+
+	// Optionally start an emulator, stopping it again on exit
+	if *startEmulator {
+		host := pubsub.GetEmulatorHost()
+		if host == "" {
+			host = pubsub.DefaultEmulatorHost
+		}
+		fmt.Fprintf(os.Stderr, "Starting Pub/Sub emulator on %s...\n", host)
+		emulator, err := pubsub.StartEmulator(*emulatorBin, host, 30*time.Second)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start emulator: %v\n", err)
+			fmt.Fprintf(os.Stderr, "\nEnsure the emulator component is installed:\n")
+			fmt.Fprintf(os.Stderr, "  gcloud components install pubsub-emulator\n")
+			return 1
+		}
+		defer emulator.Stop()
+		if err := os.Setenv(pubsub.EmulatorHostEnvVar, emulator.Host); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to set %s: %v\n", pubsub.EmulatorHostEnvVar, err)
+			return 1
+		}
+	}
+
 	emulatorMode := pubsub.IsEmulatorEnabled()
+	var emulatorHost string
+	if emulatorMode {
+		emulatorHost = pubsub.GetEmulatorHost()
+	}
 
 	// Verify GCP credentials and project before starting TUI
 	projectID, err := pubsub.GetProjectID()
@@ -26,45 +97,261 @@ func main() {
 			fmt.Fprintf(os.Stderr, "  1. Set GOOGLE_CLOUD_PROJECT environment variable\n")
 			fmt.Fprintf(os.Stderr, "  2. Run: gcloud config set project YOUR_PROJECT_ID\n")
 		}
-		os.Exit(1)
+		return 1
 	}
 
 	// Verify credentials (skipped in emulator mode)
-	if err := pubsub.VerifyCredentials(); err != nil {
+	if err := pubsub.VerifyCredentials(*credentialsFile); err != nil {
 		fmt.Fprintf(os.Stderr, "Authentication error: %v\n", err)
-		fmt.Fprintf(os.Stderr, "\nTo authenticate, run:\n")
-		fmt.Fprintf(os.Stderr, "  gcloud auth application-default login\n")
-		os.Exit(1)
+		if *credentialsFile != "" {
+			fmt.Fprintf(os.Stderr, "\nCheck that %s is a valid service account key file.\n", *credentialsFile)
+		} else {
+			fmt.Fprintf(os.Stderr, "\nTo authenticate, run:\n")
+			fmt.Fprintf(os.Stderr, "  gcloud auth application-default login\n")
+		}
+		return 1
+	}
+	if emulatorMode && *credentialsFile != "" {
+		fmt.Fprintf(os.Stderr, "Ignoring --credentials: the emulator does not use authentication\n")
+	}
+
+	// Load flow control settings for subscriptions
+	receiveCfg, err := config.ReceiveConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load batching settings for publishing
+	publishSettings, err := config.PublishSettingsFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load the publish retry setting
+	publishAttempts, err := config.PublishAttemptsFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load the footer settings
+	footerOpts, err := app.FooterOptionsFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load JSON display settings
+	jsonIndent, err := utils.JSONIndentFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load subscriber row colors
+	rowColors, err := subscriber.LoadRowColors(subscriber.RowColorsPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load the subscriber's delayed ack mode setting
+	ackDelay, err := subscriber.AckDelayFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	// Load key binding overrides
+	keyWarnings, err := app.LoadKeyConfig(*keysPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Key config error: %v\n", err)
+		return 1
+	}
+
+	// Check the message file directory
+	if *templateDir != "" {
+		if err := publisher.ValidateTemplateDir(*templateDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Template directory error: %v\n", err)
+			return 1
+		}
+	}
+
+	// Load the message template given on the command line
+	var template *utils.JSONFile
+	if *templatePath != "" {
+		file, err := utils.StatJSONFile(*templatePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Template error: %v\n", err)
+			return 1
+		}
+		template = &file
 	}
 
 	// Create Pub/Sub client
-	client, err := pubsub.NewClient(projectID)
+	client, err := pubsub.NewClient(projectID, *credentialsFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Pub/Sub client: %v\n", err)
 		if emulatorMode {
 			fmt.Fprintf(os.Stderr, "\nEmulator mode: ensure the emulator is running at %s\n", pubsub.GetEmulatorHost())
 		}
-		os.Exit(1)
+		return 1
 	}
 	defer client.Close()
+	client.ConfigurePublishSettings(publishSettings)
+	client.SetCallObserver(debuglog.Call)
 
 	// Print startup info
 	if emulatorMode {
 		fmt.Fprintf(os.Stderr, "Connecting to Pub/Sub emulator at %s...\n", pubsub.GetEmulatorHost())
 	}
 
+	// Dialing the emulator succeeds even when nothing is listening, so
+	// verify it responds before starting the TUI
+	if emulatorMode {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := client.Ping(ctx)
+		cancel()
+		if pubsub.IsTransientError(err) {
+			fmt.Fprintf(os.Stderr, "Error: emulator not reachable at %s: %v\n", pubsub.GetEmulatorHost(), err)
+			fmt.Fprintf(os.Stderr, "\nStart the emulator or run with --start-emulator:\n")
+			fmt.Fprintf(os.Stderr, "  gcloud beta emulators pubsub start --host-port=%s\n", pubsub.GetEmulatorHost())
+			return 1
+		}
+	}
+
+	// Export or import the inventory instead of starting the TUI
+	if *exportPath != "" {
+		return exportInventory(client, projectID, emulatorMode, *exportPath)
+	}
+	if *importPath != "" {
+		return importInventory(client, *importPath)
+	}
+
+	// Optionally serve captured messages and metrics over HTTP
+	var snapshots *httpapi.Store
+	var metrics *httpapi.Metrics
+	if addr := httpapi.AddrFromEnv(); addr != "" {
+		snapshots = httpapi.NewStore()
+		metrics = httpapi.NewMetrics(projectID)
+		server := httpapi.New(addr, snapshots, metrics)
+		if err := server.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start HTTP server on %s: %v\n", addr, err)
+			return 1
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			server.Shutdown(ctx)
+		}()
+	}
+
+	// The alternate screen and mouse capture can be turned off
+	var programOpts []tea.ProgramOption
+	if !*noAltScreen {
+		programOpts = append(programOpts, tea.WithAltScreen())
+	}
+	if !*noMouse {
+		programOpts = append(programOpts, tea.WithMouseCellMotion())
+	}
+
 	// Initialize and run the TUI application
 	p := tea.NewProgram(
-		app.New(client, projectID),
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
+		app.New(client, projectID, app.Options{
+			ReceiveConfig:      receiveCfg,
+			Snapshots:          snapshots,
+			Metrics:            metrics,
+			JSONIndent:         jsonIndent,
+			ListAttribute:      subscriber.ListAttributeFromEnv(),
+			RowColors:          rowColors,
+			AckDelay:           ackDelay,
+			EmulatorHost:       emulatorHost,
+			CredentialsFile:    *credentialsFile,
+			TemplateDir:        *templateDir,
+			RecursiveTemplates: *recursive,
+			Template:           template,
+			StatePath:          app.StatePathFromEnv(),
+			KeyWarnings:        keyWarnings,
+			PublishAttempts:    publishAttempts,
+			Footer:             footerOpts,
+		}),
+		programOpts...,
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
+	if m, ok := final.(app.Model); ok {
+		if m.ExitSummary() != "" {
+			fmt.Fprintln(os.Stderr, m.ExitSummary())
+		}
+		if err := m.SaveState(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		m.CloseClient()
+	}
+
+	return 0
+}
+
+// exportInventory writes the project's topics and subscriptions to path and
+// returns the process exit code
+func exportInventory(client *pubsub.Client, projectID string, emulator bool, path string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	inv, err := inventory.Export(ctx, client, projectID, emulator)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export error: %v\n", err)
+		return 1
+	}
+	if err := inventory.Write(path, inv); err != nil {
+		fmt.Fprintf(os.Stderr, "Export error: %v\n", err)
+		return 1
+	}
+	if path != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %d topics and %d subscriptions to %s\n", inv.Counts.Topics, inv.Counts.Subscriptions, path)
+	}
+	return 0
 }
 
+// importInventory creates the missing topics and subscriptions listed in the
+// inventory at path, printing the outcome of each, and returns the process
+// exit code: 1 if any resource failed
+func importInventory(client *pubsub.Client, path string) int {
+	inv, err := inventory.Read(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import error: %v\n", err)
+		return 1
+	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	report, err := inventory.Import(ctx, client, inv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import error: %v\n", err)
+		return 1
+	}
+	for _, res := range report.Results {
+		switch {
+		case res.Err != nil:
+			fmt.Fprintf(os.Stderr, "%-7s %s %s: %v\n", res.Status, res.Kind, res.Name, res.Err)
+		case res.Note != "":
+			fmt.Fprintf(os.Stderr, "%-7s %s %s (%s)\n", res.Status, res.Kind, res.Name, res.Note)
+		default:
+			fmt.Fprintf(os.Stderr, "%-7s %s %s\n", res.Status, res.Kind, res.Name)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nImported %s: %d created, %d skipped, %d failed\n", path,
+		report.Count(inventory.StatusCreated), report.Count(inventory.StatusSkipped), report.Count(inventory.StatusFailed))
 
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2/google"
+)
+
+// pubsubScope is the OAuth scope requested when verifying credentials
+const pubsubScope = "https://www.googleapis.com/auth/pubsub"
+
+// VerifyCredentials checks if valid GCP credentials are available. When
+// credentialsFile is set, the service account key in that file is checked
+// instead of the application default credentials.
+// When the Pub/Sub emulator is enabled (PUBSUB_EMULATOR_HOST is set),
+// credential verification is skipped as the emulator does not require authentication.
+func VerifyCredentials(credentialsFile string) error {
+	// Skip credential verification when using the emulator
+	if IsEmulatorEnabled() {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	var creds *google.Credentials
+	var err error
+	if credentialsFile != "" {
+		// Load the key file given on the command line
+		data, readErr := os.ReadFile(credentialsFile)
+		if readErr != nil {
+			return fmt.Errorf("failed to read credentials file: %w", readErr)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, data, pubsubScope)
+		if err != nil {
+			return fmt.Errorf("invalid credentials file %s: %w", credentialsFile, err)
+		}
+	} else {
+		// Find default credentials
+		creds, err = google.FindDefaultCredentials(ctx, pubsubScope)
+		if err != nil {
+			return fmt.Errorf("failed to find credentials: %w", err)
+		}
+	}
+
+	// Try to get a token to verify credentials are valid
+	_, err = creds.TokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("credentials invalid or expired: %w", err)
+	}
+
+	return nil
+}
+
+
+
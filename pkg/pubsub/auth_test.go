@@ -0,0 +1,28 @@
+package pubsub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyCredentials_KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"type":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EmulatorHostEnvVar, "")
+	for _, path := range []string{filepath.Join(dir, "missing.json"), invalid} {
+		if err := VerifyCredentials(path); err == nil {
+			t.Errorf("VerifyCredentials(%s) should fail", filepath.Base(path))
+		}
+	}
+
+	// The emulator skips verification
+	t.Setenv(EmulatorHostEnvVar, "localhost:8085")
+	if err := VerifyCredentials(invalid); err != nil {
+		t.Errorf("VerifyCredentials() in emulator mode error = %v", err)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -14,14 +17,28 @@ import (
 
 // Client wraps the GCP Pub/Sub client with additional functionality
 type Client struct {
-	client    *pubsub.Client
-	projectID string
+	client          *pubsub.Client
+	projectID       string
+	credentialsFile string // Key file for clients created later, if set
+
+	// Topics used for publishing, reused so messages can be batched
+	mu              sync.Mutex
+	topics          map[topicKey]*pubsub.Topic
+	publishSettings PublishSettings
+
+	// Schema clients by project, created on first use
+	schemaMu      sync.Mutex
+	schemaClients map[string]*pubsub.SchemaClient
+
+	observer CallObserver // Told about each request, if set
 }
 
 // NewClient creates a new Pub/Sub client for the given project.
+// credentialsFile is a service account key file to authenticate with;
+// when empty, application default credentials are used.
 // When PUBSUB_EMULATOR_HOST is set, it connects to the emulator with
-// insecure transport and no authentication.
-func NewClient(projectID string) (*Client, error) {
+// insecure transport and no authentication, ignoring credentialsFile.
+func NewClient(projectID, credentialsFile string) (*Client, error) {
 	ctx := context.Background()
 
 	var client *pubsub.Client
@@ -42,8 +59,8 @@ func NewClient(projectID string) (*Client, error) {
 			option.WithoutAuthentication(),
 		)
 	} else {
-		// Connect to real GCP with default credentials
-		client, err = pubsub.NewClient(ctx, projectID)
+		// Connect to real GCP with the key file or default credentials
+		client, err = pubsub.NewClient(ctx, projectID, clientOptions(credentialsFile)...)
 	}
 
 	if err != nil {
@@ -51,13 +68,48 @@ func NewClient(projectID string) (*Client, error) {
 	}
 
 	return &Client{
-		client:    client,
-		projectID: projectID,
+		client:          client,
+		projectID:       projectID,
+		credentialsFile: credentialsFile,
+		publishSettings: DefaultPublishSettings(),
 	}, nil
 }
 
-// Close closes the underlying Pub/Sub client
+// clientOptions returns the options for connecting to real GCP
+func clientOptions(credentialsFile string) []option.ClientOption {
+	if credentialsFile == "" {
+		return nil
+	}
+	return []option.ClientOption{option.WithCredentialsFile(credentialsFile)}
+}
+
+// Ping checks that the Pub/Sub API is reachable by requesting a single topic.
+// Dialing does not verify connectivity, so this surfaces an unreachable
+// emulator before the UI starts.
+func (c *Client) Ping(ctx context.Context) (err error) {
+	defer c.observe("Ping", time.Now(), &err)
+
+	_, err = c.client.Topics(ctx).Next()
+	if err != nil && err != iterator.Done {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Close flushes pending publishes and closes the underlying Pub/Sub client
 func (c *Client) Close() error {
+	c.mu.Lock()
+	topics := c.removeTopicsLocked(func(topicKey) bool { return true })
+	c.mu.Unlock()
+	stopTopics(topics)
+
+	c.schemaMu.Lock()
+	for project, sc := range c.schemaClients {
+		sc.Close()
+		delete(c.schemaClients, project)
+	}
+	c.schemaMu.Unlock()
+
 	return c.client.Close()
 }
 
@@ -0,0 +1,119 @@
+package pubsub
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+)
+
+func TestClientOptions(t *testing.T) {
+	if opts := clientOptions(""); opts != nil {
+		t.Errorf("clientOptions(\"\") = %v, want none", opts)
+	}
+
+	want := []option.ClientOption{option.WithCredentialsFile("/keys/ci.json")}
+	if got := clientOptions("/keys/ci.json"); !reflect.DeepEqual(got, want) {
+		t.Errorf("clientOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestNewClient_CredentialsFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	// The key file is passed to the client, which fails to read it
+	t.Setenv(EmulatorHostEnvVar, "")
+	if client, err := NewClient("test-project", missing); err == nil {
+		client.Close()
+		t.Error("NewClient() with a missing key file should fail")
+	}
+
+	// The emulator ignores credentials
+	t.Setenv(EmulatorHostEnvVar, "localhost:8085")
+	client, err := NewClient("test-project", missing)
+	if err != nil {
+		t.Fatalf("NewClient() in emulator mode error = %v", err)
+	}
+	client.Close()
+}
+
+func TestClient_CallObserver(t *testing.T) {
+	t.Setenv(EmulatorHostEnvVar, "localhost:8085")
+	client, err := NewClient("test-project", "")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	var ops []string
+	var errs []error
+	client.SetCallObserver(func(op string, elapsed time.Duration, err error) {
+		ops = append(ops, op)
+		errs = append(errs, err)
+	})
+
+	// An invalid ID fails before reaching the server, and is still observed
+	err = client.CreateTopic(context.Background(), "x")
+	if len(ops) != 1 || ops[0] != "CreateTopic" || errs[0] != err || err == nil {
+		t.Errorf("observed %v %v, want CreateTopic with its error %v", ops, errs, err)
+	}
+
+	client.SetCallObserver(nil)
+	client.CreateTopic(context.Background(), "x")
+	if len(ops) != 1 {
+		t.Error("a nil observer should stop observing")
+	}
+}
+
+func TestClient_SchemaClient(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.json")
+
+	// Schema clients use the key file given to the client
+	c := &Client{projectID: "test-project", credentialsFile: missing}
+	if _, err := c.schemaClient(context.Background(), "test-project"); err == nil {
+		t.Error("schemaClient() with a missing key file should fail")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "test-project",
+		"client_email": "ci@test-project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})),
+	})
+	key := filepath.Join(dir, "key.json")
+	if err := os.WriteFile(key, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c = &Client{projectID: "test-project", credentialsFile: key}
+	defer func() {
+		for _, sc := range c.schemaClients {
+			sc.Close()
+		}
+	}()
+	first, err := c.schemaClient(context.Background(), "test-project")
+	if err != nil {
+		t.Fatalf("schemaClient() error = %v", err)
+	}
+
+	// They are created once per project
+	if again, _ := c.schemaClient(context.Background(), "test-project"); again != first {
+		t.Error("schemaClient() should reuse the client for a project")
+	}
+	if other, _ := c.schemaClient(context.Background(), "other-project"); other == first {
+		t.Error("schemaClient() should create a client per project")
+	}
+}
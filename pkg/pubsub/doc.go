@@ -0,0 +1,6 @@
+// Package pubsub wraps the Google Cloud Pub/Sub client with the operations
+// pubsub-tui is built on: listing, creating and deleting topics and
+// subscriptions, publishing, and streaming messages from a subscription
+// with explicit acks. It works against real GCP and the emulator
+// (PUBSUB_EMULATOR_HOST), and can be used on its own to build other tools.
+package pubsub
@@ -0,0 +1,109 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// EmulatorHostEnvVar is the environment variable used to specify the Pub/Sub emulator host.
+const EmulatorHostEnvVar = "PUBSUB_EMULATOR_HOST"
+
+// DefaultEmulatorHost is the address used when starting an emulator without
+// PUBSUB_EMULATOR_HOST set
+const DefaultEmulatorHost = "localhost:8085"
+
+// IsEmulatorEnabled returns true if the Pub/Sub emulator is configured via PUBSUB_EMULATOR_HOST.
+func IsEmulatorEnabled() bool {
+	return os.Getenv(EmulatorHostEnvVar) != ""
+}
+
+// GetEmulatorHost returns the emulator host address from the environment.
+// Returns empty string if emulator is not enabled.
+func GetEmulatorHost() string {
+	return os.Getenv(EmulatorHostEnvVar)
+}
+
+// Emulator is a Pub/Sub emulator process started by StartEmulator
+type Emulator struct {
+	Host string
+
+	cmd  *exec.Cmd
+	done chan struct{} // Closed when the process exits
+}
+
+// StartEmulator runs "<bin> beta emulators pubsub start" on host, waits up to
+// timeout for it to accept connections. It leaves the environment alone:
+// callers point clients at Emulator.Host themselves.
+// This is a best-effort helper: bin must be a gcloud installation with the
+// pubsub-emulator component, and the emulator's output is discarded.
+func StartEmulator(bin, host string, timeout time.Duration) (*Emulator, error) {
+	if host == "" {
+		host = DefaultEmulatorHost
+	}
+
+	cmd := exec.Command(bin, "beta", "emulators", "pubsub", "start", "--host-port="+host)
+	configureEmulatorProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start emulator: %w", err)
+	}
+
+	e := &Emulator{Host: host, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		cmd.Wait()
+		close(e.done)
+	}()
+
+	if err := waitForPort(host, timeout, e.done); err != nil {
+		e.Stop()
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Stop terminates the emulator and its child processes, forcing a kill if
+// it has not exited within a few seconds
+func (e *Emulator) Stop() {
+	select {
+	case <-e.done:
+		return
+	default:
+	}
+
+	interruptEmulatorProcess(e.cmd)
+	select {
+	case <-e.done:
+	case <-time.After(5 * time.Second):
+		killEmulatorProcess(e.cmd)
+		<-e.done
+	}
+}
+
+// errEmulatorExited is returned when the emulator exits before it is ready
+var errEmulatorExited = errors.New("emulator exited before accepting connections")
+
+// waitForPort polls addr until it accepts TCP connections, the timeout
+// elapses or exited is closed
+func waitForPort(addr string, timeout time.Duration, exited <-chan struct{}) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, 500*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("emulator did not accept connections on %s within %s", addr, timeout)
+		}
+
+		select {
+		case <-exited:
+			return errEmulatorExited
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
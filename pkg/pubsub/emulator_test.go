@@ -1,8 +1,11 @@
 package pubsub
 
 import (
+	"errors"
+	"net"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestIsEmulatorEnabled(t *testing.T) {
@@ -232,3 +235,42 @@ func TestProjectNotFoundError(t *testing.T) {
 		}
 	})
 }
+
+func TestWaitForPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	if err := waitForPort(ln.Addr().String(), time.Second, nil); err != nil {
+		t.Errorf("waitForPort() on listening port error = %v", err)
+	}
+
+	// A closed port fails once the process has exited
+	addr := ln.Addr().String()
+	ln.Close()
+	exited := make(chan struct{})
+	close(exited)
+	if err := waitForPort(addr, 5*time.Second, exited); !errors.Is(err, errEmulatorExited) {
+		t.Errorf("waitForPort() after exit error = %v, want %v", err, errEmulatorExited)
+	}
+
+	// ...or once the timeout elapses
+	if err := waitForPort(addr, 0, nil); err == nil {
+		t.Error("waitForPort() on closed port should time out")
+	}
+}
+
+func TestStartEmulator_MissingBinary(t *testing.T) {
+	original := os.Getenv(EmulatorHostEnvVar)
+	defer os.Setenv(EmulatorHostEnvVar, original)
+	os.Unsetenv(EmulatorHostEnvVar)
+
+	if _, err := StartEmulator("pubsub-tui-no-such-gcloud", "", time.Second); err == nil {
+		t.Fatal("StartEmulator() with a missing binary should fail")
+	}
+	if IsEmulatorEnabled() {
+		t.Error("PUBSUB_EMULATOR_HOST should not be set after a failed start")
+	}
+}
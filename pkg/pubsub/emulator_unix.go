@@ -0,0 +1,24 @@
+//go:build !windows
+
+package pubsub
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureEmulatorProcess starts the emulator in its own process group so
+// the Java server gcloud spawns can be stopped along with it
+func configureEmulatorProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptEmulatorProcess asks the emulator process group to shut down
+func interruptEmulatorProcess(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+}
+
+// killEmulatorProcess forcibly stops the emulator process group
+func killEmulatorProcess(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
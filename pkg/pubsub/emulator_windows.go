@@ -0,0 +1,19 @@
+//go:build windows
+
+package pubsub
+
+import "os/exec"
+
+// configureEmulatorProcess is a no-op on Windows
+func configureEmulatorProcess(cmd *exec.Cmd) {}
+
+// interruptEmulatorProcess stops the emulator; Windows has no SIGINT for
+// child processes, so this kills it directly
+func interruptEmulatorProcess(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// killEmulatorProcess forcibly stops the emulator
+func killEmulatorProcess(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -0,0 +1,134 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error categories used to classify failures from the Pub/Sub API
+const (
+	CategoryNotFound         = "NotFound"
+	CategoryPermissionDenied = "PermissionDenied"
+	CategoryUnavailable      = "Unavailable"
+	CategoryDeadlineExceeded = "DeadlineExceeded"
+	CategoryUnimplemented    = "Unimplemented"
+	CategoryUnknown          = "Unknown"
+)
+
+// Error wraps an error returned by the Pub/Sub API with its category
+type Error struct {
+	category string
+	err      error
+}
+
+// Error returns the underlying error message
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Category returns the error category, e.g. CategoryNotFound
+func (e *Error) Category() string {
+	return e.category
+}
+
+// Transient reports whether the operation is likely to succeed on retry
+func (e *Error) Transient() bool {
+	return isTransient(e.category, e.err)
+}
+
+// isTransient reports whether an error of the given category is worth
+// retrying. Unknown errors, which include gRPC Internal errors and errors
+// without a status, are often a dropped stream, so they are retried too;
+// only a cancelled context is not, since the caller gave up on purpose.
+func isTransient(category string, err error) bool {
+	switch category {
+	case CategoryUnavailable, CategoryDeadlineExceeded:
+		return true
+	case CategoryUnknown:
+		return !errors.Is(err, context.Canceled)
+	default:
+		return false
+	}
+}
+
+// classifyError maps an error to a category based on its gRPC status code.
+// The client retries unavailable servers until the context expires, so an
+// expired context is classified as CategoryDeadlineExceeded. Other errors
+// without a gRPC status are classified as CategoryUnknown.
+func classifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryDeadlineExceeded
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return CategoryNotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return CategoryPermissionDenied
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return CategoryUnavailable
+	case codes.DeadlineExceeded:
+		return CategoryDeadlineExceeded
+	case codes.Unimplemented:
+		// Features such as snapshots are missing from some emulator versions
+		return CategoryUnimplemented
+	default:
+		return CategoryUnknown
+	}
+}
+
+// wrapError attaches a category to err. Returns nil for a nil error.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{category: classifyError(err), err: err}
+}
+
+// ErrorCategory returns the category of err, or an empty string for nil
+func ErrorCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.category
+	}
+	return classifyError(err)
+}
+
+// IsTransientError reports whether err is likely to succeed on retry,
+// such as a dropped connection or an overloaded server
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return isTransient(ErrorCategory(err), err)
+}
+
+// IsAuthError reports whether err is caused by missing or expired
+// credentials, or by a lack of permission, so re-authenticating (for
+// example with gcloud auth application-default login) may fix it
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var re *oauth2.RetrieveError
+	if errors.As(err, &re) {
+		return true
+	}
+	return ErrorCategory(err) == CategoryPermissionDenied
+}
@@ -0,0 +1,198 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "unavailable",
+			err:  status.Error(codes.Unavailable, "connection reset"),
+			want: true,
+		},
+		{
+			name: "deadline exceeded",
+			err:  status.Error(codes.DeadlineExceeded, "timeout"),
+			want: true,
+		},
+		{
+			name: "not found",
+			err:  status.Error(codes.NotFound, "subscription not found"),
+			want: false,
+		},
+		{
+			name: "permission denied",
+			err:  status.Error(codes.PermissionDenied, "denied"),
+			want: false,
+		},
+		{
+			name: "plain error is unknown",
+			err:  errors.New("something broke"),
+			want: true,
+		},
+		{
+			name: "internal",
+			err:  status.Error(codes.Internal, "stream reset"),
+			want: true,
+		},
+		{
+			name: "cancelled",
+			err:  wrapError(fmt.Errorf("failed to receive: %w", context.Canceled)),
+			want: false,
+		},
+		{
+			name: "wrapped unavailable",
+			err:  wrapError(fmt.Errorf("failed to list: %w", status.Error(codes.Unavailable, "down"))),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.want {
+				t.Errorf("IsTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"unauthenticated", status.Error(codes.Unauthenticated, "token expired"), true},
+		{"permission denied", wrapError(fmt.Errorf("failed to list: %w", status.Error(codes.PermissionDenied, "denied"))), true},
+		{"token refresh", fmt.Errorf("failed to publish: %w", &oauth2.RetrieveError{Body: []byte("invalid_grant")}), true},
+		{"not found", status.Error(codes.NotFound, "topic not found"), false},
+		{"unavailable", status.Error(codes.Unavailable, "down"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.want {
+				t.Errorf("IsAuthError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "not found",
+			err:  status.Error(codes.NotFound, "topic not found"),
+			want: CategoryNotFound,
+		},
+		{
+			name: "permission denied",
+			err:  status.Error(codes.PermissionDenied, "denied"),
+			want: CategoryPermissionDenied,
+		},
+		{
+			name: "unauthenticated",
+			err:  status.Error(codes.Unauthenticated, "no credentials"),
+			want: CategoryPermissionDenied,
+		},
+		{
+			name: "unavailable",
+			err:  status.Error(codes.Unavailable, "connection refused"),
+			want: CategoryUnavailable,
+		},
+		{
+			name: "resource exhausted",
+			err:  status.Error(codes.ResourceExhausted, "quota"),
+			want: CategoryUnavailable,
+		},
+		{
+			name: "deadline exceeded",
+			err:  status.Error(codes.DeadlineExceeded, "timeout"),
+			want: CategoryDeadlineExceeded,
+		},
+		{
+			name: "context deadline",
+			err:  fmt.Errorf("failed to list: %w", context.DeadlineExceeded),
+			want: CategoryDeadlineExceeded,
+		},
+		{
+			name: "invalid argument",
+			err:  status.Error(codes.InvalidArgument, "bad filter"),
+			want: CategoryUnknown,
+		},
+		{
+			name: "unimplemented",
+			err:  status.Error(codes.Unimplemented, "method CreateSnapshot not implemented"),
+			want: CategoryUnimplemented,
+		},
+		{
+			name: "wrapped status error",
+			err:  fmt.Errorf("failed to create topic: %w", status.Error(codes.PermissionDenied, "denied")),
+			want: CategoryPermissionDenied,
+		},
+		{
+			name: "plain error",
+			err:  errors.New("something broke"),
+			want: CategoryUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.want {
+				t.Errorf("classifyError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	if wrapError(nil) != nil {
+		t.Error("wrapError(nil) should return nil")
+	}
+
+	inner := status.Error(codes.NotFound, "subscription not found")
+	err := wrapError(inner)
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("wrapError() = %T, want *Error", err)
+	}
+	if e.Category() != CategoryNotFound {
+		t.Errorf("Category() = %q, want %q", e.Category(), CategoryNotFound)
+	}
+	if e.Transient() {
+		t.Error("NotFound should not be transient")
+	}
+	if err.Error() != inner.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), inner.Error())
+	}
+	if !errors.Is(err, inner) {
+		t.Error("wrapped error should unwrap to the original")
+	}
+	if wrapError(err) != err {
+		t.Error("wrapping an already categorized error should return it unchanged")
+	}
+}
@@ -0,0 +1,44 @@
+package pubsub_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/anmaso/pubsub-tui/pkg/pubsub"
+)
+
+// Publish a message and read it back from a subscription on the topic
+func Example() {
+	client, err := pubsub.NewClient("my-project", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	result := client.Publish(ctx, "orders", []byte(`{"id": 1}`), map[string]string{"source": "example"})
+	if result.Error != nil {
+		log.Fatal(result.Error)
+	}
+
+	sub := client.Subscribe("orders-sub", pubsub.DefaultReceiveConfig())
+	sub.Start(ctx)
+	defer sub.Stop()
+
+	msg := <-sub.Messages()
+	fmt.Println(msg.ID, string(msg.Data))
+	msg.Ack()
+}
+
+func ExampleReceivedMessage_Ack() {
+	msg := pubsub.NewReceivedMessage("msg-1", []byte("hello"), func() {
+		fmt.Println("acked")
+	})
+	msg.Ack()
+	msg.Ack() // Only the first ack reaches the server
+	fmt.Println(msg.IsAcked())
+	// Output:
+	// acked
+	// true
+}
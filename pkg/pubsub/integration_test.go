@@ -10,7 +10,7 @@ import (
 )
 
 // skipIfNoEmulator skips the test if the emulator is not configured
-func skipIfNoEmulator(t *testing.T) {
+func skipIfNoEmulator(t testing.TB) {
 	t.Helper()
 	if !IsEmulatorEnabled() {
 		t.Skip("Skipping integration test: PUBSUB_EMULATOR_HOST not set")
@@ -20,12 +20,12 @@ func skipIfNoEmulator(t *testing.T) {
 	}
 }
 
-func getTestClient(t *testing.T) *Client {
+func getTestClient(t testing.TB) *Client {
 	t.Helper()
 	skipIfNoEmulator(t)
 
 	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
-	client, err := NewClient(projectID)
+	client, err := NewClient(projectID, "")
 	if err != nil {
 		t.Fatalf("Failed to create client: %v", err)
 	}
@@ -194,6 +194,38 @@ func TestIntegration_SubscriptionCreate_TopicNotExists(t *testing.T) {
 	}
 }
 
+func TestIntegration_SubscriptionCreate_WithFilter(t *testing.T) {
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	topicName := "test-topic-filter-" + time.Now().Format("20060102150405")
+	subName := "test-sub-filter-" + time.Now().Format("20060102150405")
+	filter := `attributes.type = "order"`
+
+	err := client.CreateTopic(ctx, topicName)
+	if err != nil {
+		t.Fatalf("CreateTopic failed: %v", err)
+	}
+	defer client.DeleteTopic(ctx, topicName)
+
+	err = client.CreateSubscriptionWithFilter(ctx, subName, topicName, filter)
+	if err != nil {
+		t.Fatalf("CreateSubscriptionWithFilter failed: %v", err)
+	}
+	defer client.DeleteSubscription(ctx, subName)
+
+	subs, err := client.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("ListSubscriptions failed: %v", err)
+	}
+	for _, sub := range subs {
+		if sub.Name == subName && sub.Filter != filter {
+			t.Errorf("Subscription Filter = %q, want %q", sub.Filter, filter)
+		}
+	}
+}
+
 func TestIntegration_PublishReceive(t *testing.T) {
 	client := getTestClient(t)
 	defer client.Close()
@@ -214,7 +246,7 @@ func TestIntegration_PublishReceive(t *testing.T) {
 	defer client.DeleteSubscription(ctx, subName)
 
 	// Start subscription
-	sub := client.Subscribe(subName)
+	sub := client.Subscribe(subName, DefaultReceiveConfig())
 	subCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	sub.Start(subCtx)
@@ -285,7 +317,7 @@ func TestIntegration_PublishMultipleMessages(t *testing.T) {
 	defer client.DeleteSubscription(ctx, subName)
 
 	// Start subscription
-	sub := client.Subscribe(subName)
+	sub := client.Subscribe(subName, DefaultReceiveConfig())
 	subCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	sub.Start(subCtx)
@@ -346,7 +378,7 @@ func TestIntegration_SubscriptionStartStop(t *testing.T) {
 	}
 	defer client.DeleteSubscription(ctx, subName)
 
-	sub := client.Subscribe(subName)
+	sub := client.Subscribe(subName, DefaultReceiveConfig())
 
 	// Initially not running
 	if sub.IsRunning() {
@@ -370,16 +402,217 @@ func TestIntegration_SubscriptionStartStop(t *testing.T) {
 		t.Error("Subscription should still be running after second Start()")
 	}
 
-	// Stop
-	sub.Stop()
-	cancel()
-
-	// Give it time to stop
-	time.Sleep(100 * time.Millisecond)
+	// Stop and wait for the receive goroutine to exit
+	defer cancel()
+	if !sub.StopWait(10 * time.Second) {
+		t.Fatal("receive goroutine did not exit after StopWait()")
+	}
 
 	if sub.IsRunning() {
-		t.Error("Subscription should not be running after Stop()")
+		t.Error("Subscription should not be running after StopWait()")
+	}
+
+	// Nothing may be delivered once StopWait has returned
+	select {
+	case msg := <-sub.Messages():
+		t.Errorf("received message %s after StopWait()", msg.ID)
+	default:
 	}
 }
 
+func TestIntegration_SnapshotCRUD(t *testing.T) {
+	client := getTestClient(t)
+	defer client.Close()
 
+	ctx := context.Background()
+	suffix := time.Now().Format("20060102150405")
+	topicName := "test-topic-snap-" + suffix
+	subName := "test-sub-snap-" + suffix
+	snapName := "test-snap-" + suffix
+
+	// Setup
+	if err := client.CreateTopic(ctx, topicName); err != nil {
+		t.Fatalf("CreateTopic failed: %v", err)
+	}
+	defer client.DeleteTopic(ctx, topicName)
+
+	if err := client.CreateSubscription(ctx, subName, topicName); err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	defer client.DeleteSubscription(ctx, subName)
+
+	// 1. Create snapshot
+	err := client.CreateSnapshot(ctx, snapName, subName)
+	if ErrorCategory(err) == CategoryUnimplemented {
+		t.Skipf("Skipping: emulator does not support snapshots: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+
+	// 2. Verify snapshot exists in list with its topic
+	snapshots, err := client.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+
+	var found *SnapshotInfo
+	for i := range snapshots {
+		if snapshots[i].Name == snapName {
+			found = &snapshots[i]
+			break
+		}
+	}
+	if found == nil {
+		t.Fatalf("Created snapshot %q not found in ListSnapshots", snapName)
+	}
+	if found.TopicName != topicName {
+		t.Errorf("Snapshot TopicName = %q, want %q", found.TopicName, topicName)
+	}
+
+	// 3. Creating it again fails
+	if err := client.CreateSnapshot(ctx, snapName, subName); err == nil {
+		t.Error("CreateSnapshot should fail for an existing snapshot")
+	}
+
+	// 4. Delete snapshot
+	if err := client.DeleteSnapshot(ctx, snapName); err != nil {
+		t.Fatalf("DeleteSnapshot failed: %v", err)
+	}
+
+	// 5. Verify snapshot is gone
+	snapshots, err = client.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListSnapshots after delete failed: %v", err)
+	}
+	for _, snap := range snapshots {
+		if snap.Name == snapName {
+			t.Errorf("Snapshot %q still listed after deletion", snapName)
+		}
+	}
+}
+
+// receiveOne starts a subscription, acks the first message and stops
+func receiveOne(t *testing.T, client *Client, subName string) *ReceivedMessage {
+	t.Helper()
+
+	sub := client.Subscribe(subName, DefaultReceiveConfig())
+	subCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub.Start(subCtx)
+	defer sub.StopWait(10 * time.Second)
+
+	select {
+	case msg := <-sub.Messages():
+		msg.Ack()
+		return msg
+	case err := <-sub.Errors():
+		t.Fatalf("Subscription error: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for message")
+	}
+	return nil
+}
+
+func TestIntegration_SeekToSnapshot(t *testing.T) {
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	suffix := time.Now().Format("20060102150405")
+	topicName := "test-topic-seek-" + suffix
+	otherTopicName := "test-topic-seek-other-" + suffix
+	subName := "test-sub-seek-" + suffix
+	otherSubName := "test-sub-seek-other-" + suffix
+	snapName := "test-snap-seek-" + suffix
+
+	// Setup
+	for _, topic := range []string{topicName, otherTopicName} {
+		if err := client.CreateTopic(ctx, topic); err != nil {
+			t.Fatalf("CreateTopic failed: %v", err)
+		}
+		defer client.DeleteTopic(ctx, topic)
+	}
+	if err := client.CreateSubscription(ctx, subName, topicName); err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	defer client.DeleteSubscription(ctx, subName)
+	if err := client.CreateSubscription(ctx, otherSubName, otherTopicName); err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	defer client.DeleteSubscription(ctx, otherSubName)
+
+	// 1. Snapshot before publishing so the message is replayable
+	err := client.CreateSnapshot(ctx, snapName, subName)
+	if ErrorCategory(err) == CategoryUnimplemented {
+		t.Skipf("Skipping: emulator does not support snapshots: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	defer client.DeleteSnapshot(ctx, snapName)
+
+	// 2. Publish, receive and ack a message
+	testData := []byte(`{"test": "seek"}`)
+	if result := client.Publish(ctx, topicName, testData, nil); result.Error != nil {
+		t.Fatalf("Publish failed: %v", result.Error)
+	}
+	first := receiveOne(t, client, subName)
+
+	// 3. Seeking a subscription of another topic fails
+	if err := client.SeekToSnapshot(ctx, otherSubName, snapName); err == nil {
+		t.Error("SeekToSnapshot should fail for a subscription of another topic")
+	}
+
+	// 4. Seek and receive the acked message again
+	err = client.SeekToSnapshot(ctx, subName, snapName)
+	if ErrorCategory(err) == CategoryUnimplemented {
+		t.Skipf("Skipping: emulator does not support seek: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("SeekToSnapshot failed: %v", err)
+	}
+
+	replayed := receiveOne(t, client, subName)
+	if replayed.ID != first.ID {
+		t.Errorf("Replayed message ID = %q, want %q", replayed.ID, first.ID)
+	}
+}
+
+// benchmarkPublish publishes b.N messages to a fresh topic with publish
+func benchmarkPublish(b *testing.B, publish func(ctx context.Context, client *Client, topic string, n int)) {
+	client := getTestClient(b)
+	defer client.Close()
+
+	ctx := context.Background()
+	topicName := "bench-topic-" + time.Now().Format("20060102150405.000000")
+	if err := client.CreateTopic(ctx, topicName); err != nil {
+		b.Fatalf("CreateTopic failed: %v", err)
+	}
+	defer client.DeleteTopic(ctx, topicName)
+
+	b.ResetTimer()
+	publish(ctx, client, topicName, b.N)
+}
+
+func BenchmarkIntegration_PublishSync(b *testing.B) {
+	benchmarkPublish(b, func(ctx context.Context, client *Client, topic string, n int) {
+		for i := 0; i < n; i++ {
+			if result := client.Publish(ctx, topic, []byte(`{"bench": true}`), nil); result.Error != nil {
+				b.Fatalf("Publish failed: %v", result.Error)
+			}
+		}
+	})
+}
+
+func BenchmarkIntegration_PublishAsync(b *testing.B) {
+	benchmarkPublish(b, func(ctx context.Context, client *Client, topic string, n int) {
+		handles := make([]*PublishHandle, 0, n)
+		for i := 0; i < n; i++ {
+			handles = append(handles, client.PublishAsync(ctx, topic, []byte(`{"bench": true}`), nil, ""))
+		}
+		if result := AwaitAll(ctx, handles); len(result.Errors) > 0 {
+			b.Fatalf("%d publishes failed, first: %v", len(result.Errors), result.Errors[0])
+		}
+	})
+}
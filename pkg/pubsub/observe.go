@@ -0,0 +1,29 @@
+package pubsub
+
+import (
+	"time"
+)
+
+// CallObserver is told about each request the client makes to the server:
+// the operation, such as "ListTopics", how long it took, and the error it
+// returned, if any. It is called from the goroutine that made the request.
+type CallObserver func(op string, elapsed time.Duration, err error)
+
+// SetCallObserver sets the func told about each request to the server, e.g.
+// to log them. Nil stops observing.
+func (c *Client) SetCallObserver(fn CallObserver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observer = fn
+}
+
+// observe reports a request started at start to the observer, if any. It is
+// deferred with a pointer to the request's named error result.
+func (c *Client) observe(op string, start time.Time, err *error) {
+	c.mu.Lock()
+	fn := c.observer
+	c.mu.Unlock()
+	if fn != nil {
+		fn(op, time.Since(start), *err)
+	}
+}
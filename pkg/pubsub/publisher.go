@@ -0,0 +1,251 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// PublishResult contains the result of a publish operation
+type PublishResult struct {
+	MessageID string
+	Topic     string // Full resource name, e.g. projects/my-project/topics/my-topic
+	Error     error
+}
+
+// PublishSettings controls how published messages are batched.
+//
+// A batch is sent as soon as it holds CountThreshold messages or
+// ByteThreshold bytes, or once its oldest message has waited DelayThreshold.
+// Larger thresholds and longer delays send fewer, bigger requests and raise
+// throughput when many messages are published at once, but every message may
+// wait up to DelayThreshold before it is sent. Zero values use the client
+// library defaults.
+type PublishSettings struct {
+	CountThreshold int
+	DelayThreshold time.Duration
+	ByteThreshold  int
+}
+
+// DefaultPublishSettings returns the client library's default batching settings
+func DefaultPublishSettings() PublishSettings {
+	return PublishSettings{
+		CountThreshold: pubsub.DefaultPublishSettings.CountThreshold,
+		DelayThreshold: pubsub.DefaultPublishSettings.DelayThreshold,
+		ByteThreshold:  pubsub.DefaultPublishSettings.ByteThreshold,
+	}
+}
+
+// withDefaults fills zero values with the default settings
+func (s PublishSettings) withDefaults() PublishSettings {
+	defaults := DefaultPublishSettings()
+	if s.CountThreshold <= 0 {
+		s.CountThreshold = defaults.CountThreshold
+	}
+	if s.DelayThreshold <= 0 {
+		s.DelayThreshold = defaults.DelayThreshold
+	}
+	if s.ByteThreshold <= 0 {
+		s.ByteThreshold = defaults.ByteThreshold
+	}
+	return s
+}
+
+// apply copies the batching thresholds onto a topic's publish settings
+func (s PublishSettings) apply(topic *pubsub.Topic) {
+	topic.PublishSettings.CountThreshold = s.CountThreshold
+	topic.PublishSettings.DelayThreshold = s.DelayThreshold
+	topic.PublishSettings.ByteThreshold = s.ByteThreshold
+}
+
+// ConfigurePublishSettings sets the batching settings for publishing.
+// Topics already used for publishing are flushed and recreated so the new
+// settings take effect on the next publish.
+func (c *Client) ConfigurePublishSettings(settings PublishSettings) {
+	c.mu.Lock()
+	c.publishSettings = settings.withDefaults()
+	topics := c.removeTopicsLocked(func(topicKey) bool { return true })
+	c.mu.Unlock()
+
+	stopTopics(topics)
+}
+
+// PublishSettings returns the current batching settings
+func (c *Client) PublishSettings() PublishSettings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.publishSettings
+}
+
+// topicKey identifies a cached publishing topic
+type topicKey struct {
+	name    string
+	ordered bool
+}
+
+// publishTopic returns the cached topic used for publishing, creating it
+// with the current batching settings. Reusing one topic per name lets
+// messages published close together share a batch.
+func (c *Client) publishTopic(topicName string, ordered bool) *pubsub.Topic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.publishTopicLocked(topicName, ordered)
+}
+
+// publishTopicLocked is publishTopic for callers holding c.mu
+func (c *Client) publishTopicLocked(topicName string, ordered bool) *pubsub.Topic {
+	key := topicKey{name: topicName, ordered: ordered}
+	if topic, ok := c.topics[key]; ok {
+		return topic
+	}
+
+	topic := c.client.Topic(topicName)
+	topic.EnableMessageOrdering = ordered
+	c.publishSettings.withDefaults().apply(topic)
+
+	if c.topics == nil {
+		c.topics = make(map[topicKey]*pubsub.Topic)
+	}
+	c.topics[key] = topic
+	return topic
+}
+
+// evictTopic flushes and forgets the cached publishing topics for a name
+func (c *Client) evictTopic(topicName string) {
+	c.mu.Lock()
+	topics := c.removeTopicsLocked(func(key topicKey) bool { return key.name == topicName })
+	c.mu.Unlock()
+
+	stopTopics(topics)
+}
+
+// removeTopicsLocked forgets the cached publishing topics whose key matches
+// and returns them for stopping. Callers must hold c.mu, and should stop
+// the topics after releasing it, since stopping waits for pending
+// publishes.
+func (c *Client) removeTopicsLocked(match func(topicKey) bool) []*pubsub.Topic {
+	var removed []*pubsub.Topic
+	for key, topic := range c.topics {
+		if match(key) {
+			removed = append(removed, topic)
+			delete(c.topics, key)
+		}
+	}
+	return removed
+}
+
+// stopTopics flushes topics removed from the cache
+func stopTopics(topics []*pubsub.Topic) {
+	for _, topic := range topics {
+		topic.Stop()
+	}
+}
+
+// PublishHandle is a pending publish started by PublishAsync
+type PublishHandle struct {
+	result      *pubsub.PublishResult
+	topic       *pubsub.Topic
+	orderingKey string
+	client      *Client   // Observes the publish once it completes
+	start       time.Time // When the publish was queued
+}
+
+// Ready returns a channel that is closed once the publish has completed
+func (h *PublishHandle) Ready() <-chan struct{} {
+	return h.result.Ready()
+}
+
+// Get blocks until the publish completes or ctx is done and returns the result
+func (h *PublishHandle) Get(ctx context.Context) PublishResult {
+	id, err := h.result.Get(ctx)
+	h.client.observe("Publish", h.start, &err)
+	if err != nil {
+		if h.orderingKey != "" {
+			// Publishing for a key is paused after an error until resumed
+			h.topic.ResumePublish(h.orderingKey)
+		}
+		return PublishResult{Topic: h.topic.String(), Error: wrapError(err)}
+	}
+	return PublishResult{MessageID: id, Topic: h.topic.String()}
+}
+
+// PublishAsync queues a message for publishing and returns without waiting,
+// so many messages published in a row are sent in batches. An empty ordering
+// key publishes without ordering.
+func (c *Client) PublishAsync(ctx context.Context, topicName string, data []byte, attributes map[string]string, orderingKey string) *PublishHandle {
+	msg := &pubsub.Message{
+		Data:        data,
+		Attributes:  attributes,
+		OrderingKey: orderingKey,
+	}
+
+	// Publish under the lock so the topic cannot be evicted and stopped
+	// between looking it up and queueing the message. Publish only queues,
+	// and an eviction stops the topic after unlocking, flushing the message.
+	c.mu.Lock()
+	topic := c.publishTopicLocked(topicName, orderingKey != "")
+	result := topic.Publish(ctx, msg)
+	c.mu.Unlock()
+
+	return &PublishHandle{
+		result:      result,
+		topic:       topic,
+		orderingKey: orderingKey,
+		client:      c,
+		start:       time.Now(),
+	}
+}
+
+// BatchPublishResult summarizes the outcome of many publishes
+type BatchPublishResult struct {
+	MessageIDs []string // IDs of published messages, in submission order
+	Errors     []error  // Failures, in submission order
+}
+
+// AwaitAll waits for all pending publishes concurrently and collects the
+// results. Messages still pending when ctx is done count as failures.
+func AwaitAll(ctx context.Context, handles []*PublishHandle) BatchPublishResult {
+	results := make([]PublishResult, len(handles))
+
+	var wg sync.WaitGroup
+	for i, h := range handles {
+		wg.Add(1)
+		go func(i int, h *PublishHandle) {
+			defer wg.Done()
+			results[i] = h.Get(ctx)
+		}(i, h)
+	}
+	wg.Wait()
+
+	var batch BatchPublishResult
+	for _, r := range results {
+		if r.Error != nil {
+			batch.Errors = append(batch.Errors, r.Error)
+			continue
+		}
+		batch.MessageIDs = append(batch.MessageIDs, r.MessageID)
+	}
+	return batch
+}
+
+// Publish publishes a message to the specified topic
+func (c *Client) Publish(ctx context.Context, topicName string, data []byte, attributes map[string]string) PublishResult {
+	return c.PublishWithOrderingKey(ctx, topicName, data, attributes, "")
+}
+
+// PublishWithOrderingKey publishes a message to the specified topic with an
+// ordering key and blocks until it is sent. An empty ordering key publishes
+// without ordering.
+func (c *Client) PublishWithOrderingKey(ctx context.Context, topicName string, data []byte, attributes map[string]string, orderingKey string) PublishResult {
+	return c.PublishAsync(ctx, topicName, data, attributes, orderingKey).Get(ctx)
+}
+
+// TopicExists checks if a topic exists
+func (c *Client) TopicExists(ctx context.Context, topicName string) (_ bool, err error) {
+	defer c.observe("TopicExists", time.Now(), &err)
+
+	topic := c.client.Topic(topicName)
+	return topic.Exists(ctx)
+}
@@ -0,0 +1,181 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newOfflineClient creates a client whose connection is never used, for
+// tests that only exercise local state
+func newOfflineClient(t *testing.T) *Client {
+	t.Helper()
+
+	client, err := pubsub.NewClient(context.Background(), "test-project",
+		option.WithEndpoint("localhost:1"),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		t.Fatalf("pubsub.NewClient() error = %v", err)
+	}
+
+	c := &Client{client: client, projectID: "test-project", publishSettings: DefaultPublishSettings()}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// newFakeClient creates a client connected to an in-memory Pub/Sub server
+func newFakeClient(t *testing.T) *Client {
+	t.Helper()
+
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+
+	client, err := pubsub.NewClient(context.Background(), "test-project",
+		option.WithEndpoint(srv.Addr),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		t.Fatalf("pubsub.NewClient() error = %v", err)
+	}
+
+	c := &Client{client: client, projectID: "test-project", publishSettings: DefaultPublishSettings()}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestPublishSettings_WithDefaults(t *testing.T) {
+	defaults := DefaultPublishSettings()
+	got := PublishSettings{CountThreshold: 5}.withDefaults()
+	want := PublishSettings{CountThreshold: 5, DelayThreshold: defaults.DelayThreshold, ByteThreshold: defaults.ByteThreshold}
+	if got != want {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_ConfigurePublishSettings(t *testing.T) {
+	c := newOfflineClient(t)
+
+	settings := PublishSettings{CountThreshold: 50, DelayThreshold: 5 * time.Millisecond, ByteThreshold: 4096}
+	c.ConfigurePublishSettings(settings)
+	if got := c.PublishSettings(); got != settings {
+		t.Errorf("PublishSettings() = %+v, want %+v", got, settings)
+	}
+
+	topic := c.publishTopic("orders", false)
+	if topic.PublishSettings.CountThreshold != 50 ||
+		topic.PublishSettings.DelayThreshold != 5*time.Millisecond ||
+		topic.PublishSettings.ByteThreshold != 4096 {
+		t.Errorf("topic settings = %+v, want thresholds from %+v", topic.PublishSettings, settings)
+	}
+
+	// The topic is reused so publishes share batches
+	if c.publishTopic("orders", false) != topic {
+		t.Error("publishTopic() should reuse the cached topic")
+	}
+	if ordered := c.publishTopic("orders", true); ordered == topic || !ordered.EnableMessageOrdering {
+		t.Error("ordered publishing should use a separate topic with ordering enabled")
+	}
+
+	// Reconfiguring replaces cached topics so the new settings apply
+	c.ConfigurePublishSettings(PublishSettings{CountThreshold: 10})
+	replaced := c.publishTopic("orders", false)
+	if replaced == topic {
+		t.Error("ConfigurePublishSettings() should drop cached topics")
+	}
+	if replaced.PublishSettings.CountThreshold != 10 {
+		t.Errorf("CountThreshold = %d, want 10", replaced.PublishSettings.CountThreshold)
+	}
+	if replaced.PublishSettings.DelayThreshold != DefaultPublishSettings().DelayThreshold {
+		t.Errorf("DelayThreshold = %v, want the default for a zero value", replaced.PublishSettings.DelayThreshold)
+	}
+}
+
+func TestClient_EvictTopic(t *testing.T) {
+	c := newOfflineClient(t)
+
+	orders := c.publishTopic("orders", false)
+	c.publishTopic("orders", true)
+	events := c.publishTopic("events", false)
+
+	c.evictTopic("orders")
+	if len(c.topics) != 1 {
+		t.Errorf("cached topics = %d, want 1 after evicting orders", len(c.topics))
+	}
+	if c.publishTopic("orders", false) == orders {
+		t.Error("evicted topic should be recreated")
+	}
+	if c.publishTopic("events", false) != events {
+		t.Error("other topics should stay cached")
+	}
+}
+
+func TestClient_EvictTopicDuringPublish(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateTopic(ctx, "orders"); err != nil {
+		t.Fatalf("CreateTopic() error = %v", err)
+	}
+
+	// Evicting stops topics, which must not fail publishes racing with it
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			c.evictTopic("orders")
+		}
+	}()
+	var handles []*PublishHandle
+	for i := 0; i < 50; i++ {
+		handles = append(handles, c.PublishAsync(ctx, "orders", []byte("msg"), nil, ""))
+	}
+	<-done
+
+	result := AwaitAll(ctx, handles)
+	if len(result.Errors) != 0 {
+		t.Errorf("publishes failed while evicting: %v", result.Errors[0])
+	}
+}
+
+func TestAwaitAll(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateTopic(ctx, "orders"); err != nil {
+		t.Fatalf("CreateTopic() error = %v", err)
+	}
+
+	var handles []*PublishHandle
+	for i := 0; i < 25; i++ {
+		handles = append(handles, c.PublishAsync(ctx, "orders", []byte(`{"n": 1}`), nil, ""))
+	}
+	// Messages for a missing topic fail without affecting the others
+	handles = append(handles, c.PublishAsync(ctx, "missing", []byte(`{}`), nil, ""))
+
+	got := AwaitAll(ctx, handles)
+	if len(got.MessageIDs) != 25 {
+		t.Errorf("published %d messages, want 25", len(got.MessageIDs))
+	}
+	if len(got.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(got.Errors))
+	}
+	if classifyError(got.Errors[0]) != CategoryNotFound {
+		t.Errorf("error category = %q, want %q", classifyError(got.Errors[0]), CategoryNotFound)
+	}
+}
+
+func TestAwaitAll_Empty(t *testing.T) {
+	got := AwaitAll(context.Background(), nil)
+	if len(got.MessageIDs) != 0 || len(got.Errors) != 0 {
+		t.Errorf("AwaitAll(nil) = %+v, want empty", got)
+	}
+}
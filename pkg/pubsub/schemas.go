@@ -0,0 +1,130 @@
+package pubsub
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Schema types and encodings reported in SchemaInfo
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOCOL_BUFFER"
+
+	SchemaEncodingJSON   = "JSON"
+	SchemaEncodingBinary = "BINARY"
+)
+
+// Attributes Pub/Sub adds to messages published to a topic with a schema
+const (
+	SchemaNameAttribute     = "googclient_schemaname"
+	SchemaEncodingAttribute = "googclient_schemaencoding"
+)
+
+// SchemaInfo describes the schema attached to a topic
+type SchemaInfo struct {
+	Name       string // Short schema name
+	Type       string // SchemaTypeAvro, SchemaTypeProtobuf, or "" if unknown
+	Encoding   string // SchemaEncodingJSON or SchemaEncodingBinary
+	Definition string // Schema source, empty if it could not be fetched
+}
+
+// GetTopicSchema returns the schema attached to a topic, or nil if it has
+// none. The emulator does not serve schema definitions, so in emulator mode
+// only the name and encoding from the topic config are returned. If the
+// definition cannot be fetched, the partial info is returned with the error.
+func (c *Client) GetTopicSchema(ctx context.Context, topicName string) (_ *SchemaInfo, err error) {
+	defer c.observe("GetTopicSchema", time.Now(), &err)
+
+	cfg, err := c.client.Topic(topicName).Config(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if cfg.SchemaSettings == nil || cfg.SchemaSettings.Schema == "" {
+		return nil, nil
+	}
+
+	info := &SchemaInfo{
+		Name:     extractName(cfg.SchemaSettings.Schema),
+		Encoding: schemaEncodingName(cfg.SchemaSettings.Encoding),
+	}
+	if IsEmulatorEnabled() {
+		return info, nil
+	}
+
+	// The schema may belong to another project, which the client must target
+	sc, err := c.schemaClient(ctx, schemaProject(cfg.SchemaSettings.Schema, c.projectID))
+	if err != nil {
+		return info, wrapError(err)
+	}
+
+	schema, err := sc.Schema(ctx, info.Name, pubsub.SchemaViewFull)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return info, nil
+		}
+		return info, wrapError(err)
+	}
+
+	info.Type = schemaTypeName(schema.Type)
+	info.Definition = schema.Definition
+	return info, nil
+}
+
+// schemaClient returns the schema client for project, creating it with the
+// client's credentials on first use
+func (c *Client) schemaClient(ctx context.Context, project string) (*pubsub.SchemaClient, error) {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+
+	if sc, ok := c.schemaClients[project]; ok {
+		return sc, nil
+	}
+	sc, err := pubsub.NewSchemaClient(ctx, project, clientOptions(c.credentialsFile)...)
+	if err != nil {
+		return nil, err
+	}
+	if c.schemaClients == nil {
+		c.schemaClients = make(map[string]*pubsub.SchemaClient)
+	}
+	c.schemaClients[project] = sc
+	return sc, nil
+}
+
+// schemaProject returns the project of a full schema name such as
+// "projects/p/schemas/s", or fallback if the name has no project
+func schemaProject(fullName, fallback string) string {
+	parts := strings.Split(fullName, "/")
+	if len(parts) == 4 && parts[0] == "projects" && parts[1] != "" && parts[2] == "schemas" {
+		return parts[1]
+	}
+	return fallback
+}
+
+// schemaTypeName converts a schema type to its API name
+func schemaTypeName(t pubsub.SchemaType) string {
+	switch t {
+	case pubsub.SchemaAvro:
+		return SchemaTypeAvro
+	case pubsub.SchemaProtocolBuffer:
+		return SchemaTypeProtobuf
+	default:
+		return ""
+	}
+}
+
+// schemaEncodingName converts a schema encoding to its API name
+func schemaEncodingName(e pubsub.SchemaEncoding) string {
+	switch e {
+	case pubsub.EncodingJSON:
+		return SchemaEncodingJSON
+	case pubsub.EncodingBinary:
+		return SchemaEncodingBinary
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,22 @@
+package pubsub
+
+import "testing"
+
+func TestSchemaProject(t *testing.T) {
+	tests := []struct {
+		fullName string
+		want     string
+	}{
+		{"projects/my-project/schemas/orders", "my-project"},
+		{"projects/shared-schemas/schemas/orders", "shared-schemas"},
+		{"orders", "default"},
+		{"projects//schemas/orders", "default"},
+		{"projects/p/topics/orders", "default"},
+	}
+
+	for _, tt := range tests {
+		if got := schemaProject(tt.fullName, "default"); got != tt.want {
+			t.Errorf("schemaProject(%q) = %q, want %q", tt.fullName, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/iterator"
+)
+
+// SnapshotInfo represents information about a Pub/Sub snapshot
+type SnapshotInfo struct {
+	Name       string    // Short name (without project prefix)
+	FullName   string    // Full resource name
+	TopicName  string    // Short name of the topic the snapshot was taken from
+	TopicFull  string    // Full name of the topic the snapshot was taken from
+	Expiration time.Time // When the server deletes the snapshot
+}
+
+// ListSnapshots retrieves all snapshots in the project
+func (c *Client) ListSnapshots(ctx context.Context) (_ []SnapshotInfo, err error) {
+	defer c.observe("ListSnapshots", time.Now(), &err)
+
+	var snapshots []SnapshotInfo
+
+	it := c.client.Snapshots(ctx)
+	for {
+		cfg, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		info := SnapshotInfo{
+			Name:       extractName(cfg.ID()),
+			FullName:   fmt.Sprintf("projects/%s/snapshots/%s", c.projectID, cfg.ID()),
+			Expiration: cfg.Expiration,
+		}
+		if cfg.Topic != nil {
+			info.TopicName = extractName(cfg.Topic.String())
+			info.TopicFull = cfg.Topic.String()
+		}
+		snapshots = append(snapshots, info)
+	}
+
+	return snapshots, nil
+}
+
+// CreateSnapshot captures the acknowledgment state of a subscription in a new
+// snapshot. Messages unacknowledged at creation, and those published later,
+// can be replayed by seeking a subscription of the same topic to it.
+func (c *Client) CreateSnapshot(ctx context.Context, snapshotID, subscriptionID string) (err error) {
+	defer c.observe("CreateSnapshot", time.Now(), &err)
+
+	if err := ValidateResourceID(snapshotID); err != nil {
+		return err
+	}
+
+	sub := c.client.Subscription(subscriptionID)
+	exists, err := sub.Exists(ctx)
+	if err != nil {
+		return wrapError(fmt.Errorf("failed to check subscription existence: %w", err))
+	}
+	if !exists {
+		return fmt.Errorf("subscription %q does not exist", subscriptionID)
+	}
+
+	if _, err := sub.CreateSnapshot(ctx, snapshotID); err != nil {
+		return wrapError(fmt.Errorf("failed to create snapshot: %w", err))
+	}
+
+	return nil
+}
+
+// DeleteSnapshot deletes a snapshot by ID
+func (c *Client) DeleteSnapshot(ctx context.Context, snapshotID string) (err error) {
+	defer c.observe("DeleteSnapshot", time.Now(), &err)
+
+	if err := c.client.Snapshot(snapshotID).Delete(ctx); err != nil {
+		return wrapError(fmt.Errorf("failed to delete snapshot: %w", err))
+	}
+	return nil
+}
+
+// findSnapshot looks up a snapshot by ID
+func (c *Client) findSnapshot(ctx context.Context, snapshotID string) (*SnapshotInfo, error) {
+	snapshots, err := c.ListSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range snapshots {
+		if snapshots[i].Name == snapshotID {
+			return &snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot %q does not exist", snapshotID)
+}
+
+// SeekToSnapshot resets a subscription's acknowledgment state to a snapshot,
+// so messages unacknowledged when the snapshot was taken are redelivered.
+// The snapshot must have been taken from a subscription of the same topic.
+func (c *Client) SeekToSnapshot(ctx context.Context, subscriptionID, snapshotID string) (err error) {
+	defer c.observe("SeekToSnapshot", time.Now(), &err)
+
+	sub := c.client.Subscription(subscriptionID)
+	cfg, err := sub.Config(ctx)
+	if err != nil {
+		return wrapError(fmt.Errorf("failed to get subscription %q: %w", subscriptionID, err))
+	}
+
+	snap, err := c.findSnapshot(ctx, snapshotID)
+	if err != nil {
+		return err
+	}
+	if err := checkSnapshotTopic(snap, cfg.Topic.String()); err != nil {
+		return err
+	}
+
+	if err := sub.SeekToSnapshot(ctx, c.client.Snapshot(snapshotID)); err != nil {
+		return wrapError(fmt.Errorf("failed to seek to snapshot: %w", err))
+	}
+
+	return nil
+}
+
+// checkSnapshotTopic verifies a snapshot was taken from the given topic,
+// since a subscription can only seek to snapshots of its own topic
+func checkSnapshotTopic(snap *SnapshotInfo, topicFull string) error {
+	if snap.TopicFull != topicFull {
+		return fmt.Errorf("snapshot %q is for topic %q, not %q", snap.Name, extractName(snap.TopicFull), extractName(topicFull))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+)
+
+// The in-memory fake server does not implement snapshots, so the snapshot
+// round trip is covered by TestIntegration_SnapshotCRUD
+
+func TestClient_CreateSnapshot_Errors(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateSnapshot(ctx, "1bad", "orders-sub"); err == nil {
+		t.Error("CreateSnapshot() with an invalid ID should fail")
+	}
+	if err := c.CreateSnapshot(ctx, "orders-snap", "missing-sub"); err == nil {
+		t.Error("CreateSnapshot() for a missing subscription should fail")
+	}
+}
+
+func TestCheckSnapshotTopic(t *testing.T) {
+	snap := &SnapshotInfo{Name: "orders-snap", TopicFull: "projects/p/topics/orders"}
+
+	if err := checkSnapshotTopic(snap, "projects/p/topics/orders"); err != nil {
+		t.Errorf("checkSnapshotTopic() same topic error = %v", err)
+	}
+
+	err := checkSnapshotTopic(snap, "projects/p/topics/events")
+	if err == nil {
+		t.Fatal("checkSnapshotTopic() should reject a snapshot of another topic")
+	}
+	if want := `snapshot "orders-snap" is for topic "orders", not "events"`; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestClient_SeekToSnapshot_MissingSubscription(t *testing.T) {
+	c := newFakeClient(t)
+
+	err := c.SeekToSnapshot(context.Background(), "missing-sub", "orders-snap")
+	if ErrorCategory(err) != CategoryNotFound {
+		t.Errorf("SeekToSnapshot() category = %q, want %q (err %v)", ErrorCategory(err), CategoryNotFound, err)
+	}
+}
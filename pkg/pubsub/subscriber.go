@@ -0,0 +1,364 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// ReceivedMessage represents a message received from a subscription
+type ReceivedMessage struct {
+	ID          string
+	Data        []byte
+	Attributes  map[string]string
+	PublishTime time.Time
+	OrderingKey string
+	AckID       string
+	Sequence    int64 // Arrival order in the subscriber panel, from 1; 0 until received there
+	DataSize    int   // Size of the published data; larger than len(Data) when truncated
+
+	// Internal fields for ack/nack
+	ackFunc  func()
+	nackFunc func()
+	onSettle func() // Called once when the message is first acked or nacked
+	acked    bool
+	nacked   bool
+	settled  bool
+	mu       sync.Mutex
+}
+
+// Ack acknowledges the message
+func (m *ReceivedMessage) Ack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.acked && !m.nacked && m.ackFunc != nil {
+		m.ackFunc()
+		m.acked = true
+		m.settle()
+	}
+}
+
+// Nack negative-acknowledges the message (will be redelivered)
+func (m *ReceivedMessage) Nack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.acked || m.nacked {
+		return
+	}
+	if m.nackFunc != nil {
+		m.nackFunc()
+	}
+	m.nacked = true
+	m.settle()
+}
+
+// settle notifies the owning subscription the first time the message is
+// acked or nacked. Callers must hold m.mu.
+func (m *ReceivedMessage) settle() {
+	if !m.settled && m.onSettle != nil {
+		m.settled = true
+		m.onSettle()
+	}
+}
+
+// IsAcked returns whether the message has been acknowledged
+func (m *ReceivedMessage) IsAcked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.acked
+}
+
+// IsNacked returns whether the message was handed back for redelivery. A
+// nacked message can no longer be acked.
+func (m *ReceivedMessage) IsNacked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nacked
+}
+
+// Truncated returns whether Data holds only the start of the published data
+func (m *ReceivedMessage) Truncated() bool {
+	return m.DataSize > len(m.Data)
+}
+
+// SetAcked marks the message as acknowledged (for display purposes)
+func (m *ReceivedMessage) SetAcked(acked bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acked = acked
+}
+
+// NewReceivedMessage creates a message that did not come from a
+// subscription stream, calling ack when it is acknowledged
+func NewReceivedMessage(id string, data []byte, ack func()) *ReceivedMessage {
+	return &ReceivedMessage{
+		ID:          id,
+		Data:        data,
+		DataSize:    len(data),
+		PublishTime: time.Now(),
+		AckID:       id,
+		ackFunc:     ack,
+	}
+}
+
+// Subscription wraps a Pub/Sub subscription for streaming messages
+type Subscription struct {
+	client       *Client
+	subscription *pubsub.Subscription
+	receive      func(context.Context, func(context.Context, *pubsub.Message)) error
+	cancel       context.CancelFunc
+	done         chan struct{} // Closed when the receive goroutine exits
+	messages     chan *ReceivedMessage
+	errors       chan error
+	running      atomic.Bool  // Written under mu, read without it
+	dropped      atomic.Int64 // Messages nacked because the buffer was full
+	outstanding  atomic.Int64 // Delivered messages not yet acked or nacked
+	mu           sync.Mutex
+
+	maxMessageBytes int // Data kept per message; 0 keeps all of it
+}
+
+// Default flow control settings for receiving messages
+const (
+	DefaultMaxOutstandingMessages = 100
+	DefaultMaxOutstandingBytes    = 10 * 1024 * 1024 // 10 MB
+)
+
+// ReceiveConfig controls flow control when receiving messages.
+//
+// MaxOutstandingMessages and MaxOutstandingBytes bound how many unacknowledged
+// messages (and how much data) the client holds at once. Higher values allow
+// more throughput on busy subscriptions but use more memory; lower values keep
+// memory bounded and let other consumers pick up messages that this client
+// has not yet accepted. Zero values use the defaults.
+//
+// MaxMessageBytes caps the data kept from each received message, so a few
+// huge payloads cannot fill memory while buffered for display. Data beyond
+// it is dropped; zero keeps all of it.
+type ReceiveConfig struct {
+	MaxOutstandingMessages int
+	MaxOutstandingBytes    int
+	MaxMessageBytes        int
+}
+
+// DefaultReceiveConfig returns the default receive settings
+func DefaultReceiveConfig() ReceiveConfig {
+	return ReceiveConfig{
+		MaxOutstandingMessages: DefaultMaxOutstandingMessages,
+		MaxOutstandingBytes:    DefaultMaxOutstandingBytes,
+	}
+}
+
+// withDefaults fills zero values with the default settings
+func (cfg ReceiveConfig) withDefaults() ReceiveConfig {
+	if cfg.MaxOutstandingMessages <= 0 {
+		cfg.MaxOutstandingMessages = DefaultMaxOutstandingMessages
+	}
+	if cfg.MaxOutstandingBytes <= 0 {
+		cfg.MaxOutstandingBytes = DefaultMaxOutstandingBytes
+	}
+	return cfg
+}
+
+// Subscribe creates a new subscription stream with the given flow control settings
+func (c *Client) Subscribe(subscriptionName string, cfg ReceiveConfig) *Subscription {
+	sub := c.client.Subscription(subscriptionName)
+
+	// Configure subscription settings
+	cfg = cfg.withDefaults()
+	sub.ReceiveSettings.MaxOutstandingMessages = cfg.MaxOutstandingMessages
+	sub.ReceiveSettings.MaxOutstandingBytes = cfg.MaxOutstandingBytes
+
+	return &Subscription{
+		client:          c,
+		subscription:    sub,
+		receive:         sub.Receive,
+		messages:        make(chan *ReceivedMessage, 100),
+		errors:          make(chan error, 10),
+		maxMessageBytes: cfg.MaxMessageBytes,
+	}
+}
+
+// Start begins receiving messages from the subscription
+func (s *Subscription) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running.Load() {
+		return
+	}
+	s.running.Store(true)
+	s.done = make(chan struct{})
+	done := s.done
+
+	// Create cancellable context
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	go func() {
+		defer close(done)
+
+		err := s.receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+			s.deliver(ctx, newReceived(msg, s.maxMessageBytes))
+		})
+
+		if err != nil && ctx.Err() == nil {
+			select {
+			case s.errors <- wrapError(err):
+			default:
+			}
+		}
+
+		// A Stop followed by a new Start may have replaced this run;
+		// only clear the flag if it still belongs to us
+		s.mu.Lock()
+		if s.done == done {
+			s.running.Store(false)
+		}
+		s.mu.Unlock()
+	}()
+}
+
+// newReceived wraps a message from the stream, keeping at most maxBytes of
+// its data (all of it when maxBytes is 0)
+func newReceived(msg *pubsub.Message, maxBytes int) *ReceivedMessage {
+	data := msg.Data
+	if maxBytes > 0 && len(data) > maxBytes {
+		// Copy the kept bytes so the full payload can be freed
+		data = append([]byte(nil), data[:maxBytes]...)
+	}
+	return &ReceivedMessage{
+		ID:          msg.ID,
+		Data:        data,
+		DataSize:    len(msg.Data),
+		Attributes:  msg.Attributes,
+		PublishTime: msg.PublishTime,
+		OrderingKey: msg.OrderingKey,
+		AckID:       msg.ID,
+		ackFunc:     msg.Ack,
+		nackFunc:    msg.Nack,
+	}
+}
+
+// deliver hands a received message to the UI without blocking the receive
+// callback. If the buffer is full the message is nacked (so it will be
+// redelivered) and counted as dropped. Returns whether it was delivered.
+func (s *Subscription) deliver(ctx context.Context, received *ReceivedMessage) bool {
+	if ctx.Err() != nil {
+		received.Nack()
+		return false
+	}
+
+	// Count the message as outstanding until it is settled; a dropped
+	// message settles immediately through the nack below
+	s.outstanding.Add(1)
+	received.onSettle = func() { s.outstanding.Add(-1) }
+
+	select {
+	case s.messages <- received:
+		return true
+	default:
+		received.Nack()
+		s.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns the number of messages nacked because the buffer was full
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Stop stops receiving messages without waiting for the receive goroutine
+// to exit; use StopWait when no message may be delivered afterwards
+func (s *Subscription) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.running.Store(false)
+}
+
+// Drain stops intake and waits up to timeout for the receive goroutine to
+// exit, giving acks already issued a chance to be sent. Messages buffered but
+// not yet read are nacked for prompt redelivery. Returns how many delivered
+// messages were left neither acked nor nacked.
+func (s *Subscription) Drain(timeout time.Duration) int {
+	s.Stop()
+	s.nackBuffered()
+	s.wait(timeout)
+	return int(s.outstanding.Load())
+}
+
+// StopWait stops receiving messages and blocks until the receive goroutine
+// has exited, so no further messages are delivered, or until timeout.
+// The goroutine only exits once every delivered message has been acked or
+// nacked. Returns whether it exited in time.
+func (s *Subscription) StopWait(timeout time.Duration) bool {
+	s.Stop()
+	return s.wait(timeout)
+}
+
+// wait blocks until the receive goroutine exits or timeout elapses.
+// Returns true immediately if the subscription was never started.
+func (s *Subscription) wait(timeout time.Duration) bool {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+
+	if done == nil {
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// nackBuffered nacks messages waiting in the buffer that were never read
+func (s *Subscription) nackBuffered() {
+	for {
+		select {
+		case msg := <-s.messages:
+			msg.Nack()
+		default:
+			return
+		}
+	}
+}
+
+// Messages returns the channel for receiving messages
+func (s *Subscription) Messages() <-chan *ReceivedMessage {
+	return s.messages
+}
+
+// Errors returns the channel for receiving errors
+func (s *Subscription) Errors() <-chan error {
+	return s.errors
+}
+
+// IsRunning returns whether the subscription is actively receiving
+func (s *Subscription) IsRunning() bool {
+	return s.running.Load()
+}
+
+// SubscriptionExists checks if a subscription exists
+func (c *Client) SubscriptionExists(ctx context.Context, subscriptionName string) (_ bool, err error) {
+	defer c.observe("SubscriptionExists", time.Now(), &err)
+
+	sub := c.client.Subscription(subscriptionName)
+	return sub.Exists(ctx)
+}
@@ -0,0 +1,511 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+func TestReceivedMessage_Ack(t *testing.T) {
+	ackCalled := false
+	msg := &ReceivedMessage{
+		ID:   "test-msg-1",
+		Data: []byte("test data"),
+		ackFunc: func() {
+			ackCalled = true
+		},
+	}
+
+	// Initially not acked
+	if msg.IsAcked() {
+		t.Error("message should not be acked initially")
+	}
+
+	// Ack the message
+	msg.Ack()
+
+	if !ackCalled {
+		t.Error("ackFunc should have been called")
+	}
+	if !msg.IsAcked() {
+		t.Error("message should be acked after Ack()")
+	}
+}
+
+func TestReceivedMessage_Ack_Idempotent(t *testing.T) {
+	ackCount := 0
+	msg := &ReceivedMessage{
+		ID:   "test-msg-2",
+		Data: []byte("test data"),
+		ackFunc: func() {
+			ackCount++
+		},
+	}
+
+	// Ack multiple times
+	msg.Ack()
+	msg.Ack()
+	msg.Ack()
+
+	// Should only call ackFunc once
+	if ackCount != 1 {
+		t.Errorf("ackFunc should be called exactly once, was called %d times", ackCount)
+	}
+}
+
+func TestReceivedMessage_Nack(t *testing.T) {
+	nackCalled := false
+	msg := &ReceivedMessage{
+		ID:   "test-msg-3",
+		Data: []byte("test data"),
+		nackFunc: func() {
+			nackCalled = true
+		},
+	}
+
+	// Nack the message
+	msg.Nack()
+
+	if !nackCalled {
+		t.Error("nackFunc should have been called")
+	}
+
+	// Nack should NOT mark as acked
+	if msg.IsAcked() {
+		t.Error("Nack should not mark message as acked")
+	}
+	if !msg.IsNacked() {
+		t.Error("Nack should mark message as nacked")
+	}
+}
+
+func TestReceivedMessage_Ack_AfterNack_NoOp(t *testing.T) {
+	ackCalled := false
+	msg := &ReceivedMessage{
+		ID:      "test-msg-5",
+		ackFunc: func() { ackCalled = true },
+	}
+
+	// The server redelivers a nacked message, so acking this copy does nothing
+	msg.Nack()
+	msg.Ack()
+
+	if ackCalled || msg.IsAcked() {
+		t.Error("Ack should do nothing after Nack")
+	}
+}
+
+func TestReceivedMessage_Nack_AfterAck_NoOp(t *testing.T) {
+	ackCalled := false
+	nackCalled := false
+	msg := &ReceivedMessage{
+		ID:   "test-msg-4",
+		Data: []byte("test data"),
+		ackFunc: func() {
+			ackCalled = true
+		},
+		nackFunc: func() {
+			nackCalled = true
+		},
+	}
+
+	// Ack first
+	msg.Ack()
+
+	// Then try to nack
+	msg.Nack()
+
+	if !ackCalled {
+		t.Error("ackFunc should have been called")
+	}
+	if nackCalled {
+		t.Error("nackFunc should NOT be called after message is acked")
+	}
+}
+
+func TestReceivedMessage_SetAcked(t *testing.T) {
+	msg := &ReceivedMessage{
+		ID:   "test-msg-5",
+		Data: []byte("test data"),
+	}
+
+	if msg.IsAcked() {
+		t.Error("message should not be acked initially")
+	}
+
+	msg.SetAcked(true)
+	if !msg.IsAcked() {
+		t.Error("message should be acked after SetAcked(true)")
+	}
+
+	msg.SetAcked(false)
+	if msg.IsAcked() {
+		t.Error("message should not be acked after SetAcked(false)")
+	}
+}
+
+func TestReceivedMessage_NilFuncs(t *testing.T) {
+	// Test that Ack/Nack don't panic with nil funcs
+	msg := &ReceivedMessage{
+		ID:      "test-msg-6",
+		Data:    []byte("test data"),
+		ackFunc: nil,
+	}
+
+	// Should not panic
+	msg.Ack()
+	msg.Nack()
+
+	// Message should not be marked as acked when ackFunc is nil
+	if msg.IsAcked() {
+		t.Error("message should not be acked when ackFunc is nil")
+	}
+}
+
+func TestReceivedMessage_Concurrency(t *testing.T) {
+	ackCount := 0
+	var mu sync.Mutex
+	msg := &ReceivedMessage{
+		ID:   "test-msg-7",
+		Data: []byte("test data"),
+		ackFunc: func() {
+			mu.Lock()
+			ackCount++
+			mu.Unlock()
+		},
+	}
+
+	// Attempt concurrent acks
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg.Ack()
+		}()
+	}
+	wg.Wait()
+
+	// Should only have called ackFunc once
+	if ackCount != 1 {
+		t.Errorf("ackFunc should be called exactly once under concurrent access, was called %d times", ackCount)
+	}
+}
+
+func TestSubscription_IsRunning(t *testing.T) {
+	sub := &Subscription{
+		messages: make(chan *ReceivedMessage, 10),
+		errors:   make(chan error, 10),
+	}
+
+	if sub.IsRunning() {
+		t.Error("subscription should not be running initially")
+	}
+
+	sub.running.Store(true)
+
+	if !sub.IsRunning() {
+		t.Error("subscription should be running after setting flag")
+	}
+}
+
+func TestSubscription_Channels(t *testing.T) {
+	messages := make(chan *ReceivedMessage, 10)
+	errors := make(chan error, 10)
+
+	sub := &Subscription{
+		messages: messages,
+		errors:   errors,
+	}
+
+	// Verify channels are returned correctly
+	if sub.Messages() != messages {
+		t.Error("Messages() should return the messages channel")
+	}
+	if sub.Errors() != errors {
+		t.Error("Errors() should return the errors channel")
+	}
+}
+
+func TestSubscription_Deliver_FullChannel(t *testing.T) {
+	sub := &Subscription{
+		messages: make(chan *ReceivedMessage, 1),
+		errors:   make(chan error, 10),
+	}
+	ctx := context.Background()
+
+	// First message fits in the buffer
+	first := &ReceivedMessage{ID: "msg-1"}
+	if !sub.deliver(ctx, first) {
+		t.Fatal("first message should be delivered")
+	}
+
+	// Buffer is now full: the next message must be nacked and counted, not block
+	nackCalled := false
+	second := &ReceivedMessage{
+		ID:       "msg-2",
+		nackFunc: func() { nackCalled = true },
+	}
+	if sub.deliver(ctx, second) {
+		t.Error("message should not be delivered when the buffer is full")
+	}
+	if !nackCalled {
+		t.Error("dropped message should be nacked")
+	}
+	if sub.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", sub.Dropped())
+	}
+
+	// Draining the buffer allows delivery again
+	<-sub.Messages()
+	if !sub.deliver(ctx, &ReceivedMessage{ID: "msg-3"}) {
+		t.Error("message should be delivered after the buffer is drained")
+	}
+	if sub.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1 after successful delivery", sub.Dropped())
+	}
+}
+
+func TestSubscription_Deliver_CancelledContext(t *testing.T) {
+	sub := &Subscription{
+		messages: make(chan *ReceivedMessage, 1),
+		errors:   make(chan error, 10),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	nackCalled := false
+	msg := &ReceivedMessage{
+		ID:       "msg-1",
+		nackFunc: func() { nackCalled = true },
+	}
+	if sub.deliver(ctx, msg) {
+		t.Error("message should not be delivered after cancellation")
+	}
+	if !nackCalled {
+		t.Error("message should be nacked after cancellation")
+	}
+	if sub.Dropped() != 0 {
+		t.Errorf("Dropped() = %d, want 0 for cancelled delivery", sub.Dropped())
+	}
+}
+
+func TestSubscription_Drain(t *testing.T) {
+	cancelled := false
+	sub := &Subscription{
+		cancel:   func() { cancelled = true },
+		done:     make(chan struct{}),
+		messages: make(chan *ReceivedMessage, 10),
+		errors:   make(chan error, 10),
+	}
+	sub.running.Store(true)
+	ctx := context.Background()
+
+	// One message is read and acked, one is read and left pending,
+	// and one is still in the buffer when draining starts
+	acked := &ReceivedMessage{ID: "msg-1", ackFunc: func() {}}
+	pending := &ReceivedMessage{ID: "msg-2", ackFunc: func() {}}
+	bufferedNacked := false
+	buffered := &ReceivedMessage{ID: "msg-3", nackFunc: func() { bufferedNacked = true }}
+	for _, msg := range []*ReceivedMessage{acked, pending, buffered} {
+		sub.deliver(ctx, msg)
+	}
+	<-sub.Messages()
+	<-sub.Messages()
+	acked.Ack()
+
+	// The receive goroutine never exits, so Drain gives up after the timeout
+	if got := sub.Drain(10 * time.Millisecond); got != 1 {
+		t.Errorf("Drain() = %d, want 1 unacked message", got)
+	}
+	if !cancelled {
+		t.Error("Drain() should stop intake")
+	}
+	if sub.IsRunning() {
+		t.Error("subscription should not be running after Drain()")
+	}
+	if !bufferedNacked {
+		t.Error("buffered message should be nacked")
+	}
+
+	// Settling the pending message clears the count
+	pending.Ack()
+	if got := sub.outstanding.Load(); got != 0 {
+		t.Errorf("outstanding = %d, want 0", got)
+	}
+}
+
+func TestSubscription_Drain_WaitsForExit(t *testing.T) {
+	done := make(chan struct{})
+	sub := &Subscription{
+		done:     done,
+		messages: make(chan *ReceivedMessage, 1),
+		errors:   make(chan error, 10),
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(done)
+	}()
+
+	start := time.Now()
+	if got := sub.Drain(5 * time.Second); got != 0 {
+		t.Errorf("Drain() = %d, want 0", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Drain() took %v, should return once the goroutine exits", elapsed)
+	}
+}
+
+func TestSubscription_StopWait(t *testing.T) {
+	// Never started: nothing to wait for
+	sub := &Subscription{messages: make(chan *ReceivedMessage, 1)}
+	if !sub.StopWait(time.Second) {
+		t.Error("StopWait() should return true for a subscription that never started")
+	}
+
+	// Goroutine still running: times out
+	cancelled := false
+	sub = &Subscription{
+		cancel:   func() { cancelled = true },
+		done:     make(chan struct{}),
+		messages: make(chan *ReceivedMessage, 1),
+	}
+	sub.running.Store(true)
+	if sub.StopWait(10 * time.Millisecond) {
+		t.Error("StopWait() should time out while the goroutine is running")
+	}
+	if !cancelled {
+		t.Error("StopWait() should cancel the receive context")
+	}
+
+	// Goroutine exits: returns true
+	close(sub.done)
+	if !sub.StopWait(time.Second) {
+		t.Error("StopWait() should return true once the goroutine has exited")
+	}
+}
+
+// blockingReceive stands in for Pub/Sub Receive, returning when ctx is done
+func blockingReceive(ctx context.Context, _ func(context.Context, *pubsub.Message)) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestSubscription_StartStop_Loop(t *testing.T) {
+	sub := &Subscription{
+		receive:  blockingReceive,
+		messages: make(chan *ReceivedMessage, 1),
+		errors:   make(chan error, 1),
+	}
+	ctx := context.Background()
+
+	// Poll concurrently so the race detector sees reads alongside the
+	// goroutine's writes
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				sub.IsRunning()
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		sub.Start(ctx)
+		if !sub.IsRunning() {
+			t.Fatalf("iteration %d: should be running after Start()", i)
+		}
+		sub.Stop()
+		if sub.IsRunning() {
+			t.Fatalf("iteration %d: should not be running after Stop()", i)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if !sub.StopWait(time.Second) {
+		t.Fatal("receive goroutine did not exit")
+	}
+}
+
+func TestSubscription_Restart_KeepsRunning(t *testing.T) {
+	sub := &Subscription{
+		receive:  blockingReceive,
+		messages: make(chan *ReceivedMessage, 1),
+		errors:   make(chan error, 1),
+	}
+	ctx := context.Background()
+
+	sub.Start(ctx)
+	sub.Stop()
+	old := sub.done
+	sub.Start(ctx)
+
+	// The first goroutine exiting must not clear the restarted run's flag
+	<-old
+	if !sub.IsRunning() {
+		t.Error("subscription should still be running after the previous run exited")
+	}
+
+	if !sub.StopWait(time.Second) {
+		t.Fatal("receive goroutine did not exit")
+	}
+	if sub.IsRunning() {
+		t.Error("subscription should not be running after StopWait()")
+	}
+}
+
+func TestReceiveConfig_WithDefaults(t *testing.T) {
+	got := ReceiveConfig{MaxOutstandingMessages: 5}.withDefaults()
+	want := ReceiveConfig{MaxOutstandingMessages: 5, MaxOutstandingBytes: DefaultMaxOutstandingBytes}
+	if got != want {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewReceived_Truncation(t *testing.T) {
+	data := []byte("0123456789")
+	tests := []struct {
+		name          string
+		maxBytes      int
+		want          string
+		wantTruncated bool
+	}{
+		{"unlimited", 0, "0123456789", false},
+		{"under the limit", 20, "0123456789", false},
+		{"at the limit", 10, "0123456789", false},
+		{"over the limit", 4, "0123", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newReceived(&pubsub.Message{ID: "msg-1", Data: data}, tt.maxBytes)
+			if string(got.Data) != tt.want || got.Truncated() != tt.wantTruncated {
+				t.Errorf("Data = %q, Truncated() = %v; want %q, %v", got.Data, got.Truncated(), tt.want, tt.wantTruncated)
+			}
+			if got.DataSize != len(data) {
+				t.Errorf("DataSize = %d, want the full size %d", got.DataSize, len(data))
+			}
+		})
+	}
+
+	// The kept bytes do not share the full payload's memory
+	got := newReceived(&pubsub.Message{Data: data}, 4)
+	if cap(got.Data) >= len(data) {
+		t.Errorf("truncated data has capacity %d, want a copy of the kept bytes", cap(got.Data))
+	}
+
+	// Messages built without a size are never reported as truncated
+	if (&ReceivedMessage{Data: data}).Truncated() {
+		t.Error("a message without DataSize should not be truncated")
+	}
+}
@@ -0,0 +1,240 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/iterator"
+)
+
+// SubscriptionInfo represents information about a Pub/Sub subscription
+type SubscriptionInfo struct {
+	Name      string // Short name (without project prefix)
+	FullName  string // Full resource name
+	TopicName string // Associated topic short name
+	TopicFull string // Associated topic full name
+	Filter    string // Server-side message filter expression, if any
+	Orphaned  bool   // Whether the topic was deleted; TopicName is DeletedTopic
+
+	// Delivery configuration; only meaningful when ConfigLoaded is true
+	ConfigLoaded bool   // Whether the subscription config could be fetched
+	IsPush       bool   // Whether messages are pushed to an endpoint
+	PushEndpoint string // Push endpoint URL (empty for pull subscriptions)
+
+	// Inactivity after which the subscription is deleted; 0 when it never
+	// expires. The emulator reports 0 whatever the policy.
+	ExpirationTTL time.Duration
+}
+
+// UnknownTopic is the topic name reported for subscriptions whose config
+// could not be fetched, distinguishing them from ones with no topic info
+const UnknownTopic = "(unknown)"
+
+// DeletedTopic is the topic name the server reports for a subscription
+// whose topic was deleted. Such a subscription keeps its backlog but
+// receives no new messages.
+const DeletedTopic = "_deleted-topic_"
+
+// configRetryTimeout bounds the single retry of a failed config fetch
+const configRetryTimeout = 2 * time.Second
+
+// ListSubscriptions retrieves all subscriptions in the project
+func (c *Client) ListSubscriptions(ctx context.Context) (_ []SubscriptionInfo, err error) {
+	defer c.observe("ListSubscriptions", time.Now(), &err)
+
+	var subscriptions []SubscriptionInfo
+
+	it := c.client.Subscriptions(ctx)
+	for {
+		sub, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		// Get subscription config to retrieve the associated topic
+		cfg, err := fetchConfig(ctx, sub.Config)
+		if err != nil {
+			// If we can't get config, still include the subscription
+			subscriptions = append(subscriptions, unknownSubscriptionInfo(sub.ID(), sub.String()))
+			continue
+		}
+
+		subscriptions = append(subscriptions, subscriptionInfo(sub, cfg))
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscription retrieves a single subscription and its config, e.g. to
+// remember how to recreate it before deleting it
+func (c *Client) GetSubscription(ctx context.Context, subscriptionID string) (_ SubscriptionInfo, err error) {
+	defer c.observe("GetSubscription", time.Now(), &err)
+
+	sub := c.client.Subscription(subscriptionID)
+	cfg, err := sub.Config(ctx)
+	if err != nil {
+		return SubscriptionInfo{}, wrapError(fmt.Errorf("failed to get subscription %q: %w", subscriptionID, err))
+	}
+	return subscriptionInfo(sub, cfg), nil
+}
+
+// subscriptionInfo describes a subscription from its fetched config
+func subscriptionInfo(sub *pubsub.Subscription, cfg pubsub.SubscriptionConfig) SubscriptionInfo {
+	// The topic name is taken from its path: Topic.ID() panics on the
+	// deleted-topic marker, which is not a resource path
+	return SubscriptionInfo{
+		Name:      extractName(sub.ID()),
+		FullName:  sub.String(),
+		TopicName: extractName(cfg.Topic.String()),
+		TopicFull: cfg.Topic.String(),
+		Filter:    cfg.Filter,
+		Orphaned:  cfg.Topic.String() == DeletedTopic,
+
+		ConfigLoaded: true,
+		IsPush:       cfg.PushConfig.Endpoint != "",
+		PushEndpoint: cfg.PushConfig.Endpoint,
+
+		ExpirationTTL: expirationTTL(cfg),
+	}
+}
+
+// expirationTTL returns the expiration TTL of a fetched config. The client
+// reports a never-expiring subscription as 0.
+func expirationTTL(cfg pubsub.SubscriptionConfig) time.Duration {
+	ttl, _ := cfg.ExpirationPolicy.(time.Duration)
+	return ttl
+}
+
+// fetchConfig fetches a subscription config, retrying once with a short
+// timeout since a single failure is often a transient hiccup
+func fetchConfig(ctx context.Context, fetch func(context.Context) (pubsub.SubscriptionConfig, error)) (pubsub.SubscriptionConfig, error) {
+	cfg, err := fetch(ctx)
+	if err == nil {
+		return cfg, nil
+	}
+
+	retryCtx, cancel := context.WithTimeout(ctx, configRetryTimeout)
+	defer cancel()
+	cfg, err = fetch(retryCtx)
+	return cfg, wrapError(err)
+}
+
+// unknownSubscriptionInfo describes a subscription whose config could not be
+// fetched, so its topic and delivery type are unknown
+func unknownSubscriptionInfo(id, fullName string) SubscriptionInfo {
+	return SubscriptionInfo{
+		Name:      extractName(id),
+		FullName:  fullName,
+		TopicName: UnknownTopic,
+	}
+}
+
+// SubscriptionConfig is the full configuration of a subscription, including
+// its push endpoint, ack deadline, retention, expiration, dead-letter and
+// retry policies, ordering and labels. It is captured before a subscription
+// is deleted so it can be recreated as it was.
+type SubscriptionConfig struct {
+	TopicName string // Associated topic short name
+	Filter    string // Server-side message filter expression, if any
+
+	cfg pubsub.SubscriptionConfig
+}
+
+// GetSubscriptionConfig returns the full configuration of a subscription
+func (c *Client) GetSubscriptionConfig(ctx context.Context, subscriptionID string) (_ *SubscriptionConfig, err error) {
+	defer c.observe("GetSubscriptionConfig", time.Now(), &err)
+
+	cfg, err := c.client.Subscription(subscriptionID).Config(ctx)
+	if err != nil {
+		return nil, wrapError(fmt.Errorf("failed to get subscription %q: %w", subscriptionID, err))
+	}
+	return &SubscriptionConfig{
+		TopicName: extractName(cfg.Topic.String()),
+		Filter:    cfg.Filter,
+		cfg:       cfg,
+	}, nil
+}
+
+// RecreateSubscription creates a subscription with a configuration captured
+// by GetSubscriptionConfig, e.g. to undo its deletion
+func (c *Client) RecreateSubscription(ctx context.Context, subscriptionID string, cfg *SubscriptionConfig) (err error) {
+	defer c.observe("CreateSubscription", time.Now(), &err)
+
+	if err := ValidateResourceID(subscriptionID); err != nil {
+		return err
+	}
+	if _, err := c.client.CreateSubscription(ctx, subscriptionID, cfg.cfg); err != nil {
+		return wrapError(fmt.Errorf("failed to create subscription: %w", err))
+	}
+	return nil
+}
+
+// CreateSubscription creates a new subscription for the given topic
+func (c *Client) CreateSubscription(ctx context.Context, subscriptionID, topicID string) error {
+	return c.CreateSubscriptionWithFilter(ctx, subscriptionID, topicID, "")
+}
+
+// CreateSubscriptionWithFilter creates a new subscription for the given topic
+// that only receives messages matching the filter expression
+// (e.g. attributes.type = "order"). An empty filter receives all messages.
+// The filter syntax is validated by the server on creation.
+func (c *Client) CreateSubscriptionWithFilter(ctx context.Context, subscriptionID, topicID, filter string) (err error) {
+	defer c.observe("CreateSubscription", time.Now(), &err)
+
+	if err := ValidateResourceID(subscriptionID); err != nil {
+		return err
+	}
+
+	sub := c.client.Subscription(subscriptionID)
+	exists, err := sub.Exists(ctx)
+	if err != nil {
+		return wrapError(fmt.Errorf("failed to check subscription existence: %w", err))
+	}
+	if exists {
+		return fmt.Errorf("subscription %q already exists", subscriptionID)
+	}
+
+	topic := c.client.Topic(topicID)
+	topicExists, err := topic.Exists(ctx)
+	if err != nil {
+		return wrapError(fmt.Errorf("failed to check topic existence: %w", err))
+	}
+	if !topicExists {
+		return fmt.Errorf("topic %q does not exist", topicID)
+	}
+
+	_, err = c.client.CreateSubscription(ctx, subscriptionID, pubsub.SubscriptionConfig{
+		Topic:  topic,
+		Filter: filter,
+	})
+	if err != nil {
+		return wrapError(fmt.Errorf("failed to create subscription: %w", err))
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a subscription by ID
+func (c *Client) DeleteSubscription(ctx context.Context, subscriptionID string) (err error) {
+	defer c.observe("DeleteSubscription", time.Now(), &err)
+
+	sub := c.client.Subscription(subscriptionID)
+	exists, err := sub.Exists(ctx)
+	if err != nil {
+		return wrapError(fmt.Errorf("failed to check subscription existence: %w", err))
+	}
+	if !exists {
+		return fmt.Errorf("subscription %q does not exist", subscriptionID)
+	}
+
+	if err := sub.Delete(ctx); err != nil {
+		return wrapError(fmt.Errorf("failed to delete subscription: %w", err))
+	}
+
+	return nil
+}
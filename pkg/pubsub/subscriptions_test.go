@@ -0,0 +1,244 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFetchConfig(t *testing.T) {
+	t.Run("retries once after a failure", func(t *testing.T) {
+		calls := 0
+		fetch := func(ctx context.Context) (pubsub.SubscriptionConfig, error) {
+			calls++
+			if calls == 1 {
+				return pubsub.SubscriptionConfig{}, status.Error(codes.Unavailable, "connection reset")
+			}
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("retry should use a context with a timeout")
+			}
+			return pubsub.SubscriptionConfig{Filter: `attributes.type = "order"`}, nil
+		}
+
+		cfg, err := fetchConfig(context.Background(), fetch)
+		if err != nil {
+			t.Fatalf("fetchConfig() unexpected error: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("fetch called %d times, want 2", calls)
+		}
+		if cfg.Filter != `attributes.type = "order"` {
+			t.Errorf("fetchConfig() returned config from the wrong attempt: %+v", cfg)
+		}
+	})
+
+	t.Run("gives up after the retry fails", func(t *testing.T) {
+		calls := 0
+		fetch := func(ctx context.Context) (pubsub.SubscriptionConfig, error) {
+			calls++
+			return pubsub.SubscriptionConfig{}, status.Error(codes.PermissionDenied, "denied")
+		}
+
+		_, err := fetchConfig(context.Background(), fetch)
+		if err == nil {
+			t.Fatal("fetchConfig() should fail when both attempts fail")
+		}
+		if calls != 2 {
+			t.Errorf("fetch called %d times, want 2", calls)
+		}
+		if ErrorCategory(err) != CategoryPermissionDenied {
+			t.Errorf("ErrorCategory() = %q, want %q", ErrorCategory(err), CategoryPermissionDenied)
+		}
+	})
+
+	t.Run("does not retry on success", func(t *testing.T) {
+		calls := 0
+		fetch := func(ctx context.Context) (pubsub.SubscriptionConfig, error) {
+			calls++
+			return pubsub.SubscriptionConfig{}, nil
+		}
+
+		if _, err := fetchConfig(context.Background(), fetch); err != nil {
+			t.Fatalf("fetchConfig() unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("fetch called %d times, want 1", calls)
+		}
+	})
+}
+
+func TestUnknownSubscriptionInfo(t *testing.T) {
+	info := unknownSubscriptionInfo("orders-sub", "projects/p/subscriptions/orders-sub")
+
+	if info.Name != "orders-sub" {
+		t.Errorf("Name = %q, want %q", info.Name, "orders-sub")
+	}
+	if info.TopicName != UnknownTopic {
+		t.Errorf("TopicName = %q, want %q", info.TopicName, UnknownTopic)
+	}
+	if info.ConfigLoaded {
+		t.Error("ConfigLoaded should be false when the config could not be fetched")
+	}
+}
+
+func TestExpirationTTL(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy interface{}
+		want   time.Duration
+	}{
+		{"expires", 31 * 24 * time.Hour, 31 * 24 * time.Hour},
+		{"never expires", time.Duration(0), 0},
+		{"not reported", nil, 0},
+	}
+	for _, tt := range tests {
+		if got := expirationTTL(pubsub.SubscriptionConfig{ExpirationPolicy: tt.policy}); got != tt.want {
+			t.Errorf("%s: expirationTTL() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClient_GetSubscription(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateTopic(ctx, "orders"); err != nil {
+		t.Fatalf("CreateTopic() error = %v", err)
+	}
+	filter := `attributes.type = "order"`
+	if err := c.CreateSubscriptionWithFilter(ctx, "orders-sub", "orders", filter); err != nil {
+		t.Fatalf("CreateSubscriptionWithFilter() error = %v", err)
+	}
+
+	info, err := c.GetSubscription(ctx, "orders-sub")
+	if err != nil {
+		t.Fatalf("GetSubscription() error = %v", err)
+	}
+	if info.Name != "orders-sub" || info.TopicName != "orders" || info.Filter != filter {
+		t.Errorf("GetSubscription() = %+v, want orders-sub on orders with the filter", info)
+	}
+
+	if _, err := c.GetSubscription(ctx, "missing-sub"); ErrorCategory(err) != CategoryNotFound {
+		t.Errorf("GetSubscription() missing category = %q, want %q (err %v)", ErrorCategory(err), CategoryNotFound, err)
+	}
+}
+
+func TestClient_RecreateSubscription(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateTopic(ctx, "orders"); err != nil {
+		t.Fatalf("CreateTopic() error = %v", err)
+	}
+	_, err := c.client.CreateSubscription(ctx, "orders-sub", pubsub.SubscriptionConfig{
+		Topic:                 c.client.Topic("orders"),
+		AckDeadline:           45 * time.Second,
+		RetentionDuration:     time.Hour,
+		EnableMessageOrdering: true,
+		Filter:                `attributes.type = "order"`,
+		Labels:                map[string]string{"team": "billing"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	cfg, err := c.GetSubscriptionConfig(ctx, "orders-sub")
+	if err != nil {
+		t.Fatalf("GetSubscriptionConfig() error = %v", err)
+	}
+	if cfg.TopicName != "orders" || cfg.Filter != `attributes.type = "order"` {
+		t.Errorf("GetSubscriptionConfig() = %+v, want orders with the filter", cfg)
+	}
+	if err := c.DeleteSubscription(ctx, "orders-sub"); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+
+	// The recreated subscription has the whole config, not just the filter
+	if err := c.RecreateSubscription(ctx, "orders-sub", cfg); err != nil {
+		t.Fatalf("RecreateSubscription() error = %v", err)
+	}
+	got, err := c.client.Subscription("orders-sub").Config(ctx)
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	if got.AckDeadline != 45*time.Second || got.RetentionDuration != time.Hour || !got.EnableMessageOrdering ||
+		got.Filter != cfg.Filter || got.Labels["team"] != "billing" {
+		t.Errorf("recreated config = %+v, want the captured one", got)
+	}
+}
+
+func TestClient_ListSubscriptions_Orphaned(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	for _, topic := range []string{"orders", "billing"} {
+		if err := c.CreateTopic(ctx, topic); err != nil {
+			t.Fatalf("CreateTopic(%q) error = %v", topic, err)
+		}
+		if err := c.CreateSubscription(ctx, topic+"-sub", topic); err != nil {
+			t.Fatalf("CreateSubscription(%q) error = %v", topic+"-sub", err)
+		}
+	}
+	// The server keeps the subscription and marks its topic as deleted
+	if err := c.DeleteTopic(ctx, "orders"); err != nil {
+		t.Fatalf("DeleteTopic() error = %v", err)
+	}
+
+	subs, err := c.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	got := make(map[string]SubscriptionInfo)
+	for _, sub := range subs {
+		got[sub.Name] = sub
+	}
+	if orphan := got["orders-sub"]; !orphan.Orphaned || orphan.TopicName != DeletedTopic || !orphan.ConfigLoaded {
+		t.Errorf("orders-sub = %+v, want an orphaned subscription", orphan)
+	}
+	if sub := got["billing-sub"]; sub.Orphaned || sub.TopicName != "billing" {
+		t.Errorf("billing-sub = %+v, want attached to billing", sub)
+	}
+
+	info, err := c.GetSubscription(ctx, "orders-sub")
+	if err != nil || !info.Orphaned {
+		t.Errorf("GetSubscription() = %+v, %v; want orphaned", info, err)
+	}
+}
+
+func TestClient_ListSubscriptions_Delivery(t *testing.T) {
+	c := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := c.CreateTopic(ctx, "orders"); err != nil {
+		t.Fatalf("CreateTopic() error = %v", err)
+	}
+	if err := c.CreateSubscription(ctx, "pull-sub", "orders"); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	endpoint := "https://example.com/push"
+	if _, err := c.client.CreateSubscription(ctx, "push-sub", pubsub.SubscriptionConfig{
+		Topic:      c.client.Topic("orders"),
+		PushConfig: pubsub.PushConfig{Endpoint: endpoint},
+	}); err != nil {
+		t.Fatalf("CreateSubscription(push-sub) error = %v", err)
+	}
+
+	subs, err := c.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	got := make(map[string]SubscriptionInfo)
+	for _, sub := range subs {
+		got[sub.Name] = sub
+	}
+	if sub := got["push-sub"]; !sub.ConfigLoaded || !sub.IsPush || sub.PushEndpoint != endpoint {
+		t.Errorf("push-sub = %+v, want a push subscription to %s", sub, endpoint)
+	}
+	if sub := got["pull-sub"]; !sub.ConfigLoaded || sub.IsPush || sub.PushEndpoint != "" {
+		t.Errorf("pull-sub = %+v, want a pull subscription", sub)
+	}
+}
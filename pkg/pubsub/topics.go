@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"google.golang.org/api/iterator"
 )
@@ -16,7 +17,9 @@ type TopicInfo struct {
 }
 
 // ListTopics retrieves all topics in the project
-func (c *Client) ListTopics(ctx context.Context) ([]TopicInfo, error) {
+func (c *Client) ListTopics(ctx context.Context) (_ []TopicInfo, err error) {
+	defer c.observe("ListTopics", time.Now(), &err)
+
 	var topics []TopicInfo
 
 	it := c.client.Topics(ctx)
@@ -26,7 +29,7 @@ func (c *Client) ListTopics(ctx context.Context) ([]TopicInfo, error) {
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, wrapError(err)
 		}
 
 		topics = append(topics, TopicInfo{
@@ -39,15 +42,17 @@ func (c *Client) ListTopics(ctx context.Context) ([]TopicInfo, error) {
 }
 
 // CreateTopic creates a new topic with the given ID
-func (c *Client) CreateTopic(ctx context.Context, topicID string) error {
-	if err := validateResourceID(topicID); err != nil {
+func (c *Client) CreateTopic(ctx context.Context, topicID string) (err error) {
+	defer c.observe("CreateTopic", time.Now(), &err)
+
+	if err := ValidateResourceID(topicID); err != nil {
 		return err
 	}
 
 	topic := c.client.Topic(topicID)
 	exists, err := topic.Exists(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to check topic existence: %w", err)
+		return wrapError(fmt.Errorf("failed to check topic existence: %w", err))
 	}
 	if exists {
 		return fmt.Errorf("topic %q already exists", topicID)
@@ -55,25 +60,29 @@ func (c *Client) CreateTopic(ctx context.Context, topicID string) error {
 
 	_, err = c.client.CreateTopic(ctx, topicID)
 	if err != nil {
-		return fmt.Errorf("failed to create topic: %w", err)
+		return wrapError(fmt.Errorf("failed to create topic: %w", err))
 	}
 
 	return nil
 }
 
 // DeleteTopic deletes a topic by ID
-func (c *Client) DeleteTopic(ctx context.Context, topicID string) error {
+func (c *Client) DeleteTopic(ctx context.Context, topicID string) (err error) {
+	defer c.observe("DeleteTopic", time.Now(), &err)
+
+	c.evictTopic(topicID)
+
 	topic := c.client.Topic(topicID)
 	exists, err := topic.Exists(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to check topic existence: %w", err)
+		return wrapError(fmt.Errorf("failed to check topic existence: %w", err))
 	}
 	if !exists {
 		return fmt.Errorf("topic %q does not exist", topicID)
 	}
 
 	if err := topic.Delete(ctx); err != nil {
-		return fmt.Errorf("failed to delete topic: %w", err)
+		return wrapError(fmt.Errorf("failed to delete topic: %w", err))
 	}
 
 	return nil
@@ -89,10 +98,10 @@ func extractName(fullPath string) string {
 	return fullPath
 }
 
-// validateResourceID validates a Pub/Sub resource ID
+// ValidateResourceID validates a Pub/Sub resource ID
 // Must be 3-255 characters, start with a letter, and contain only
 // letters, numbers, dashes, periods, underscores, and tildes
-func validateResourceID(id string) error {
+func ValidateResourceID(id string) error {
 	if len(id) < 3 || len(id) > 255 {
 		return fmt.Errorf("resource ID must be 3-255 characters, got %d", len(id))
 	}
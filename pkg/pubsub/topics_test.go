@@ -107,18 +107,18 @@ func TestValidateResourceID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateResourceID(tt.id)
+			err := ValidateResourceID(tt.id)
 			if tt.wantErr {
 				if err == nil {
-					t.Errorf("validateResourceID(%q) expected error, got nil", tt.id)
+					t.Errorf("ValidateResourceID(%q) expected error, got nil", tt.id)
 					return
 				}
 				if tt.errMsg != "" && !containsSubstring(err.Error(), tt.errMsg) {
-					t.Errorf("validateResourceID(%q) error = %q, want error containing %q", tt.id, err.Error(), tt.errMsg)
+					t.Errorf("ValidateResourceID(%q) error = %q, want error containing %q", tt.id, err.Error(), tt.errMsg)
 				}
 			} else {
 				if err != nil {
-					t.Errorf("validateResourceID(%q) unexpected error: %v", tt.id, err)
+					t.Errorf("ValidateResourceID(%q) unexpected error: %v", tt.id, err)
 				}
 			}
 		})
@@ -132,14 +132,14 @@ func TestValidateResourceID_MaxLength(t *testing.T) {
 		validLongID = validLongID[:i] + "a" + validLongID[i+1:]
 	}
 	validLongID = make255CharID()
-	if err := validateResourceID(validLongID); err != nil {
-		t.Errorf("validateResourceID(255 chars) should be valid, got: %v", err)
+	if err := ValidateResourceID(validLongID); err != nil {
+		t.Errorf("ValidateResourceID(255 chars) should be valid, got: %v", err)
 	}
 
 	// Test 256 characters (invalid)
 	invalidLongID := validLongID + "x"
-	if err := validateResourceID(invalidLongID); err == nil {
-		t.Error("validateResourceID(256 chars) should be invalid")
+	if err := ValidateResourceID(invalidLongID); err == nil {
+		t.Error("ValidateResourceID(256 chars) should be invalid")
 	}
 }
 